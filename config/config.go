@@ -0,0 +1,221 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Port        string            `yaml:"port"`
+	Database    DatabaseConfig    `yaml:"database"`
+	CORS        CORSConfig        `yaml:"cors"`
+	Log         LogConfig         `yaml:"log"`
+	Token       TokenConfig       `yaml:"token"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+}
+
+type DatabaseConfig struct {
+	DSN             string        `yaml:"dsn"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	QueryTimeout    time.Duration `yaml:"query_timeout"`
+	AutoMigrate     bool          `yaml:"auto_migrate"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level"`
+}
+
+type TokenConfig struct {
+	Secret string        `yaml:"secret"`
+	TTL    time.Duration `yaml:"ttl"`
+}
+
+type IdempotencyConfig struct {
+	TTL   time.Duration `yaml:"ttl"`   // How long a retry with the same "Idempotency-Key" gets the first response back
+	Lease time.Duration `yaml:"lease"` // How long the key of a request that is still in progress is held, a retry after it runs the request again. It is extended while the request runs
+}
+
+func defaultConfig() Config {
+	return Config{
+		Port: "8080",
+		Database: DatabaseConfig{
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			QueryTimeout:    5 * time.Second,
+		},
+		CORS:        CORSConfig{AllowedOrigins: []string{"*"}},
+		Log:         LogConfig{Level: "info"},
+		Token:       TokenConfig{TTL: 24 * time.Hour},
+		Idempotency: IdempotencyConfig{TTL: 24 * time.Hour, Lease: time.Minute},
+	}
+}
+
+// Load builds the configuration from the defaults, the optional YAML file at "CONFIG_FILE"
+// and the environment variables, in that order of precedence, and validates the result
+func Load() (*Config, error) {
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+	err = cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadMigration builds the configuration like Load but only validates the database and log settings that "migrate" uses,
+// so that the migrations can run without "TOKEN_SECRET" and the other settings of the server
+func LoadMigration() (*Config, error) {
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+	err = joinErrors(cfg.migrationErrors())
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func read() (*Config, error) {
+	cfg := defaultConfig()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: read %s: %w", path, err)
+		}
+		err = yaml.Unmarshal(content, &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse %s: %w", path, err)
+		}
+	}
+	err := cfg.loadEnv()
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *Config) loadEnv() error {
+	var errList []error
+	setString := func(key string, target *string) {
+		if value, ok := os.LookupEnv(key); ok {
+			*target = value
+		}
+	}
+	setInt := func(key string, target *int) {
+		if value, ok := os.LookupEnv(key); ok {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				errList = append(errList, fmt.Errorf("%s need to be an integer", key))
+				return
+			}
+			*target = number
+		}
+	}
+	setDuration := func(key string, target *time.Duration) {
+		if value, ok := os.LookupEnv(key); ok {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				errList = append(errList, fmt.Errorf("%s need to be a duration e.g. \"30m\"", key))
+				return
+			}
+			*target = duration
+		}
+	}
+	setBool := func(key string, target *bool) {
+		if value, ok := os.LookupEnv(key); ok {
+			flag, err := strconv.ParseBool(value)
+			if err != nil {
+				errList = append(errList, fmt.Errorf("%s need to be a boolean", key))
+				return
+			}
+			*target = flag
+		}
+	}
+	setString("PORT", &c.Port)
+	setString("DATABASE_URL", &c.Database.DSN)
+	setInt("DB_MAX_OPEN_CONNS", &c.Database.MaxOpenConns)
+	setInt("DB_MAX_IDLE_CONNS", &c.Database.MaxIdleConns)
+	setDuration("DB_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime)
+	setDuration("DB_QUERY_TIMEOUT", &c.Database.QueryTimeout)
+	setBool("DB_AUTO_MIGRATE", &c.Database.AutoMigrate)
+	if value, ok := os.LookupEnv("CORS_ALLOWED_ORIGINS"); ok {
+		c.CORS.AllowedOrigins = strings.Split(value, ",")
+	}
+	setString("LOG_LEVEL", &c.Log.Level)
+	setString("TOKEN_SECRET", &c.Token.Secret)
+	setDuration("TOKEN_TTL", &c.Token.TTL)
+	setDuration("IDEMPOTENCY_TTL", &c.Idempotency.TTL)
+	setDuration("IDEMPOTENCY_LEASE", &c.Idempotency.Lease)
+	return joinErrors(errList)
+}
+
+func (c Config) Validate() error {
+	errList := c.migrationErrors()
+	if c.Port == "" {
+		errList = append(errList, errors.New("PORT is required"))
+	}
+	if len(c.CORS.AllowedOrigins) == 0 {
+		errList = append(errList, errors.New("CORS_ALLOWED_ORIGINS (cors.allowed_origins) need at least one origin"))
+	}
+	if c.Token.Secret == "" {
+		errList = append(errList, errors.New("TOKEN_SECRET (token.secret) is required"))
+	} else if len(c.Token.Secret) < 32 {
+		errList = append(errList, errors.New("TOKEN_SECRET (token.secret) need to contain at least 32 characters"))
+	}
+	if c.Token.TTL <= 0 {
+		errList = append(errList, errors.New("TOKEN_TTL (token.ttl) need to be positive"))
+	}
+	if c.Idempotency.TTL <= 0 {
+		errList = append(errList, errors.New("IDEMPOTENCY_TTL (idempotency.ttl) need to be positive"))
+	}
+	if c.Idempotency.Lease < time.Second {
+		errList = append(errList, errors.New("IDEMPOTENCY_LEASE (idempotency.lease) need to be at least 1s"))
+	}
+	return joinErrors(errList)
+}
+
+// migrationErrors validates the database and log settings, which are the only ones that "migrate" need
+func (c Config) migrationErrors() []error {
+	var errList []error
+	if c.Database.DSN == "" {
+		errList = append(errList, errors.New("DATABASE_URL (database.dsn) is required"))
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errList = append(errList, errors.New("database pool sizes can not be negative"))
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		errList = append(errList, errors.New("database connection max lifetime can not be negative"))
+	}
+	if c.Database.QueryTimeout <= 0 {
+		errList = append(errList, errors.New("DB_QUERY_TIMEOUT (database.query_timeout) need to be positive"))
+	}
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		errList = append(errList, fmt.Errorf("LOG_LEVEL (log.level) %q need to be one of debug, info, warn, error", c.Log.Level))
+	}
+	return errList
+}
+
+func joinErrors(errList []error) error {
+	if len(errList) != 0 {
+		return fmt.Errorf("config: %w", errors.Join(errList...))
+	}
+	return nil
+}
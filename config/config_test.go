@@ -0,0 +1,143 @@
+package config_test
+
+import (
+	"go-nutritioncalculator2/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const secret = "0123456789abcdef0123456789abcdef"
+
+func TestLoad(t *testing.T) {
+	t.Run("Success Case: Environment Variables", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://localhost/nutrition")
+		t.Setenv("TOKEN_SECRET", secret)
+		t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example,https://b.example")
+		t.Setenv("DB_MAX_OPEN_CONNS", "20")
+		t.Setenv("TOKEN_TTL", "2h")
+		t.Setenv("IDEMPOTENCY_TTL", "12h")
+		t.Setenv("IDEMPOTENCY_LEASE", "30s")
+		t.Setenv("DB_AUTO_MIGRATE", "true")
+		t.Setenv("DB_QUERY_TIMEOUT", "3s")
+		result, err := config.Load()
+		assert.ErrorIs(t, err, nil)
+		expected := &config.Config{
+			Port: "8080",
+			Database: config.DatabaseConfig{
+				DSN:             "postgres://localhost/nutrition",
+				MaxOpenConns:    20,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 30 * time.Minute,
+				QueryTimeout:    3 * time.Second,
+				AutoMigrate:     true,
+			},
+			CORS:        config.CORSConfig{AllowedOrigins: []string{"https://a.example", "https://b.example"}},
+			Log:         config.LogConfig{Level: "info"},
+			Token:       config.TokenConfig{Secret: secret, TTL: 2 * time.Hour},
+			Idempotency: config.IdempotencyConfig{TTL: 12 * time.Hour, Lease: 30 * time.Second},
+		}
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Success Case: Config File Overridden By Environment Variables", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		_ = os.WriteFile(path, []byte(`
+port: "9090"
+database:
+  dsn: postgres://file/nutrition
+  conn_max_lifetime: 1h
+log:
+  level: debug
+token:
+  secret: `+secret+`
+`), 0o600)
+		t.Setenv("CONFIG_FILE", path)
+		t.Setenv("LOG_LEVEL", "warn")
+		result, err := config.Load()
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, "9090", result.Port)
+		assert.Equal(t, "postgres://file/nutrition", result.Database.DSN)
+		assert.Equal(t, time.Hour, result.Database.ConnMaxLifetime)
+		assert.Equal(t, "warn", result.Log.Level)
+		assert.Equal(t, secret, result.Token.Secret)
+	})
+	t.Run("Missing Required Values", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "")
+		t.Setenv("TOKEN_SECRET", "")
+		_, err := config.Load()
+		assert.ErrorContains(t, err, "DATABASE_URL (database.dsn) is required")
+		assert.ErrorContains(t, err, "TOKEN_SECRET (token.secret) is required")
+	})
+	t.Run("Invalid Values", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://localhost/nutrition")
+		t.Setenv("TOKEN_SECRET", secret)
+		t.Setenv("DB_MAX_IDLE_CONNS", "many")
+		_, err := config.Load()
+		assert.ErrorContains(t, err, "DB_MAX_IDLE_CONNS need to be an integer")
+	})
+	t.Run("Invalid Auto Migrate Flag", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://localhost/nutrition")
+		t.Setenv("TOKEN_SECRET", secret)
+		t.Setenv("DB_AUTO_MIGRATE", "sometimes")
+		_, err := config.Load()
+		assert.ErrorContains(t, err, "DB_AUTO_MIGRATE need to be a boolean")
+	})
+	t.Run("Invalid Query Timeout", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://localhost/nutrition")
+		t.Setenv("TOKEN_SECRET", secret)
+		t.Setenv("DB_QUERY_TIMEOUT", "0s")
+		_, err := config.Load()
+		assert.ErrorContains(t, err, "DB_QUERY_TIMEOUT (database.query_timeout) need to be positive")
+	})
+	t.Run("Invalid Idempotency TTL", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://localhost/nutrition")
+		t.Setenv("TOKEN_SECRET", secret)
+		t.Setenv("IDEMPOTENCY_TTL", "-1h")
+		_, err := config.Load()
+		assert.ErrorContains(t, err, "IDEMPOTENCY_TTL (idempotency.ttl) need to be positive")
+	})
+	t.Run("Invalid Idempotency Lease", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://localhost/nutrition")
+		t.Setenv("TOKEN_SECRET", secret)
+		t.Setenv("IDEMPOTENCY_LEASE", "0s")
+		_, err := config.Load()
+		assert.ErrorContains(t, err, "IDEMPOTENCY_LEASE (idempotency.lease) need to be at least 1s")
+	})
+	t.Run("Invalid Log Level", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://localhost/nutrition")
+		t.Setenv("TOKEN_SECRET", secret)
+		t.Setenv("LOG_LEVEL", "verbose")
+		_, err := config.Load()
+		assert.ErrorContains(t, err, "need to be one of debug, info, warn, error")
+	})
+	t.Run("Missing Config File", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+		_, err := config.Load()
+		assert.ErrorContains(t, err, "config: read")
+	})
+}
+
+func TestLoadMigration(t *testing.T) {
+	t.Run("Success Case: Without Server Settings", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://localhost/nutrition")
+		t.Setenv("TOKEN_SECRET", "")
+		t.Setenv("IDEMPOTENCY_TTL", "0s")
+		result, err := config.LoadMigration()
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, "postgres://localhost/nutrition", result.Database.DSN)
+	})
+	t.Run("Missing Database URL", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "")
+		_, err := config.LoadMigration()
+		assert.ErrorContains(t, err, "DATABASE_URL (database.dsn) is required")
+	})
+	t.Run("Invalid Log Level", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://localhost/nutrition")
+		t.Setenv("LOG_LEVEL", "verbose")
+		_, err := config.LoadMigration()
+		assert.ErrorContains(t, err, "need to be one of debug, info, warn, error")
+	})
+}
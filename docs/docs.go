@@ -17,7 +17,12 @@ const docTemplate = `{
     "paths": {
         "/favlist/": {
             "put": {
-                "description": "Update a ` + "`" + `Favorite List` + "`" + `",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a ` + "`" + `Favorite List` + "`" + `, a field that is omitted is kept and a field that is null is cleared",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,25 +39,75 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/service.UpdateFavListRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `Favorite List` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable"
+                    "400": {
+                        "description": "Request Body Not Acceptable or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Favorite List` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `Favorite List` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Create a ` + "`" + `Favorite List` + "`" + ` for recording the daily meal easily",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Favorite List"
                 ],
@@ -66,23 +121,152 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/service.NewFavListRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that the client generated for the request e.g. a UUID, a retry with the same key gets the first response back instead of creating it again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Id and revision of the created ` + "`" + `Favorite List` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/service.CreatedResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the ` + "`" + `Favorite List` + "`" + ` in quotes"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The request with the ` + "`" + `Idempotency-Key` + "`" + ` is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "` + "`" + `Idempotency-Key` + "`" + ` is already used by another request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a ` + "`" + `Favorite List` + "`" + `, a field that is omitted is kept and a field that is null is cleared",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favorite List"
+                ],
+                "summary": "Update a \"Favorite List\"",
+                "parameters": [
+                    {
+                        "description": "` + "`" + `Favorite List` + "`" + `'s data detail that you want to update and can ignore the unchanged parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateFavListRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `Favorite List` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable"
+                    "400": {
+                        "description": "Request Body Not Acceptable or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Favorite List` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `Favorite List` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
         "/favlist/{favlist_id}": {
             "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Delete a ` + "`" + `Favorite List` + "`" + `",
                 "tags": [
                     "Favorite List"
@@ -95,287 +279,766 @@ const docTemplate = `{
                         "name": "favlist_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `Favorite List` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
-                    "406": {
-                        "description": "Request Parameter Not Acceptable"
+                    "400": {
+                        "description": "Request Parameter Not Acceptable or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Favorite List` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `Favorite List` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/favlist/{user_id}": {
-            "get": {
-                "description": "Get all ` + "`" + `Favorite List` + "`" + ` of the ` + "`" + `User Id` + "`" + `",
+        "/favlist/{favlist_id}/log": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a ` + "`" + `Record` + "`" + ` of every ` + "`" + `Menu` + "`" + ` in the ` + "`" + `Favorite List` + "`" + ` multiplied by ` + "`" + `multiplier` + "`" + `, every ` + "`" + `Menu` + "`" + ` in the ` + "`" + `Favorite List` + "`" + ` need to be up to date",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Favorite List"
                 ],
-                "summary": "Get all \"Favorite List\" of the \"User Id\"",
+                "summary": "Log a \"Favorite List\" as a \"Record\"",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User Id",
-                        "name": "user_id",
+                        "type": "integer",
+                        "description": "` + "`" + `Favorite List` + "`" + `'s id that you want to log",
+                        "name": "favlist_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "` + "`" + `Record` + "`" + `'s data detail",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.LogFavListRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that the client generated for the request e.g. a UUID, a retry with the same key gets the first response back instead of logging it again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Id and revision of the created ` + "`" + `Record` + "`" + `",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/service.FavListResponse"
+                            "$ref": "#/definitions/service.CreatedResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the ` + "`" + `Record` + "`" + ` in quotes"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Favorite List` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Atleast one ` + "`" + `Menu` + "`" + ` in the ` + "`" + `Favorite List` + "`" + ` is not up to date, the message lists their id, or the request with the ` + "`" + `Idempotency-Key` + "`" + ` is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "` + "`" + `Idempotency-Key` + "`" + ` is already used by another request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/menu/": {
+        "/favlist/{user_id}": {
             "get": {
-                "description": "Get all 'Menu'",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all ` + "`" + `Favorite List` + "`" + ` of the ` + "`" + `User Id` + "`" + `",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Favorite List"
+                ],
+                "summary": "Get all \"Favorite List\" of the \"User Id\"",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User Id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `ETag` + "`" + ` of the list that you already have",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Get all \"Menu\"",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service.MenuResponse"
+                                "$ref": "#/definitions/service.FavListResponse"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the body"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error"
-                    }
-                }
-            },
-            "put": {
-                "description": "Update a ` + "`" + `Menu` + "`" + `",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Menu"
-                ],
-                "summary": "Update a \"Menu\"",
-                "parameters": [
-                    {
-                        "description": "` + "`" + `Menu` + "`" + `'s data detail that you want to update and the unchanged parameters need to be input the old value",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                    "304": {
+                        "description": "Not Modified, the ETag in ` + "`" + `If-None-Match` + "`" + ` is still current"
+                    },
+                    "400": {
+                        "description": "` + "`" + `If-None-Match` + "`" + ` is not a list of ETags",
                         "schema": {
-                            "$ref": "#/definitions/service.UpdateMenuRequest"
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK"
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable"
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a 'Menu'",
-                "consumes": [
+            }
+        },
+        "/menu/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a page of ` + "`" + `Menu` + "`" + ` that match every given filter, only active ` + "`" + `Menu` + "`" + ` by default",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Menu"
                 ],
-                "summary": "Create a \"Menu\"",
+                "summary": "Search \"Menu\"",
                 "parameters": [
                     {
-                        "description": "` + "`" + `Menu` + "`" + `'s data detail",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service.NewMenuRequest"
-                        }
+                        "type": "string",
+                        "description": "Case-insensitive part of the name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `User Id` + "`" + ` that create the ` + "`" + `Menu` + "`" + `",
+                        "name": "creator_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `1` + "`" + ` (default) = Active, ` + "`" + `0` + "`" + ` = Deleted, ` + "`" + `all` + "`" + ` = both",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum protein (g.)",
+                        "name": "min_protein",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum protein (g.)",
+                        "name": "max_protein",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum fat (g.)",
+                        "name": "min_fat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum fat (g.)",
+                        "name": "max_fat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum carb (g.)",
+                        "name": "min_carb",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum carb (g.)",
+                        "name": "max_carb",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum energy (kcal)",
+                        "name": "min_kcal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum energy (kcal)",
+                        "name": "max_kcal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `newest` + "`" + `, ` + "`" + `likes` + "`" + ` (most liked first), ` + "`" + `protein_density` + "`" + ` (highest percentage of energy from protein first), default = creation order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum amount of ` + "`" + `Menu` + "`" + ` in the page, 1 - 200, default = 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Amount of ` + "`" + `Menu` + "`" + ` to skip, use ` + "`" + `next_offset` + "`" + ` of the previous page",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `ETag` + "`" + ` of the page that you already have",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.MenuPageResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the body"
+                            }
+                        }
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable"
+                    "304": {
+                        "description": "Not Modified, the ETag in ` + "`" + `If-None-Match` + "`" + ` is still current"
+                    },
+                    "400": {
+                        "description": "Request parameters Not Acceptable or ` + "`" + `If-None-Match` + "`" + ` is not a list of ETags",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/menu/{menu_id}": {
-            "delete": {
-                "description": "Delete a 'Menu'",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a ` + "`" + `Menu` + "`" + ` as a new version, a field that is omitted is kept and a field that is null is cleared",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Menu"
                 ],
-                "summary": "Delete a \"Menu\"",
+                "summary": "Update a \"Menu\"",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "` + "`" + `Menu` + "`" + `'s id that you want to delete",
-                        "name": "menu_id",
-                        "in": "path",
-                        "required": true
+                        "description": "` + "`" + `Menu` + "`" + `'s data detail that you want to update and the unchanged parameters need to be input the old value",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateMenuRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `Menu` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "Id and revision of the new version of the ` + "`" + `Menu` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/service.CreatedResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the new version in quotes"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Menu Id` + "`" + ` is not found or is deleted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "` + "`" + `Menu` + "`" + ` is already replaced by a newer version, the message names it",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
-                    "406": {
-                        "description": "Request Parameter Not Acceptable"
+                    "412": {
+                        "description": "` + "`" + `Menu` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/record/": {
-            "put": {
-                "description": "Update a 'Record'",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a 'Menu'",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Record"
+                    "Menu"
                 ],
-                "summary": "Update a \"Record\"",
+                "summary": "Create a \"Menu\"",
                 "parameters": [
                     {
-                        "description": "` + "`" + `Record` + "`" + `'s data detail that you want to change to",
+                        "description": "` + "`" + `Menu` + "`" + `'s data detail",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.UpdateRecordRequest"
+                            "$ref": "#/definitions/service.NewMenuRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that the client generated for the request e.g. a UUID, a retry with the same key gets the first response back instead of creating it again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "Id and revision of the created ` + "`" + `Menu` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/service.CreatedResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the ` + "`" + `Menu` + "`" + ` in quotes"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The request with the ` + "`" + `Idempotency-Key` + "`" + ` is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable or ` + "`" + `Record` + "`" + `'s id is not found"
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "` + "`" + `Idempotency-Key` + "`" + ` is already used by another request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             },
-            "post": {
-                "description": "Create a 'Record'",
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a ` + "`" + `Menu` + "`" + ` as a new version, a field that is omitted is kept and a field that is null is cleared",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Record"
+                    "Menu"
                 ],
-                "summary": "Create a \"Record\"",
+                "summary": "Update a \"Menu\"",
                 "parameters": [
                     {
-                        "description": "` + "`" + `Record` + "`" + `'s data detail",
+                        "description": "` + "`" + `Menu` + "`" + `'s data detail that you want to update and the unchanged parameters need to be input the old value",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.NewRecordRequest"
+                            "$ref": "#/definitions/service.UpdateMenuRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `Menu` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "Id and revision of the new version of the ` + "`" + `Menu` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/service.CreatedResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the new version in quotes"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Menu Id` + "`" + ` is not found or is deleted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "` + "`" + `Menu` + "`" + ` is already replaced by a newer version, the message names it",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `Menu` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable"
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/record/{record_id}": {
+        "/menu/{menu_id}": {
             "delete": {
-                "description": "Delete a 'Record'",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a 'Menu'",
                 "tags": [
-                    "Record"
+                    "Menu"
                 ],
-                "summary": "Delete a \"Record\"",
+                "summary": "Delete a \"Menu\"",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "` + "`" + `Record` + "`" + `'s id that you want to delete",
-                        "name": "record_id",
+                        "description": "` + "`" + `Menu` + "`" + `'s id that you want to delete",
+                        "name": "menu_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `Menu` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
-                    "406": {
-                        "description": "Request parameters Not Acceptable"
+                    "400": {
+                        "description": "Request Parameter Not Acceptable or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Menu Id` + "`" + ` is not found or is deleted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "` + "`" + `Menu` + "`" + ` is already replaced by a newer version, the message names it",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `Menu` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/record/{user_id}": {
+        "/menu/{menu_id}/history": {
             "get": {
-                "description": "Get all ` + "`" + `Record` + "`" + ` of ` + "`" + `User` + "`" + ` by ` + "`" + `User Id` + "`" + `",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every version in the lineage of a ` + "`" + `Menu` + "`" + ` from the first version to the newest one, any version's id can be used",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Record"
+                    "Menu"
                 ],
-                "summary": "Get all \"Record\" of \"User\"",
+                "summary": "Get every version of a \"Menu\"",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "` + "`" + `User Id` + "`" + ` that you want to get ` + "`" + `Record` + "`" + `",
-                        "name": "user_id",
+                        "type": "integer",
+                        "description": "` + "`" + `Menu` + "`" + `'s id of any version in the lineage",
+                        "name": "menu_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `ETag` + "`" + ` of the history that you already have",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -384,275 +1047,2701 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service.RecordResponse"
+                                "$ref": "#/definitions/service.MenuResponse"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the body"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error"
-                    }
-                }
-            }
-        },
-        "/recover/": {
-            "put": {
-                "description": "Get the deleted ` + "`" + `Menu` + "`" + ` off from ` + "`" + `Favorite Menu` + "`" + ` and {1. replace the deleted ` + "`" + `Menu` + "`" + ` in ` + "`" + `Favorite List` + "`" + ` with the new ` + "`" + `Menu` + "`" + ` that has the same detail (Can change the \"Menu\"'s name) / 2. get the deleted ` + "`" + `Menu` + "`" + ` off from ` + "`" + `Favorite List` + "`" + `}",
+                    "304": {
+                        "description": "Not Modified, the ETag in ` + "`" + `If-None-Match` + "`" + ` is still current"
+                    },
+                    "400": {
+                        "description": "Request Parameter Not Acceptable or ` + "`" + `If-None-Match` + "`" + ` is not a list of ETags",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Menu Id` + "`" + ` is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/record/": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a 'Record', a field that is omitted is kept and a field that is null is cleared",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Recover"
+                    "Record"
                 ],
-                "summary": "Recover a deleted \"Menu\"",
+                "summary": "Update a \"Record\"",
                 "parameters": [
                     {
-                        "description": "The data detail that you want",
+                        "description": "` + "`" + `Record` + "`" + `'s data detail that you want to change to",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.MultiRequest"
+                            "$ref": "#/definitions/service.UpdateRecordRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `Record` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable"
+                    "400": {
+                        "description": "Request Body Not Acceptable or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Record` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `Record` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/user/": {
+            },
             "post": {
-                "description": "Create a ` + "`" + `User` + "`" + `",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a 'Record'",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "User"
+                    "Record"
                 ],
-                "summary": "Create a \"User\"",
+                "summary": "Create a \"Record\"",
                 "parameters": [
                     {
-                        "description": "` + "`" + `User` + "`" + `'s data detail",
+                        "description": "` + "`" + `Record` + "`" + `'s data detail",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.NewUserRequest"
+                            "$ref": "#/definitions/service.NewRecordRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that the client generated for the request e.g. a UUID, a retry with the same key gets the first response back instead of creating it again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "Id and revision of the created ` + "`" + `Record` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/service.CreatedResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the ` + "`" + `Record` + "`" + ` in quotes"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The request with the ` + "`" + `Idempotency-Key` + "`" + ` is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable"
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "` + "`" + `Idempotency-Key` + "`" + ` is already used by another request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/user/login": {
-            "put": {
-                "description": "Check ` + "`" + `User Id` + "`" + ` and ` + "`" + `Password` + "`" + ` are correct or not",
-                "consumes": [
-                    "application/json"
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
-                "produces": [
+                "description": "Update a 'Record', a field that is omitted is kept and a field that is null is cleared",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Record"
                 ],
-                "summary": "Check \"User Id\" and \"Password\" are correct or not",
+                "summary": "Update a \"Record\"",
                 "parameters": [
                     {
-                        "description": "` + "`" + `User Id` + "`" + ` and ` + "`" + `Password` + "`" + `",
+                        "description": "` + "`" + `Record` + "`" + `'s data detail that you want to change to",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.LogInRequest"
+                            "$ref": "#/definitions/service.UpdateRecordRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `Record` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable or ` + "`" + `If-Match` + "`" + ` is not an ETag",
                         "schema": {
-                            "$ref": "#/definitions/service.LogInResponse"
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Record` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `Record` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable"
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/user/userdetail": {
-            "put": {
-                "description": "Update a ` + "`" + `User` + "`" + `'s detail",
+        "/record/{record_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a 'Record'",
                 "tags": [
-                    "User"
+                    "Record"
                 ],
-                "summary": "Update a \"User\"'s detail",
+                "summary": "Delete a \"Record\"",
                 "parameters": [
                     {
-                        "description": "` + "`" + `User` + "`" + `'s data detail that you want to update and can ignore the unchanged parameters",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service.UpdateUserRequest"
-                        }
+                        "type": "integer",
+                        "description": "` + "`" + `Record` + "`" + `'s id that you want to delete",
+                        "name": "record_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `Record` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
-                    "406": {
-                        "description": "Request Body Not Acceptable or ` + "`" + `User Id` + "`" + ` is not found"
+                    "400": {
+                        "description": "Request parameters Not Acceptable or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Record` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `Record` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/user/{user_id}": {
+        "/record/{user_id}": {
             "get": {
-                "description": "Get a ` + "`" + `User` + "`" + `'s detail by ` + "`" + `User Id` + "`" + `",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a page of ` + "`" + `Record` + "`" + ` of ` + "`" + `User` + "`" + ` by ` + "`" + `User Id` + "`" + ` ordered by the event timestamp, pass ` + "`" + `next_cursor` + "`" + ` of the page as ` + "`" + `cursor` + "`" + ` to get the next page",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "User"
+                    "Record"
                 ],
-                "summary": "Get a \"User\"'s detail",
+                "summary": "Get \"Record\" of \"User\"",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "` + "`" + `User Id` + "`" + `",
+                        "description": "` + "`" + `User Id` + "`" + ` that you want to get ` + "`" + `Record` + "`" + `",
                         "name": "user_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only ` + "`" + `Record` + "`" + ` that is eaten from this timestamp (inclusive) *format=` + "`" + `2023-01-01` + "`" + ` or ` + "`" + `2023-01-01 00:00:00` + "`" + `",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only ` + "`" + `Record` + "`" + ` that is eaten before this timestamp (exclusive) *format=` + "`" + `2023-01-01` + "`" + ` or ` + "`" + `2023-01-01 00:00:00` + "`" + `",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum amount of ` + "`" + `Record` + "`" + ` in the page, 1 - 200, default = 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `next_cursor` + "`" + ` of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `desc` + "`" + ` (default) = newest first, ` + "`" + `asc` + "`" + ` = oldest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `ETag` + "`" + ` of the page that you already have",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service.UserResponse"
+                            "$ref": "#/definitions/service.RecordPageResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the body"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified, the ETag in ` + "`" + `If-None-Match` + "`" + ` is still current"
+                    },
+                    "400": {
+                        "description": "Request parameters Not Acceptable or ` + "`" + `If-None-Match` + "`" + ` is not a list of ETags",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "406": {
-                        "description": "` + "`" + `User Id` + "`" + ` is not found"
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.MultiRequest": {
-            "type": "object",
-            "required": [
-                "deleted_menu_id",
-                "is_create",
-                "user_id"
-            ],
-            "properties": {
-                "deleted_menu_id": {
-                    "description": "\"Menu\"'s id that was deleted",
-                    "type": "integer",
-                    "example": 9
-                },
-                "is_create": {
-                    "description": "1 = Want to create new \"Menu\" for replace \"Menu\" in the \"Favorite List\", 0 = Dont want to create new \"Menu\" so the \"Favorite List\" that contain the deleted \"Menu\" will be updated by get the \"Menu\" off",
-                    "type": "integer",
-                    "example": 1
-                },
-                "new_menu_name": {
-                    "description": "New name of recovered \"Menu\"",
-                    "type": "string",
-                    "example": "Moo Yang V2"
-                },
-                "user_id": {
-                    "description": "\"User Id\" that want to recover the deleted \"Menu\"",
-                    "type": "string",
-                    "example": "gooddy20"
-                }
-            }
         },
-        "service.FavListResponse": {
-            "type": "object",
-            "properties": {
+        "/recover/": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the deleted ` + "`" + `Menu` + "`" + ` off from ` + "`" + `Favorite Menu` + "`" + ` and {1. replace the deleted ` + "`" + `Menu` + "`" + ` in ` + "`" + `Favorite List` + "`" + ` with the new ` + "`" + `Menu` + "`" + ` that has the same detail owned by the ` + "`" + `User` + "`" + ` (Can change the \"Menu\"'s name) / 2. get the deleted ` + "`" + `Menu` + "`" + ` off from ` + "`" + `Favorite List` + "`" + `}, nothing is changed when one of the steps fails",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recover"
+                ],
+                "summary": "Recover a deleted \"Menu\"",
+                "parameters": [
+                    {
+                        "description": "The data detail that you want",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MultiRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "The deleted ` + "`" + `Menu Id` + "`" + ` is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The ` + "`" + `Menu` + "`" + ` is not deleted or it is already replaced by a newer version",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Total nutrition of each day from ` + "`" + `from` + "`" + ` to ` + "`" + `to` + "`" + ` in the ` + "`" + `User` + "`" + `'s time zone with the average, min, max, standard deviation and adherence to the ` + "`" + `User` + "`" + `'s target e.g. a week or a month",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Summary"
+                ],
+                "summary": "Get the nutrition report of \"User\"",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `User Id` + "`" + ` that you want to get the report",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the report *format=` + "`" + `2023-01-01` + "`" + `",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the report *format=` + "`" + `2023-01-01` + "`" + `, atmost 366 days after ` + "`" + `from` + "`" + `",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.ReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request parameters Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/{user_id}/daily": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sum every ` + "`" + `Record` + "`" + ` of the calendar day in the ` + "`" + `User` + "`" + `'s time zone and compare it with the ` + "`" + `User` + "`" + `'s target",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Summary"
+                ],
+                "summary": "Get the daily nutrition summary of \"User\"",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `User Id` + "`" + ` that you want to get the summary",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calendar day of the summary *format=` + "`" + `2023-01-01` + "`" + `, default = today",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.DailySummaryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request parameters Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sync": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every ` + "`" + `Record` + "`" + `, ` + "`" + `Favorite List` + "`" + ` and ` + "`" + `Favorite Menu` + "`" + ` of the ` + "`" + `User` + "`" + ` that is created, updated or deleted since the cursor with the ` + "`" + `Menu` + "`" + ` that they use, a deleted row is returned with ` + "`" + `deleted` + "`" + ` = true (` + "`" + `status` + "`" + ` = 0 for ` + "`" + `Menu` + "`" + `) so the offline client can delete it too, no ` + "`" + `since` + "`" + ` = every row for the first sync and pass ` + "`" + `next_cursor` + "`" + ` as ` + "`" + `since` + "`" + ` to the next sync",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Get the changes of \"User\" since the previous sync",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `next_cursor` + "`" + ` of the previous sync",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.SyncChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request parameters Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply a batch of ` + "`" + `upsert` + "`" + ` and ` + "`" + `delete` + "`" + ` of ` + "`" + `Record` + "`" + ` and ` + "`" + `Favorite List` + "`" + ` in order, a row that the client created offline is sent with the UUID that the client generated as ` + "`" + `client_id` + "`" + ` so a retried create is ` + "`" + `applied` + "`" + ` again without a second row. The server's row wins a conflict: a change that is based on an older ` + "`" + `base_revision` + "`" + ` than the server's one or a row that is deleted on the server is not applied and its result has the server's row. A change that can not be saved e.g. it uses a deleted ` + "`" + `Menu` + "`" + ` is ` + "`" + `rejected` + "`" + ` without stopping the others, and an unexpected error saves none of the batch so that it can be retried as a whole",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Apply the changes that the client made offline",
+                "parameters": [
+                    {
+                        "description": "Changes that the client made offline",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.SyncRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.SyncResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error, none of the mutations is saved",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/target/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "BMR by the Mifflin-St Jeor equation, TDEE of the activity level and the daily protein, fat and carb targets of the goal from the ` + "`" + `User` + "`" + `'s profile, send ` + "`" + `apply_suggested_targets` + "`" + ` = ` + "`" + `true` + "`" + ` to ` + "`" + `PUT /user/userdetail` + "`" + ` to use them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get the suggested targets of \"User\"",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `User Id` + "`" + ` that you want to get the suggested targets",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.TargetResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `User Id` + "`" + ` is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "The profile is incomplete",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tdee/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Estimate the maintenance energy of the ` + "`" + `User` + "`" + ` from the average intake of the logged days in the last weeks before today and the change of the trend weight of ` + "`" + `Weight Log` + "`" + ` and the weight of ` + "`" + `Record` + "`" + ` over the same days, with the recommended targets of the ` + "`" + `User` + "`" + `'s goal",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Summary"
+                ],
+                "summary": "Get the adaptive TDEE of \"User\"",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `User Id` + "`" + ` that you want to estimate TDEE",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Amount of weeks to look back, 2 - 12, default = 4",
+                        "name": "weeks",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.AdaptiveTdeeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request parameters Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Not enough logged data",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/": {
+            "post": {
+                "description": "Create a ` + "`" + `User` + "`" + `",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Create a \"User\"",
+                "parameters": [
+                    {
+                        "description": "` + "`" + `User` + "`" + `'s data detail",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.NewUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "` + "`" + `User Id` + "`" + ` or ` + "`" + `Username` + "`" + ` is already used",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login": {
+            "put": {
+                "description": "Check ` + "`" + `User Id` + "`" + ` and ` + "`" + `Password` + "`" + ` are correct or not and issue an access token for the other endpoints when they are correct",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Check \"User Id\" and \"Password\" are correct or not",
+                "parameters": [
+                    {
+                        "description": "` + "`" + `User Id` + "`" + ` and ` + "`" + `Password` + "`" + `",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.LogInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.LogInResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/userdetail": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a ` + "`" + `User` + "`" + `'s detail, a field that is omitted is kept and a field that is null is cleared",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update a \"User\"'s detail",
+                "parameters": [
+                    {
+                        "description": "` + "`" + `User` + "`" + `'s data detail that you want to update and can ignore the unchanged parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `User` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable, a ` + "`" + `Favorite Menu` + "`" + ` is not found or deleted or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `User Id` + "`" + ` is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "` + "`" + `Username` + "`" + ` is already used",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `User` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a ` + "`" + `User` + "`" + `'s detail, a field that is omitted is kept and a field that is null is cleared",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update a \"User\"'s detail",
+                "parameters": [
+                    {
+                        "description": "` + "`" + `User` + "`" + `'s data detail that you want to update and can ignore the unchanged parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quoted ` + "`" + `revision` + "`" + ` of the ` + "`" + `User` + "`" + ` that the change is based on, no header or ` + "`" + `*` + "`" + ` = any revision",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable, a ` + "`" + `Favorite Menu` + "`" + ` is not found or deleted or ` + "`" + `If-Match` + "`" + ` is not an ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `User Id` + "`" + ` is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "` + "`" + `Username` + "`" + ` is already used",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "` + "`" + `User` + "`" + ` has been changed since the ETag in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a ` + "`" + `User` + "`" + `'s detail by ` + "`" + `User Id` + "`" + `",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get a \"User\"'s detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `User Id` + "`" + `",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `ETag` + "`" + ` of the ` + "`" + `User` + "`" + ` that you already have",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.UserResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the ` + "`" + `User` + "`" + ` in quotes"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified, the ETag in ` + "`" + `If-None-Match` + "`" + ` is still current"
+                    },
+                    "400": {
+                        "description": "` + "`" + `If-None-Match` + "`" + ` is not a list of ETags",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `User Id` + "`" + ` is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weight/": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a 'Weight Log', a field that is omitted is kept and a field that is null is cleared",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Weight"
+                ],
+                "summary": "Update a \"Weight Log\"",
+                "parameters": [
+                    {
+                        "description": "` + "`" + `Weight Log` + "`" + `'s data detail that you want to change to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateWeightLogRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Weight Log` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Log the body weight and the optional body fat of the 'User' at a timestamp",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Weight"
+                ],
+                "summary": "Create a \"Weight Log\"",
+                "parameters": [
+                    {
+                        "description": "` + "`" + `Weight Log` + "`" + `'s data detail",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.NewWeightLogRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `User Id` + "`" + ` is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a 'Weight Log', a field that is omitted is kept and a field that is null is cleared",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Weight"
+                ],
+                "summary": "Update a \"Weight Log\"",
+                "parameters": [
+                    {
+                        "description": "` + "`" + `Weight Log` + "`" + `'s data detail that you want to change to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateWeightLogRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Request Body Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Weight Log` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Request Body is not ` + "`" + `application/json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weight/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get ` + "`" + `Weight Log` + "`" + ` of ` + "`" + `User` + "`" + ` by ` + "`" + `User Id` + "`" + ` ordered by the logged timestamp",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Weight"
+                ],
+                "summary": "Get \"Weight Log\" of \"User\"",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `User Id` + "`" + ` that you want to get ` + "`" + `Weight Log` + "`" + `",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only ` + "`" + `Weight Log` + "`" + ` that is logged from this timestamp (inclusive) *format=` + "`" + `2023-01-01` + "`" + ` or ` + "`" + `2023-01-01 00:00:00` + "`" + `",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only ` + "`" + `Weight Log` + "`" + ` that is logged before this timestamp (exclusive) *format=` + "`" + `2023-01-01` + "`" + ` or ` + "`" + `2023-01-01 00:00:00` + "`" + `",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service.WeightLogResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Request parameters Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weight/{user_id}/trend": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Average weight of each logged day from ` + "`" + `from` + "`" + ` to ` + "`" + `to` + "`" + ` in the ` + "`" + `User` + "`" + `'s time zone smoothed by a 7-day exponential moving average with the weekly rate of change of the trend",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Weight"
+                ],
+                "summary": "Get the weight trend of \"User\"",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `User Id` + "`" + ` that you want to get the weight trend",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the trend *format=` + "`" + `2023-01-01` + "`" + `, default = 89 days before ` + "`" + `to` + "`" + `",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the trend *format=` + "`" + `2023-01-01` + "`" + `, default = today, atmost 366 days after ` + "`" + `from` + "`" + `",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.WeightTrendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request parameters Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weight/{weight_log_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a 'Weight Log'",
+                "tags": [
+                    "Weight"
+                ],
+                "summary": "Delete a \"Weight Log\"",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "` + "`" + `Weight Log` + "`" + `'s id that you want to delete",
+                        "name": "weight_log_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Request parameters Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Invalid Access Token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission Denied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "` + "`" + `Weight Log` + "`" + `'s id is not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "errs.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ErrorBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Stable code of the error e.g. \"USER_NOT_FOUND\", \"VALIDATION_FAILED\"",
+                    "type": "string",
+                    "example": "VALIDATION_FAILED"
+                },
+                "details": {
+                    "description": "Invalid fields of the request (only for \"VALIDATION_FAILED\")",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errs.FieldError"
+                    }
+                },
+                "message": {
+                    "description": "Message of the error for people",
+                    "type": "string",
+                    "example": "Weight need to be positive"
+                },
+                "request_id": {
+                    "description": "Id of the request that is also sent in the \"X-Request-Id\" header",
+                    "type": "string",
+                    "example": "3f9c1e7a5b2d4c60"
+                }
+            }
+        },
+        "handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/handler.ErrorBody"
+                }
+            }
+        },
+        "handler.MultiRequest": {
+            "type": "object",
+            "required": [
+                "deleted_menu_id"
+            ],
+            "properties": {
+                "deleted_menu_id": {
+                    "description": "\"Menu\"'s id that was deleted",
+                    "type": "integer",
+                    "example": 9
+                },
+                "is_create": {
+                    "description": "1 = Want to create new \"Menu\" for replace \"Menu\" in the \"Favorite List\", 0 (default) = Dont want to create new \"Menu\" so the \"Favorite List\" that contain the deleted \"Menu\" will be updated by get the \"Menu\" off",
+                    "type": "integer",
+                    "maximum": 1,
+                    "minimum": 0,
+                    "example": 1
+                },
+                "new_menu_name": {
+                    "description": "New name of recovered \"Menu\"",
+                    "type": "string",
+                    "example": "Moo Yang V2"
+                }
+            }
+        },
+        "service.AdaptiveTdeeResponse": {
+            "type": "object",
+            "properties": {
+                "average_intake": {
+                    "description": "Average energy (kcal) of the logged days",
+                    "type": "number",
+                    "example": 2150.4
+                },
+                "end_trend": {
+                    "description": "Trend weight (kg.) at the last weighed day",
+                    "type": "number",
+                    "example": 70.4
+                },
+                "from": {
+                    "description": "First day of the estimation *format=\"2023-01-01\"",
+                    "type": "string",
+                    "example": "2023-11-07"
+                },
+                "goal": {
+                    "description": "Goal that the targets are recommended for, \"maintain\" when the \"User\" has no goal",
+                    "type": "string",
+                    "example": "cut"
+                },
+                "logged_days": {
+                    "description": "Amount of days that have at least 1 \"Record\"",
+                    "type": "integer",
+                    "example": 26
+                },
+                "start_trend": {
+                    "description": "Trend weight (kg.) at the first weighed day",
+                    "type": "number",
+                    "example": 71.2
+                },
+                "target": {
+                    "description": "Recommended daily protein, fat and carb (g.) and energy (kcal) of the goal from the estimated TDEE",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "tdee": {
+                    "description": "Estimated maintenance energy (kcal/day) = average intake - energy of the weight change per day",
+                    "type": "number",
+                    "example": 2370.4
+                },
+                "timezone": {
+                    "description": "Time zone that the days are counted in",
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
+                "to": {
+                    "description": "Last day of the estimation *format=\"2023-01-01\"",
+                    "type": "string",
+                    "example": "2023-12-04"
+                },
+                "user_id": {
+                    "description": "\"User Id\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                },
+                "weekly_rate": {
+                    "description": "Change of the trend weight (kg.) per week",
+                    "type": "number",
+                    "example": -0.2
+                },
+                "weeks": {
+                    "description": "Amount of weeks that the estimation looks back",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "service.CreatedResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Id of the created row",
+                    "type": "integer",
+                    "example": 12
+                },
+                "revision": {
+                    "description": "Revision of the created row that is sent as \"If-Match\" e.g. \"1\" to update or delete it",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "service.DailySummaryResponse": {
+            "type": "object",
+            "properties": {
+                "consumed": {
+                    "description": "Total nutrition of every \"Record\" in the day",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "date": {
+                    "description": "Calendar day of the summary *format=\"2023-01-01\"",
+                    "type": "string",
+                    "example": "2023-12-05"
+                },
+                "meals": {
+                    "description": "Consumed nutrition of each note in the order that it is first eaten",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.MealSummary"
+                    }
+                },
+                "remaining": {
+                    "description": "Target - consumed, it is negative when the target is exceeded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "target": {
+                    "description": "Default protein, fat and carb of the \"User\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "timezone": {
+                    "description": "Time zone that the day is counted in",
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
+                "user_id": {
+                    "description": "\"User Id\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                }
+            }
+        },
+        "service.DailyTotal": {
+            "type": "object",
+            "properties": {
+                "consumed": {
+                    "description": "Total nutrition of every \"Record\" in the day",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "date": {
+                    "description": "Calendar day *format=\"2023-01-01\"",
+                    "type": "string",
+                    "example": "2023-12-05"
+                },
+                "is_adherent": {
+                    "description": "\"true\" = protein, fat and carb of the logged day are within ±10% of the target",
+                    "type": "boolean",
+                    "example": false
+                },
+                "is_logged": {
+                    "description": "\"true\" = atleast one \"Record\" in the day, a day that is not logged is not in the statistics",
+                    "type": "boolean",
+                    "example": true
+                },
+                "record_ids": {
+                    "description": "\"Record\"'s id in the day",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "service.FavListResponse": {
+            "type": "object",
+            "properties": {
+                "alcohol": {
+                    "description": "Total alcohol (g.) in the \"Favorite List\"",
+                    "type": "number",
+                    "example": 0
+                },
+                "carb": {
+                    "description": "Total carb (g.) in the \"Favorite List\"",
+                    "type": "number",
+                    "example": 20
+                },
+                "fat": {
+                    "description": "Total fat (g.) in the \"Favorite List\"",
+                    "type": "number",
+                    "example": 10
+                },
+                "id": {
+                    "description": "\"Favorite List\"'s id that generate by system",
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_updated": {
+                    "description": "1 = All \"Menu\" in the \"Favorite List\" are up to date, 0 = atleast one \"Menu\" in the \"Favorite List\" are not up to date",
+                    "type": "integer",
+                    "example": 1
+                },
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Item"
+                    }
+                },
+                "kcal": {
+                    "description": "Total energy (kcal) in the \"Favorite List\"",
+                    "type": "number",
+                    "example": 330
+                },
+                "list": {
+                    "description": "Summary meal with \"Menu\"'s id e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Favorite List\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea",
+                    "type": "string",
+                    "example": "9,9,10"
+                },
+                "macro_split": {
+                    "description": "Percentage of energy from each macro nutrient in the \"Favorite List\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.MacroSplit"
+                        }
+                    ]
+                },
+                "menues": {
+                    "description": "Summary each \"Menu\"'s name and amount of the \"Favorite List\"",
+                    "type": "string",
+                    "example": "Moo Yang-2, Sticky Rice-1 "
+                },
+                "name": {
+                    "description": "Name of \"Favorite List\" that named by the user",
+                    "type": "string",
+                    "example": "Daily Breakfast"
+                },
+                "nutrients": {
+                    "description": "Total known extended nutrients in the \"Favorite List\", a \"Menu\" without the nutrient does not add to it",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "protein": {
+                    "description": "Total protein (g.) in the \"Favorite List\"",
+                    "type": "number",
+                    "example": 40
+                },
+                "revision": {
+                    "description": "Revision of the \"Favorite List\" that is sent as \"If-Match\" e.g. \"3\" to update or delete it",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "service.Item": {
+            "type": "object",
+            "properties": {
+                "menu_id": {
+                    "description": "\"Menu\"'s id",
+                    "type": "integer",
+                    "example": 9
+                },
+                "quantity": {
+                    "description": "Amount of the \"Menu\" in the unit e.g. 1.5 servings of \"Sticky Rice\" or 180 g of \"Chicken Breast\"",
+                    "type": "number",
+                    "example": 1.5
+                },
+                "unit": {
+                    "description": "\"serving\" (default), \"g\" or \"ml\", \"g\" and \"ml\" need to be the same unit as the \"Menu\"'s serving size",
+                    "type": "string",
+                    "example": "serving"
+                }
+            }
+        },
+        "service.LogFavListRequest": {
+            "type": "object",
+            "required": [
+                "event_timestamp"
+            ],
+            "properties": {
+                "event_timestamp": {
+                    "description": "Wall-clock timestamp in your time zone that you eat, it is kept as it is and the days of the summary are counted on it *format=\"2023-01-01 00:00:00\"",
+                    "type": "string",
+                    "example": "2023-11-01 09:30:00"
+                },
+                "multiplier": {
+                    "description": "Multiply the quantity of every \"Menu\" in the \"Favorite List\" e.g. 0.5 = half of the meal, default = 1",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 1.5
+                },
+                "note": {
+                    "description": "Note for the \"Record\", default = name of the \"Favorite List\"",
+                    "type": "string",
+                    "example": "Breakfast"
+                },
+                "weight": {
+                    "description": "Weight (kg.) that you are on that day",
+                    "type": "number",
+                    "maximum": 500,
+                    "minimum": 20,
+                    "example": 63
+                }
+            }
+        },
+        "service.LogInRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "user_id"
+            ],
+            "properties": {
+                "password": {
+                    "description": "\"Password\"",
+                    "type": "string",
+                    "example": "zxc123zxc123"
+                },
+                "user_id": {
+                    "description": "\"User Id\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                }
+            }
+        },
+        "service.LogInResponse": {
+            "type": "object",
+            "properties": {
+                "IsLogIn": {
+                    "description": "\"true\" = Pass, \"false\" = Incorrect \"User Id\" or \"Password\"",
+                    "type": "boolean",
+                    "example": true
+                },
+                "access_token": {
+                    "description": "Signed access token that need to be sent as \"Authorization: Bearer \u003ctoken\u003e\" (only when \"IsLogIn\" = \"true\")",
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "expires_at": {
+                    "description": "Timestamp that the access token is expired",
+                    "type": "string",
+                    "example": "2023-12-06T10:00:00Z"
+                },
+                "token_type": {
+                    "description": "Type of the access token",
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "service.MacroSplit": {
+            "type": "object",
+            "properties": {
+                "alcohol": {
+                    "description": "Percentage of energy from alcohol",
+                    "type": "number",
+                    "example": 0
+                },
+                "carb": {
+                    "description": "Percentage of energy from carb",
+                    "type": "number",
+                    "example": 28.3
+                },
+                "fat": {
+                    "description": "Percentage of energy from fat",
+                    "type": "number",
+                    "example": 41.2
+                },
+                "protein": {
+                    "description": "Percentage of energy from protein",
+                    "type": "number",
+                    "example": 30.5
+                }
+            }
+        },
+        "service.MealSummary": {
+            "type": "object",
+            "properties": {
+                "consumed": {
+                    "description": "Total nutrition of the \"Record\" with the note",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "note": {
+                    "description": "\"Record\"'s note that the meals are grouped by",
+                    "type": "string",
+                    "example": "Breakfast"
+                },
+                "record_ids": {
+                    "description": "\"Record\"'s id with the note in the day",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1
+                    ]
+                }
+            }
+        },
+        "service.MenuPageResponse": {
+            "type": "object",
+            "properties": {
+                "menues": {
+                    "description": "\"Menu\" in the page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.MenuResponse"
+                    }
+                },
+                "next_offset": {
+                    "description": "\"offset\" of the next page, null when it is the last page",
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
+        "service.MenuResponse": {
+            "type": "object",
+            "properties": {
+                "alcohol": {
+                    "description": "Alcohol of \"Menu\"",
+                    "type": "number",
+                    "example": 0
+                },
+                "carb": {
+                    "description": "Carb of \"Menu\"",
+                    "type": "number",
+                    "example": 0
+                },
+                "creator_id": {
+                    "description": "\"User Id\" that create the \"Menu\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                },
+                "creator_name": {
+                    "description": "\"Username\" that create the \"Menu\"",
+                    "type": "string",
+                    "example": "GoodDy"
+                },
+                "fat": {
+                    "description": "Fat of \"Menu\"",
+                    "type": "number",
+                    "example": 5
+                },
+                "id": {
+                    "description": "\"Menu\"'s id that generate by system",
+                    "type": "integer",
+                    "example": 9
+                },
+                "kcal": {
+                    "description": "Energy (kcal) of \"Menu\" = 4 x protein + 9 x fat + 4 x carb + 7 x alcohol",
+                    "type": "number",
+                    "example": 125
+                },
+                "like": {
+                    "description": "Amount of using as favorite menu by \"User Id\"",
+                    "type": "integer",
+                    "example": 1
+                },
+                "macro_split": {
+                    "description": "Percentage of energy from each macro nutrient",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.MacroSplit"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "Name of \"Menu\" that named by the user",
+                    "type": "string",
+                    "example": "Moo Yang"
+                },
+                "nutrients": {
+                    "description": "Known extended nutrients per serving size, an unknown nutrient is omitted",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "parent_menu_id": {
+                    "description": "\"Menu\"'s id of the previous version, null = the first version",
+                    "type": "integer",
+                    "example": 4
+                },
+                "protein": {
+                    "description": "Protein of \"Menu\"",
+                    "type": "number",
+                    "example": 20
+                },
+                "revision": {
+                    "description": "Revision of the \"Menu\" that is sent as \"If-Match\" e.g. \"1\" to update or delete it",
+                    "type": "integer",
+                    "example": 1
+                },
+                "serving_size": {
+                    "description": "Amount of one serving that protein, fat and carb are measured for",
+                    "type": "number",
+                    "example": 1
+                },
+                "serving_unit": {
+                    "description": "Unit of the serving size \"serving\", \"g\" or \"ml\"",
+                    "type": "string",
+                    "example": "serving"
+                },
+                "status": {
+                    "description": "1 = Active, 0 = Deleted",
+                    "type": "integer",
+                    "example": 1
+                },
+                "version": {
+                    "description": "Version of the \"Menu\" in its lineage, 1 = the first version",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "service.NewFavListRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "user_id"
+            ],
+            "properties": {
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity, it is used instead of \"list\" when it is not empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Item"
+                    }
+                },
+                "list": {
+                    "description": "Summary meal with \"Menu\"'s id  e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Favorite List\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea *required when \"items\" is empty",
+                    "type": "string",
+                    "example": "9,9,10"
+                },
+                "name": {
+                    "description": "The name of this \"Favorite List\"",
+                    "type": "string",
+                    "example": "Daily Breakfast"
+                },
+                "user_id": {
+                    "description": "The \"User Id\" that create this \"Favorite List\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                }
+            }
+        },
+        "service.NewMenuRequest": {
+            "type": "object",
+            "required": [
+                "creator_id",
+                "name"
+            ],
+            "properties": {
+                "alcohol": {
+                    "description": "Alcohol (g.) of this \"Menu\"",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "carb": {
+                    "description": "Carb (g.) of this \"Menu\"",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "creator_id": {
+                    "description": "\"User Id\" that create this \"Menu\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                },
+                "fat": {
+                    "description": "Fat (g.) of this \"Menu\"",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0.5
+                },
+                "name": {
+                    "description": "Name of this \"Menu\"",
+                    "type": "string",
+                    "example": "7-11 Pepper Chicken Breast"
+                },
+                "nutrients": {
+                    "description": "Extended nutrients per serving size e.g. {\"fiber\": 2.5, \"sodium\": 450}, a missing or null nutrient is unknown *keys: fiber, sugar, saturated_fat (g.), cholesterol, sodium, potassium, calcium, iron, vitamin_c (mg.), vitamin_a, vitamin_d (mcg.)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "protein": {
+                    "description": "Protein (g.) of this \"Menu\"",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 19
+                },
+                "serving_size": {
+                    "description": "Amount of one serving that protein, fat and carb are measured for e.g. 100 (g.), default = 1",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "serving_unit": {
+                    "description": "Unit of the serving size \"serving\" (default), \"g\" or \"ml\"",
+                    "type": "string",
+                    "example": "g"
+                }
+            }
+        },
+        "service.NewRecordRequest": {
+            "type": "object",
+            "required": [
+                "event_timestamp",
+                "user_id"
+            ],
+            "properties": {
+                "event_timestamp": {
+                    "description": "Wall-clock timestamp in your time zone that you eat, it is kept as it is and the days of the summary are counted on it *format=\"2023-01-01 00:00:00\"",
+                    "type": "string",
+                    "example": "2023-11-01 09:30:00"
+                },
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity, it is used instead of \"list\" when it is not empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Item"
+                    }
+                },
+                "list": {
+                    "description": "Summary meal with \"Menu\"'s id e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Record\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea *required when \"items\" is empty",
+                    "type": "string",
+                    "example": "9,9,10"
+                },
+                "note": {
+                    "description": "Note for this \"Record\"",
+                    "type": "string",
+                    "example": "Breakfast"
+                },
+                "user_id": {
+                    "description": "\"User Id\" that create this \"Record\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                },
+                "weight": {
+                    "description": "Weight (kg.) that you are on that day",
+                    "type": "number",
+                    "maximum": 500,
+                    "minimum": 20,
+                    "example": 63
+                }
+            }
+        },
+        "service.NewUserRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "user_id",
+                "username"
+            ],
+            "properties": {
+                "activity_level": {
+                    "description": "\"sedentary\", \"light\", \"moderate\", \"active\" or \"very_active\"",
+                    "type": "string",
+                    "example": "moderate"
+                },
+                "auto_update_menues": {
+                    "description": "\"true\" = favorite menues and favorite lists follow the newest version of an updated \"Menu\"",
+                    "type": "boolean",
+                    "example": true
+                },
+                "birth_date": {
+                    "description": "Birth date of the \"User\" *format=\"2023-01-01\"",
+                    "type": "string",
+                    "example": "1993-04-20"
+                },
+                "carb": {
+                    "description": "Default carb (g.) of the \"User\"",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 120
+                },
+                "fat": {
+                    "description": "Default fat (g.) of the \"User\"",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 60
+                },
+                "goal": {
+                    "description": "\"cut\", \"maintain\" or \"bulk\"",
+                    "type": "string",
+                    "example": "cut"
+                },
+                "height": {
+                    "description": "Height (cm.) of the \"User\"",
+                    "type": "number",
+                    "example": 175
+                },
+                "password": {
+                    "description": "\"Password\"",
+                    "type": "string",
+                    "example": "zxc123zxc123"
+                },
+                "protein": {
+                    "description": "Default protein (g.) of the \"User\"",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 120
+                },
+                "sex": {
+                    "description": "\"male\" or \"female\"",
+                    "type": "string",
+                    "example": "male"
+                },
+                "timezone": {
+                    "description": "IANA time zone that the days of the \"User\" are counted in, default = \"UTC\"",
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
+                "user_id": {
+                    "description": "\"User Id\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                },
+                "username": {
+                    "description": "\"Username\"",
+                    "type": "string",
+                    "example": "GoodDy"
+                },
+                "weight": {
+                    "description": "Default weight (kg.) of the \"User\"",
+                    "type": "number",
+                    "maximum": 500,
+                    "minimum": 20,
+                    "example": 70
+                }
+            }
+        },
+        "service.NewWeightLogRequest": {
+            "type": "object",
+            "required": [
+                "user_id",
+                "weight"
+            ],
+            "properties": {
+                "body_fat": {
+                    "description": "Body fat (%), null when it is not measured",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 18.5
+                },
+                "logged_timestamp": {
+                    "description": "Wall-clock timestamp in your time zone that you weigh, default = now in your time zone *format=\"2023-01-01 00:00:00\"",
+                    "type": "string",
+                    "example": "2023-12-05 07:00:00"
+                },
+                "user_id": {
+                    "description": "\"User Id\" that weigh",
+                    "type": "string",
+                    "example": "gooddy20"
+                },
+                "weight": {
+                    "description": "Body weight (kg.)",
+                    "type": "number",
+                    "maximum": 500,
+                    "minimum": 20,
+                    "example": 70.5
+                }
+            }
+        },
+        "service.NutritionTotal": {
+            "type": "object",
+            "properties": {
+                "alcohol": {
+                    "description": "Alcohol (g.)",
+                    "type": "number",
+                    "example": 0
+                },
                 "carb": {
-                    "description": "Total carb (g.) in the \"Favorite List\"",
+                    "description": "Carb (g.)",
                     "type": "number",
-                    "example": 20
+                    "example": 110
                 },
                 "fat": {
-                    "description": "Total fat (g.) in the \"Favorite List\"",
+                    "description": "Fat (g.)",
                     "type": "number",
-                    "example": 10
+                    "example": 45
+                },
+                "kcal": {
+                    "description": "Energy (kcal) = 4 x protein + 9 x fat + 4 x carb + 7 x alcohol",
+                    "type": "number",
+                    "example": 1205
+                },
+                "protein": {
+                    "description": "Protein (g.)",
+                    "type": "number",
+                    "example": 90
+                }
+            }
+        },
+        "service.RecordPageResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "Cursor of the next page, empty when it is the last page",
+                    "type": "string",
+                    "example": "ZGVzYzoxNzAxNzcy..."
+                },
+                "records": {
+                    "description": "\"Record\" in the page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.RecordResponse"
+                    }
+                }
+            }
+        },
+        "service.RecordResponse": {
+            "type": "object",
+            "properties": {
+                "alcohol": {
+                    "description": "Total alcohol (g.) of the \"Record\"",
+                    "type": "number"
+                },
+                "carb": {
+                    "description": "Total carb (g.) of the \"Record\"",
+                    "type": "number"
+                },
+                "eventTimestamp": {
+                    "description": "Timestamp that you eat *format=\"2023-01-01 00:00:00\"",
+                    "type": "string"
+                },
+                "fat": {
+                    "description": "Total fat (g.) of the \"Record\"",
+                    "type": "number"
                 },
                 "id": {
-                    "description": "\"Favorite List\"'s id that generate by system",
-                    "type": "integer",
-                    "example": 1
+                    "description": "\"Record\"'s id",
+                    "type": "integer"
                 },
-                "is_updated": {
-                    "description": "1 = All \"Menu\" in the \"Favorite List\" are up to date, 0 = atleast one \"Menu\" in the \"Favorite List\" are not up to date",
-                    "type": "integer",
-                    "example": 1
+                "isUpdated": {
+                    "description": "1 = All \"Menu\" in the \"Record\" are up to date, 0 = atleast one \"Menu\" in the \"Record\" are not up to date",
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Item"
+                    }
+                },
+                "kcal": {
+                    "description": "Total energy (kcal) of the \"Record\"",
+                    "type": "number"
                 },
                 "list": {
-                    "description": "Summary meal with \"Menu\"'s id e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Favorite List\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea",
-                    "type": "string",
-                    "example": "9,9,10"
+                    "description": "Summary meal with \"Menu\"'s id e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Record\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea",
+                    "type": "string"
+                },
+                "macroSplit": {
+                    "description": "Percentage of energy from each macro nutrient of the \"Record\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.MacroSplit"
+                        }
+                    ]
                 },
                 "menues": {
-                    "description": "Summary each \"Menu\"'s name and amount of the \"Favorite List\"",
-                    "type": "string",
-                    "example": "Moo Yang-2, Sticky Rice-1 "
+                    "type": "string"
                 },
-                "name": {
-                    "description": "Name of \"Favorite List\" that named by the user",
-                    "type": "string",
-                    "example": "Daily Breakfast"
+                "note": {
+                    "description": "Note for the \"Record\"",
+                    "type": "string"
+                },
+                "nutrients": {
+                    "description": "Total known extended nutrients of the \"Record\", a \"Menu\" without the nutrient does not add to it",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
                 },
                 "protein": {
-                    "description": "Total protein (g.) in the \"Favorite List\"",
-                    "type": "number",
-                    "example": 40
+                    "description": "Total protein (g.) of the \"Record\"",
+                    "type": "number"
+                },
+                "revision": {
+                    "description": "Revision of the \"Record\" that is sent as \"If-Match\" e.g. \"3\" to update or delete it",
+                    "type": "integer"
+                },
+                "weight": {
+                    "description": "Weight (kg.) that you are on that day",
+                    "type": "number"
                 }
             }
         },
-        "service.LogInRequest": {
+        "service.ReportResponse": {
             "type": "object",
-            "required": [
-                "password",
-                "user_id"
-            ],
             "properties": {
-                "password": {
-                    "description": "\"Password\"",
+                "adherence": {
+                    "description": "Percentage of the logged days that are within ±10% of the target",
+                    "type": "number",
+                    "example": 66.7
+                },
+                "adherent_days": {
+                    "description": "Amount of logged days that are within ±10% of the target",
+                    "type": "integer",
+                    "example": 4
+                },
+                "average": {
+                    "description": "Average total of the logged days",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "days": {
+                    "description": "Total nutrition of each day from \"from\" to \"to\"",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.DailyTotal"
+                    }
+                },
+                "from": {
+                    "description": "First day of the report *format=\"2023-01-01\"",
                     "type": "string",
-                    "example": "zxc123zxc123"
+                    "example": "2023-12-04"
+                },
+                "logged_days": {
+                    "description": "Amount of days with atleast one \"Record\"",
+                    "type": "integer",
+                    "example": 6
+                },
+                "max": {
+                    "description": "Maximum total of the logged days",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "min": {
+                    "description": "Minimum total of the logged days",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "std_dev": {
+                    "description": "Population standard deviation of the totals of the logged days",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "target": {
+                    "description": "Default protein, fat and carb of the \"User\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "timezone": {
+                    "description": "Time zone that the days are counted in",
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
+                "to": {
+                    "description": "Last day of the report *format=\"2023-01-01\"",
+                    "type": "string",
+                    "example": "2023-12-10"
                 },
                 "user_id": {
                     "description": "\"User Id\"",
@@ -661,215 +3750,339 @@ const docTemplate = `{
                 }
             }
         },
-        "service.LogInResponse": {
+        "service.SyncChangesResponse": {
             "type": "object",
             "properties": {
-                "IsLogIn": {
-                    "description": "\"true\" = Pass, \"false\" = Incorrect \"User Id\" or \"Password\"",
-                    "type": "boolean",
-                    "example": true
+                "favorite_lists": {
+                    "description": "\"Favorite List\" that is created, updated or deleted since the cursor",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.SyncFavListResponse"
+                    }
+                },
+                "favorite_menues": {
+                    "description": "Every \"Menu\"'s id of the \"Favorite Menu\", null = not changed since the cursor",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        9,
+                        10
+                    ]
+                },
+                "menues": {
+                    "description": "\"Menu\" that is changed since the cursor or that the changes use, \"status\" = 0 is a deleted \"Menu\"",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.MenuResponse"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Cursor that is sent as \"since\" to get the changes after these ones",
+                    "type": "string",
+                    "example": "c3luYzo3NDg1Nw"
+                },
+                "records": {
+                    "description": "\"Record\" that is created, updated or deleted since the cursor",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.SyncRecordResponse"
+                    }
                 }
             }
         },
-        "service.MenuResponse": {
+        "service.SyncFavListData": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "carb": {
-                    "description": "Carb of \"Menu\"",
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity, it is used instead of \"list\" when it is not empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Item"
+                    }
+                },
+                "list": {
+                    "description": "Summary meal with \"Menu\"'s id e.g. \"9,9,10\" *required when \"items\" is empty",
+                    "type": "string",
+                    "example": "9,9,10"
+                },
+                "name": {
+                    "description": "Name of the \"Favorite List\"",
+                    "type": "string",
+                    "example": "Daily Breakfast"
+                }
+            }
+        },
+        "service.SyncFavListResponse": {
+            "type": "object",
+            "properties": {
+                "alcohol": {
+                    "description": "Total alcohol (g.) in the \"Favorite List\"",
                     "type": "number",
                     "example": 0
                 },
-                "creator_id": {
-                    "description": "\"User Id\" that create the \"Menu\"",
-                    "type": "string",
-                    "example": "gooddy20"
+                "carb": {
+                    "description": "Total carb (g.) in the \"Favorite List\"",
+                    "type": "number",
+                    "example": 20
                 },
-                "creator_name": {
-                    "description": "\"Username\" that create the \"Menu\"",
+                "client_id": {
+                    "description": "Id that the client generated for a \"Favorite List\" that is created offline",
                     "type": "string",
-                    "example": "GoodDy"
+                    "example": "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"
+                },
+                "deleted": {
+                    "description": "true = the \"Favorite List\" is deleted and the client should delete it too",
+                    "type": "boolean",
+                    "example": false
                 },
                 "fat": {
-                    "description": "Fat of \"Menu\"",
+                    "description": "Total fat (g.) in the \"Favorite List\"",
                     "type": "number",
-                    "example": 5
+                    "example": 10
                 },
                 "id": {
-                    "description": "\"Menu\"'s id that generate by system",
+                    "description": "\"Favorite List\"'s id that generate by system",
                     "type": "integer",
-                    "example": 9
+                    "example": 1
                 },
-                "like": {
-                    "description": "Amount of using as favorite menu by \"User Id\"",
+                "is_updated": {
+                    "description": "1 = All \"Menu\" in the \"Favorite List\" are up to date, 0 = atleast one \"Menu\" in the \"Favorite List\" are not up to date",
                     "type": "integer",
                     "example": 1
                 },
-                "name": {
-                    "description": "Name of \"Menu\" that named by the user",
-                    "type": "string",
-                    "example": "Moo Yang"
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Item"
+                    }
                 },
-                "protein": {
-                    "description": "Protein of \"Menu\"",
+                "kcal": {
+                    "description": "Total energy (kcal) in the \"Favorite List\"",
                     "type": "number",
-                    "example": 20
+                    "example": 330
                 },
-                "status": {
-                    "description": "1 = Active, 0 = Deleted",
-                    "type": "integer",
-                    "example": 1
-                }
-            }
-        },
-        "service.NewFavListRequest": {
-            "type": "object",
-            "required": [
-                "list",
-                "name",
-                "user_id"
-            ],
-            "properties": {
                 "list": {
-                    "description": "Summary meal with \"Menu\"'s id  e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Favorite List\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea",
+                    "description": "Summary meal with \"Menu\"'s id e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Favorite List\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea",
                     "type": "string",
                     "example": "9,9,10"
                 },
+                "macro_split": {
+                    "description": "Percentage of energy from each macro nutrient in the \"Favorite List\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.MacroSplit"
+                        }
+                    ]
+                },
+                "menues": {
+                    "description": "Summary each \"Menu\"'s name and amount of the \"Favorite List\"",
+                    "type": "string",
+                    "example": "Moo Yang-2, Sticky Rice-1 "
+                },
                 "name": {
-                    "description": "The name of this \"Favorite List\"",
+                    "description": "Name of \"Favorite List\" that named by the user",
                     "type": "string",
                     "example": "Daily Breakfast"
                 },
-                "user_id": {
-                    "description": "The \"User Id\" that create this \"Favorite List\"",
-                    "type": "string",
-                    "example": "gooddy20"
+                "nutrients": {
+                    "description": "Total known extended nutrients in the \"Favorite List\", a \"Menu\" without the nutrient does not add to it",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "protein": {
+                    "description": "Total protein (g.) in the \"Favorite List\"",
+                    "type": "number",
+                    "example": 40
+                },
+                "revision": {
+                    "description": "Revision of the \"Favorite List\" that is sent as \"If-Match\" e.g. \"3\" to update or delete it",
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "service.NewMenuRequest": {
+        "service.SyncMutation": {
             "type": "object",
             "required": [
-                "carb",
-                "creator_id",
-                "fat",
-                "name",
-                "protein"
+                "entity",
+                "op"
             ],
             "properties": {
-                "carb": {
-                    "description": "Carb (g.) of this \"Menu\"",
-                    "type": "number",
+                "base_revision": {
+                    "description": "Revision of the row that the change is based on, 0 = the client created the row *required when \"id\" is set",
+                    "type": "integer",
+                    "minimum": 0,
                     "example": 0
                 },
-                "creator_id": {
-                    "description": "\"User Id\" that create this \"Menu\"",
+                "client_id": {
+                    "description": "UUID that the client generated for the row that it created offline *required when \"id\" is 0",
                     "type": "string",
-                    "example": "gooddy20"
-                },
-                "fat": {
-                    "description": "Fat (g.) of this \"Menu\"",
-                    "type": "number",
-                    "example": 0.5
+                    "example": "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"
                 },
-                "name": {
-                    "description": "Name of this \"Menu\"",
+                "entity": {
+                    "description": "\"record\" or \"favlist\"",
                     "type": "string",
-                    "example": "7-11 Pepper Chicken Breast"
+                    "enum": [
+                        "record",
+                        "favlist"
+                    ],
+                    "example": "record"
                 },
-                "protein": {
-                    "description": "Protein (g.) of this \"Menu\"",
-                    "type": "number",
-                    "example": 19
+                "favorite_list": {
+                    "description": "The whole \"Favorite List\" *required for \"upsert\" of a \"favlist\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.SyncFavListData"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "Server's id of the row, 0 = the row that the client created with \"client_id\"",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "op": {
+                    "description": "\"upsert\" = create or replace the row, \"delete\" = delete the row",
+                    "type": "string",
+                    "enum": [
+                        "upsert",
+                        "delete"
+                    ],
+                    "example": "upsert"
+                },
+                "record": {
+                    "description": "The whole \"Record\" *required for \"upsert\" of a \"record\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.SyncRecordData"
+                        }
+                    ]
                 }
             }
         },
-        "service.NewRecordRequest": {
+        "service.SyncMutationResult": {
             "type": "object",
-            "required": [
-                "event_timestamp",
-                "list",
-                "user_id"
-            ],
             "properties": {
-                "event_timestamp": {
-                    "description": "Timestamp that you eat *format=\"2023-01-01 00:00:00\"",
+                "client_id": {
+                    "description": "\"client_id\" of the mutation",
                     "type": "string",
-                    "example": "2023-11-01 09:30:00"
+                    "example": "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"
                 },
-                "list": {
-                    "description": "Summary meal with \"Menu\"'s id e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Record\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea",
+                "entity": {
+                    "description": "\"Entity\" of the mutation",
                     "type": "string",
-                    "example": "9,9,10"
+                    "example": "record"
                 },
-                "note": {
-                    "description": "Note for this \"Record\"",
+                "error_code": {
+                    "description": "Stable code of the error of a \"conflict\" or \"rejected\" mutation",
                     "type": "string",
-                    "example": "Breakfast"
+                    "example": "VALIDATION_FAILED"
                 },
-                "user_id": {
-                    "description": "\"User Id\" that create this \"Record\"",
+                "favorite_list": {
+                    "description": "The server's \"Favorite List\" that wins the conflict",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.SyncFavListResponse"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "Server's id of the row, 0 = the row does not exist",
+                    "type": "integer",
+                    "example": 12
+                },
+                "message": {
+                    "description": "Error of a \"conflict\" or \"rejected\" mutation",
                     "type": "string",
-                    "example": "gooddy20"
+                    "example": "Menu Id - 9 is deleted"
                 },
-                "weight": {
-                    "description": "Weight (kg.) that you are on that day",
-                    "type": "number",
-                    "example": 63
+                "record": {
+                    "description": "The server's \"Record\" that wins the conflict",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.SyncRecordResponse"
+                        }
+                    ]
+                },
+                "revision": {
+                    "description": "Revision of the row on the server after the mutation",
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "description": "\"applied\", \"conflict\" or \"rejected\"",
+                    "type": "string",
+                    "example": "applied"
                 }
             }
         },
-        "service.NewUserRequest": {
+        "service.SyncRecordData": {
             "type": "object",
             "required": [
-                "password",
-                "user_id",
-                "username"
+                "event_timestamp"
             ],
             "properties": {
-                "carb": {
-                    "description": "Default carb (g.) of the \"User\"",
-                    "type": "number",
-                    "example": 120
-                },
-                "fat": {
-                    "description": "Default fat (g.) of the \"User\"",
-                    "type": "number",
-                    "example": 60
-                },
-                "password": {
-                    "description": "\"Password\"",
+                "event_timestamp": {
+                    "description": "Wall-clock timestamp in your time zone that you eat, it is kept as it is and the days of the summary are counted on it *format=\"2023-01-01 00:00:00\"",
                     "type": "string",
-                    "example": "zxc123zxc123"
+                    "example": "2023-11-01 09:30:00"
                 },
-                "protein": {
-                    "description": "Default protein (g.) of the \"User\"",
-                    "type": "number",
-                    "example": 120
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity, it is used instead of \"list\" when it is not empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Item"
+                    }
                 },
-                "user_id": {
-                    "description": "\"User Id\"",
+                "list": {
+                    "description": "Summary meal with \"Menu\"'s id e.g. \"9,9,10\" *required when \"items\" is empty",
                     "type": "string",
-                    "example": "gooddy20"
+                    "example": "9,9,10"
                 },
-                "username": {
-                    "description": "\"Username\"",
+                "note": {
+                    "description": "Note for the \"Record\"",
                     "type": "string",
-                    "example": "GoodDy"
+                    "example": "Breakfast"
                 },
                 "weight": {
-                    "description": "Default weight (kg.) of the \"User\"",
+                    "description": "Weight (kg.) that you are on that day",
                     "type": "number",
-                    "example": 70
+                    "maximum": 500,
+                    "minimum": 20,
+                    "example": 63
                 }
             }
         },
-        "service.RecordResponse": {
+        "service.SyncRecordResponse": {
             "type": "object",
             "properties": {
+                "alcohol": {
+                    "description": "Total alcohol (g.) of the \"Record\"",
+                    "type": "number"
+                },
                 "carb": {
                     "description": "Total carb (g.) of the \"Record\"",
                     "type": "number"
                 },
+                "client_id": {
+                    "description": "Id that the client generated for a \"Record\" that is created offline",
+                    "type": "string",
+                    "example": "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"
+                },
+                "deleted": {
+                    "description": "true = the \"Record\" is deleted and the client should delete it too",
+                    "type": "boolean",
+                    "example": false
+                },
                 "eventTimestamp": {
                     "description": "Timestamp that you eat *format=\"2023-01-01 00:00:00\"",
                     "type": "string"
@@ -886,10 +4099,29 @@ const docTemplate = `{
                     "description": "1 = All \"Menu\" in the \"Record\" are up to date, 0 = atleast one \"Menu\" in the \"Record\" are not up to date",
                     "type": "integer"
                 },
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.Item"
+                    }
+                },
+                "kcal": {
+                    "description": "Total energy (kcal) of the \"Record\"",
+                    "type": "number"
+                },
                 "list": {
                     "description": "Summary meal with \"Menu\"'s id e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Record\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea",
                     "type": "string"
                 },
+                "macroSplit": {
+                    "description": "Percentage of energy from each macro nutrient of the \"Record\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.MacroSplit"
+                        }
+                    ]
+                },
                 "menues": {
                     "type": "string"
                 },
@@ -897,16 +4129,112 @@ const docTemplate = `{
                     "description": "Note for the \"Record\"",
                     "type": "string"
                 },
+                "nutrients": {
+                    "description": "Total known extended nutrients of the \"Record\", a \"Menu\" without the nutrient does not add to it",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
                 "protein": {
                     "description": "Total protein (g.) of the \"Record\"",
                     "type": "number"
                 },
+                "revision": {
+                    "description": "Revision of the \"Record\" that is sent as \"If-Match\" e.g. \"3\" to update or delete it",
+                    "type": "integer"
+                },
                 "weight": {
                     "description": "Weight (kg.) that you are on that day",
                     "type": "number"
                 }
             }
         },
+        "service.SyncRequest": {
+            "type": "object",
+            "required": [
+                "mutations"
+            ],
+            "properties": {
+                "mutations": {
+                    "description": "Changes that are applied in order, 1 - 100 and a row can be changed once in a batch",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.SyncMutation"
+                    }
+                }
+            }
+        },
+        "service.SyncResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "description": "Result of every mutation in the same order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.SyncMutationResult"
+                    }
+                }
+            }
+        },
+        "service.TargetResponse": {
+            "type": "object",
+            "properties": {
+                "activity_level": {
+                    "description": "Activity level of the \"User\"",
+                    "type": "string",
+                    "example": "moderate"
+                },
+                "age": {
+                    "description": "Age (years) of the \"User\" today",
+                    "type": "integer",
+                    "example": 30
+                },
+                "bmr": {
+                    "description": "Basal metabolic rate (kcal/day) by the Mifflin-St Jeor equation",
+                    "type": "number",
+                    "example": 1648.8
+                },
+                "goal": {
+                    "description": "Goal of the \"User\"",
+                    "type": "string",
+                    "example": "cut"
+                },
+                "height": {
+                    "description": "Height (cm.) of the \"User\"",
+                    "type": "number",
+                    "example": 175
+                },
+                "sex": {
+                    "description": "Sex of the \"User\"",
+                    "type": "string",
+                    "example": "male"
+                },
+                "target": {
+                    "description": "Suggested daily protein, fat and carb (g.) and energy (kcal) of the goal",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.NutritionTotal"
+                        }
+                    ]
+                },
+                "tdee": {
+                    "description": "Total daily energy expenditure (kcal/day) = BMR x the factor of the activity level",
+                    "type": "number",
+                    "example": 2555.6
+                },
+                "user_id": {
+                    "description": "\"User Id\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                },
+                "weight": {
+                    "description": "Weight (kg.) of the \"User\"",
+                    "type": "number",
+                    "example": 70
+                }
+            }
+        },
         "service.UpdateFavListRequest": {
             "type": "object",
             "required": [
@@ -918,13 +4246,20 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity (\"menu_id\", \"quantity\" and \"unit\" like \"Item\") that you want to change to, it is used instead of \"list\" when it is set and it can not be cleared",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
                 "list": {
-                    "description": "Summary meal with \"Menu\"'s id that you want to change e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Favorite List\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea",
+                    "description": "Summary meal with \"Menu\"'s id that you want to change e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Favorite List\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea, it can not be cleared",
                     "type": "string",
                     "example": "9,10"
                 },
                 "name": {
-                    "description": "The name that you want to change to",
+                    "description": "The name that you want to change to, null = clear",
                     "type": "string",
                     "example": "Daily Breakfast"
                 }
@@ -933,21 +4268,25 @@ const docTemplate = `{
         "service.UpdateMenuRequest": {
             "type": "object",
             "required": [
-                "carb",
-                "fat",
-                "id",
-                "name",
-                "protein"
+                "id"
             ],
             "properties": {
+                "alcohol": {
+                    "description": "The alcohol (g.) that you want to change to, null = 0",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0
+                },
                 "carb": {
-                    "description": "The carb (g.) that you want to change to",
+                    "description": "The carb (g.) that you want to change to, null = 0",
                     "type": "number",
+                    "minimum": 0,
                     "example": 1
                 },
                 "fat": {
-                    "description": "The fat (g.) that you want to change to",
+                    "description": "The fat (g.) that you want to change to, null = 0",
                     "type": "number",
+                    "minimum": 0,
                     "example": 0.5
                 },
                 "id": {
@@ -956,14 +4295,33 @@ const docTemplate = `{
                     "example": 1
                 },
                 "name": {
-                    "description": "The name that you want to change to",
+                    "description": "The name that you want to change to, it can not be null",
                     "type": "string",
                     "example": "7-11 Chilli Chicken Breast"
                 },
+                "nutrients": {
+                    "description": "The extended nutrients that you want to change, a nutrient that is omitted is kept and a null one is removed, null = remove every nutrient",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
                 "protein": {
-                    "description": "The protein (g.) that you want to change to",
+                    "description": "The protein (g.) that you want to change to, null = 0",
                     "type": "number",
+                    "minimum": 0,
                     "example": 20
+                },
+                "serving_size": {
+                    "description": "The serving size that you want to change to, null = 1",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "serving_unit": {
+                    "description": "The unit of the serving size that you want to change to, null = \"serving\"",
+                    "type": "string",
+                    "example": "g"
                 }
             }
         },
@@ -974,7 +4332,7 @@ const docTemplate = `{
             ],
             "properties": {
                 "event_timestamp": {
-                    "description": "Timestamp that you want to change to *format=\"2023-01-01 00:00:00\"",
+                    "description": "Wall-clock timestamp in your time zone that you want to change to *format=\"2023-01-01 00:00:00\", it can not be null",
                     "type": "string",
                     "example": "2023-11-01 12:30:00"
                 },
@@ -983,19 +4341,28 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "items": {
+                    "description": "Summary meal with \"Menu\"'s id and quantity (\"menu_id\", \"quantity\" and \"unit\" like \"Item\") that you want to change to, it is used instead of \"list\" when it is set and it can not be cleared",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
                 "list": {
-                    "description": "Summary meal with \"Menu\"'s id that you want to change to e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Record\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea",
+                    "description": "Summary meal with \"Menu\"'s id that you want to change to e.g. \"9,9,10\" -\u003e 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so the \"Record\" contain \"Moo Yang\" 2 ea and \"Sticky Rice\" 1 ea, it can not be cleared",
                     "type": "string",
                     "example": "9,9,10"
                 },
                 "note": {
-                    "description": "Note that you want to change to",
+                    "description": "Note that you want to change to, null = clear",
                     "type": "string",
                     "example": "Lunch"
                 },
                 "weight": {
-                    "description": "Weight (kg.) that you want to change to",
+                    "description": "Weight (kg.) that you want to change to, null = clear",
                     "type": "number",
+                    "maximum": 500,
+                    "minimum": 20,
                     "example": 63
                 }
             }
@@ -1006,51 +4373,156 @@ const docTemplate = `{
                 "user_id"
             ],
             "properties": {
+                "activity_level": {
+                    "description": "\"sedentary\", \"light\", \"moderate\", \"active\" or \"very_active\" that you want to change to, null = clear",
+                    "type": "string",
+                    "example": "active"
+                },
+                "apply_suggested_targets": {
+                    "description": "\"true\" = replace protein, fat and carb with the targets suggested from the updated profile",
+                    "type": "boolean",
+                    "example": false
+                },
+                "auto_update_menues": {
+                    "description": "\"true\" = favorite menues and favorite lists follow the newest version of an updated \"Menu\", null = \"false\"",
+                    "type": "boolean",
+                    "example": true
+                },
+                "birth_date": {
+                    "description": "Birth date that you want to change to *format=\"2023-01-01\", null = clear",
+                    "type": "string",
+                    "example": "1993-04-20"
+                },
                 "carb": {
-                    "description": "Carb that you want to change to",
+                    "description": "Carb that you want to change to, null = clear",
                     "type": "number",
+                    "minimum": 0,
                     "example": 160
                 },
                 "fat": {
-                    "description": "Fat (g.) that you want to change to",
+                    "description": "Fat (g.) that you want to change to, null = clear",
                     "type": "number",
+                    "minimum": 0,
                     "example": 70
                 },
+                "favorite_menu_ids": {
+                    "description": "Favorite Menues's id that you want to change to, it is used instead of \"favorite_menues\" when it is set, null or [] = clear",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        4,
+                        7,
+                        9,
+                        10,
+                        11
+                    ]
+                },
                 "favorite_menues": {
-                    "description": "Favorite Menues's id that you want to change to e.g. \"9,10\" 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so this \"User\" got \"Moo Yang\" and \"Sticky Rice\" as \"Favorite Menu\"",
+                    "description": "Favorite Menues's id that you want to change to e.g. \"9,10\" 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so this \"User\" got \"Moo Yang\" and \"Sticky Rice\" as \"Favorite Menu\", null or \"\" = clear",
                     "type": "string",
                     "example": "4,7,9,10,11"
                 },
+                "goal": {
+                    "description": "\"cut\", \"maintain\" or \"bulk\" that you want to change to, null = clear",
+                    "type": "string",
+                    "example": "maintain"
+                },
+                "height": {
+                    "description": "Height (cm.) that you want to change to, null = clear",
+                    "type": "number",
+                    "example": 176
+                },
                 "password": {
-                    "description": "\"Password\" that you want to change",
+                    "description": "\"Password\" that you want to change, it can not be null",
                     "type": "string",
                     "example": "zxc123zxc456"
                 },
                 "protein": {
-                    "description": "Protein (g.) that you want to change to",
+                    "description": "Protein (g.) that you want to change to, null = clear",
                     "type": "number",
+                    "minimum": 0,
                     "example": 150
                 },
+                "sex": {
+                    "description": "\"male\" or \"female\" that you want to change to, null = clear",
+                    "type": "string",
+                    "example": "male"
+                },
+                "timezone": {
+                    "description": "IANA time zone that you want to change to, null = UTC",
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
                 "user_id": {
                     "description": "\"User Id\"",
                     "type": "string",
                     "example": "gooddy20"
                 },
                 "username": {
-                    "description": "\"Username\" that you want to change to",
+                    "description": "\"Username\" that you want to change to, it can not be null",
                     "type": "string",
                     "example": "GooDDy19"
                 },
                 "weight": {
-                    "description": "Weight (kg.) that you want to change to",
+                    "description": "Weight (kg.) that you want to change to, null = clear",
                     "type": "number",
+                    "maximum": 500,
+                    "minimum": 20,
                     "example": 72
                 }
             }
         },
+        "service.UpdateWeightLogRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "body_fat": {
+                    "description": "Body fat (%) that you want to change to, null = clear",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 18.2
+                },
+                "id": {
+                    "description": "\"Weight Log\"'s id that you want to update",
+                    "type": "integer",
+                    "example": 1
+                },
+                "logged_timestamp": {
+                    "description": "Wall-clock timestamp in your time zone that you want to change to *format=\"2023-01-01 00:00:00\", it can not be null",
+                    "type": "string",
+                    "example": "2023-12-05 07:30:00"
+                },
+                "weight": {
+                    "description": "Body weight (kg.) that you want to change to, it can not be null",
+                    "type": "number",
+                    "maximum": 500,
+                    "minimum": 20,
+                    "example": 70.2
+                }
+            }
+        },
         "service.UserResponse": {
             "type": "object",
             "properties": {
+                "activity_level": {
+                    "description": "Activity level of the \"User\", \"\" = not set",
+                    "type": "string",
+                    "example": "moderate"
+                },
+                "auto_update_menues": {
+                    "description": "\"true\" = favorite menues and favorite lists follow the newest version of an updated \"Menu\"",
+                    "type": "boolean",
+                    "example": true
+                },
+                "birth_date": {
+                    "description": "Birth date of the \"User\", \"\" = not set",
+                    "type": "string",
+                    "example": "1993-04-20"
+                },
                 "carb": {
                     "description": "Default carb (g.) of the \"User\"",
                     "type": "number",
@@ -1061,16 +4533,52 @@ const docTemplate = `{
                     "type": "number",
                     "example": 40
                 },
+                "favorite_menu_ids": {
+                    "description": "Favorite Menues's id",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        9,
+                        10
+                    ]
+                },
                 "favorite_menues": {
                     "description": "Favorite Menues's id e.g. \"9,10\" 9 = \"Moo Yang\" and 10 = \"Sticky Rice\" so this \"User\" got \"Moo Yang\" and \"Sticky Rice\" as \"Favorite Menu\"",
                     "type": "string",
                     "example": "9,10"
                 },
+                "goal": {
+                    "description": "Goal of the \"User\", \"\" = not set",
+                    "type": "string",
+                    "example": "cut"
+                },
+                "height": {
+                    "description": "Height (cm.) of the \"User\", 0 = not set",
+                    "type": "number",
+                    "example": 175
+                },
                 "protein": {
                     "description": "Default protein (g.) of the \"User\"",
                     "type": "number",
                     "example": 140
                 },
+                "revision": {
+                    "description": "Revision of the \"User\" that is also sent as the \"ETag\" header",
+                    "type": "integer",
+                    "example": 3
+                },
+                "sex": {
+                    "description": "\"male\" or \"female\", \"\" = not set",
+                    "type": "string",
+                    "example": "male"
+                },
+                "timezone": {
+                    "description": "IANA time zone that the days of the \"User\" are counted in",
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
                 "username": {
                     "description": "\"Username\"",
                     "type": "string",
@@ -1082,6 +4590,106 @@ const docTemplate = `{
                     "example": 62
                 }
             }
+        },
+        "service.WeightLogResponse": {
+            "type": "object",
+            "properties": {
+                "body_fat": {
+                    "description": "Body fat (%), null when it is not measured",
+                    "type": "number",
+                    "example": 18.5
+                },
+                "id": {
+                    "description": "\"Weight Log\"'s id",
+                    "type": "integer",
+                    "example": 1
+                },
+                "logged_timestamp": {
+                    "description": "Wall-clock timestamp in your time zone that you weigh, the \"Z\" does not mean UTC",
+                    "type": "string",
+                    "example": "2023-12-05T07:00:00Z"
+                },
+                "weight": {
+                    "description": "Body weight (kg.)",
+                    "type": "number",
+                    "example": 70.5
+                }
+            }
+        },
+        "service.WeightTrendPoint": {
+            "type": "object",
+            "properties": {
+                "body_fat": {
+                    "description": "Average body fat (%) of the day, null when it is not measured",
+                    "type": "number",
+                    "example": 18.5
+                },
+                "date": {
+                    "description": "Calendar day *format=\"2023-01-01\"",
+                    "type": "string",
+                    "example": "2023-12-05"
+                },
+                "trend": {
+                    "description": "7-day exponential moving average of the weight (kg.)",
+                    "type": "number",
+                    "example": 70.8
+                },
+                "weight": {
+                    "description": "Average weight (kg.) of the day",
+                    "type": "number",
+                    "example": 70.5
+                }
+            }
+        },
+        "service.WeightTrendResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "First day of the trend *format=\"2023-01-01\"",
+                    "type": "string",
+                    "example": "2023-09-07"
+                },
+                "points": {
+                    "description": "Each logged day from \"from\" to \"to\"",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.WeightTrendPoint"
+                    }
+                },
+                "timezone": {
+                    "description": "Time zone that the days are counted in",
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
+                "to": {
+                    "description": "Last day of the trend *format=\"2023-01-01\"",
+                    "type": "string",
+                    "example": "2023-12-05"
+                },
+                "trend": {
+                    "description": "Latest trend weight (kg.), null when nothing is logged",
+                    "type": "number",
+                    "example": 70.8
+                },
+                "user_id": {
+                    "description": "\"User Id\"",
+                    "type": "string",
+                    "example": "gooddy20"
+                },
+                "weekly_rate": {
+                    "description": "Change of the trend weight (kg.) per week, null when there is less than 2 logged days",
+                    "type": "number",
+                    "example": -0.4
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Access token from \"/user/login\" in the format \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -1,10 +1,114 @@
 package errs
 
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Stable codes of the errors, clients can rely on the code while the message is free to change
+const (
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeMalformedBody        = "MALFORMED_BODY"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodePermissionDenied     = "PERMISSION_DENIED"
+	CodeUserNotFound         = "USER_NOT_FOUND"
+	CodeMenuNotFound         = "MENU_NOT_FOUND"
+	CodeRecordNotFound       = "RECORD_NOT_FOUND"
+	CodeFavListNotFound      = "FAVORITE_LIST_NOT_FOUND"
+	CodeWeightLogNotFound    = "WEIGHT_LOG_NOT_FOUND"
+	CodeUserIdTaken          = "USER_ID_TAKEN"
+	CodeUsernameTaken        = "USERNAME_TAKEN"
+	CodeUserAlreadyExists    = "USER_ALREADY_EXISTS"
+	CodeMenuOutdated         = "MENU_OUTDATED"
+	CodeMenuNotDeleted       = "MENU_NOT_DELETED"
+	CodeProfileIncomplete    = "PROFILE_INCOMPLETE"
+	CodeNotEnoughData        = "NOT_ENOUGH_DATA"
+	CodeFavListEmpty         = "FAVORITE_LIST_EMPTY"
+	CodePreconditionFailed   = "PRECONDITION_FAILED"
+	CodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	CodeRequestInProgress    = "REQUEST_IN_PROGRESS"
+	CodeConstraintViolation  = "CONSTRAINT_VIOLATION"
+	CodeInternal             = "INTERNAL_ERROR"
+)
+
+// FieldError tells which field of the request is invalid and why
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 type AppError struct {
-	Code    int
-	Message string
+	Code      int          // HTTP status code
+	ErrorCode string       // Stable code from the catalog
+	Message   string       // Message for people
+	Details   []FieldError // Invalid fields of a validation error
 }
 
 func (e AppError) Error() string {
 	return e.Message
 }
+
+// Is compares every part of the error so errors.Is keeps working although the details make AppError not comparable
+func (e AppError) Is(target error) bool {
+	t, ok := target.(AppError)
+	return ok && e.Code == t.Code && e.ErrorCode == t.ErrorCode && e.Message == t.Message && slices.Equal(e.Details, t.Details)
+}
+
+// NewValidationError is a 400 for an invalid value of the field, an empty field is an error of the request as a whole
+func NewValidationError(field string, message string) AppError {
+	if field == "" {
+		return AppError{Code: http.StatusBadRequest, ErrorCode: CodeValidationFailed, Message: message}
+	}
+	return NewValidationErrors([]FieldError{{Field: field, Message: message}})
+}
+
+// NewValidationErrors is a 400 that lists every invalid field of the request at once
+func NewValidationErrors(details []FieldError) AppError {
+	messages := []string{}
+	for _, detail := range details {
+		messages = append(messages, detail.Message)
+	}
+	return AppError{Code: http.StatusBadRequest, ErrorCode: CodeValidationFailed, Message: strings.Join(messages, ", "), Details: details}
+}
+
+// NewMalformedBodyError is a 400 for a body that can not be decoded
+func NewMalformedBodyError() AppError {
+	return AppError{Code: http.StatusBadRequest, ErrorCode: CodeMalformedBody, Message: "Incorrect Request Body"}
+}
+
+// NewUnsupportedMediaTypeError is a 415 for a body that is not sent as "application/json"
+func NewUnsupportedMediaTypeError() AppError {
+	return AppError{Code: http.StatusUnsupportedMediaType, ErrorCode: CodeUnsupportedMediaType, Message: "Incorrect Request Header"}
+}
+
+func NewUnauthorizedError(message string) AppError {
+	return AppError{Code: http.StatusUnauthorized, ErrorCode: CodeUnauthorized, Message: message}
+}
+
+func NewPermissionDeniedError() AppError {
+	return AppError{Code: http.StatusForbidden, ErrorCode: CodePermissionDenied, Message: "Permission denied"}
+}
+
+func NewNotFoundError(errorCode string, message string) AppError {
+	return AppError{Code: http.StatusNotFound, ErrorCode: errorCode, Message: message}
+}
+
+func NewConflictError(errorCode string, message string) AppError {
+	return AppError{Code: http.StatusConflict, ErrorCode: errorCode, Message: message}
+}
+
+// NewPreconditionFailedError is a 412 for an "If-Match" that is not the current revision of the resource
+func NewPreconditionFailedError(message string) AppError {
+	return AppError{Code: http.StatusPreconditionFailed, ErrorCode: CodePreconditionFailed, Message: message}
+}
+
+// NewUnprocessableError is a 422 for a valid request that can not be done with the data that the "User" has now
+func NewUnprocessableError(errorCode string, message string) AppError {
+	return AppError{Code: http.StatusUnprocessableEntity, ErrorCode: errorCode, Message: message}
+}
+
+func NewUnexpectedError() AppError {
+	return AppError{Code: http.StatusInternalServerError, ErrorCode: CodeInternal, Message: "Unexpected error"}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Headers of the conditional requests, the ETag of a "User", "Record", "Favorite List" or "Menu" is its revision e.g. "3"
+// and the ETag of a list of them is the hash of the body
+const (
+	ETagHeader        = "ETag"
+	IfMatchHeader     = "If-Match"
+	IfNoneMatchHeader = "If-None-Match"
+)
+
+func revisionETag(revision int) string {
+	return `"` + strconv.Itoa(revision) + `"`
+}
+
+// ifMatchRevision reads the revision that the change is based on from the "If-Match" header,
+// 0 = no header or "*" so the change is made to whatever the current revision is and a header that is not an ETag is an invalid request
+func ifMatchRevision(r *http.Request) (int, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get(IfMatchHeader))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+	tag, ok := strings.CutPrefix(ifMatch, `"`)
+	tag, isQuoted := strings.CutSuffix(tag, `"`)
+	revision, err := strconv.Atoi(tag)
+	if !ok || !isQuoted || err != nil || revision <= 0 {
+		return 0, errs.NewValidationError(IfMatchHeader, `If-Match need to be the ETag of the resource e.g. "3"`)
+	}
+	return revision, nil
+}
+
+// writeJSON writes the response with its ETag (the hash of the body when the ETag is empty),
+// or only 304 when the client already has it as one of the ETags in the "If-None-Match" header
+func writeJSON(w http.ResponseWriter, r *http.Request, etag string, response any) {
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(response)
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	if etag == "" {
+		sum := sha256.Sum256(body.Bytes())
+		etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	}
+	matches, err := etagMatches(r.Header.Get(IfNoneMatchHeader), etag)
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	w.Header().Set(ETagHeader, etag)
+	if matches {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	w.Write(body.Bytes())
+}
+
+// etagMatches compares the ETags of an "If-None-Match" header with the weak comparison, "*" matches any ETag
+// and a header that is not a list of quoted ETags is an invalid request
+func etagMatches(header string, etag string) (bool, error) {
+	if strings.TrimSpace(header) == "" {
+		return false, nil
+	}
+	matches := false
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			matches = true
+			continue
+		}
+		opaqueTag := strings.TrimPrefix(tag, "W/")
+		if len(opaqueTag) < 2 || !strings.HasPrefix(opaqueTag, `"`) || !strings.HasSuffix(opaqueTag, `"`) || strings.Contains(opaqueTag[1:len(opaqueTag)-1], `"`) {
+			return false, errs.NewValidationError(IfNoneMatchHeader, `If-None-Match need to be the ETags separated by comma e.g. "3", "4"`)
+		}
+		if opaqueTag == etag {
+			matches = true
+		}
+	}
+	return matches, nil
+}
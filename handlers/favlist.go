@@ -22,77 +22,111 @@ func NewFavListHandler(favListSrv service.FavListService) favListHandler {
 // @Summary Create a "Favorite List"
 // @Description Create a `Favorite List` for recording the daily meal easily
 // @Tags Favorite List
+// @Security BearerAuth
 // @Accept json
+// @Produce json
 // @Param request body service.NewFavListRequest true "`Favorite List`'s data detail"
-// @Response 200
-// @Response 406 "Request Body Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Param Idempotency-Key header string false "Key that the client generated for the request e.g. a UUID, a retry with the same key gets the first response back instead of creating it again"
+// @Response 200 {object} service.CreatedResponse "Id and revision of the created `Favorite List`"
+// @Header 200 {string} ETag "Revision of the `Favorite List` in quotes"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 409 {object} ErrorResponse "The request with the `Idempotency-Key` is still in progress"
+// @Response 422 {object} ErrorResponse "`Idempotency-Key` is already used by another request"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /favlist/ [post]
 func (h favListHandler) CreateFavList(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request service.NewFavListRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
 		return
 	}
-	err = h.favListSrv.CreateFavList(request)
+	request.UserId = userIdFromContext(r.Context())
+	response, err := h.favListSrv.CreateFavList(r.Context(), request)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
+	writeJSON(w, r, revisionETag(response.Revision), response)
 }
 
 // DeleteFavList ... Delete a "Favorite List"
 // @Summary Delete a "Favorite List"
 // @Description Delete a `Favorite List`
 // @Tags Favorite List
+// @Security BearerAuth
 // @Param favlist_id path int true "`Favorite List`'s id that you want to delete"
+// @Param If-Match header string false "Quoted `revision` of the `Favorite List` that the change is based on, no header or `*` = any revision"
 // @Response 200
-// @Response 406 "Request Parameter Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request Parameter Not Acceptable or `If-Match` is not an ETag"
+// @Response 404 {object} ErrorResponse "`Favorite List`'s id is not found"
+// @Response 412 {object} ErrorResponse "`Favorite List` has been changed since the ETag in `If-Match`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /favlist/{favlist_id} [delete]
 func (h favListHandler) DeleteFavList(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	favListId, err := strconv.ParseInt(vars["favlist_id"], 0, 0)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Parse data type error"})
+		handlerError(w, r, errs.NewValidationError("favlist_id", "Parse data type error"))
+		return
+	}
+	revision, err := ifMatchRevision(r)
+	if err != nil {
+		handlerError(w, r, err)
 		return
 	}
-	err = h.favListSrv.DeleteFavList(int(favListId))
+	err = h.favListSrv.DeleteFavList(r.Context(), userIdFromContext(r.Context()), int(favListId), revision)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
 }
 
 // UpdateFavList ... Update a "Favorite List"
 // @Summary Update a "Favorite List"
-// @Description Update a `Favorite List`
+// @Description Update a `Favorite List`, a field that is omitted is kept and a field that is null is cleared
 // @Tags Favorite List
+// @Security BearerAuth
 // @Accept json
 // @Param request body service.UpdateFavListRequest true "`Favorite List`'s data detail that you want to update and can ignore the unchanged parameters"
+// @Param If-Match header string false "Quoted `revision` of the `Favorite List` that the change is based on, no header or `*` = any revision"
 // @Response 200
-// @Response 406 "Request Body Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable or `If-Match` is not an ETag"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 404 {object} ErrorResponse "`Favorite List`'s id is not found"
+// @Response 412 {object} ErrorResponse "`Favorite List` has been changed since the ETag in `If-Match`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /favlist/ [put]
+// @Router /favlist/ [patch]
 func (h favListHandler) UpdateFavList(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request service.UpdateFavListRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
+		return
+	}
+	request.Revision, err = ifMatchRevision(r)
+	if err != nil {
+		handlerError(w, r, err)
 		return
 	}
-	err = h.favListSrv.UpdateFavList(request)
+	err = h.favListSrv.UpdateFavList(r.Context(), userIdFromContext(r.Context()), request)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
 }
@@ -101,18 +135,75 @@ func (h favListHandler) UpdateFavList(w http.ResponseWriter, r *http.Request) {
 // @Summary Get all "Favorite List" of the "User Id"
 // @Description Get all `Favorite List` of the `User Id`
 // @Tags Favorite List
+// @Security BearerAuth
 // @Produce json
 // @Param user_id path string true "User Id"
+// @Param If-None-Match header string false "`ETag` of the list that you already have"
 // @Response 200 {object} []service.FavListResponse
-// @Response 500 "Internal Server Error"
+// @Header 200 {string} ETag "Hash of the body"
+// @Response 304 "Not Modified, the ETag in `If-None-Match` is still current"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "`If-None-Match` is not a list of ETags"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /favlist/{user_id} [get]
 func (h favListHandler) GetFavListsByUserId(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	response, err := h.favListSrv.GetFavListsByUserId(vars["user_id"])
+	err := checkOwner(r, vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	response, err := h.favListSrv.GetFavListsByUserId(r.Context(), vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	writeJSON(w, r, "", response)
+}
+
+// LogFavList ... Log a "Favorite List" as a "Record"
+// @Summary Log a "Favorite List" as a "Record"
+// @Description Create a `Record` of every `Menu` in the `Favorite List` multiplied by `multiplier`, every `Menu` in the `Favorite List` need to be up to date
+// @Tags Favorite List
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param favlist_id path int true "`Favorite List`'s id that you want to log"
+// @Param request body service.LogFavListRequest true "`Record`'s data detail"
+// @Param Idempotency-Key header string false "Key that the client generated for the request e.g. a UUID, a retry with the same key gets the first response back instead of logging it again"
+// @Response 200 {object} service.CreatedResponse "Id and revision of the created `Record`"
+// @Header 200 {string} ETag "Revision of the `Record` in quotes"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 404 {object} ErrorResponse "`Favorite List`'s id is not found"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 409 {object} ErrorResponse "Atleast one `Menu` in the `Favorite List` is not up to date, the message lists their id, or the request with the `Idempotency-Key` is still in progress"
+// @Response 422 {object} ErrorResponse "`Idempotency-Key` is already used by another request"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /favlist/{favlist_id}/log [post]
+func (h favListHandler) LogFavList(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("content-type") != "application/json" {
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
+		return
+	}
+	vars := mux.Vars(r)
+	favListId, err := strconv.ParseInt(vars["favlist_id"], 0, 0)
+	if err != nil {
+		handlerError(w, r, errs.NewValidationError("favlist_id", "Parse data type error"))
+		return
+	}
+	var request service.LogFavListRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handlerError(w, r, errs.NewMalformedBodyError())
+		return
+	}
+	response, err := h.favListSrv.LogFavList(r.Context(), userIdFromContext(r.Context()), int(favListId), request)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
-	w.Header().Set("content-type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, r, revisionETag(response.Revision), response)
 }
@@ -8,10 +8,8 @@ import (
 	service "go-nutritioncalculator2/services"
 	"net/http"
 	"net/http/httptest"
-	"strings"
 	"testing"
 
-	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,9 +20,9 @@ func TestCreateFavList(t *testing.T) {
 			UserId: "gooddy20",
 			Name:   "Breakfast",
 			List:   "9,10",
-		}).Return(nil)
+		}).Return(&service.CreatedResponse{Id: 12, Revision: 1}, nil)
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/", hdlr.CreateFavList).Methods("POST")
 		preReqBody := map[string]interface{}{
 			"user_id": "gooddy20",
@@ -33,6 +31,7 @@ func TestCreateFavList(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("POST", "/favlist/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
@@ -44,9 +43,9 @@ func TestCreateFavList(t *testing.T) {
 			UserId: "gooddy20",
 			Name:   "Breakfast",
 			List:   "9,10",
-		}).Return(nil)
+		}).Return(&service.CreatedResponse{Id: 12, Revision: 1}, nil)
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/", hdlr.CreateFavList).Methods("POST")
 		preReqBody := map[string]interface{}{
 			"user_id": "gooddy20",
@@ -55,24 +54,26 @@ func TestCreateFavList(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("POST", "/favlist/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Header", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CreateFavList")
 	})
 	t.Run("Incorrect Request Body", func(t *testing.T) {
 		srv := service.NewFavListServiceMock()
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/", hdlr.CreateFavList).Methods("POST")
 		reqBody := []byte("")
 		req := httptest.NewRequest("POST", "/favlist/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Body", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CreateFavList")
 	})
 	t.Run("Service Error", func(t *testing.T) {
@@ -81,9 +82,9 @@ func TestCreateFavList(t *testing.T) {
 			UserId: "gooddy20",
 			Name:   "Breakfast",
 			List:   "9,10",
-		}).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		}).Return((*service.CreatedResponse)(nil), errs.NewUnexpectedError())
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/", hdlr.CreateFavList).Methods("POST")
 		preReqBody := map[string]interface{}{
 			"user_id": "gooddy20",
@@ -92,63 +93,67 @@ func TestCreateFavList(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("POST", "/favlist/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
 func TestDeleteFavList(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		srv := service.NewFavListServiceMock()
-		srv.On("DeleteFavList", 1).Return(nil)
+		srv.On("DeleteFavList", "gooddy20", 1, 0).Return(nil)
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/{favlist_id}", hdlr.DeleteFavList).Methods("DELETE")
 		req := httptest.NewRequest("DELETE", "/favlist/1", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusOK, res.Code)
 	})
 	t.Run("Parse Id (String to Int) Error", func(t *testing.T) {
 		srv := service.NewFavListServiceMock()
-		srv.On("DeleteFavList", 1).Return(nil)
+		srv.On("DeleteFavList", "gooddy20", 1, 0).Return(nil)
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/{favlist_id}", hdlr.DeleteFavList).Methods("DELETE")
 		req := httptest.NewRequest("DELETE", "/favlist/1.1", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Parse data type error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Parse data type error", errorOf(res).Message)
 		srv.AssertNotCalled(t, "DeleteFavList")
 	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewFavListServiceMock()
-		srv.On("DeleteFavList", 1).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv.On("DeleteFavList", "gooddy20", 1, 0).Return(errs.NewUnexpectedError())
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/{favlist_id}", hdlr.DeleteFavList).Methods("DELETE")
 		req := httptest.NewRequest("DELETE", "/favlist/1", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
 func TestUpdateFavList(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		srv := service.NewFavListServiceMock()
-		srv.On("UpdateFavList", service.UpdateFavListRequest{
+		srv.On("UpdateFavList", "gooddy20", service.UpdateFavListRequest{
 			Id:   1,
-			Name: "Extra Breakfast",
-			List: "9,9,10",
+			Name: service.NewNullable("Extra Breakfast"),
+			List: service.NewNullable("9,9,10"),
 		}).Return(nil)
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/", hdlr.UpdateFavList).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"id":   1,
@@ -157,6 +162,7 @@ func TestUpdateFavList(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/favlist/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
@@ -165,7 +171,7 @@ func TestUpdateFavList(t *testing.T) {
 	t.Run("Incorrect Request Header", func(t *testing.T) {
 		srv := service.NewFavListServiceMock()
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/", hdlr.UpdateFavList).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"id":   1,
@@ -174,35 +180,37 @@ func TestUpdateFavList(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/favlist/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Header", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
 		srv.AssertNotCalled(t, "UpdateFavList")
 	})
 	t.Run("Incorrect Request Body", func(t *testing.T) {
 		srv := service.NewFavListServiceMock()
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/", hdlr.UpdateFavList).Methods("PUT")
 		reqBody := []byte("")
 		req := httptest.NewRequest("PUT", "/favlist/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Body", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
 		srv.AssertNotCalled(t, "UpdateFavList")
 	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewFavListServiceMock()
-		srv.On("UpdateFavList", service.UpdateFavListRequest{
+		srv.On("UpdateFavList", "gooddy20", service.UpdateFavListRequest{
 			Id:   1,
-			Name: "Extra Breakfast",
-			List: "9,9,10",
-		}).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+			Name: service.NewNullable("Extra Breakfast"),
+			List: service.NewNullable("9,9,10"),
+		}).Return(errs.NewUnexpectedError())
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/", hdlr.UpdateFavList).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"id":   1,
@@ -211,11 +219,12 @@ func TestUpdateFavList(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/favlist/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
@@ -227,9 +236,10 @@ func TestGetFavListsByUserId(t *testing.T) {
 			{Id: 2, Name: "Lunch", Menues: "Ramyeon-1 ,Moo Yang-1 ", List: "1,9", Protein: 30, Fat: 20, Carb: 63, IsUpdated: 1},
 		}, nil)
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/{user_id}", hdlr.GetFavListsByUserId).Methods("GET")
 		req := httptest.NewRequest("GET", "/favlist/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		resultBody := []service.FavListResponse{}
@@ -243,14 +253,74 @@ func TestGetFavListsByUserId(t *testing.T) {
 	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewFavListServiceMock()
-		srv.On("GetFavListsByUserId", "gooddy20").Return([]service.FavListResponse{}, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv.On("GetFavListsByUserId", "gooddy20").Return([]service.FavListResponse{}, errs.NewUnexpectedError())
 		hdlr := handler.NewFavListHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/favlist/{user_id}", hdlr.GetFavListsByUserId).Methods("GET")
 		req := httptest.NewRequest("GET", "/favlist/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
+}
+
+func TestLogFavList(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		srv := service.NewFavListServiceMock()
+		srv.On("LogFavList", "gooddy20", 1, service.LogFavListRequest{Note: "Breakfast", Multiplier: 1.5, EventTimestamp: "2023-12-05 08:00:00"}).Return(&service.CreatedResponse{Id: 31, Revision: 1}, nil)
+		hdlr := handler.NewFavListHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/favlist/{favlist_id}/log", hdlr.LogFavList).Methods("POST")
+		req := httptest.NewRequest("POST", "/favlist/1/log", bytes.NewBufferString(`{"note": "Breakfast", "multiplier": 1.5, "event_timestamp": "2023-12-05 08:00:00"}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `"1"`, res.Header().Get("etag"))
+		assert.JSONEq(t, `{"id":31,"revision":1}`, res.Body.String())
+	})
+	t.Run("Parse Id (String to Int) Error", func(t *testing.T) {
+		srv := service.NewFavListServiceMock()
+		hdlr := handler.NewFavListHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/favlist/{favlist_id}/log", hdlr.LogFavList).Methods("POST")
+		req := httptest.NewRequest("POST", "/favlist/one/log", bytes.NewBufferString(`{"event_timestamp": "2023-12-05 08:00:00"}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Parse data type error", errorOf(res).Message)
+		srv.AssertNotCalled(t, "LogFavList")
+	})
+	t.Run("Incorrect Request Header", func(t *testing.T) {
+		srv := service.NewFavListServiceMock()
+		hdlr := handler.NewFavListHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/favlist/{favlist_id}/log", hdlr.LogFavList).Methods("POST")
+		req := httptest.NewRequest("POST", "/favlist/1/log", bytes.NewBufferString(`{"event_timestamp": "2023-12-05 08:00:00"}`))
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
+		srv.AssertNotCalled(t, "LogFavList")
+	})
+	t.Run("Outdated Menu", func(t *testing.T) {
+		srv := service.NewFavListServiceMock()
+		srv.On("LogFavList", "gooddy20", 1, service.LogFavListRequest{EventTimestamp: "2023-12-05 08:00:00"}).Return((*service.CreatedResponse)(nil), errs.NewConflictError(errs.CodeMenuOutdated, "Menu Id - 10 in the Favorite List are outdated, update the Favorite List before logging it"))
+		hdlr := handler.NewFavListHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/favlist/{favlist_id}/log", hdlr.LogFavList).Methods("POST")
+		req := httptest.NewRequest("POST", "/favlist/1/log", bytes.NewBufferString(`{"event_timestamp": "2023-12-05 08:00:00"}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, "Menu Id - 10 in the Favorite List are outdated, update the Favorite List before logging it", errorOf(res).Message)
 	})
 }
@@ -1,15 +1,37 @@
 package handler
 
 import (
-	"fmt"
+	"encoding/json"
+	"errors"
 	"go-nutritioncalculator2/errs"
+	"go-nutritioncalculator2/logs"
 	"net/http"
 )
 
-func handlerError(w http.ResponseWriter, err error) {
-	switch e := err.(type) {
-	case errs.AppError:
-		w.WriteHeader(e.Code)
-		fmt.Fprintln(w, e)
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code      string            `json:"code" example:"VALIDATION_FAILED"`             // Stable code of the error e.g. "USER_NOT_FOUND", "VALIDATION_FAILED"
+	Message   string            `json:"message" example:"Weight need to be positive"` // Message of the error for people
+	Details   []errs.FieldError `json:"details,omitempty"`                            // Invalid fields of the request (only for "VALIDATION_FAILED")
+	RequestId string            `json:"request_id" example:"3f9c1e7a5b2d4c60"`        // Id of the request that is also sent in the "X-Request-Id" header
+}
+
+// handlerError writes the error as the JSON envelope, an error that is not an AppError is logged and hidden behind a 500
+func handlerError(w http.ResponseWriter, r *http.Request, err error) {
+	var appErr errs.AppError
+	if !errors.As(err, &appErr) {
+		logs.Error(err)
+		appErr = errs.NewUnexpectedError()
 	}
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(appErr.Code)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: ErrorBody{
+		Code:      appErr.ErrorCode,
+		Message:   appErr.Message,
+		Details:   appErr.Details,
+		RequestId: requestIdFromContext(r.Context()),
+	}})
 }
@@ -0,0 +1,26 @@
+package handler_test
+
+import (
+	"encoding/json"
+	handler "go-nutritioncalculator2/handlers"
+	service "go-nutritioncalculator2/services"
+	"net/http/httptest"
+
+	"github.com/gorilla/mux"
+)
+
+// newAuthRouter returns a router that authenticates "Bearer token" as "gooddy20"
+func newAuthRouter() *mux.Router {
+	authSrv := service.NewAuthServiceMock()
+	authSrv.On("ParseToken", "token").Return("gooddy20", nil)
+	r := mux.NewRouter()
+	r.Use(handler.NewAuthMiddleware(authSrv))
+	return r
+}
+
+// errorOf decodes the error envelope of the response
+func errorOf(res *httptest.ResponseRecorder) handler.ErrorBody {
+	var errRes handler.ErrorResponse
+	json.Unmarshal(res.Body.Bytes(), &errRes)
+	return errRes.Error
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"go-nutritioncalculator2/errs"
+	service "go-nutritioncalculator2/services"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Headers of an idempotent request, a retry with the same "Idempotency-Key" gets the first response back with "Idempotent-Replayed: true"
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// NewIdempotencyMiddleware runs a request with an "Idempotency-Key" header once per key of the "User" and sends the saved response to its retries,
+// a request without the header is run as usual and a response that failed on the server (5xx) or a panic releases the key so that the retry is run again.
+// The lease of the key is extended while the handler runs so that a slow request is not run again by a retry.
+// It reads the "User" from the context so it is used inside the auth middleware
+func NewIdempotencyMiddleware(idempotencySrv service.IdempotencyService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				handlerError(w, r, errs.NewMalformedBodyError())
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			userId := userIdFromContext(r.Context())
+			lease, response, err := idempotencySrv.BeginRequest(r.Context(), userId, key, requestFingerprint(r, body))
+			if err != nil {
+				handlerError(w, r, err)
+				return
+			}
+			if response != nil {
+				if response.ContentType != "" {
+					w.Header().Set("content-type", response.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(response.Status)
+				w.Write(response.Body)
+				return
+			}
+			// The response is already sent so the errors are only logged by the service, and the key is settled even when the client has gone
+			ctx := context.WithoutCancel(r.Context())
+			stopExtending := extendLease(ctx, idempotencySrv, *lease)
+			defer func() {
+				// A handler that panics never sends its response, so the key is released for the retry before the panic goes on
+				if p := recover(); p != nil {
+					stopExtending()
+					idempotencySrv.CancelRequest(ctx, *lease)
+					panic(p)
+				}
+			}()
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+			stopExtending()
+			if recorder.status >= http.StatusInternalServerError {
+				idempotencySrv.CancelRequest(ctx, *lease)
+				return
+			}
+			idempotencySrv.CompleteRequest(ctx, *lease, service.IdempotentResponse{
+				Status:      recorder.status,
+				ContentType: w.Header().Get("content-type"),
+				Body:        recorder.body.Bytes(),
+			})
+		})
+	}
+}
+
+// extendLease extends the lease of the key every third of its duration until the returned stop is called
+func extendLease(ctx context.Context, idempotencySrv service.IdempotencyService, lease service.IdempotencyLease) (stop func()) {
+	if lease.Duration <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lease.Duration / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				idempotencySrv.ExtendRequest(ctx, lease)
+			}
+		}
+	}()
+	// The extension in flight is waited for so that it never runs after the key is settled
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+// requestFingerprint is the hash of the method, path and body so that a key can not be reused by a different request
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder writes the response through to the client and keeps a copy of its status and body
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(body []byte) (int, error) {
+	w.wroteHeader = true
+	w.body.Write(body)
+	return w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,159 @@
+package handler_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"go-nutritioncalculator2/errs"
+	handler "go-nutritioncalculator2/handlers"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	reqBody := []byte(`{"list":"9,9,10","event_timestamp":"2023-12-05 10:00:00"}`)
+	sum := sha256.Sum256(append([]byte("POST /record/\n"), reqBody...))
+	fingerprint := hex.EncodeToString(sum[:])
+	newRecordReq := service.NewRecordRequest{UserId: "gooddy20", List: "9,9,10", EventTimestamp: "2023-12-05 10:00:00"}
+	lease := &service.IdempotencyLease{UserId: "gooddy20", Key: "retry-1", CreatedTimestamp: time.Date(2023, 12, 5, 10, 0, 0, 0, time.UTC)}
+	t.Run("Success", func(t *testing.T) {
+		idempotencySrv := service.NewIdempotencyServiceMock()
+		idempotencySrv.On("BeginRequest", "gooddy20", "retry-1", fingerprint).Return(lease, (*service.IdempotentResponse)(nil), nil)
+		idempotencySrv.On("CompleteRequest", *lease, service.IdempotentResponse{Status: http.StatusOK, ContentType: "application/json", Body: []byte(`{"id":12,"revision":1}` + "\n")}).Return(nil)
+		srv := service.NewRecordServiceMock()
+		srv.On("CreateRecord", newRecordReq).Return(&service.CreatedResponse{Id: 12, Revision: 1}, nil)
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.Handle("/record/", handler.NewIdempotencyMiddleware(idempotencySrv)(http.HandlerFunc(hdlr.CreateRecord))).Methods("POST")
+		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		req.Header.Add("Idempotency-Key", "retry-1")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "", res.Header().Get("Idempotent-Replayed"))
+		srv.AssertCalled(t, "CreateRecord", newRecordReq)
+		idempotencySrv.AssertCalled(t, "CompleteRequest", *lease, service.IdempotentResponse{Status: http.StatusOK, ContentType: "application/json", Body: []byte(`{"id":12,"revision":1}` + "\n")})
+	})
+	t.Run("Success Case: Slow Request Extends The Lease", func(t *testing.T) {
+		slowLease := &service.IdempotencyLease{UserId: "gooddy20", Key: "retry-1", CreatedTimestamp: lease.CreatedTimestamp, Duration: 30 * time.Millisecond}
+		idempotencySrv := service.NewIdempotencyServiceMock()
+		idempotencySrv.On("BeginRequest", "gooddy20", "retry-1", fingerprint).Return(slowLease, (*service.IdempotentResponse)(nil), nil)
+		idempotencySrv.On("ExtendRequest", *slowLease).Return(nil)
+		idempotencySrv.On("CompleteRequest", *slowLease, service.IdempotentResponse{Status: http.StatusOK, ContentType: "text/plain", Body: []byte("done")}).Return(nil)
+		r := newAuthRouter()
+		r.Handle("/record/", handler.NewIdempotencyMiddleware(idempotencySrv)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			w.Header().Set("content-type", "text/plain")
+			w.Write([]byte("done"))
+		}))).Methods("POST")
+		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		req.Header.Add("Idempotency-Key", "retry-1")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		idempotencySrv.AssertCalled(t, "ExtendRequest", *slowLease)
+		idempotencySrv.AssertCalled(t, "CompleteRequest", *slowLease, service.IdempotentResponse{Status: http.StatusOK, ContentType: "text/plain", Body: []byte("done")})
+	})
+	t.Run("Success Case: No Idempotency-Key", func(t *testing.T) {
+		idempotencySrv := service.NewIdempotencyServiceMock()
+		srv := service.NewRecordServiceMock()
+		srv.On("CreateRecord", newRecordReq).Return(&service.CreatedResponse{Id: 12, Revision: 1}, nil)
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.Handle("/record/", handler.NewIdempotencyMiddleware(idempotencySrv)(http.HandlerFunc(hdlr.CreateRecord))).Methods("POST")
+		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		srv.AssertCalled(t, "CreateRecord", newRecordReq)
+		idempotencySrv.AssertNotCalled(t, "BeginRequest")
+	})
+	t.Run("Success Case: Retry", func(t *testing.T) {
+		idempotencySrv := service.NewIdempotencyServiceMock()
+		idempotencySrv.On("BeginRequest", "gooddy20", "retry-1", fingerprint).Return((*service.IdempotencyLease)(nil), &service.IdempotentResponse{
+			Status:      http.StatusBadRequest,
+			ContentType: "application/json",
+			Body:        []byte(`{"error":{"code":"VALIDATION_FAILED","message":"Menu Id - 9 is deleted","request_id":"3f9c1e7a5b2d4c60"}}`),
+		}, nil)
+		srv := service.NewRecordServiceMock()
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.Handle("/record/", handler.NewIdempotencyMiddleware(idempotencySrv)(http.HandlerFunc(hdlr.CreateRecord))).Methods("POST")
+		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		req.Header.Add("Idempotency-Key", "retry-1")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "true", res.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, "application/json", res.Header().Get("content-type"))
+		assert.Equal(t, "Menu Id - 9 is deleted", errorOf(res).Message)
+		srv.AssertNotCalled(t, "CreateRecord")
+		idempotencySrv.AssertNotCalled(t, "CompleteRequest")
+	})
+	t.Run("Server Error Releases The Key", func(t *testing.T) {
+		idempotencySrv := service.NewIdempotencyServiceMock()
+		idempotencySrv.On("BeginRequest", "gooddy20", "retry-1", fingerprint).Return(lease, (*service.IdempotentResponse)(nil), nil)
+		idempotencySrv.On("CancelRequest", *lease).Return(nil)
+		srv := service.NewRecordServiceMock()
+		srv.On("CreateRecord", newRecordReq).Return((*service.CreatedResponse)(nil), errs.NewUnexpectedError())
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.Handle("/record/", handler.NewIdempotencyMiddleware(idempotencySrv)(http.HandlerFunc(hdlr.CreateRecord))).Methods("POST")
+		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		req.Header.Add("Idempotency-Key", "retry-1")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		idempotencySrv.AssertCalled(t, "CancelRequest", *lease)
+		idempotencySrv.AssertNotCalled(t, "CompleteRequest")
+	})
+	t.Run("Panic Releases The Key", func(t *testing.T) {
+		idempotencySrv := service.NewIdempotencyServiceMock()
+		idempotencySrv.On("BeginRequest", "gooddy20", "retry-1", fingerprint).Return(lease, (*service.IdempotentResponse)(nil), nil)
+		idempotencySrv.On("CancelRequest", *lease).Return(nil)
+		r := newAuthRouter()
+		r.Handle("/record/", handler.NewIdempotencyMiddleware(idempotencySrv)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("unexpected nil")
+		}))).Methods("POST")
+		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		req.Header.Add("Idempotency-Key", "retry-1")
+		res := httptest.NewRecorder()
+		assert.PanicsWithValue(t, "unexpected nil", func() { r.ServeHTTP(res, req) })
+		idempotencySrv.AssertCalled(t, "CancelRequest", *lease)
+		idempotencySrv.AssertNotCalled(t, "CompleteRequest")
+	})
+	t.Run("Key Used By Another Request", func(t *testing.T) {
+		idempotencySrv := service.NewIdempotencyServiceMock()
+		idempotencySrv.On("BeginRequest", "gooddy20", "retry-1", fingerprint).Return((*service.IdempotencyLease)(nil), (*service.IdempotentResponse)(nil), errs.NewUnprocessableError(errs.CodeIdempotencyKeyReused, "Idempotency-Key is already used by another request"))
+		srv := service.NewRecordServiceMock()
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.Handle("/record/", handler.NewIdempotencyMiddleware(idempotencySrv)(http.HandlerFunc(hdlr.CreateRecord))).Methods("POST")
+		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		req.Header.Add("Idempotency-Key", "retry-1")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		assert.Equal(t, errs.CodeIdempotencyKeyReused, errorOf(res).Code)
+		srv.AssertNotCalled(t, "CreateRecord")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"go-nutritioncalculator2/errs"
 	service "go-nutritioncalculator2/services"
+	"math"
 	"net/http"
 	"strconv"
 
@@ -22,95 +23,223 @@ func NewMenuHandler(menuSrv service.MenuService) menuHandler {
 // @Summary Create a "Menu"
 // @Description Create a 'Menu'
 // @Tags Menu
+// @Security BearerAuth
 // @Accept json
+// @Produce json
 // @Param request body service.NewMenuRequest true "`Menu`'s data detail"
-// @Response 200
-// @Response 406 "Request Body Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Param Idempotency-Key header string false "Key that the client generated for the request e.g. a UUID, a retry with the same key gets the first response back instead of creating it again"
+// @Response 200 {object} service.CreatedResponse "Id and revision of the created `Menu`"
+// @Header 200 {string} ETag "Revision of the `Menu` in quotes"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 409 {object} ErrorResponse "The request with the `Idempotency-Key` is still in progress"
+// @Response 422 {object} ErrorResponse "`Idempotency-Key` is already used by another request"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /menu/ [post]
 func (h menuHandler) CreateMenu(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request service.NewMenuRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
 		return
 	}
-	err = h.menuSrv.CreateMenu(request)
+	request.CreatorId = userIdFromContext(r.Context())
+	response, err := h.menuSrv.CreateMenu(r.Context(), request)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
+	writeJSON(w, r, revisionETag(response.Revision), response)
 }
 
 // DeleteMenu ... Delete a "Menu"
 // @Summary Delete a "Menu"
 // @Description Delete a 'Menu'
 // @Tags Menu
+// @Security BearerAuth
 // @Param menu_id path int true "`Menu`'s id that you want to delete"
+// @Param If-Match header string false "Quoted `revision` of the `Menu` that the change is based on, no header or `*` = any revision"
 // @Response 200
-// @Response 406 "Request Parameter Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request Parameter Not Acceptable or `If-Match` is not an ETag"
+// @Response 404 {object} ErrorResponse "`Menu Id` is not found or is deleted"
+// @Response 409 {object} ErrorResponse "`Menu` is already replaced by a newer version, the message names it"
+// @Response 412 {object} ErrorResponse "`Menu` has been changed since the ETag in `If-Match`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /menu/{menu_id} [delete]
 func (h menuHandler) DeleteMenu(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	menu_id, err := strconv.ParseInt(vars["menu_id"], 0, 0)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Parse data type error"})
+		handlerError(w, r, errs.NewValidationError("menu_id", "Parse data type error"))
 		return
 	}
-	err = h.menuSrv.DeleteMenu(int(menu_id))
+	revision, err := ifMatchRevision(r)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
+		return
+	}
+	err = h.menuSrv.DeleteMenu(r.Context(), userIdFromContext(r.Context()), int(menu_id), revision)
+	if err != nil {
+		handlerError(w, r, err)
 		return
 	}
 }
 
 // UpdateMenu ... Update a "Menu"
 // @Summary Update a "Menu"
-// @Description Update a `Menu`
+// @Description Update a `Menu` as a new version, a field that is omitted is kept and a field that is null is cleared
 // @Tags Menu
+// @Security BearerAuth
 // @Accept json
+// @Produce json
 // @Param request body service.UpdateMenuRequest true "`Menu`'s data detail that you want to update and the unchanged parameters need to be input the old value"
-// @Response 200
-// @Response 406 "Request Body Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Param If-Match header string false "Quoted `revision` of the `Menu` that the change is based on, no header or `*` = any revision"
+// @Response 200 {object} service.CreatedResponse "Id and revision of the new version of the `Menu`"
+// @Header 200 {string} ETag "Revision of the new version in quotes"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable or `If-Match` is not an ETag"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 404 {object} ErrorResponse "`Menu Id` is not found or is deleted"
+// @Response 409 {object} ErrorResponse "`Menu` is already replaced by a newer version, the message names it"
+// @Response 412 {object} ErrorResponse "`Menu` has been changed since the ETag in `If-Match`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /menu/ [put]
+// @Router /menu/ [patch]
 func (h menuHandler) UpdateMenu(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request service.UpdateMenuRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
 		return
 	}
-	err = h.menuSrv.UpdateMenu(request)
+	request.Revision, err = ifMatchRevision(r)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
+	response, err := h.menuSrv.UpdateMenu(r.Context(), userIdFromContext(r.Context()), request)
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	writeJSON(w, r, revisionETag(response.Revision), response)
 }
 
-// GetAllMenues ... Get all "Menu"
-// @Summary Get all "Menu"
-// @Description Get all 'Menu'
+// GetAllMenues ... Search "Menu"
+// @Summary Search "Menu"
+// @Description Get a page of `Menu` that match every given filter, only active `Menu` by default
 // @Tags Menu
+// @Security BearerAuth
 // @Produce json
-// @Response 200 {object} []service.MenuResponse
-// @Response 500 "Internal Server Error"
+// @Param name query string false "Case-insensitive part of the name"
+// @Param creator_id query string false "`User Id` that create the `Menu`"
+// @Param status query string false "`1` (default) = Active, `0` = Deleted, `all` = both"
+// @Param min_protein query number false "Minimum protein (g.)"
+// @Param max_protein query number false "Maximum protein (g.)"
+// @Param min_fat query number false "Minimum fat (g.)"
+// @Param max_fat query number false "Maximum fat (g.)"
+// @Param min_carb query number false "Minimum carb (g.)"
+// @Param max_carb query number false "Maximum carb (g.)"
+// @Param min_kcal query number false "Minimum energy (kcal)"
+// @Param max_kcal query number false "Maximum energy (kcal)"
+// @Param sort query string false "`newest`, `likes` (most liked first), `protein_density` (highest percentage of energy from protein first), default = creation order"
+// @Param limit query int false "Maximum amount of `Menu` in the page, 1 - 200, default = 50"
+// @Param offset query int false "Amount of `Menu` to skip, use `next_offset` of the previous page"
+// @Param If-None-Match header string false "`ETag` of the page that you already have"
+// @Response 200 {object} service.MenuPageResponse
+// @Header 200 {string} ETag "Hash of the body"
+// @Response 304 "Not Modified, the ETag in `If-None-Match` is still current"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable or `If-None-Match` is not a list of ETags"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /menu/ [get]
 func (h menuHandler) GetAllMenues(w http.ResponseWriter, r *http.Request) {
-	response, err := h.menuSrv.GetAllMenues()
+	query := r.URL.Query()
+	menuQuery := service.MenuQuery{
+		Name:      query.Get("name"),
+		CreatorId: query.Get("creator_id"),
+		Status:    query.Get("status"),
+		Sort:      query.Get("sort"),
+	}
+	numbers := map[string]**float64{
+		"min_protein": &menuQuery.MinProtein,
+		"max_protein": &menuQuery.MaxProtein,
+		"min_fat":     &menuQuery.MinFat,
+		"max_fat":     &menuQuery.MaxFat,
+		"min_carb":    &menuQuery.MinCarb,
+		"max_carb":    &menuQuery.MaxCarb,
+		"min_kcal":    &menuQuery.MinKcal,
+		"max_kcal":    &menuQuery.MaxKcal,
+	}
+	for key, target := range numbers {
+		if query.Get(key) == "" {
+			continue
+		}
+		number, err := strconv.ParseFloat(query.Get(key), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			handlerError(w, r, errs.NewValidationError(key, "Parse data type error"))
+			return
+		}
+		*target = &number
+	}
+	for key, target := range map[string]*int{"limit": &menuQuery.Limit, "offset": &menuQuery.Offset} {
+		if query.Get(key) == "" {
+			continue
+		}
+		number, err := strconv.ParseInt(query.Get(key), 0, 0)
+		if err != nil {
+			handlerError(w, r, errs.NewValidationError(key, "Parse data type error"))
+			return
+		}
+		*target = int(number)
+	}
+	response, err := h.menuSrv.GetAllMenues(r.Context(), menuQuery)
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	writeJSON(w, r, "", response)
+}
+
+// GetMenuHistory ... Get every version of a "Menu"
+// @Summary Get every version of a "Menu"
+// @Description Get every version in the lineage of a `Menu` from the first version to the newest one, any version's id can be used
+// @Tags Menu
+// @Security BearerAuth
+// @Produce json
+// @Param menu_id path int true "`Menu`'s id of any version in the lineage"
+// @Param If-None-Match header string false "`ETag` of the history that you already have"
+// @Response 200 {array} service.MenuResponse
+// @Header 200 {string} ETag "Hash of the body"
+// @Response 304 "Not Modified, the ETag in `If-None-Match` is still current"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request Parameter Not Acceptable or `If-None-Match` is not a list of ETags"
+// @Response 404 {object} ErrorResponse "`Menu Id` is not found"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /menu/{menu_id}/history [get]
+func (h menuHandler) GetMenuHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	menu_id, err := strconv.ParseInt(vars["menu_id"], 0, 0)
+	if err != nil {
+		handlerError(w, r, errs.NewValidationError("menu_id", "Parse data type error"))
+		return
+	}
+	response, err := h.menuSrv.GetMenuHistory(r.Context(), int(menu_id))
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
-	w.Header().Set("content-type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, r, "", response)
 }
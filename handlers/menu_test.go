@@ -11,7 +11,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,9 +23,9 @@ func TestCreateMenu(t *testing.T) {
 			Fat:       15,
 			Carb:      65,
 			CreatorId: "gooddy20",
-		}).Return(nil)
+		}).Return(&service.CreatedResponse{Id: 12, Revision: 1}, nil)
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.CreateMenu).Methods("POST")
 		preReqBody := map[string]interface{}{
 			"name":       "Ramyeon",
@@ -37,6 +36,7 @@ func TestCreateMenu(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("POST", "/menu/", bytes.NewReader(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
@@ -45,7 +45,7 @@ func TestCreateMenu(t *testing.T) {
 	t.Run("Incorrect Request Header", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.CreateMenu).Methods("POST")
 		preReqBody := map[string]interface{}{
 			"name":       "Ramyeon",
@@ -56,24 +56,26 @@ func TestCreateMenu(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("POST", "/menu/", bytes.NewReader(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Header", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CreateMenu")
 	})
 	t.Run("Incorrect Request Body", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.CreateMenu).Methods("POST")
 		reqBody := []byte("")
 		req := httptest.NewRequest("POST", "/menu/", bytes.NewReader(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Body", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CreateMenu")
 	})
 	t.Run("Service Error", func(t *testing.T) {
@@ -84,9 +86,9 @@ func TestCreateMenu(t *testing.T) {
 			Fat:       15,
 			Carb:      65,
 			CreatorId: "gooddy20",
-		}).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		}).Return((*service.CreatedResponse)(nil), errs.NewUnexpectedError())
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.CreateMenu).Methods("POST")
 		preReqBody := map[string]interface{}{
 			"name":       "Ramyeon",
@@ -97,64 +99,68 @@ func TestCreateMenu(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("POST", "/menu/", bytes.NewReader(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
 func TestDeleteMenu(t *testing.T) {
 	t.Run("Complete", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
-		srv.On("DeleteMenu", 1).Return(nil)
+		srv.On("DeleteMenu", "gooddy20", 1, 0).Return(nil)
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/{menu_id}", hdlr.DeleteMenu).Methods("DELETE")
 		req := httptest.NewRequest("DELETE", "/menu/1", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusOK, res.Code)
 	})
 	t.Run("Parse Int Error", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
-		srv.On("DeleteMenu", 1).Return(nil)
+		srv.On("DeleteMenu", "gooddy20", 1, 0).Return(nil)
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/{menu_id}", hdlr.DeleteMenu).Methods("DELETE")
 		req := httptest.NewRequest("DELETE", "/menu/1.1", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Parse data type error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Parse data type error", errorOf(res).Message)
 	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
-		srv.On("DeleteMenu", 1).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv.On("DeleteMenu", "gooddy20", 1, 0).Return(errs.NewUnexpectedError())
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/{menu_id}", hdlr.DeleteMenu).Methods("DELETE")
 		req := httptest.NewRequest("DELETE", "/menu/1", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
 func TestUpdateMenu(t *testing.T) {
 	t.Run("Complete", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
-		srv.On("UpdateMenu", service.UpdateMenuRequest{
+		srv.On("UpdateMenu", "gooddy20", service.UpdateMenuRequest{
 			Id:      1,
-			Name:    "Ramyeon v2",
-			Protein: 8,
-			Fat:     20,
-			Carb:    70,
-		}).Return(nil)
+			Name:    service.NewNullable("Ramyeon v2"),
+			Protein: service.NewNullable[float64](8),
+			Fat:     service.NewNullable[float64](20),
+			Carb:    service.NewNullable[float64](70),
+		}).Return(&service.CreatedResponse{Id: 4, Revision: 1}, nil)
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.UpdateMenu).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"id":      1,
@@ -165,22 +171,25 @@ func TestUpdateMenu(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/menu/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `"1"`, res.Header().Get("etag"))
+		assert.JSONEq(t, `{"id":4,"revision":1}`, res.Body.String())
 	})
 	t.Run("content-type is not correct", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
-		srv.On("UpdateMenu", service.UpdateMenuRequest{
+		srv.On("UpdateMenu", "gooddy20", service.UpdateMenuRequest{
 			Id:      1,
-			Name:    "Ramyeon v2",
-			Protein: 8,
-			Fat:     20,
-			Carb:    70,
-		}).Return(nil)
+			Name:    service.NewNullable("Ramyeon v2"),
+			Protein: service.NewNullable[float64](8),
+			Fat:     service.NewNullable[float64](20),
+			Carb:    service.NewNullable[float64](70),
+		}).Return(&service.CreatedResponse{Id: 4, Revision: 1}, nil)
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.UpdateMenu).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"id":      1,
@@ -191,42 +200,44 @@ func TestUpdateMenu(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/menu/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Header", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
 	})
 	t.Run("Decode Request Body Error", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
-		srv.On("UpdateMenu", service.UpdateMenuRequest{
+		srv.On("UpdateMenu", "gooddy20", service.UpdateMenuRequest{
 			Id:      1,
-			Name:    "Ramyeon v2",
-			Protein: 8,
-			Fat:     20,
-			Carb:    70,
-		}).Return(nil)
+			Name:    service.NewNullable("Ramyeon v2"),
+			Protein: service.NewNullable[float64](8),
+			Fat:     service.NewNullable[float64](20),
+			Carb:    service.NewNullable[float64](70),
+		}).Return(&service.CreatedResponse{Id: 4, Revision: 1}, nil)
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.UpdateMenu).Methods("PUT")
 		reqBody := []byte("")
 		req := httptest.NewRequest("PUT", "/menu/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Body", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
 	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
-		srv.On("UpdateMenu", service.UpdateMenuRequest{
+		srv.On("UpdateMenu", "gooddy20", service.UpdateMenuRequest{
 			Id:      1,
-			Name:    "Ramyeon v2",
-			Protein: 8,
-			Fat:     20,
-			Carb:    70,
-		}).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+			Name:    service.NewNullable("Ramyeon v2"),
+			Protein: service.NewNullable[float64](8),
+			Fat:     service.NewNullable[float64](20),
+			Carb:    service.NewNullable[float64](70),
+		}).Return((*service.CreatedResponse)(nil), errs.NewUnexpectedError())
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.UpdateMenu).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"id":      1,
@@ -237,18 +248,19 @@ func TestUpdateMenu(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/menu/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
 func TestGetAllMenues(t *testing.T) {
 	t.Run("Complete", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
-		srv.On("GetAllMenues").Return([]service.MenuResponse{
+		srv.On("GetAllMenues", service.MenuQuery{}).Return(&service.MenuPageResponse{Menues: []service.MenuResponse{
 			{Id: 1,
 				Name:        "Ramyeon v2",
 				Protein:     8,
@@ -267,16 +279,17 @@ func TestGetAllMenues(t *testing.T) {
 				CreatorName: "GoodDy",
 				Like:        3,
 				Status:      1},
-		}, nil)
+		}}, nil)
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.GetAllMenues).Methods("GET")
 		req := httptest.NewRequest("GET", "/menu/", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		resultBody := []service.MenuResponse{}
+		resultBody := service.MenuPageResponse{}
 		_ = json.Unmarshal(res.Body.Bytes(), &resultBody)
-		expectedBody := []service.MenuResponse{
+		expectedBody := service.MenuPageResponse{Menues: []service.MenuResponse{
 			{Id: 1,
 				Name:        "Ramyeon v2",
 				Protein:     8,
@@ -295,13 +308,13 @@ func TestGetAllMenues(t *testing.T) {
 				CreatorName: "GoodDy",
 				Like:        3,
 				Status:      1},
-		}
+		}}
 		assert.Equal(t, http.StatusOK, res.Code)
 		assert.Equal(t, expectedBody, resultBody)
 	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewMenuServiceMock()
-		srv.On("GetAllMenues").Return([]service.MenuResponse{
+		srv.On("GetAllMenues", service.MenuQuery{}).Return(&service.MenuPageResponse{Menues: []service.MenuResponse{
 			{Id: 1,
 				Name:        "Ramyeon v2",
 				Protein:     8,
@@ -320,14 +333,105 @@ func TestGetAllMenues(t *testing.T) {
 				CreatorName: "GoodDy",
 				Like:        3,
 				Status:      1},
-		}, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		}}, errs.NewUnexpectedError())
 		hdlr := handler.NewMenuHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/menu/", hdlr.GetAllMenues).Methods("GET")
 		req := httptest.NewRequest("GET", "/menu/", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
+	t.Run("Success Case: Query Parameters", func(t *testing.T) {
+		minProtein, maxKcal := 20.0, 500.5
+		srv := service.NewMenuServiceMock()
+		srv.On("GetAllMenues", service.MenuQuery{
+			Name:       "chicken",
+			CreatorId:  "gooddy20",
+			Status:     "all",
+			MinProtein: &minProtein,
+			MaxKcal:    &maxKcal,
+			Sort:       "likes",
+			Limit:      10,
+			Offset:     20,
+		}).Return(&service.MenuPageResponse{Menues: []service.MenuResponse{}}, nil)
+		hdlr := handler.NewMenuHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/menu/", hdlr.GetAllMenues).Methods("GET")
+		req := httptest.NewRequest("GET", "/menu/?name=chicken&creator_id=gooddy20&status=all&min_protein=20&max_kcal=500.5&sort=likes&limit=10&offset=20", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `{"menues":[],"next_offset":null}`, strings.Replace(res.Body.String(), "\n", "", -1))
+	})
+	t.Run("Invalid Number", func(t *testing.T) {
+		srv := service.NewMenuServiceMock()
+		hdlr := handler.NewMenuHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/menu/", hdlr.GetAllMenues).Methods("GET")
+		for _, query := range []string{"min_fat=low", "max_carb=NaN", "offset=1.5"} {
+			req := httptest.NewRequest("GET", "/menu/?"+query, nil)
+			req.Header.Add("authorization", "Bearer token")
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "Parse data type error", errorOf(res).Message)
+		}
+		srv.AssertNotCalled(t, "GetAllMenues")
+	})
+}
+
+func TestGetMenuHistory(t *testing.T) {
+	t.Run("Complete", func(t *testing.T) {
+		firstId := 1
+		srv := service.NewMenuServiceMock()
+		srv.On("GetMenuHistory", 4).Return([]service.MenuResponse{
+			{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, CreatorId: "gooddy20", Status: 0, Version: 1},
+			{Id: 4, Name: "Omelet", Protein: 5.5, Fat: 0.5, Carb: 1, CreatorId: "gooddy20", Status: 1, ParentMenuId: &firstId, Version: 2},
+		}, nil)
+		hdlr := handler.NewMenuHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/menu/{menu_id}/history", hdlr.GetMenuHistory).Methods("GET")
+		req := httptest.NewRequest("GET", "/menu/4/history", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		resultBody := []service.MenuResponse{}
+		_ = json.Unmarshal(res.Body.Bytes(), &resultBody)
+		expectedBody := []service.MenuResponse{
+			{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, CreatorId: "gooddy20", Status: 0, Version: 1},
+			{Id: 4, Name: "Omelet", Protein: 5.5, Fat: 0.5, Carb: 1, CreatorId: "gooddy20", Status: 1, ParentMenuId: &firstId, Version: 2},
+		}
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, expectedBody, resultBody)
+	})
+	t.Run("Parse Data Type Error", func(t *testing.T) {
+		srv := service.NewMenuServiceMock()
+		hdlr := handler.NewMenuHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/menu/{menu_id}/history", hdlr.GetMenuHistory).Methods("GET")
+		req := httptest.NewRequest("GET", "/menu/four/history", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Parse data type error", errorOf(res).Message)
+		srv.AssertNotCalled(t, "GetMenuHistory")
+	})
+	t.Run("Error", func(t *testing.T) {
+		srv := service.NewMenuServiceMock()
+		srv.On("GetMenuHistory", 4).Return([]service.MenuResponse{}, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found"))
+		hdlr := handler.NewMenuHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/menu/{menu_id}/history", hdlr.GetMenuHistory).Methods("GET")
+		req := httptest.NewRequest("GET", "/menu/4/history", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, "Menu Id is not found", errorOf(res).Message)
 	})
 }
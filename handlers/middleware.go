@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"go-nutritioncalculator2/errs"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type contextKey string
+
+const (
+	userIdContextKey    contextKey = "user_id"
+	requestIdContextKey contextKey = "request_id"
+)
+
+// RequestIdHeader carries the id of the request in both directions
+const RequestIdHeader = "X-Request-Id"
+
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// NewRequestIdMiddleware keeps the id of the "X-Request-Id" header when it is safe to log or gives the request a new one,
+// and sends the id back in the same header so that a client can quote it with an error
+func NewRequestIdMiddleware() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestId := r.Header.Get(RequestIdHeader)
+			if !requestIdPattern.MatchString(requestId) {
+				requestId = newRequestId()
+			}
+			w.Header().Set(RequestIdHeader, requestId)
+			ctx := context.WithValue(r.Context(), requestIdContextKey, requestId)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func newRequestId() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+func requestIdFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdContextKey).(string)
+	return requestId
+}
+
+// NewAuthMiddleware resolves the caller's "User Id" from the "Authorization: Bearer <token>" header
+// and rejects the request with 401 when the token is missing, invalid or expired.
+func NewAuthMiddleware(authSrv service.AuthService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accessToken, ok := strings.CutPrefix(r.Header.Get("authorization"), "Bearer ")
+			if !ok || accessToken == "" {
+				handlerError(w, r, errs.NewUnauthorizedError("Missing access token"))
+				return
+			}
+			userId, err := authSrv.ParseToken(accessToken)
+			if err != nil {
+				handlerError(w, r, err)
+				return
+			}
+			ctx := context.WithValue(r.Context(), userIdContextKey, userId)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func userIdFromContext(ctx context.Context) string {
+	userId, _ := ctx.Value(userIdContextKey).(string)
+	return userId
+}
+
+func checkOwner(r *http.Request, userId string) error {
+	if userIdFromContext(r.Context()) != userId {
+		return errs.NewPermissionDeniedError()
+	}
+	return nil
+}
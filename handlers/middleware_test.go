@@ -0,0 +1,98 @@
+package handler_test
+
+import (
+	"go-nutritioncalculator2/errs"
+	handler "go-nutritioncalculator2/handlers"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		authSrv := service.NewAuthServiceMock()
+		authSrv.On("ParseToken", "token").Return("gooddy20", nil)
+		srv := service.NewRecordServiceMock()
+		srv.On("GetAllRecordsByUserId", "gooddy20", service.RecordQuery{}).Return(&service.RecordPageResponse{Records: []service.RecordResponse{}}, nil)
+		hdlr := handler.NewRecordHandler(srv)
+		r := mux.NewRouter()
+		r.Use(handler.NewAuthMiddleware(authSrv))
+		r.HandleFunc("/record/{user_id}", hdlr.GetRecordsByUserId).Methods("GET")
+		req := httptest.NewRequest("GET", "/record/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+	t.Run("Missing Access Token", func(t *testing.T) {
+		authSrv := service.NewAuthServiceMock()
+		srv := service.NewRecordServiceMock()
+		hdlr := handler.NewRecordHandler(srv)
+		r := mux.NewRouter()
+		r.Use(handler.NewAuthMiddleware(authSrv))
+		r.HandleFunc("/record/{user_id}", hdlr.GetRecordsByUserId).Methods("GET")
+		req := httptest.NewRequest("GET", "/record/gooddy20", nil)
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, "Missing access token", errorOf(res).Message)
+		assert.Equal(t, errs.CodeUnauthorized, errorOf(res).Code)
+		authSrv.AssertNotCalled(t, "ParseToken")
+		srv.AssertNotCalled(t, "GetAllRecordsByUserId")
+	})
+	t.Run("Invalid Access Token", func(t *testing.T) {
+		authSrv := service.NewAuthServiceMock()
+		authSrv.On("ParseToken", "expired").Return("", errs.NewUnauthorizedError("Invalid or expired access token"))
+		srv := service.NewRecordServiceMock()
+		hdlr := handler.NewRecordHandler(srv)
+		r := mux.NewRouter()
+		r.Use(handler.NewAuthMiddleware(authSrv))
+		r.HandleFunc("/record/{user_id}", hdlr.GetRecordsByUserId).Methods("GET")
+		req := httptest.NewRequest("GET", "/record/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer expired")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, "Invalid or expired access token", errorOf(res).Message)
+		srv.AssertNotCalled(t, "GetAllRecordsByUserId")
+	})
+}
+
+func TestRequestIdMiddleware(t *testing.T) {
+	t.Run("Keep Incoming Request Id", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("DeleteRecord", "gooddy20", 1, 0).Return(errs.NewNotFoundError(errs.CodeRecordNotFound, "Record Id is not found"))
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.Use(handler.NewRequestIdMiddleware())
+		r.HandleFunc("/record/{record_id}", hdlr.DeleteRecord).Methods("DELETE")
+		req := httptest.NewRequest("DELETE", "/record/1", nil)
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add(handler.RequestIdHeader, "req-42")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, "req-42", res.Header().Get(handler.RequestIdHeader))
+		assert.Equal(t, handler.ErrorBody{Code: errs.CodeRecordNotFound, Message: "Record Id is not found", RequestId: "req-42"}, errorOf(res))
+	})
+	t.Run("Generate Request Id", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("DeleteRecord", "gooddy20", 1, 0).Return(errs.NewUnexpectedError())
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.Use(handler.NewRequestIdMiddleware())
+		r.HandleFunc("/record/{record_id}", hdlr.DeleteRecord).Methods("DELETE")
+		req := httptest.NewRequest("DELETE", "/record/1", nil)
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add(handler.RequestIdHeader, "not a safe id\n")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		requestId := res.Header().Get(handler.RequestIdHeader)
+		assert.Regexp(t, "^[0-9a-f]{16}$", requestId)
+		assert.Equal(t, requestId, errorOf(res).RequestId)
+	})
+}
@@ -8,71 +8,70 @@ import (
 )
 
 type multiHandler struct {
-	menuSrv    service.MenuService
-	userSrv    service.UserService
-	favListSrv service.FavListService
+	recoverSrv service.RecoverService
 }
 
 type MultiRequest struct {
-	UserId        string `json:"user_id" example:"gooddy20" binding:"required"`  // "User Id" that want to recover the deleted "Menu"
-	DeletedMenuId int    `json:"deleted_menu_id" example:"9" binding:"required"` // "Menu"'s id that was deleted
-	NewMenuName   string `json:"new_menu_name" example:"Moo Yang V2"`            // New name of recovered "Menu"
-	IsCreate      int    `json:"is_create" example:"1" binding:"required"`       // 1 = Want to create new "Menu" for replace "Menu" in the "Favorite List", 0 = Dont want to create new "Menu" so the "Favorite List" that contain the deleted "Menu" will be updated by get the "Menu" off
+	DeletedMenuId int    `json:"deleted_menu_id" example:"9" binding:"required,gt=0"` // "Menu"'s id that was deleted
+	NewMenuName   string `json:"new_menu_name" example:"Moo Yang V2"`                 // New name of recovered "Menu"
+	IsCreate      int    `json:"is_create" example:"1" binding:"min=0,max=1"`         // 1 = Want to create new "Menu" for replace "Menu" in the "Favorite List", 0 (default) = Dont want to create new "Menu" so the "Favorite List" that contain the deleted "Menu" will be updated by get the "Menu" off
 }
 
-func NewMultiHandler(menuSrv service.MenuService, userSrv service.UserService, favListSrv service.FavListService) multiHandler {
-	return multiHandler{menuSrv: menuSrv, userSrv: userSrv, favListSrv: favListSrv}
+// validate checks the request before it reaches the service, which only gets its values
+func (request MultiRequest) validate() error {
+	details := []errs.FieldError{}
+	if request.DeletedMenuId == 0 {
+		details = append(details, errs.FieldError{Field: "deleted_menu_id", Message: "Deleted menu Id is required"})
+	} else if request.DeletedMenuId < 0 {
+		details = append(details, errs.FieldError{Field: "deleted_menu_id", Message: "Deleted menu Id need to be positive"})
+	}
+	if request.IsCreate != 0 && request.IsCreate != 1 {
+		details = append(details, errs.FieldError{Field: "is_create", Message: "Is create need to be between 0 and 1"})
+	}
+	if len(details) != 0 {
+		return errs.NewValidationErrors(details)
+	}
+	return nil
+}
+
+func NewMultiHandler(recoverSrv service.RecoverService) multiHandler {
+	return multiHandler{recoverSrv: recoverSrv}
 }
 
 // RecoverDeletedMenu ... Recover a deleted "Menu"
 // @Summary Recover a deleted "Menu"
-// @Description Get the deleted `Menu` off from `Favorite Menu` and {1. replace the deleted `Menu` in `Favorite List` with the new `Menu` that has the same detail (Can change the "Menu"'s name) / 2. get the deleted `Menu` off from `Favorite List`}
+// @Description Get the deleted `Menu` off from `Favorite Menu` and {1. replace the deleted `Menu` in `Favorite List` with the new `Menu` that has the same detail owned by the `User` (Can change the "Menu"'s name) / 2. get the deleted `Menu` off from `Favorite List`}, nothing is changed when one of the steps fails
 // @Tags Recover
+// @Security BearerAuth
 // @Accept json
 // @Param request body MultiRequest true "The data detail that you want"
 // @Response 200
-// @Response 406 "Request Body Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 404 {object} ErrorResponse "The deleted `Menu Id` is not found"
+// @Response 409 {object} ErrorResponse "The `Menu` is not deleted or it is already replaced by a newer version"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /recover/ [put]
 func (h multiHandler) RecoverDeletedMenu(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request MultiRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
 		return
 	}
-	userId := request.UserId
-	deletedMenuId := request.DeletedMenuId
-	newMenuName := request.NewMenuName
-	isCreateMenu := request.IsCreate
-	var newMenuId int
-	if isCreateMenu == 1 {
-		newMenu, err := h.menuSrv.RecoverMenu(deletedMenuId, newMenuName)
-		if err != nil {
-			handlerError(w, err)
-			return
-		}
-		newMenuId = newMenu.Id
-	}
-	err = h.userSrv.RecoverFavoriteMenues(userId, deletedMenuId)
+	err = request.validate()
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
-	favLists, err := h.favListSrv.GetFavListsByUserId(userId)
+	err = h.recoverSrv.RecoverDeletedMenu(r.Context(), userIdFromContext(r.Context()), request.DeletedMenuId, request.NewMenuName, request.IsCreate == 1)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
-	for i := 0; i < len(favLists); i++ {
-		err = h.favListSrv.RecoverFavList(favLists[i].Id, deletedMenuId, newMenuId)
-		if err != nil {
-			handlerError(w, err)
-			return
-		}
-	}
 }
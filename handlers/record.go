@@ -22,97 +22,162 @@ func NewRecordHandler(recordSrv service.RecordService) recordHandler {
 // @Summary Create a "Record"
 // @Description Create a 'Record'
 // @Tags Record
+// @Security BearerAuth
 // @Accept json
+// @Produce json
 // @Param request body service.NewRecordRequest true "`Record`'s data detail"
-// @Response 200
-// @Response 406 "Request Body Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Param Idempotency-Key header string false "Key that the client generated for the request e.g. a UUID, a retry with the same key gets the first response back instead of creating it again"
+// @Response 200 {object} service.CreatedResponse "Id and revision of the created `Record`"
+// @Header 200 {string} ETag "Revision of the `Record` in quotes"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 409 {object} ErrorResponse "The request with the `Idempotency-Key` is still in progress"
+// @Response 422 {object} ErrorResponse "`Idempotency-Key` is already used by another request"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /record/ [post]
 func (h recordHandler) CreateRecord(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request service.NewRecordRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
 		return
 	}
-	err = h.recordSrv.CreateRecord(request)
+	request.UserId = userIdFromContext(r.Context())
+	response, err := h.recordSrv.CreateRecord(r.Context(), request)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
+	writeJSON(w, r, revisionETag(response.Revision), response)
 }
 
 // DeleteRecord ... Delete a "Record"
 // @Summary Delete a "Record"
 // @Description Delete a 'Record'
 // @Tags Record
+// @Security BearerAuth
 // @Param record_id path int true "`Record`'s id that you want to delete"
+// @Param If-Match header string false "Quoted `revision` of the `Record` that the change is based on, no header or `*` = any revision"
 // @Response 200
-// @Response 406 "Request parameters Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable or `If-Match` is not an ETag"
+// @Response 404 {object} ErrorResponse "`Record`'s id is not found"
+// @Response 412 {object} ErrorResponse "`Record` has been changed since the ETag in `If-Match`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /record/{record_id} [delete]
 func (h recordHandler) DeleteRecord(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	recordId, err := strconv.ParseInt(vars["record_id"], 0, 0)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Parse data type error"})
+		handlerError(w, r, errs.NewValidationError("record_id", "Parse data type error"))
 		return
 	}
-	err = h.recordSrv.DeleteRecord(int(recordId))
+	revision, err := ifMatchRevision(r)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
+		return
+	}
+	err = h.recordSrv.DeleteRecord(r.Context(), userIdFromContext(r.Context()), int(recordId), revision)
+	if err != nil {
+		handlerError(w, r, err)
 		return
 	}
 }
 
 // UpdateRecord ... Update a "Record"
 // @Summary Update a "Record"
-// @Description Update a 'Record'
+// @Description Update a 'Record', a field that is omitted is kept and a field that is null is cleared
 // @Tags Record
+// @Security BearerAuth
 // @Accept json
 // @Param request body service.UpdateRecordRequest true "`Record`'s data detail that you want to change to"
+// @Param If-Match header string false "Quoted `revision` of the `Record` that the change is based on, no header or `*` = any revision"
 // @Response 200
-// @Response 406 "Request Body Not Acceptable or `Record`'s id is not found"
-// @Response 500 "Internal Server Error"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable or `If-Match` is not an ETag"
+// @Response 404 {object} ErrorResponse "`Record`'s id is not found"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 412 {object} ErrorResponse "`Record` has been changed since the ETag in `If-Match`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /record/ [put]
+// @Router /record/ [patch]
 func (h recordHandler) UpdateRecord(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request service.UpdateRecordRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
 		return
 	}
-	err = h.recordSrv.UpdateRecord(request)
+	request.Revision, err = ifMatchRevision(r)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
+		return
+	}
+	err = h.recordSrv.UpdateRecord(r.Context(), userIdFromContext(r.Context()), request)
+	if err != nil {
+		handlerError(w, r, err)
 		return
 	}
 }
 
-// GetRecordsByUserId ... Get all "Record" of "User"
-// @Summary Get all "Record" of "User"
-// @Description Get all `Record` of `User` by `User Id`
+// GetRecordsByUserId ... Get "Record" of "User"
+// @Summary Get "Record" of "User"
+// @Description Get a page of `Record` of `User` by `User Id` ordered by the event timestamp, pass `next_cursor` of the page as `cursor` to get the next page
 // @Tags Record
+// @Security BearerAuth
 // @Produce json
 // @Param user_id path string true "`User Id` that you want to get `Record`"
-// @Response 200 {object} []service.RecordResponse
-// @Response 500 "Internal Server Error"
+// @Param from query string false "Only `Record` that is eaten from this timestamp (inclusive) *format=`2023-01-01` or `2023-01-01 00:00:00`"
+// @Param to query string false "Only `Record` that is eaten before this timestamp (exclusive) *format=`2023-01-01` or `2023-01-01 00:00:00`"
+// @Param limit query int false "Maximum amount of `Record` in the page, 1 - 200, default = 50"
+// @Param cursor query string false "`next_cursor` of the previous page"
+// @Param sort query string false "`desc` (default) = newest first, `asc` = oldest first"
+// @Param If-None-Match header string false "`ETag` of the page that you already have"
+// @Response 200 {object} service.RecordPageResponse
+// @Header 200 {string} ETag "Hash of the body"
+// @Response 304 "Not Modified, the ETag in `If-None-Match` is still current"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable or `If-None-Match` is not a list of ETags"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /record/{user_id} [get]
 func (h recordHandler) GetRecordsByUserId(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	response, err := h.recordSrv.GetAllRecordsByUserId(vars["user_id"])
+	err := checkOwner(r, vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	query := r.URL.Query()
+	recordQuery := service.RecordQuery{
+		From:   query.Get("from"),
+		To:     query.Get("to"),
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+	}
+	if query.Get("limit") != "" {
+		limit, err := strconv.ParseInt(query.Get("limit"), 0, 0)
+		if err != nil {
+			handlerError(w, r, errs.NewValidationError("limit", "Parse data type error"))
+			return
+		}
+		recordQuery.Limit = int(limit)
+	}
+	response, err := h.recordSrv.GetAllRecordsByUserId(r.Context(), vars["user_id"], recordQuery)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
-	w.Header().Set("content-type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, r, "", response)
 }
@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"go-nutritioncalculator2/errs"
 	handler "go-nutritioncalculator2/handlers"
@@ -12,7 +13,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,9 +25,9 @@ func TestCreateRecord(t *testing.T) {
 			Note:           "Breakfast",
 			Weight:         70,
 			EventTimestamp: "2023-12-05 10:00:00",
-		}).Return(nil)
+		}).Return(&service.CreatedResponse{Id: 12, Revision: 1}, nil)
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/", hdlr.CreateRecord).Methods("POST")
 		preReqBody := map[string]interface{}{
 			"user_id":         "gooddy20",
@@ -38,6 +38,33 @@ func TestCreateRecord(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `"1"`, res.Header().Get("etag"))
+		assert.JSONEq(t, `{"id":12,"revision":1}`, res.Body.String())
+	})
+	t.Run("Success Case: Items", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("CreateRecord", service.NewRecordRequest{
+			UserId:         "gooddy20",
+			Items:          []service.Item{{MenuId: 9, Quantity: 2}, {MenuId: 10, Quantity: 1}},
+			Note:           "Breakfast",
+			EventTimestamp: "2023-12-05 10:00:00",
+		}).Return(&service.CreatedResponse{Id: 12, Revision: 1}, nil)
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/record/", hdlr.CreateRecord).Methods("POST")
+		preReqBody := map[string]interface{}{
+			"items":           []map[string]int{{"menu_id": 9, "quantity": 2}, {"menu_id": 10, "quantity": 1}},
+			"note":            "Breakfast",
+			"event_timestamp": "2023-12-05 10:00:00",
+		}
+		reqBody, _ := json.Marshal(preReqBody)
+		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
@@ -46,7 +73,7 @@ func TestCreateRecord(t *testing.T) {
 	t.Run("Incorrect Request Header", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/", hdlr.CreateRecord).Methods("POST")
 		preReqBody := map[string]interface{}{
 			"user_id":         "gooddy20",
@@ -57,24 +84,26 @@ func TestCreateRecord(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Header", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CreateRecord")
 	})
 	t.Run("Incorrect Request Body", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/", hdlr.CreateRecord).Methods("POST")
 		reqBody := []byte("")
 		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Body", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CreateRecord")
 	})
 	t.Run("Service Error", func(t *testing.T) {
@@ -85,9 +114,9 @@ func TestCreateRecord(t *testing.T) {
 			Note:           "Breakfast",
 			Weight:         70,
 			EventTimestamp: "2023-12-05 10:00:00",
-		}).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		}).Return((*service.CreatedResponse)(nil), errs.NewUnexpectedError())
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/", hdlr.CreateRecord).Methods("POST")
 		preReqBody := map[string]interface{}{
 			"user_id":         "gooddy20",
@@ -98,22 +127,37 @@ func TestCreateRecord(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("POST", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
 func TestDeleteRecord(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
-		srv.On("DeleteRecord", 1).Return(nil)
+		srv.On("DeleteRecord", "gooddy20", 1, 0).Return(nil)
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/{record_id}", hdlr.DeleteRecord).Methods("DELETE")
 		req := httptest.NewRequest("DELETE", "/record/1", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+	t.Run("Success Case: If-Match", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("DeleteRecord", "gooddy20", 1, 3).Return(nil)
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/record/{record_id}", hdlr.DeleteRecord).Methods("DELETE")
+		req := httptest.NewRequest("DELETE", "/record/1", nil)
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("if-match", `"3"`)
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusOK, res.Code)
@@ -121,41 +165,59 @@ func TestDeleteRecord(t *testing.T) {
 	t.Run("Parse Id (String to Int) Error", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/{record_id}", hdlr.DeleteRecord).Methods("DELETE")
 		req := httptest.NewRequest("DELETE", "/record/1.1", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Parse data type error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Parse data type error", errorOf(res).Message)
+		assert.Equal(t, errs.CodeValidationFailed, errorOf(res).Code)
+		assert.Equal(t, []errs.FieldError{{Field: "record_id", Message: "Parse data type error"}}, errorOf(res).Details)
 		srv.AssertNotCalled(t, "DeleteRecord")
 	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
-		srv.On("DeleteRecord", 1).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv.On("DeleteRecord", "gooddy20", 1, 0).Return(errs.NewUnexpectedError())
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/record/{record_id}", hdlr.DeleteRecord).Methods("DELETE")
+		req := httptest.NewRequest("DELETE", "/record/1", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
+	t.Run("Error That Is Not an AppError", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("DeleteRecord", "gooddy20", 1, 0).Return(sql.ErrConnDone)
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/{record_id}", hdlr.DeleteRecord).Methods("DELETE")
 		req := httptest.NewRequest("DELETE", "/record/1", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "application/json", res.Header().Get("content-type"))
+		assert.Equal(t, handler.ErrorBody{Code: errs.CodeInternal, Message: "Unexpected error"}, errorOf(res))
 	})
 }
 
 func TestUpdateRecord(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
-		srv.On("UpdateRecord", service.UpdateRecordRequest{
+		srv.On("UpdateRecord", "gooddy20", service.UpdateRecordRequest{
 			Id:             1,
-			List:           "9,9,10,11",
-			Note:           "Breakfast + Juice",
-			Weight:         0,
-			EventTimestamp: "2023-12-05 10:20:00",
+			List:           service.NewNullable("9,9,10,11"),
+			Note:           service.NewNullable("Breakfast + Juice"),
+			Weight:         service.NewNullable[float64](0),
+			EventTimestamp: service.NewNullable("2023-12-05 10:20:00"),
 		}).Return(nil)
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/", hdlr.UpdateRecord).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"id":              1,
@@ -166,15 +228,87 @@ func TestUpdateRecord(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusOK, res.Code)
 	})
+	t.Run("Success Case: Patch With Null", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("UpdateRecord", "gooddy20", service.UpdateRecordRequest{
+			Id:   1,
+			Note: service.NewNull[string](),
+		}).Return(nil)
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/record/", hdlr.UpdateRecord).Methods("PUT", "PATCH")
+		req := httptest.NewRequest("PATCH", "/record/", strings.NewReader(`{"id":1,"note":null}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+	t.Run("Success Case: If-Match", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("UpdateRecord", "gooddy20", service.UpdateRecordRequest{
+			Id:       1,
+			Note:     service.NewNullable("Breakfast"),
+			Revision: 3,
+		}).Return(nil)
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/record/", hdlr.UpdateRecord).Methods("PUT", "PATCH")
+		req := httptest.NewRequest("PATCH", "/record/", strings.NewReader(`{"id":1,"note":"Breakfast"}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		req.Header.Add("if-match", `"3"`)
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+	for _, ifMatch := range []string{"3", `W/"3"`, `"0"`, `"abc"`} {
+		t.Run("Invalid If-Match "+ifMatch, func(t *testing.T) {
+			srv := service.NewRecordServiceMock()
+			hdlr := handler.NewRecordHandler(srv)
+			r := newAuthRouter()
+			r.HandleFunc("/record/", hdlr.UpdateRecord).Methods("PUT", "PATCH")
+			req := httptest.NewRequest("PATCH", "/record/", strings.NewReader(`{"id":1,"note":"Breakfast"}`))
+			req.Header.Add("authorization", "Bearer token")
+			req.Header.Add("content-type", "application/json")
+			req.Header.Add("if-match", ifMatch)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, errs.CodeValidationFailed, errorOf(res).Code)
+			assert.Equal(t, []errs.FieldError{{Field: "If-Match", Message: `If-Match need to be the ETag of the resource e.g. "3"`}}, errorOf(res).Details)
+			srv.AssertNotCalled(t, "UpdateRecord")
+		})
+	}
+	t.Run("Stale Revision", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("UpdateRecord", "gooddy20", service.UpdateRecordRequest{
+			Id:       1,
+			Note:     service.NewNullable("Breakfast"),
+			Revision: 2,
+		}).Return(errs.NewPreconditionFailedError("Record has been changed by another request, get it again and retry"))
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/record/", hdlr.UpdateRecord).Methods("PUT", "PATCH")
+		req := httptest.NewRequest("PATCH", "/record/", strings.NewReader(`{"id":1,"note":"Breakfast"}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		req.Header.Add("if-match", `"2"`)
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusPreconditionFailed, res.Code)
+		assert.Equal(t, errs.CodePreconditionFailed, errorOf(res).Code)
+	})
 	t.Run("Incorrect Request Header", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/", hdlr.UpdateRecord).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"id":              1,
@@ -185,37 +319,39 @@ func TestUpdateRecord(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Header", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
 		srv.AssertNotCalled(t, "UpdateRecord")
 	})
 	t.Run("Incorrect Request Body", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/", hdlr.UpdateRecord).Methods("PUT")
 		reqBody := []byte("")
 		req := httptest.NewRequest("PUT", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Body", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
 		srv.AssertNotCalled(t, "UpdateRecord")
 	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
-		srv.On("UpdateRecord", service.UpdateRecordRequest{
+		srv.On("UpdateRecord", "gooddy20", service.UpdateRecordRequest{
 			Id:             1,
-			List:           "9,9,10,11",
-			Note:           "Breakfast + Juice",
-			Weight:         0,
-			EventTimestamp: "2023-12-05 10:20:00",
-		}).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+			List:           service.NewNullable("9,9,10,11"),
+			Note:           service.NewNullable("Breakfast + Juice"),
+			Weight:         service.NewNullable[float64](0),
+			EventTimestamp: service.NewNullable("2023-12-05 10:20:00"),
+		}).Return(errs.NewUnexpectedError())
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/", hdlr.UpdateRecord).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"id":              1,
@@ -226,18 +362,19 @@ func TestUpdateRecord(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/record/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
 func TestGetRecordsByUserId(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
-		srv.On("GetAllRecordsByUserId", "gooddy20").Return([]service.RecordResponse{
+		srv.On("GetAllRecordsByUserId", "gooddy20", service.RecordQuery{}).Return(&service.RecordPageResponse{Records: []service.RecordResponse{
 			{Id: 1,
 				List:           "9,9,10",
 				Menues:         "Moo Yang-2 ,Sticky Rice-1 ",
@@ -258,16 +395,17 @@ func TestGetRecordsByUserId(t *testing.T) {
 				Carb:           65,
 				EventTimestamp: time.Date(2023, 12, 5, 12, 30, 0, 0, time.UTC).UTC(),
 				IsUpdated:      1},
-		}, nil)
+		}, NextCursor: "ZGVzYzoxNzAxNzc3NDAwMDAwMDAwMDAwOjI"}, nil)
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/{user_id}", hdlr.GetRecordsByUserId).Methods("GET")
 		req := httptest.NewRequest("GET", "/record/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		resultBody := []service.RecordResponse{}
+		resultBody := service.RecordPageResponse{}
 		_ = json.Unmarshal(res.Body.Bytes(), &resultBody)
-		expectedBody := []service.RecordResponse{
+		expectedBody := service.RecordPageResponse{Records: []service.RecordResponse{
 			{Id: 1,
 				List:           "9,9,10",
 				Menues:         "Moo Yang-2 ,Sticky Rice-1 ",
@@ -288,20 +426,88 @@ func TestGetRecordsByUserId(t *testing.T) {
 				Carb:           65,
 				EventTimestamp: time.Date(2023, 12, 5, 12, 30, 0, 0, time.UTC).UTC(),
 				IsUpdated:      1},
-		}
+		}, NextCursor: "ZGVzYzoxNzAxNzc3NDAwMDAwMDAwMDAwOjI"}
 		assert.Equal(t, http.StatusOK, res.Code)
 		assert.Equal(t, expectedBody, resultBody)
 	})
+	t.Run("Success Case: Not Modified", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("GetAllRecordsByUserId", "gooddy20", service.RecordQuery{}).Return(&service.RecordPageResponse{Records: []service.RecordResponse{
+			{Id: 1, List: "9,9,10", Revision: 2},
+		}}, nil)
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/record/{user_id}", hdlr.GetRecordsByUserId).Methods("GET")
+		req := httptest.NewRequest("GET", "/record/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		etag := res.Header().Get("etag")
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.NotEmpty(t, etag)
+
+		req = httptest.NewRequest("GET", "/record/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("if-none-match", `"other", W/`+etag)
+		res = httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusNotModified, res.Code)
+		assert.Equal(t, etag, res.Header().Get("etag"))
+		assert.Empty(t, res.Body.String())
+	})
+	for _, ifNoneMatch := range []string{"3", `"3", 4`, `W/3`, `"3"4"`} {
+		t.Run("Invalid If-None-Match "+ifNoneMatch, func(t *testing.T) {
+			srv := service.NewRecordServiceMock()
+			srv.On("GetAllRecordsByUserId", "gooddy20", service.RecordQuery{}).Return(&service.RecordPageResponse{}, nil)
+			hdlr := handler.NewRecordHandler(srv)
+			r := newAuthRouter()
+			r.HandleFunc("/record/{user_id}", hdlr.GetRecordsByUserId).Methods("GET")
+			req := httptest.NewRequest("GET", "/record/gooddy20", nil)
+			req.Header.Add("authorization", "Bearer token")
+			req.Header.Add("if-none-match", ifNoneMatch)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, errs.CodeValidationFailed, errorOf(res).Code)
+			assert.Equal(t, "If-None-Match", errorOf(res).Details[0].Field)
+		})
+	}
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewRecordServiceMock()
-		srv.On("GetAllRecordsByUserId", "gooddy20").Return([]service.RecordResponse{}, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv.On("GetAllRecordsByUserId", "gooddy20", service.RecordQuery{}).Return(&service.RecordPageResponse{}, errs.NewUnexpectedError())
 		hdlr := handler.NewRecordHandler(srv)
-		r := mux.NewRouter()
+		r := newAuthRouter()
 		r.HandleFunc("/record/{user_id}", hdlr.GetRecordsByUserId).Methods("GET")
 		req := httptest.NewRequest("GET", "/record/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
+	t.Run("Success Case: Query Parameters", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		srv.On("GetAllRecordsByUserId", "gooddy20", service.RecordQuery{From: "2023-12-01", To: "2023-12-05 12:00:00", Limit: 20, Cursor: "abc", Sort: "asc"}).Return(&service.RecordPageResponse{Records: []service.RecordResponse{}}, nil)
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/record/{user_id}", hdlr.GetRecordsByUserId).Methods("GET")
+		req := httptest.NewRequest("GET", "/record/gooddy20?from=2023-12-01&to=2023-12-05+12:00:00&limit=20&cursor=abc&sort=asc", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `{"records":[],"next_cursor":""}`, strings.Replace(res.Body.String(), "\n", "", -1))
+	})
+	t.Run("Invalid Limit", func(t *testing.T) {
+		srv := service.NewRecordServiceMock()
+		hdlr := handler.NewRecordHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/record/{user_id}", hdlr.GetRecordsByUserId).Methods("GET")
+		req := httptest.NewRequest("GET", "/record/gooddy20?limit=ten", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		srv.AssertNotCalled(t, "GetAllRecordsByUserId")
 	})
 }
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"encoding/json"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type reportHandler struct {
+	reportSrv service.ReportService
+}
+
+func NewReportHandler(reportSrv service.ReportService) reportHandler {
+	return reportHandler{reportSrv: reportSrv}
+}
+
+// GetReport ... Get the nutrition report of "User"
+// @Summary Get the nutrition report of "User"
+// @Description Total nutrition of each day from `from` to `to` in the `User`'s time zone with the average, min, max, standard deviation and adherence to the `User`'s target e.g. a week or a month
+// @Tags Summary
+// @Security BearerAuth
+// @Produce json
+// @Param user_id path string true "`User Id` that you want to get the report"
+// @Param from query string true "First day of the report *format=`2023-01-01`"
+// @Param to query string true "Last day of the report *format=`2023-01-01`, atmost 366 days after `from`"
+// @Response 200 {object} service.ReportResponse
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /report/{user_id} [get]
+func (h reportHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	err := checkOwner(r, vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	query := r.URL.Query()
+	response, err := h.reportSrv.GetReport(r.Context(), vars["user_id"], query.Get("from"), query.Get("to"))
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,73 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	handler "go-nutritioncalculator2/handlers"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReport(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		report := &service.ReportResponse{
+			UserId:   "gooddy20",
+			From:     "2023-12-04",
+			To:       "2023-12-05",
+			Timezone: "UTC",
+			Target:   service.NutritionTotal{Protein: 120, Fat: 60, Carb: 150, Kcal: 1620},
+			Days: []service.DailyTotal{
+				{Date: "2023-12-04", RecordIds: []int{1}, Consumed: service.NutritionTotal{Protein: 115, Fat: 62, Carb: 140, Kcal: 1578}, IsLogged: true, IsAdherent: true},
+				{Date: "2023-12-05", RecordIds: []int{}},
+			},
+			LoggedDays:   1,
+			Average:      service.NutritionTotal{Protein: 115, Fat: 62, Carb: 140, Kcal: 1578},
+			Min:          service.NutritionTotal{Protein: 115, Fat: 62, Carb: 140, Kcal: 1578},
+			Max:          service.NutritionTotal{Protein: 115, Fat: 62, Carb: 140, Kcal: 1578},
+			AdherentDays: 1,
+			Adherence:    100,
+		}
+		srv := service.NewReportServiceMock()
+		srv.On("GetReport", "gooddy20", "2023-12-04", "2023-12-05").Return(report, nil)
+		hdlr := handler.NewReportHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/report/{user_id}", hdlr.GetReport).Methods("GET")
+		req := httptest.NewRequest("GET", "/report/gooddy20?from=2023-12-04&to=2023-12-05", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		resultBody := &service.ReportResponse{}
+		_ = json.Unmarshal(res.Body.Bytes(), resultBody)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, report, resultBody)
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		srv := service.NewReportServiceMock()
+		hdlr := handler.NewReportHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/report/{user_id}", hdlr.GetReport).Methods("GET")
+		req := httptest.NewRequest("GET", "/report/kornkoko?from=2023-12-04&to=2023-12-05", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		srv.AssertNotCalled(t, "GetReport")
+	})
+	t.Run("Service Error", func(t *testing.T) {
+		srv := service.NewReportServiceMock()
+		srv.On("GetReport", "gooddy20", "2023-12-04", "").Return(&service.ReportResponse{}, errs.NewValidationError("to", `From and To need to be in the format "2023-01-01"`))
+		hdlr := handler.NewReportHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/report/{user_id}", hdlr.GetReport).Methods("GET")
+		req := httptest.NewRequest("GET", "/report/gooddy20?from=2023-12-04", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, `From and To need to be in the format "2023-01-01"`, errorOf(res).Message)
+	})
+}
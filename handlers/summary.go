@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type summaryHandler struct {
+	summarySrv service.SummaryService
+}
+
+func NewSummaryHandler(summarySrv service.SummaryService) summaryHandler {
+	return summaryHandler{summarySrv: summarySrv}
+}
+
+// GetDailySummary ... Get the daily nutrition summary of "User"
+// @Summary Get the daily nutrition summary of "User"
+// @Description Sum every `Record` of the calendar day in the `User`'s time zone and compare it with the `User`'s target
+// @Tags Summary
+// @Security BearerAuth
+// @Produce json
+// @Param user_id path string true "`User Id` that you want to get the summary"
+// @Param date query string false "Calendar day of the summary *format=`2023-01-01`, default = today"
+// @Response 200 {object} service.DailySummaryResponse
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /summary/{user_id}/daily [get]
+func (h summaryHandler) GetDailySummary(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	err := checkOwner(r, vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	response, err := h.summarySrv.GetDailySummary(r.Context(), vars["user_id"], r.URL.Query().Get("date"))
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,67 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	handler "go-nutritioncalculator2/handlers"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDailySummary(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		summary := &service.DailySummaryResponse{
+			UserId:    "gooddy20",
+			Date:      "2023-12-05",
+			Timezone:  "Asia/Bangkok",
+			Consumed:  service.NutritionTotal{Protein: 40, Fat: 10, Carb: 20, Kcal: 330},
+			Target:    service.NutritionTotal{Protein: 120, Fat: 60, Carb: 150, Kcal: 1620},
+			Remaining: service.NutritionTotal{Protein: 80, Fat: 50, Carb: 130, Kcal: 1290},
+			Meals: []service.MealSummary{
+				{Note: "Breakfast", RecordIds: []int{1}, Consumed: service.NutritionTotal{Protein: 40, Fat: 10, Carb: 20, Kcal: 330}},
+			},
+		}
+		srv := service.NewSummaryServiceMock()
+		srv.On("GetDailySummary", "gooddy20", "2023-12-05").Return(summary, nil)
+		hdlr := handler.NewSummaryHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/summary/{user_id}/daily", hdlr.GetDailySummary).Methods("GET")
+		req := httptest.NewRequest("GET", "/summary/gooddy20/daily?date=2023-12-05", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		resultBody := &service.DailySummaryResponse{}
+		_ = json.Unmarshal(res.Body.Bytes(), resultBody)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, summary, resultBody)
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		srv := service.NewSummaryServiceMock()
+		hdlr := handler.NewSummaryHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/summary/{user_id}/daily", hdlr.GetDailySummary).Methods("GET")
+		req := httptest.NewRequest("GET", "/summary/kornkoko/daily", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		srv.AssertNotCalled(t, "GetDailySummary")
+	})
+	t.Run("Service Error", func(t *testing.T) {
+		srv := service.NewSummaryServiceMock()
+		srv.On("GetDailySummary", "gooddy20", "").Return(&service.DailySummaryResponse{}, errs.NewUnexpectedError())
+		hdlr := handler.NewSummaryHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/summary/{user_id}/daily", hdlr.GetDailySummary).Methods("GET")
+		req := httptest.NewRequest("GET", "/summary/gooddy20/daily", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
+}
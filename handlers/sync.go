@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+)
+
+type syncHandler struct {
+	syncSrv service.SyncService
+}
+
+func NewSyncHandler(syncSrv service.SyncService) syncHandler {
+	return syncHandler{syncSrv: syncSrv}
+}
+
+// GetChanges ... Get the changes of "User" since the previous sync
+// @Summary Get the changes of "User" since the previous sync
+// @Description Get every `Record`, `Favorite List` and `Favorite Menu` of the `User` that is created, updated or deleted since the cursor with the `Menu` that they use, a deleted row is returned with `deleted` = true (`status` = 0 for `Menu`) so the offline client can delete it too, no `since` = every row for the first sync and pass `next_cursor` as `since` to the next sync
+// @Tags Sync
+// @Security BearerAuth
+// @Produce json
+// @Param since query string false "`next_cursor` of the previous sync"
+// @Response 200 {object} service.SyncChangesResponse
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /sync [get]
+func (h syncHandler) GetChanges(w http.ResponseWriter, r *http.Request) {
+	response, err := h.syncSrv.GetChanges(r.Context(), userIdFromContext(r.Context()), r.URL.Query().Get("since"))
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ApplyMutations ... Apply the changes that the client made offline
+// @Summary Apply the changes that the client made offline
+// @Description Apply a batch of `upsert` and `delete` of `Record` and `Favorite List` in order, a row that the client created offline is sent with the UUID that the client generated as `client_id` so a retried create is `applied` again without a second row. The server's row wins a conflict: a change that is based on an older `base_revision` than the server's one or a row that is deleted on the server is not applied and its result has the server's row. A change that can not be saved e.g. it uses a deleted `Menu` is `rejected` without stopping the others, and an unexpected error saves none of the batch so that it can be retried as a whole
+// @Tags Sync
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body service.SyncRequest true "Changes that the client made offline"
+// @Response 200 {object} service.SyncResponse
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 500 {object} ErrorResponse "Internal Server Error, none of the mutations is saved"
+// @Router /sync [post]
+func (h syncHandler) ApplyMutations(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("content-type") != "application/json" {
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
+		return
+	}
+	var request service.SyncRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handlerError(w, r, errs.NewMalformedBodyError())
+		return
+	}
+	response, err := h.syncSrv.ApplyMutations(r.Context(), userIdFromContext(r.Context()), request)
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
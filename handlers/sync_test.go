@@ -0,0 +1,154 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	handler "go-nutritioncalculator2/handlers"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChanges(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		srv := service.NewSyncServiceMock()
+		srv.On("GetChanges", "gooddy20", "c3luYzo3").Return(&service.SyncChangesResponse{
+			Records:    []service.SyncRecordResponse{{RecordResponse: service.RecordResponse{Id: 12}, Deleted: true}},
+			FavLists:   []service.SyncFavListResponse{},
+			Menues:     []service.MenuResponse{},
+			NextCursor: "c3luYzo5",
+		}, nil)
+		hdlr := handler.NewSyncHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/sync", hdlr.GetChanges).Methods("GET")
+		req := httptest.NewRequest("GET", "/sync?since=c3luYzo3", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var body service.SyncChangesResponse
+		json.Unmarshal(res.Body.Bytes(), &body)
+		assert.Equal(t, "c3luYzo5", body.NextCursor)
+		assert.True(t, body.Records[0].Deleted)
+		assert.Nil(t, body.FavoriteMenues)
+	})
+	t.Run("Success Case: First Sync", func(t *testing.T) {
+		srv := service.NewSyncServiceMock()
+		srv.On("GetChanges", "gooddy20", "").Return(&service.SyncChangesResponse{NextCursor: "c3luYzo3"}, nil)
+		hdlr := handler.NewSyncHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/sync", hdlr.GetChanges).Methods("GET")
+		req := httptest.NewRequest("GET", "/sync", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		srv := service.NewSyncServiceMock()
+		srv.On("GetChanges", "gooddy20", "abc").Return((*service.SyncChangesResponse)(nil), errs.NewValidationError("since", "Since need to be the next_cursor of the previous sync"))
+		hdlr := handler.NewSyncHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/sync", hdlr.GetChanges).Methods("GET")
+		req := httptest.NewRequest("GET", "/sync?since=abc", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, errs.CodeValidationFailed, errorOf(res).Code)
+		assert.Equal(t, []errs.FieldError{{Field: "since", Message: "Since need to be the next_cursor of the previous sync"}}, errorOf(res).Details)
+	})
+	t.Run("Service Error", func(t *testing.T) {
+		srv := service.NewSyncServiceMock()
+		srv.On("GetChanges", "gooddy20", "").Return((*service.SyncChangesResponse)(nil), errs.NewUnexpectedError())
+		hdlr := handler.NewSyncHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/sync", hdlr.GetChanges).Methods("GET")
+		req := httptest.NewRequest("GET", "/sync", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
+}
+
+func TestApplyMutations(t *testing.T) {
+	request := service.SyncRequest{Mutations: []service.SyncMutation{{
+		Entity:   service.SyncEntityRecord,
+		Op:       service.SyncOpUpsert,
+		ClientId: "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f",
+		Record:   &service.SyncRecordData{List: "9,9,10", EventTimestamp: "2023-12-05 10:00:00"},
+	}}}
+	t.Run("Success", func(t *testing.T) {
+		srv := service.NewSyncServiceMock()
+		srv.On("ApplyMutations", "gooddy20", request).Return(&service.SyncResponse{Results: []service.SyncMutationResult{{
+			Entity:   service.SyncEntityRecord,
+			Id:       12,
+			ClientId: "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f",
+			Status:   service.SyncStatusApplied,
+			Revision: 1,
+		}}}, nil)
+		hdlr := handler.NewSyncHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/sync", hdlr.ApplyMutations).Methods("POST")
+		reqBody, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/sync", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var body service.SyncResponse
+		json.Unmarshal(res.Body.Bytes(), &body)
+		assert.Equal(t, service.SyncStatusApplied, body.Results[0].Status)
+		assert.Equal(t, 12, body.Results[0].Id)
+	})
+	t.Run("Incorrect Request Header", func(t *testing.T) {
+		srv := service.NewSyncServiceMock()
+		hdlr := handler.NewSyncHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/sync", hdlr.ApplyMutations).Methods("POST")
+		reqBody, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/sync", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
+		srv.AssertNotCalled(t, "ApplyMutations")
+	})
+	t.Run("Incorrect Request Body", func(t *testing.T) {
+		srv := service.NewSyncServiceMock()
+		hdlr := handler.NewSyncHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/sync", hdlr.ApplyMutations).Methods("POST")
+		req := httptest.NewRequest("POST", "/sync", bytes.NewBuffer([]byte("")))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
+		srv.AssertNotCalled(t, "ApplyMutations")
+	})
+	t.Run("Service Error", func(t *testing.T) {
+		srv := service.NewSyncServiceMock()
+		srv.On("ApplyMutations", "gooddy20", request).Return((*service.SyncResponse)(nil), errs.NewUnexpectedError())
+		hdlr := handler.NewSyncHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/sync", hdlr.ApplyMutations).Methods("POST")
+		reqBody, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/sync", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type targetHandler struct {
+	targetSrv service.TargetService
+}
+
+func NewTargetHandler(targetSrv service.TargetService) targetHandler {
+	return targetHandler{targetSrv: targetSrv}
+}
+
+// GetSuggestedTargets ... Get the suggested targets of "User"
+// @Summary Get the suggested targets of "User"
+// @Description BMR by the Mifflin-St Jeor equation, TDEE of the activity level and the daily protein, fat and carb targets of the goal from the `User`'s profile, send `apply_suggested_targets` = `true` to `PUT /user/userdetail` to use them
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Param user_id path string true "`User Id` that you want to get the suggested targets"
+// @Response 200 {object} service.TargetResponse
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 404 {object} ErrorResponse "`User Id` is not found"
+// @Response 422 {object} ErrorResponse "The profile is incomplete"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /target/{user_id} [get]
+func (h targetHandler) GetSuggestedTargets(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	err := checkOwner(r, vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	response, err := h.targetSrv.GetSuggestedTargets(r.Context(), vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,58 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	handler "go-nutritioncalculator2/handlers"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSuggestedTargets(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		targets := &service.TargetResponse{UserId: "gooddy20", Sex: "male", Age: 30, Weight: 70, Height: 175, ActivityLevel: "moderate", Goal: "cut",
+			Bmr: 1648.8, Tdee: 2555.6, Target: service.NutritionTotal{Protein: 154, Fat: 56.8, Carb: 229.3, Kcal: 2044.5}}
+		srv := service.NewTargetServiceMock()
+		srv.On("GetSuggestedTargets", "gooddy20").Return(targets, nil)
+		hdlr := handler.NewTargetHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/target/{user_id}", hdlr.GetSuggestedTargets).Methods("GET")
+		req := httptest.NewRequest("GET", "/target/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		resultBody := &service.TargetResponse{}
+		_ = json.Unmarshal(res.Body.Bytes(), resultBody)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, targets, resultBody)
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		srv := service.NewTargetServiceMock()
+		hdlr := handler.NewTargetHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/target/{user_id}", hdlr.GetSuggestedTargets).Methods("GET")
+		req := httptest.NewRequest("GET", "/target/kornkoko", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		srv.AssertNotCalled(t, "GetSuggestedTargets")
+	})
+	t.Run("Service Error", func(t *testing.T) {
+		srv := service.NewTargetServiceMock()
+		srv.On("GetSuggestedTargets", "gooddy20").Return(&service.TargetResponse{}, errs.NewUnprocessableError(errs.CodeProfileIncomplete, "Sex, birth date, height, weight, activity level and goal need to be set to suggest the targets"))
+		hdlr := handler.NewTargetHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/target/{user_id}", hdlr.GetSuggestedTargets).Methods("GET")
+		req := httptest.NewRequest("GET", "/target/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		assert.Equal(t, "Sex, birth date, height, weight, activity level and goal need to be set to suggest the targets", errorOf(res).Message)
+	})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type tdeeHandler struct {
+	tdeeSrv service.TdeeService
+}
+
+func NewTdeeHandler(tdeeSrv service.TdeeService) tdeeHandler {
+	return tdeeHandler{tdeeSrv: tdeeSrv}
+}
+
+// GetAdaptiveTdee ... Get the adaptive TDEE of "User"
+// @Summary Get the adaptive TDEE of "User"
+// @Description Estimate the maintenance energy of the `User` from the average intake of the logged days in the last weeks before today and the change of the trend weight of `Weight Log` and the weight of `Record` over the same days, with the recommended targets of the `User`'s goal
+// @Tags Summary
+// @Security BearerAuth
+// @Produce json
+// @Param user_id path string true "`User Id` that you want to estimate TDEE"
+// @Param weeks query int false "Amount of weeks to look back, 2 - 12, default = 4"
+// @Response 200 {object} service.AdaptiveTdeeResponse
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable"
+// @Response 422 {object} ErrorResponse "Not enough logged data"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /tdee/{user_id} [get]
+func (h tdeeHandler) GetAdaptiveTdee(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	err := checkOwner(r, vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	weeks := 0
+	if r.URL.Query().Get("weeks") != "" {
+		value, err := strconv.ParseInt(r.URL.Query().Get("weeks"), 0, 0)
+		if err != nil {
+			handlerError(w, r, errs.NewValidationError("weeks", "Parse data type error"))
+			return
+		}
+		weeks = int(value)
+	}
+	response, err := h.tdeeSrv.GetAdaptiveTdee(r.Context(), vars["user_id"], weeks)
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	handler "go-nutritioncalculator2/handlers"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAdaptiveTdee(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		tdee := &service.AdaptiveTdeeResponse{
+			UserId:        "gooddy20",
+			From:          "2023-11-21",
+			To:            "2023-12-04",
+			Timezone:      "UTC",
+			Weeks:         2,
+			LoggedDays:    14,
+			AverageIntake: 2230,
+			StartTrend:    70,
+			EndTrend:      69.02,
+			WeeklyRate:    -0.53,
+			Tdee:          2810.5,
+			Goal:          "cut",
+			Target:        service.NutritionTotal{Protein: 151.8, Fat: 62.5, Carb: 269.7, Kcal: 2248.4},
+		}
+		srv := service.NewTdeeServiceMock()
+		srv.On("GetAdaptiveTdee", "gooddy20", 2).Return(tdee, nil)
+		hdlr := handler.NewTdeeHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/tdee/{user_id}", hdlr.GetAdaptiveTdee).Methods("GET")
+		req := httptest.NewRequest("GET", "/tdee/gooddy20?weeks=2", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		resultBody := &service.AdaptiveTdeeResponse{}
+		_ = json.Unmarshal(res.Body.Bytes(), resultBody)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, tdee, resultBody)
+	})
+	t.Run("Invalid Weeks", func(t *testing.T) {
+		srv := service.NewTdeeServiceMock()
+		hdlr := handler.NewTdeeHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/tdee/{user_id}", hdlr.GetAdaptiveTdee).Methods("GET")
+		req := httptest.NewRequest("GET", "/tdee/gooddy20?weeks=two", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Parse data type error", errorOf(res).Message)
+		srv.AssertNotCalled(t, "GetAdaptiveTdee")
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		srv := service.NewTdeeServiceMock()
+		hdlr := handler.NewTdeeHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/tdee/{user_id}", hdlr.GetAdaptiveTdee).Methods("GET")
+		req := httptest.NewRequest("GET", "/tdee/kornkoko", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		srv.AssertNotCalled(t, "GetAdaptiveTdee")
+	})
+	t.Run("Service Error", func(t *testing.T) {
+		srv := service.NewTdeeServiceMock()
+		srv.On("GetAdaptiveTdee", "gooddy20", 0).Return(&service.AdaptiveTdeeResponse{}, errs.NewUnprocessableError(errs.CodeNotEnoughData, "At least 7 logged days are needed to estimate TDEE"))
+		hdlr := handler.NewTdeeHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/tdee/{user_id}", hdlr.GetAdaptiveTdee).Methods("GET")
+		req := httptest.NewRequest("GET", "/tdee/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		assert.Equal(t, "At least 7 logged days are needed to estimate TDEE", errorOf(res).Message)
+	})
+}
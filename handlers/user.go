@@ -11,39 +11,51 @@ import (
 
 type userHandler struct {
 	userSrv service.UserService
+	authSrv service.AuthService
 }
 
-func NewUserHandler(userSrv service.UserService) userHandler {
-	return userHandler{userSrv: userSrv}
+func NewUserHandler(userSrv service.UserService, authSrv service.AuthService) userHandler {
+	return userHandler{userSrv: userSrv, authSrv: authSrv}
 }
 
 // LogIn ... Check "User Id" and "Password" are correct or not
 // @Summary Check "User Id" and "Password" are correct or not
-// @Description Check `User Id` and `Password` are correct or not
+// @Description Check `User Id` and `Password` are correct or not and issue an access token for the other endpoints when they are correct
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param request body service.LogInRequest true "`User Id` and `Password`"
 // @Response 200 {object} service.LogInResponse
-// @Response 406 "Request Body Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /user/login [put]
 func (h userHandler) LogIn(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request service.LogInRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
 		return
 	}
-	isLogIn, err := h.userSrv.CheckLogIn(request)
+	isLogIn, err := h.userSrv.CheckLogIn(r.Context(), request)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
+	if isLogIn.IsLogIn {
+		token, err := h.authSrv.GenerateToken(request.UserId)
+		if err != nil {
+			handlerError(w, r, err)
+			return
+		}
+		isLogIn.AccessToken = token.AccessToken
+		isLogIn.TokenType = token.TokenType
+		isLogIn.ExpiresAt = &token.ExpiresAt
+	}
 	w.Header().Set("content-type", "application/json")
 	json.NewEncoder(w).Encode(isLogIn)
 }
@@ -55,23 +67,25 @@ func (h userHandler) LogIn(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Param request body service.NewUserRequest true "`User`'s data detail"
 // @Response 200
-// @Response 406 "Request Body Not Acceptable"
-// @Response 500 "Internal Server Error"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 409 {object} ErrorResponse "`User Id` or `Username` is already used"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /user/ [post]
 func (h userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request service.NewUserRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
 		return
 	}
-	err = h.userSrv.CreateUser(request)
+	err = h.userSrv.CreateUser(r.Context(), request)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
 }
@@ -80,45 +94,70 @@ func (h userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 // @Summary Get a "User"'s detail
 // @Description Get a `User`'s detail by `User Id`
 // @Tags User
+// @Security BearerAuth
 // @Param user_id path string true "`User Id`"
+// @Param If-None-Match header string false "`ETag` of the `User` that you already have"
 // @Response 200 {object} service.UserResponse
-// @Response 406 "`User Id` is not found"
-// @Response 500 "Internal Server Error"
+// @Header 200 {string} ETag "Revision of the `User` in quotes"
+// @Response 304 "Not Modified, the ETag in `If-None-Match` is still current"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "`If-None-Match` is not a list of ETags"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 404 {object} ErrorResponse "`User Id` is not found"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /user/{user_id} [get]
 func (h userHandler) GetUserDetail(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	response, err := h.userSrv.GetUserDetail(vars["user_id"])
+	err := checkOwner(r, vars["user_id"])
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
-	w.Header().Set("content-type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	response, err := h.userSrv.GetUserDetail(r.Context(), vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	writeJSON(w, r, revisionETag(response.Revision), response)
 }
 
 // UpdateUserDetail ... Update a "User"'s detail
 // @Summary Update a "User"'s detail
-// @Description Update a `User`'s detail
+// @Description Update a `User`'s detail, a field that is omitted is kept and a field that is null is cleared
 // @Tags User
+// @Security BearerAuth
 // @Param request body service.UpdateUserRequest true "`User`'s data detail that you want to update and can ignore the unchanged parameters"
+// @Param If-Match header string false "Quoted `revision` of the `User` that the change is based on, no header or `*` = any revision"
 // @Response 200
-// @Response 406 "Request Body Not Acceptable or `User Id` is not found"
-// @Response 500 "Internal Server Error"
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable, a `Favorite Menu` is not found or deleted or `If-Match` is not an ETag"
+// @Response 404 {object} ErrorResponse "`User Id` is not found"
+// @Response 409 {object} ErrorResponse "`Username` is already used"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 412 {object} ErrorResponse "`User` has been changed since the ETag in `If-Match`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
 // @Router /user/userdetail [put]
+// @Router /user/userdetail [patch]
 func (h userHandler) UpdateUserDetail(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("content-type") != "application/json" {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Header"})
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
 		return
 	}
 	var request service.UpdateUserRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		handlerError(w, errs.AppError{Code: http.StatusNotAcceptable, Message: "Incorrect Request Body"})
+		handlerError(w, r, errs.NewMalformedBodyError())
+		return
+	}
+	request.Revision, err = ifMatchRevision(r)
+	if err != nil {
+		handlerError(w, r, err)
 		return
 	}
-	err = h.userSrv.UpdateUser(request)
+	request.UserId = userIdFromContext(r.Context())
+	err = h.userSrv.UpdateUser(r.Context(), request)
 	if err != nil {
-		handlerError(w, err)
+		handlerError(w, r, err)
 		return
 	}
 }
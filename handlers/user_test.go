@@ -8,8 +8,8 @@ import (
 	service "go-nutritioncalculator2/services"
 	"net/http"
 	"net/http/httptest"
-	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +24,13 @@ func TestLogIn(t *testing.T) {
 		}).Return(&service.LogInResponse{
 			IsLogIn: true,
 		}, nil)
-		hdlr := handler.NewUserHandler(srv)
+		authSrv := service.NewAuthServiceMock()
+		authSrv.On("GenerateToken", "gooddy20").Return(&service.TokenResponse{
+			AccessToken: "token",
+			TokenType:   "Bearer",
+			ExpiresAt:   time.Date(2023, 12, 6, 10, 0, 0, 0, time.UTC).UTC(),
+		}, nil)
+		hdlr := handler.NewUserHandler(srv, authSrv)
 		r := mux.NewRouter()
 		r.HandleFunc("/user/login", hdlr.LogIn).Methods("PUT")
 		preReqBody := map[string]interface{}{
@@ -36,12 +42,10 @@ func TestLogIn(t *testing.T) {
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		type ResponseBody struct {
-			IsLogIn bool `json:"IsLogIn"`
-		}
-		resultBody := ResponseBody{}
+		resultBody := service.LogInResponse{}
 		_ = json.Unmarshal(res.Body.Bytes(), &resultBody)
-		expectedBody := ResponseBody{IsLogIn: true}
+		expiresAt := time.Date(2023, 12, 6, 10, 0, 0, 0, time.UTC).UTC()
+		expectedBody := service.LogInResponse{IsLogIn: true, AccessToken: "token", TokenType: "Bearer", ExpiresAt: &expiresAt}
 		assert.Equal(t, http.StatusOK, res.Code)
 		assert.Equal(t, expectedBody, resultBody)
 	})
@@ -53,7 +57,7 @@ func TestLogIn(t *testing.T) {
 		}).Return(&service.LogInResponse{
 			IsLogIn: false,
 		}, nil)
-		hdlr := handler.NewUserHandler(srv)
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
 		r := mux.NewRouter()
 		r.HandleFunc("/user/login", hdlr.LogIn).Methods("PUT")
 		preReqBody := map[string]interface{}{
@@ -74,9 +78,34 @@ func TestLogIn(t *testing.T) {
 		assert.Equal(t, http.StatusOK, res.Code)
 		assert.Equal(t, expectedBody, resultBody)
 	})
+	t.Run("Generate Token Error", func(t *testing.T) {
+		srv := service.NewUserServiceMock()
+		srv.On("CheckLogIn", service.LogInRequest{
+			UserId:   "gooddy20",
+			Password: "correctPassword",
+		}).Return(&service.LogInResponse{
+			IsLogIn: true,
+		}, nil)
+		authSrv := service.NewAuthServiceMock()
+		authSrv.On("GenerateToken", "gooddy20").Return(&service.TokenResponse{}, errs.NewUnexpectedError())
+		hdlr := handler.NewUserHandler(srv, authSrv)
+		r := mux.NewRouter()
+		r.HandleFunc("/user/login", hdlr.LogIn).Methods("PUT")
+		preReqBody := map[string]interface{}{
+			"user_id":  "gooddy20",
+			"password": "correctPassword",
+		}
+		reqBody, _ := json.Marshal(preReqBody)
+		req := httptest.NewRequest("PUT", "/user/login", bytes.NewReader(reqBody))
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
 	t.Run("Incorrect Request Header", func(t *testing.T) {
 		srv := service.NewUserServiceMock()
-		hdlr := handler.NewUserHandler(srv)
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
 		r := mux.NewRouter()
 		r.HandleFunc("/user/login", hdlr.LogIn).Methods("PUT")
 		preReqBody := map[string]interface{}{
@@ -87,13 +116,13 @@ func TestLogIn(t *testing.T) {
 		req := httptest.NewRequest("PUT", "/user/login", bytes.NewReader(reqBody))
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Header", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CheckLogIn")
 	})
 	t.Run("Incorrect Request Body", func(t *testing.T) {
 		srv := service.NewUserServiceMock()
-		hdlr := handler.NewUserHandler(srv)
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
 		r := mux.NewRouter()
 		r.HandleFunc("/user/login", hdlr.LogIn).Methods("PUT")
 		reqBody := []byte("")
@@ -101,8 +130,8 @@ func TestLogIn(t *testing.T) {
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Body", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CheckLogIn")
 	})
 	t.Run("Database Error", func(t *testing.T) {
@@ -110,8 +139,8 @@ func TestLogIn(t *testing.T) {
 		srv.On("CheckLogIn", service.LogInRequest{
 			UserId:   "gooddy20",
 			Password: "correctPassword",
-		}).Return(&service.LogInResponse{}, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
-		hdlr := handler.NewUserHandler(srv)
+		}).Return(&service.LogInResponse{}, errs.NewUnexpectedError())
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
 		r := mux.NewRouter()
 		r.HandleFunc("/user/login", hdlr.LogIn).Methods("PUT")
 		preReqBody := map[string]interface{}{
@@ -127,7 +156,7 @@ func TestLogIn(t *testing.T) {
 			IsLogIn bool `json:"IsLogIn"`
 		}
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
@@ -143,7 +172,7 @@ func TestCreateUser(t *testing.T) {
 			Fat:      50,
 			Carb:     120,
 		}).Return(nil)
-		hdlr := handler.NewUserHandler(srv)
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
 		r := mux.NewRouter()
 		r.HandleFunc("/user/", hdlr.CreateUser).Methods("POST")
 		preReqBody := map[string]interface{}{
@@ -164,7 +193,7 @@ func TestCreateUser(t *testing.T) {
 	})
 	t.Run("Incorrect Request Header", func(t *testing.T) {
 		srv := service.NewUserServiceMock()
-		hdlr := handler.NewUserHandler(srv)
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
 		r := mux.NewRouter()
 		r.HandleFunc("/user/", hdlr.CreateUser).Methods("POST")
 		preReqBody := map[string]interface{}{
@@ -180,13 +209,13 @@ func TestCreateUser(t *testing.T) {
 		req := httptest.NewRequest("POST", "/user/", bytes.NewReader(reqBody))
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Header", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CreateUser")
 	})
 	t.Run("Incorrect Request Body", func(t *testing.T) {
 		srv := service.NewUserServiceMock()
-		hdlr := handler.NewUserHandler(srv)
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
 		r := mux.NewRouter()
 		r.HandleFunc("/user/", hdlr.CreateUser).Methods("POST")
 		reqBody := []byte("")
@@ -194,8 +223,8 @@ func TestCreateUser(t *testing.T) {
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Body", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
 		srv.AssertNotCalled(t, "CreateUser")
 	})
 	t.Run("Service Error", func(t *testing.T) {
@@ -208,8 +237,8 @@ func TestCreateUser(t *testing.T) {
 			Protein:  100,
 			Fat:      50,
 			Carb:     120,
-		}).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
-		hdlr := handler.NewUserHandler(srv)
+		}).Return(errs.NewUnexpectedError())
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
 		r := mux.NewRouter()
 		r.HandleFunc("/user/", hdlr.CreateUser).Methods("POST")
 		preReqBody := map[string]interface{}{
@@ -227,7 +256,7 @@ func TestCreateUser(t *testing.T) {
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
 
@@ -242,10 +271,11 @@ func TestGetUserDetail(t *testing.T) {
 			Carb:           120,
 			FavoriteMenues: "9,10",
 		}, nil)
-		hdlr := handler.NewUserHandler(srv)
-		r := mux.NewRouter()
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
+		r := newAuthRouter()
 		r.HandleFunc("/user/{user_id}", hdlr.GetUserDetail).Methods("GET")
 		req := httptest.NewRequest("GET", "/user/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		resultBody := service.UserResponse{}
@@ -261,17 +291,52 @@ func TestGetUserDetail(t *testing.T) {
 		assert.Equal(t, http.StatusOK, res.Code)
 		assert.Equal(t, expectedBody, resultBody)
 	})
+	t.Run("Success Case: Revision ETag", func(t *testing.T) {
+		srv := service.NewUserServiceMock()
+		srv.On("GetUserDetail", "gooddy20").Return(&service.UserResponse{Username: "GoodDy", Revision: 4}, nil)
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
+		r := newAuthRouter()
+		r.HandleFunc("/user/{user_id}", hdlr.GetUserDetail).Methods("GET")
+		req := httptest.NewRequest("GET", "/user/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `"4"`, res.Header().Get("etag"))
+
+		req = httptest.NewRequest("GET", "/user/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("if-none-match", `"4"`)
+		res = httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusNotModified, res.Code)
+		assert.Empty(t, res.Body.String())
+	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewUserServiceMock()
-		srv.On("GetUserDetail", "gooddy20").Return(&service.UserResponse{}, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
-		hdlr := handler.NewUserHandler(srv)
-		r := mux.NewRouter()
+		srv.On("GetUserDetail", "gooddy20").Return(&service.UserResponse{}, errs.NewUnexpectedError())
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
+		r := newAuthRouter()
 		r.HandleFunc("/user/{user_id}", hdlr.GetUserDetail).Methods("GET")
 		req := httptest.NewRequest("GET", "/user/gooddy20", nil)
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		srv := service.NewUserServiceMock()
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
+		r := newAuthRouter()
+		r.HandleFunc("/user/{user_id}", hdlr.GetUserDetail).Methods("GET")
+		req := httptest.NewRequest("GET", "/user/kornkoko", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, "Permission denied", errorOf(res).Message)
+		srv.AssertNotCalled(t, "GetUserDetail")
 	})
 }
 
@@ -280,13 +345,13 @@ func TestUpdateUserDetail(t *testing.T) {
 		srv := service.NewUserServiceMock()
 		srv.On("UpdateUser", service.UpdateUserRequest{
 			UserId:  "gooddy20",
-			Weight:  69,
-			Protein: 100,
-			Fat:     45,
-			Carb:    100,
+			Weight:  service.NewNullable[float64](69),
+			Protein: service.NewNullable[float64](100),
+			Fat:     service.NewNullable[float64](45),
+			Carb:    service.NewNullable[float64](100),
 		}).Return(nil)
-		hdlr := handler.NewUserHandler(srv)
-		r := mux.NewRouter()
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
+		r := newAuthRouter()
 		r.HandleFunc("/user/userdetail", hdlr.UpdateUserDetail).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"user_id": "gooddy20",
@@ -297,6 +362,7 @@ func TestUpdateUserDetail(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/user/userdetail", bytes.NewReader(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
@@ -304,8 +370,8 @@ func TestUpdateUserDetail(t *testing.T) {
 	})
 	t.Run("Incorrect Request Header", func(t *testing.T) {
 		srv := service.NewUserServiceMock()
-		hdlr := handler.NewUserHandler(srv)
-		r := mux.NewRouter()
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
+		r := newAuthRouter()
 		r.HandleFunc("/user/userdetail", hdlr.UpdateUserDetail).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"user_id": "gooddy20",
@@ -316,37 +382,39 @@ func TestUpdateUserDetail(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/user/userdetail", bytes.NewReader(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Header", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
 		srv.AssertNotCalled(t, "UpdateUser")
 	})
 	t.Run("Incorrect Request Body", func(t *testing.T) {
 		srv := service.NewUserServiceMock()
-		hdlr := handler.NewUserHandler(srv)
-		r := mux.NewRouter()
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
+		r := newAuthRouter()
 		r.HandleFunc("/user/userdetail", hdlr.UpdateUserDetail).Methods("PUT")
 		reqBody := []byte("")
 		req := httptest.NewRequest("PUT", "/user/userdetail", bytes.NewReader(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
-		assert.Equal(t, http.StatusNotAcceptable, res.Code)
-		assert.Equal(t, "Incorrect Request Body", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
 		srv.AssertNotCalled(t, "UpdateUser")
 	})
 	t.Run("Service Error", func(t *testing.T) {
 		srv := service.NewUserServiceMock()
 		srv.On("UpdateUser", service.UpdateUserRequest{
 			UserId:  "gooddy20",
-			Weight:  69,
-			Protein: 100,
-			Fat:     45,
-			Carb:    100,
-		}).Return(errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
-		hdlr := handler.NewUserHandler(srv)
-		r := mux.NewRouter()
+			Weight:  service.NewNullable[float64](69),
+			Protein: service.NewNullable[float64](100),
+			Fat:     service.NewNullable[float64](45),
+			Carb:    service.NewNullable[float64](100),
+		}).Return(errs.NewUnexpectedError())
+		hdlr := handler.NewUserHandler(srv, service.NewAuthServiceMock())
+		r := newAuthRouter()
 		r.HandleFunc("/user/userdetail", hdlr.UpdateUserDetail).Methods("PUT")
 		preReqBody := map[string]interface{}{
 			"user_id": "gooddy20",
@@ -357,10 +425,11 @@ func TestUpdateUserDetail(t *testing.T) {
 		}
 		reqBody, _ := json.Marshal(preReqBody)
 		req := httptest.NewRequest("PUT", "/user/userdetail", bytes.NewReader(reqBody))
+		req.Header.Add("authorization", "Bearer token")
 		req.Header.Add("content-type", "application/json")
 		res := httptest.NewRecorder()
 		r.ServeHTTP(res, req)
 		assert.Equal(t, http.StatusInternalServerError, res.Code)
-		assert.Equal(t, "Unexpected error", strings.Replace(res.Body.String(), "\n", "", -1))
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
 	})
 }
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type weightLogHandler struct {
+	weightLogSrv service.WeightLogService
+}
+
+func NewWeightLogHandler(weightLogSrv service.WeightLogService) weightLogHandler {
+	return weightLogHandler{weightLogSrv: weightLogSrv}
+}
+
+// CreateWeightLog ... Create a "Weight Log"
+// @Summary Create a "Weight Log"
+// @Description Log the body weight and the optional body fat of the 'User' at a timestamp
+// @Tags Weight
+// @Security BearerAuth
+// @Accept json
+// @Param request body service.NewWeightLogRequest true "`Weight Log`'s data detail"
+// @Response 200
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 404 {object} ErrorResponse "`User Id` is not found"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /weight/ [post]
+func (h weightLogHandler) CreateWeightLog(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("content-type") != "application/json" {
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
+		return
+	}
+	var request service.NewWeightLogRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handlerError(w, r, errs.NewMalformedBodyError())
+		return
+	}
+	request.UserId = userIdFromContext(r.Context())
+	err = h.weightLogSrv.CreateWeightLog(r.Context(), request)
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+}
+
+// DeleteWeightLog ... Delete a "Weight Log"
+// @Summary Delete a "Weight Log"
+// @Description Delete a 'Weight Log'
+// @Tags Weight
+// @Security BearerAuth
+// @Param weight_log_id path int true "`Weight Log`'s id that you want to delete"
+// @Response 200
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable"
+// @Response 404 {object} ErrorResponse "`Weight Log`'s id is not found"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /weight/{weight_log_id} [delete]
+func (h weightLogHandler) DeleteWeightLog(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	weightLogId, err := strconv.ParseInt(vars["weight_log_id"], 0, 0)
+	if err != nil {
+		handlerError(w, r, errs.NewValidationError("weight_log_id", "Parse data type error"))
+		return
+	}
+	err = h.weightLogSrv.DeleteWeightLog(r.Context(), userIdFromContext(r.Context()), int(weightLogId))
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+}
+
+// UpdateWeightLog ... Update a "Weight Log"
+// @Summary Update a "Weight Log"
+// @Description Update a 'Weight Log', a field that is omitted is kept and a field that is null is cleared
+// @Tags Weight
+// @Security BearerAuth
+// @Accept json
+// @Param request body service.UpdateWeightLogRequest true "`Weight Log`'s data detail that you want to change to"
+// @Response 200
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request Body Not Acceptable"
+// @Response 404 {object} ErrorResponse "`Weight Log`'s id is not found"
+// @Response 415 {object} ErrorResponse "Request Body is not `application/json`"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /weight/ [put]
+// @Router /weight/ [patch]
+func (h weightLogHandler) UpdateWeightLog(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("content-type") != "application/json" {
+		handlerError(w, r, errs.NewUnsupportedMediaTypeError())
+		return
+	}
+	var request service.UpdateWeightLogRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handlerError(w, r, errs.NewMalformedBodyError())
+		return
+	}
+	err = h.weightLogSrv.UpdateWeightLog(r.Context(), userIdFromContext(r.Context()), request)
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+}
+
+// GetWeightLogsByUserId ... Get "Weight Log" of "User"
+// @Summary Get "Weight Log" of "User"
+// @Description Get `Weight Log` of `User` by `User Id` ordered by the logged timestamp
+// @Tags Weight
+// @Security BearerAuth
+// @Produce json
+// @Param user_id path string true "`User Id` that you want to get `Weight Log`"
+// @Param from query string false "Only `Weight Log` that is logged from this timestamp (inclusive) *format=`2023-01-01` or `2023-01-01 00:00:00`"
+// @Param to query string false "Only `Weight Log` that is logged before this timestamp (exclusive) *format=`2023-01-01` or `2023-01-01 00:00:00`"
+// @Response 200 {array} service.WeightLogResponse
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /weight/{user_id} [get]
+func (h weightLogHandler) GetWeightLogsByUserId(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	err := checkOwner(r, vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	query := r.URL.Query()
+	response, err := h.weightLogSrv.GetWeightLogsByUserId(r.Context(), vars["user_id"], query.Get("from"), query.Get("to"))
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetWeightTrend ... Get the weight trend of "User"
+// @Summary Get the weight trend of "User"
+// @Description Average weight of each logged day from `from` to `to` in the `User`'s time zone smoothed by a 7-day exponential moving average with the weekly rate of change of the trend
+// @Tags Weight
+// @Security BearerAuth
+// @Produce json
+// @Param user_id path string true "`User Id` that you want to get the weight trend"
+// @Param from query string false "First day of the trend *format=`2023-01-01`, default = 89 days before `to`"
+// @Param to query string false "Last day of the trend *format=`2023-01-01`, default = today, atmost 366 days after `from`"
+// @Response 200 {object} service.WeightTrendResponse
+// @Response 401 {object} ErrorResponse "Missing or Invalid Access Token"
+// @Response 403 {object} ErrorResponse "Permission Denied"
+// @Response 400 {object} ErrorResponse "Request parameters Not Acceptable"
+// @Response 500 {object} ErrorResponse "Internal Server Error"
+// @Router /weight/{user_id}/trend [get]
+func (h weightLogHandler) GetWeightTrend(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	err := checkOwner(r, vars["user_id"])
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	query := r.URL.Query()
+	response, err := h.weightLogSrv.GetWeightTrend(r.Context(), vars["user_id"], query.Get("from"), query.Get("to"))
+	if err != nil {
+		handlerError(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
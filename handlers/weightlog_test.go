@@ -0,0 +1,217 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-nutritioncalculator2/errs"
+	handler "go-nutritioncalculator2/handlers"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWeightLog(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		bodyFat := 18.5
+		srv := service.NewWeightLogServiceMock()
+		srv.On("CreateWeightLog", service.NewWeightLogRequest{
+			UserId:          "gooddy20",
+			Weight:          70.5,
+			BodyFat:         &bodyFat,
+			LoggedTimestamp: "2023-12-05 07:00:00",
+		}).Return(nil)
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/", hdlr.CreateWeightLog).Methods("POST")
+		preReqBody := map[string]interface{}{
+			"user_id":          "kornkoko",
+			"weight":           70.5,
+			"body_fat":         18.5,
+			"logged_timestamp": "2023-12-05 07:00:00",
+		}
+		reqBody, _ := json.Marshal(preReqBody)
+		req := httptest.NewRequest("POST", "/weight/", bytes.NewBuffer(reqBody))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+	t.Run("Incorrect Request Header", func(t *testing.T) {
+		srv := service.NewWeightLogServiceMock()
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/", hdlr.CreateWeightLog).Methods("POST")
+		req := httptest.NewRequest("POST", "/weight/", bytes.NewBufferString(`{"weight": 70.5}`))
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.Equal(t, "Incorrect Request Header", errorOf(res).Message)
+		srv.AssertNotCalled(t, "CreateWeightLog")
+	})
+	t.Run("Service Error", func(t *testing.T) {
+		srv := service.NewWeightLogServiceMock()
+		srv.On("CreateWeightLog", service.NewWeightLogRequest{UserId: "gooddy20", Weight: -1}).Return(errs.NewValidationError("weight", "Weight need to be positive"))
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/", hdlr.CreateWeightLog).Methods("POST")
+		req := httptest.NewRequest("POST", "/weight/", bytes.NewBufferString(`{"weight": -1}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Weight need to be positive", errorOf(res).Message)
+	})
+}
+
+func TestDeleteWeightLog(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		srv := service.NewWeightLogServiceMock()
+		srv.On("DeleteWeightLog", "gooddy20", 1).Return(nil)
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/{weight_log_id}", hdlr.DeleteWeightLog).Methods("DELETE")
+		req := httptest.NewRequest("DELETE", "/weight/1", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+	t.Run("Parse Id (String to Int) Error", func(t *testing.T) {
+		srv := service.NewWeightLogServiceMock()
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/{weight_log_id}", hdlr.DeleteWeightLog).Methods("DELETE")
+		req := httptest.NewRequest("DELETE", "/weight/1.1", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Parse data type error", errorOf(res).Message)
+		srv.AssertNotCalled(t, "DeleteWeightLog")
+	})
+}
+
+func TestUpdateWeightLog(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		srv := service.NewWeightLogServiceMock()
+		srv.On("UpdateWeightLog", "gooddy20", service.UpdateWeightLogRequest{Id: 1, Weight: service.NewNullable(70.2)}).Return(nil)
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/", hdlr.UpdateWeightLog).Methods("PUT")
+		req := httptest.NewRequest("PUT", "/weight/", bytes.NewBufferString(`{"id": 1, "weight": 70.2}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+	t.Run("Success Case: Patch With Null", func(t *testing.T) {
+		srv := service.NewWeightLogServiceMock()
+		srv.On("UpdateWeightLog", "gooddy20", service.UpdateWeightLogRequest{Id: 1, BodyFat: service.NewNull[float64]()}).Return(nil)
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/", hdlr.UpdateWeightLog).Methods("PATCH")
+		req := httptest.NewRequest("PATCH", "/weight/", bytes.NewBufferString(`{"id": 1, "body_fat": null}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+	t.Run("Incorrect Request Body", func(t *testing.T) {
+		srv := service.NewWeightLogServiceMock()
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/", hdlr.UpdateWeightLog).Methods("PUT")
+		req := httptest.NewRequest("PUT", "/weight/", bytes.NewBufferString(`{"id": "1"}`))
+		req.Header.Add("authorization", "Bearer token")
+		req.Header.Add("content-type", "application/json")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "Incorrect Request Body", errorOf(res).Message)
+		srv.AssertNotCalled(t, "UpdateWeightLog")
+	})
+}
+
+func TestGetWeightLogsByUserId(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		weightLogs := []service.WeightLogResponse{{Id: 1, Weight: 70.5, LoggedTimestamp: time.Date(2023, 12, 5, 7, 0, 0, 0, time.UTC)}}
+		srv := service.NewWeightLogServiceMock()
+		srv.On("GetWeightLogsByUserId", "gooddy20", "2023-12-01", "").Return(weightLogs, nil)
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/{user_id}", hdlr.GetWeightLogsByUserId).Methods("GET")
+		req := httptest.NewRequest("GET", "/weight/gooddy20?from=2023-12-01", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		resultBody := []service.WeightLogResponse{}
+		_ = json.Unmarshal(res.Body.Bytes(), &resultBody)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, weightLogs, resultBody)
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		srv := service.NewWeightLogServiceMock()
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/{user_id}", hdlr.GetWeightLogsByUserId).Methods("GET")
+		req := httptest.NewRequest("GET", "/weight/kornkoko", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		srv.AssertNotCalled(t, "GetWeightLogsByUserId")
+	})
+}
+
+func TestGetWeightTrend(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		latest, rate := 69.77, -1.7
+		trend := &service.WeightTrendResponse{
+			UserId:   "gooddy20",
+			From:     "2023-12-02",
+			To:       "2023-12-04",
+			Timezone: "UTC",
+			Points: []service.WeightTrendPoint{
+				{Date: "2023-12-02", Weight: 70, Trend: 70.38},
+				{Date: "2023-12-04", Weight: 69, Trend: 69.77},
+			},
+			Trend:      &latest,
+			WeeklyRate: &rate,
+		}
+		srv := service.NewWeightLogServiceMock()
+		srv.On("GetWeightTrend", "gooddy20", "2023-12-02", "2023-12-04").Return(trend, nil)
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/{user_id}/trend", hdlr.GetWeightTrend).Methods("GET")
+		req := httptest.NewRequest("GET", "/weight/gooddy20/trend?from=2023-12-02&to=2023-12-04", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		resultBody := &service.WeightTrendResponse{}
+		_ = json.Unmarshal(res.Body.Bytes(), resultBody)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, trend, resultBody)
+	})
+	t.Run("Service Error", func(t *testing.T) {
+		srv := service.NewWeightLogServiceMock()
+		srv.On("GetWeightTrend", "gooddy20", "", "").Return(&service.WeightTrendResponse{}, errs.NewUnexpectedError())
+		hdlr := handler.NewWeightLogHandler(srv)
+		r := newAuthRouter()
+		r.HandleFunc("/weight/{user_id}/trend", hdlr.GetWeightTrend).Methods("GET")
+		req := httptest.NewRequest("GET", "/weight/gooddy20/trend", nil)
+		req.Header.Add("authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.Equal(t, "Unexpected error", errorOf(res).Message)
+	})
+}
@@ -1,17 +1,29 @@
 package logs
 
-import "go.uber.org/zap"
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
 
 var log *zap.Logger
 
+var level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+
 func init() {
 	var err error
-	log, err = zap.NewDevelopment()
+	cfg := zap.NewDevelopmentConfig()
+	cfg.Level = level
+	log, err = cfg.Build()
 	if err != nil {
 		panic(err)
 	}
 }
 
+// SetLevel changes the minimum level ("debug", "info", "warn" or "error") of the logs that are written
+func SetLevel(text string) error {
+	return level.UnmarshalText([]byte(text))
+}
+
 func Info(msg string, fields ...zap.Field) {
 	log.Info(msg, fields...)
 }
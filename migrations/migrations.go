@@ -0,0 +1,201 @@
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"go-nutritioncalculator2/logs"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed sql/*.sql
+var embedded embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int        `db:"version"`
+	Name      string     `db:"name"`
+	AppliedAt *time.Time `db:"applied_at"`
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load parses the "<version>_<name>.<up|down>.sql" files of the directory into migrations ordered by version
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("migrations: unexpected file %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(matches[1])
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migrations: version %d is used by %s and %s", version, migration.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+	migrations := []Migration{}
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migrations: version %d need both up and down files", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func NewMigrator(db *sqlx.DB) (*Migrator, error) {
+	migrations, err := Load(embedded, "sql")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func (m Migrator) ensureTable() error {
+	_, err := m.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+func (m Migrator) appliedVersions() (map[int]time.Time, error) {
+	err := m.ensureTable()
+	if err != nil {
+		return nil, err
+	}
+	applied := []MigrationStatus{}
+	err = m.db.Select(&applied, "SELECT version, name, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	versions := map[int]time.Time{}
+	for _, status := range applied {
+		versions[status.Version] = *status.AppliedAt
+	}
+	return versions, nil
+}
+
+// Up applies every pending migration in order, each one in its own transaction
+func (m Migrator) Up() ([]Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	done := []Migration{}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err = m.run(migration.Up, func(tx *sqlx.Tx) error {
+			_, err := tx.Exec("INSERT INTO schema_migrations (version,name,applied_at) VALUES ($1,$2,$3)",
+				migration.Version,
+				migration.Name,
+				time.Now().UTC().Truncate(time.Second))
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migrations: up %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		logs.Info(fmt.Sprintf("migrated up %04d_%s", migration.Version, migration.Name))
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the latest applied migration, it returns nil when there is nothing to roll back
+func (m Migrator) Down() (*Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err = m.run(migration.Down, func(tx *sqlx.Tx) error {
+			_, err := tx.Exec("DELETE FROM schema_migrations WHERE version=$1", migration.Version)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("migrations: down %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		logs.Info(fmt.Sprintf("migrated down %04d_%s", migration.Version, migration.Name))
+		return &migration, nil
+	}
+	return nil, nil
+}
+
+// Status lists every known migration with the timestamp that it was applied, or nil when it is pending
+func (m Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	statuses := []MigrationStatus{}
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (m Migrator) run(script string, record func(*sqlx.Tx) error) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(script)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = record(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
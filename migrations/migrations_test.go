@@ -0,0 +1,57 @@
+package migrations_test
+
+import (
+	"go-nutritioncalculator2/migrations"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"sql/0002_add_note.up.sql":        {Data: []byte("ALTER TABLE a ADD COLUMN note TEXT;")},
+			"sql/0002_add_note.down.sql":      {Data: []byte("ALTER TABLE a DROP COLUMN note;")},
+			"sql/0001_create_tables.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER);")},
+			"sql/0001_create_tables.down.sql": {Data: []byte("DROP TABLE a;")},
+		}
+		result, err := migrations.Load(fsys, "sql")
+		expected := []migrations.Migration{
+			{Version: 1, Name: "create_tables", Up: "CREATE TABLE a (id INTEGER);", Down: "DROP TABLE a;"},
+			{Version: 2, Name: "add_note", Up: "ALTER TABLE a ADD COLUMN note TEXT;", Down: "ALTER TABLE a DROP COLUMN note;"},
+		}
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Missing Down File", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"sql/0001_create_tables.up.sql": {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		}
+		_, err := migrations.Load(fsys, "sql")
+		assert.EqualError(t, err, "migrations: version 1 need both up and down files")
+	})
+	t.Run("Duplicated Version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"sql/0001_create_tables.up.sql": {Data: []byte("CREATE TABLE a (id INTEGER);")},
+			"sql/0001_add_note.up.sql":      {Data: []byte("ALTER TABLE a ADD COLUMN note TEXT;")},
+		}
+		_, err := migrations.Load(fsys, "sql")
+		assert.EqualError(t, err, "migrations: version 1 is used by add_note and create_tables")
+	})
+	t.Run("Unexpected File", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"sql/create_tables.sql": {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		}
+		_, err := migrations.Load(fsys, "sql")
+		assert.EqualError(t, err, "migrations: unexpected file create_tables.sql")
+	})
+	t.Run("Embedded Migrations", func(t *testing.T) {
+		result, err := migrations.Load(os.DirFS("."), "sql")
+		assert.ErrorIs(t, err, nil)
+		for i, migration := range result {
+			assert.Equal(t, i+1, migration.Version)
+		}
+	})
+}
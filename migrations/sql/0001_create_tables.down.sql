@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS nutritioncalculator_record;
+DROP TABLE IF EXISTS nutritioncalculator_favorite_list;
+DROP TABLE IF EXISTS nutritioncalculator_menu;
+DROP TABLE IF EXISTS nutritioncalculator_user;
@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS nutritioncalculator_user (
+	user_id           TEXT PRIMARY KEY,
+	password          TEXT NOT NULL,
+	username          TEXT NOT NULL UNIQUE,
+	weight            DOUBLE PRECISION NOT NULL DEFAULT 0,
+	protein           DOUBLE PRECISION NOT NULL DEFAULT 0,
+	fat               DOUBLE PRECISION NOT NULL DEFAULT 0,
+	carb              DOUBLE PRECISION NOT NULL DEFAULT 0,
+	favorite_menues   TEXT NOT NULL DEFAULT '',
+	created_timestamp TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+CREATE TABLE IF NOT EXISTS nutritioncalculator_menu (
+	id                SERIAL PRIMARY KEY,
+	name              TEXT NOT NULL,
+	protein           DOUBLE PRECISION NOT NULL DEFAULT 0,
+	fat               DOUBLE PRECISION NOT NULL DEFAULT 0,
+	carb              DOUBLE PRECISION NOT NULL DEFAULT 0,
+	creator_id        TEXT NOT NULL REFERENCES nutritioncalculator_user (user_id),
+	status            INTEGER NOT NULL DEFAULT 1,
+	created_timestamp TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+CREATE TABLE IF NOT EXISTS nutritioncalculator_favorite_list (
+	id                SERIAL PRIMARY KEY,
+	user_id           TEXT NOT NULL REFERENCES nutritioncalculator_user (user_id),
+	name              TEXT NOT NULL,
+	list              TEXT NOT NULL DEFAULT '',
+	status            INTEGER NOT NULL DEFAULT 1,
+	created_timestamp TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+CREATE TABLE IF NOT EXISTS nutritioncalculator_record (
+	id                SERIAL PRIMARY KEY,
+	user_id           TEXT NOT NULL REFERENCES nutritioncalculator_user (user_id),
+	list              TEXT NOT NULL DEFAULT '',
+	weight            DOUBLE PRECISION NOT NULL DEFAULT 0,
+	note              TEXT NOT NULL DEFAULT '',
+	event_timestamp   TIMESTAMP NOT NULL,
+	status            INTEGER NOT NULL DEFAULT 1,
+	created_timestamp TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX IF NOT EXISTS nutritioncalculator_menu_creator_id_idx ON nutritioncalculator_menu (creator_id);
+CREATE INDEX IF NOT EXISTS nutritioncalculator_menu_status_idx ON nutritioncalculator_menu (status);
+CREATE INDEX IF NOT EXISTS nutritioncalculator_favorite_list_user_id_idx ON nutritioncalculator_favorite_list (user_id);
+CREATE INDEX IF NOT EXISTS nutritioncalculator_favorite_list_status_idx ON nutritioncalculator_favorite_list (status);
+CREATE INDEX IF NOT EXISTS nutritioncalculator_record_user_id_idx ON nutritioncalculator_record (user_id);
+CREATE INDEX IF NOT EXISTS nutritioncalculator_record_status_idx ON nutritioncalculator_record (status);
+CREATE INDEX IF NOT EXISTS nutritioncalculator_record_event_timestamp_idx ON nutritioncalculator_record (event_timestamp);
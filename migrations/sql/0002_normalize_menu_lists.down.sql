@@ -0,0 +1,29 @@
+ALTER TABLE nutritioncalculator_record ADD COLUMN list TEXT NOT NULL DEFAULT '';
+ALTER TABLE nutritioncalculator_favorite_list ADD COLUMN list TEXT NOT NULL DEFAULT '';
+ALTER TABLE nutritioncalculator_user ADD COLUMN favorite_menues TEXT NOT NULL DEFAULT '';
+
+-- (9, 2) and (10, 1) become "9,9,10"
+UPDATE nutritioncalculator_record AS r SET list = t.list
+FROM (
+	SELECT record_id, string_agg(rtrim(repeat(CAST(menu_id AS text) || ',', quantity), ','), ',' ORDER BY menu_id) AS list
+	FROM record_item GROUP BY 1
+) AS t
+WHERE r.id = t.record_id;
+
+UPDATE nutritioncalculator_favorite_list AS fl SET list = t.list
+FROM (
+	SELECT favlist_id, string_agg(rtrim(repeat(CAST(menu_id AS text) || ',', quantity), ','), ',' ORDER BY menu_id) AS list
+	FROM favlist_item GROUP BY 1
+) AS t
+WHERE fl.id = t.favlist_id;
+
+UPDATE nutritioncalculator_user AS u SET favorite_menues = t.favorite_menues
+FROM (
+	SELECT user_id, string_agg(CAST(menu_id AS text), ',' ORDER BY menu_id) AS favorite_menues
+	FROM user_favorite_menu GROUP BY 1
+) AS t
+WHERE u.user_id = t.user_id;
+
+DROP TABLE IF EXISTS user_favorite_menu;
+DROP TABLE IF EXISTS favlist_item;
+DROP TABLE IF EXISTS record_item;
@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS record_item (
+	record_id INTEGER NOT NULL REFERENCES nutritioncalculator_record (id) ON DELETE CASCADE,
+	menu_id   INTEGER NOT NULL REFERENCES nutritioncalculator_menu (id),
+	quantity  INTEGER NOT NULL CHECK (quantity > 0),
+	PRIMARY KEY (record_id, menu_id)
+);
+
+CREATE TABLE IF NOT EXISTS favlist_item (
+	favlist_id INTEGER NOT NULL REFERENCES nutritioncalculator_favorite_list (id) ON DELETE CASCADE,
+	menu_id    INTEGER NOT NULL REFERENCES nutritioncalculator_menu (id),
+	quantity   INTEGER NOT NULL CHECK (quantity > 0),
+	PRIMARY KEY (favlist_id, menu_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_favorite_menu (
+	user_id TEXT NOT NULL REFERENCES nutritioncalculator_user (user_id) ON DELETE CASCADE,
+	menu_id INTEGER NOT NULL REFERENCES nutritioncalculator_menu (id),
+	PRIMARY KEY (user_id, menu_id)
+);
+
+CREATE INDEX IF NOT EXISTS record_item_menu_id_idx ON record_item (menu_id);
+CREATE INDEX IF NOT EXISTS favlist_item_menu_id_idx ON favlist_item (menu_id);
+CREATE INDEX IF NOT EXISTS user_favorite_menu_menu_id_idx ON user_favorite_menu (menu_id);
+
+-- "9,9,10" becomes (9, 2) and (10, 1), ids that are not a number or not an existing menu are dropped
+INSERT INTO record_item (record_id, menu_id, quantity)
+SELECT r.id, CAST(trim(e.menu_id) AS INTEGER), COUNT(*)
+FROM nutritioncalculator_record AS r, unnest(regexp_split_to_array(r.list, ',')) AS e(menu_id)
+WHERE trim(e.menu_id) ~ '^[0-9]+$'
+AND EXISTS (SELECT 1 FROM nutritioncalculator_menu AS m WHERE m.id = CAST(trim(e.menu_id) AS INTEGER))
+GROUP BY 1, 2;
+
+INSERT INTO favlist_item (favlist_id, menu_id, quantity)
+SELECT fl.id, CAST(trim(e.menu_id) AS INTEGER), COUNT(*)
+FROM nutritioncalculator_favorite_list AS fl, unnest(regexp_split_to_array(fl.list, ',')) AS e(menu_id)
+WHERE trim(e.menu_id) ~ '^[0-9]+$'
+AND EXISTS (SELECT 1 FROM nutritioncalculator_menu AS m WHERE m.id = CAST(trim(e.menu_id) AS INTEGER))
+GROUP BY 1, 2;
+
+INSERT INTO user_favorite_menu (user_id, menu_id)
+SELECT DISTINCT u.user_id, CAST(trim(e.menu_id) AS INTEGER)
+FROM nutritioncalculator_user AS u, unnest(regexp_split_to_array(u.favorite_menues, ',')) AS e(menu_id)
+WHERE trim(e.menu_id) ~ '^[0-9]+$'
+AND EXISTS (SELECT 1 FROM nutritioncalculator_menu AS m WHERE m.id = CAST(trim(e.menu_id) AS INTEGER));
+
+ALTER TABLE nutritioncalculator_record DROP COLUMN list;
+ALTER TABLE nutritioncalculator_favorite_list DROP COLUMN list;
+ALTER TABLE nutritioncalculator_user DROP COLUMN favorite_menues;
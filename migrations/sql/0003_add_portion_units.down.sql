@@ -0,0 +1,23 @@
+-- portions in g or ml are converted back into whole servings of the menu, at least 1
+UPDATE record_item AS ri SET quantity = ri.quantity / m.serving_size
+FROM nutritioncalculator_menu AS m
+WHERE m.id = ri.menu_id AND ri.unit <> 'serving';
+DELETE FROM record_item AS ri USING record_item AS other
+WHERE ri.record_id = other.record_id AND ri.menu_id = other.menu_id AND ri.unit <> 'serving' AND other.unit = 'serving';
+ALTER TABLE record_item DROP CONSTRAINT record_item_pkey;
+ALTER TABLE record_item DROP COLUMN unit;
+ALTER TABLE record_item ALTER COLUMN quantity TYPE INTEGER USING GREATEST(round(quantity), 1);
+ALTER TABLE record_item ADD PRIMARY KEY (record_id, menu_id);
+
+UPDATE favlist_item AS fi SET quantity = fi.quantity / m.serving_size
+FROM nutritioncalculator_menu AS m
+WHERE m.id = fi.menu_id AND fi.unit <> 'serving';
+DELETE FROM favlist_item AS fi USING favlist_item AS other
+WHERE fi.favlist_id = other.favlist_id AND fi.menu_id = other.menu_id AND fi.unit <> 'serving' AND other.unit = 'serving';
+ALTER TABLE favlist_item DROP CONSTRAINT favlist_item_pkey;
+ALTER TABLE favlist_item DROP COLUMN unit;
+ALTER TABLE favlist_item ALTER COLUMN quantity TYPE INTEGER USING GREATEST(round(quantity), 1);
+ALTER TABLE favlist_item ADD PRIMARY KEY (favlist_id, menu_id);
+
+ALTER TABLE nutritioncalculator_menu DROP COLUMN serving_unit;
+ALTER TABLE nutritioncalculator_menu DROP COLUMN serving_size;
@@ -0,0 +1,13 @@
+-- protein, fat and carb of a menu are for serving_size of serving_unit e.g. 100 g of chicken breast
+ALTER TABLE nutritioncalculator_menu ADD COLUMN serving_size DOUBLE PRECISION NOT NULL DEFAULT 1 CHECK (serving_size > 0);
+ALTER TABLE nutritioncalculator_menu ADD COLUMN serving_unit TEXT NOT NULL DEFAULT 'serving' CHECK (serving_unit IN ('serving', 'g', 'ml'));
+
+ALTER TABLE record_item ALTER COLUMN quantity TYPE DOUBLE PRECISION;
+ALTER TABLE record_item ADD COLUMN unit TEXT NOT NULL DEFAULT 'serving' CHECK (unit IN ('serving', 'g', 'ml'));
+ALTER TABLE record_item DROP CONSTRAINT record_item_pkey;
+ALTER TABLE record_item ADD PRIMARY KEY (record_id, menu_id, unit);
+
+ALTER TABLE favlist_item ALTER COLUMN quantity TYPE DOUBLE PRECISION;
+ALTER TABLE favlist_item ADD COLUMN unit TEXT NOT NULL DEFAULT 'serving' CHECK (unit IN ('serving', 'g', 'ml'));
+ALTER TABLE favlist_item DROP CONSTRAINT favlist_item_pkey;
+ALTER TABLE favlist_item ADD PRIMARY KEY (favlist_id, menu_id, unit);
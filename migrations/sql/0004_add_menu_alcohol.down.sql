@@ -0,0 +1 @@
+ALTER TABLE nutritioncalculator_menu DROP COLUMN alcohol;
@@ -0,0 +1 @@
+ALTER TABLE nutritioncalculator_menu ADD COLUMN alcohol DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (alcohol >= 0);
@@ -0,0 +1,7 @@
+-- amount of each extended nutrient per serving_size of the menu, a missing row means the amount is unknown
+CREATE TABLE IF NOT EXISTS menu_nutrient (
+	menu_id  INTEGER NOT NULL REFERENCES nutritioncalculator_menu (id) ON DELETE CASCADE,
+	nutrient TEXT NOT NULL,
+	amount   DOUBLE PRECISION NOT NULL CHECK (amount >= 0),
+	PRIMARY KEY (menu_id, nutrient)
+);
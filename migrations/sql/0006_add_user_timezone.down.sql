@@ -0,0 +1 @@
+ALTER TABLE nutritioncalculator_user DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,2 @@
+-- IANA time zone that the calendar days of the user are counted in
+ALTER TABLE nutritioncalculator_user ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS nutritioncalculator_record_user_event_idx;
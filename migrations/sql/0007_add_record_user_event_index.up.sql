@@ -0,0 +1,2 @@
+-- serves the date range filter and the keyset pagination of the records of a user
+CREATE INDEX IF NOT EXISTS nutritioncalculator_record_user_event_idx ON nutritioncalculator_record (user_id, event_timestamp, id);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS weight_log (
+	id                SERIAL PRIMARY KEY,
+	user_id           TEXT NOT NULL REFERENCES nutritioncalculator_user (user_id),
+	weight            DOUBLE PRECISION NOT NULL CHECK (weight > 0),
+	body_fat          DOUBLE PRECISION CHECK (body_fat >= 0 AND body_fat < 100),
+	logged_timestamp  TIMESTAMP NOT NULL,
+	status            INTEGER NOT NULL DEFAULT 1,
+	created_timestamp TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX IF NOT EXISTS weight_log_user_logged_idx ON weight_log (user_id, logged_timestamp);
+
+-- every meal of a day used to repeat the weight, only the latest non-zero weight of each day is kept
+INSERT INTO weight_log (user_id, weight, logged_timestamp, status, created_timestamp)
+SELECT DISTINCT ON (r.user_id, CAST(r.event_timestamp AS DATE)) r.user_id, r.weight, r.event_timestamp, 1, r.created_timestamp
+FROM nutritioncalculator_record AS r
+WHERE r.weight > 0 AND r.status = 1
+ORDER BY r.user_id, CAST(r.event_timestamp AS DATE), r.event_timestamp DESC, r.id DESC;
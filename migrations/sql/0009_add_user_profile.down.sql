@@ -0,0 +1,5 @@
+ALTER TABLE nutritioncalculator_user DROP COLUMN IF EXISTS goal;
+ALTER TABLE nutritioncalculator_user DROP COLUMN IF EXISTS activity_level;
+ALTER TABLE nutritioncalculator_user DROP COLUMN IF EXISTS height;
+ALTER TABLE nutritioncalculator_user DROP COLUMN IF EXISTS birth_date;
+ALTER TABLE nutritioncalculator_user DROP COLUMN IF EXISTS sex;
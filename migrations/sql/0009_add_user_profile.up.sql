@@ -0,0 +1,6 @@
+-- Body profile of the user that the energy expenditure and the suggested targets are calculated from, '' / 0 / NULL = not set
+ALTER TABLE nutritioncalculator_user ADD COLUMN IF NOT EXISTS sex TEXT NOT NULL DEFAULT '';
+ALTER TABLE nutritioncalculator_user ADD COLUMN IF NOT EXISTS birth_date DATE NULL;
+ALTER TABLE nutritioncalculator_user ADD COLUMN IF NOT EXISTS height DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE nutritioncalculator_user ADD COLUMN IF NOT EXISTS activity_level TEXT NOT NULL DEFAULT '';
+ALTER TABLE nutritioncalculator_user ADD COLUMN IF NOT EXISTS goal TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE nutritioncalculator_user DROP COLUMN IF EXISTS auto_update_menues;
+DROP INDEX IF EXISTS menu_parent_menu_id_idx;
+ALTER TABLE nutritioncalculator_menu DROP COLUMN IF EXISTS version;
+ALTER TABLE nutritioncalculator_menu DROP COLUMN IF EXISTS parent_menu_id;
@@ -0,0 +1,7 @@
+-- An updated menu is the next version of its parent, the first version has no parent
+ALTER TABLE nutritioncalculator_menu ADD COLUMN IF NOT EXISTS parent_menu_id INTEGER NULL REFERENCES nutritioncalculator_menu (id);
+ALTER TABLE nutritioncalculator_menu ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1 CHECK (version > 0);
+CREATE INDEX IF NOT EXISTS menu_parent_menu_id_idx ON nutritioncalculator_menu (parent_menu_id);
+
+-- A user that opts in has the favorite menues and the favorite lists moved to the newest version of an updated menu
+ALTER TABLE nutritioncalculator_user ADD COLUMN IF NOT EXISTS auto_update_menues BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+ALTER TABLE nutritioncalculator_favorite_list DROP COLUMN IF EXISTS revision;
+ALTER TABLE nutritioncalculator_record DROP COLUMN IF EXISTS revision;
+ALTER TABLE nutritioncalculator_menu DROP COLUMN IF EXISTS revision;
+ALTER TABLE nutritioncalculator_user DROP COLUMN IF EXISTS revision;
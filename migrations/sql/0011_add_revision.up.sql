@@ -0,0 +1,5 @@
+-- Every update of a row adds 1 to the revision, an update that is based on an old revision is rejected
+ALTER TABLE nutritioncalculator_user ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1 CHECK (revision > 0);
+ALTER TABLE nutritioncalculator_menu ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1 CHECK (revision > 0);
+ALTER TABLE nutritioncalculator_record ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1 CHECK (revision > 0);
+ALTER TABLE nutritioncalculator_favorite_list ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1 CHECK (revision > 0);
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS nutritioncalculator_favorite_list_user_client_id_idx;
+DROP INDEX IF EXISTS nutritioncalculator_record_user_client_id_idx;
+ALTER TABLE nutritioncalculator_favorite_list DROP COLUMN IF EXISTS client_id;
+ALTER TABLE nutritioncalculator_record DROP COLUMN IF EXISTS client_id;
+
+DROP TRIGGER IF EXISTS nutritioncalculator_favorite_list_changed_xid ON nutritioncalculator_favorite_list;
+DROP TRIGGER IF EXISTS nutritioncalculator_record_changed_xid ON nutritioncalculator_record;
+DROP TRIGGER IF EXISTS nutritioncalculator_menu_changed_xid ON nutritioncalculator_menu;
+DROP TRIGGER IF EXISTS nutritioncalculator_user_changed_xid ON nutritioncalculator_user;
+DROP FUNCTION IF EXISTS set_changed_xid();
+
+ALTER TABLE nutritioncalculator_favorite_list DROP COLUMN IF EXISTS changed_xid;
+ALTER TABLE nutritioncalculator_record DROP COLUMN IF EXISTS changed_xid;
+ALTER TABLE nutritioncalculator_menu DROP COLUMN IF EXISTS changed_xid;
+ALTER TABLE nutritioncalculator_user DROP COLUMN IF EXISTS changed_xid;
@@ -0,0 +1,28 @@
+-- "changed_xid" is the transaction that made the latest change of a row, the sync feed returns the rows that are changed
+-- by the transactions that finished since the cursor so a change that commits late is returned by the next sync instead of skipped
+CREATE OR REPLACE FUNCTION set_changed_xid() RETURNS trigger AS $$
+BEGIN
+	NEW.changed_xid := pg_current_xact_id()::text::bigint;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE nutritioncalculator_user ADD COLUMN IF NOT EXISTS changed_xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint;
+ALTER TABLE nutritioncalculator_menu ADD COLUMN IF NOT EXISTS changed_xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint;
+ALTER TABLE nutritioncalculator_record ADD COLUMN IF NOT EXISTS changed_xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint;
+ALTER TABLE nutritioncalculator_favorite_list ADD COLUMN IF NOT EXISTS changed_xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint;
+
+CREATE TRIGGER nutritioncalculator_user_changed_xid BEFORE UPDATE ON nutritioncalculator_user FOR EACH ROW EXECUTE FUNCTION set_changed_xid();
+CREATE TRIGGER nutritioncalculator_menu_changed_xid BEFORE UPDATE ON nutritioncalculator_menu FOR EACH ROW EXECUTE FUNCTION set_changed_xid();
+CREATE TRIGGER nutritioncalculator_record_changed_xid BEFORE UPDATE ON nutritioncalculator_record FOR EACH ROW EXECUTE FUNCTION set_changed_xid();
+CREATE TRIGGER nutritioncalculator_favorite_list_changed_xid BEFORE UPDATE ON nutritioncalculator_favorite_list FOR EACH ROW EXECUTE FUNCTION set_changed_xid();
+
+CREATE INDEX IF NOT EXISTS nutritioncalculator_menu_changed_xid_idx ON nutritioncalculator_menu (changed_xid);
+CREATE INDEX IF NOT EXISTS nutritioncalculator_record_user_changed_xid_idx ON nutritioncalculator_record (user_id, changed_xid);
+CREATE INDEX IF NOT EXISTS nutritioncalculator_favorite_list_user_changed_xid_idx ON nutritioncalculator_favorite_list (user_id, changed_xid);
+
+-- A record or a favorite list that is created offline keeps the id that the client generated so a retried mutation finds it
+ALTER TABLE nutritioncalculator_record ADD COLUMN IF NOT EXISTS client_id UUID NULL;
+ALTER TABLE nutritioncalculator_favorite_list ADD COLUMN IF NOT EXISTS client_id UUID NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS nutritioncalculator_record_user_client_id_idx ON nutritioncalculator_record (user_id, client_id);
+CREATE UNIQUE INDEX IF NOT EXISTS nutritioncalculator_favorite_list_user_client_id_idx ON nutritioncalculator_favorite_list (user_id, client_id);
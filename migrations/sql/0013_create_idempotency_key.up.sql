@@ -0,0 +1,12 @@
+-- The first response of a create request is kept with the "Idempotency-Key" of the request so a retry gets it back instead of creating the row again
+CREATE TABLE IF NOT EXISTS idempotency_key (
+	user_id               TEXT NOT NULL REFERENCES nutritioncalculator_user (user_id),
+	idempotency_key       TEXT NOT NULL,
+	fingerprint           TEXT NOT NULL,
+	response_status       INTEGER,
+	response_content_type TEXT NOT NULL DEFAULT '',
+	response_body         BYTEA NOT NULL DEFAULT '',
+	expires_timestamp     TIMESTAMP NOT NULL,
+	created_timestamp     TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	PRIMARY KEY (user_id, idempotency_key)
+);
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrNotFound is returned when no active row matches
+	ErrNotFound = errors.New("repository: not found")
+	// ErrConflict is returned when a row with the same unique key already exists
+	ErrConflict = errors.New("repository: conflict")
+	// ErrConstraint is returned when a row breaks a foreign key, check or not null constraint
+	ErrConstraint = errors.New("repository: constraint violation")
+	// ErrStale is returned when the row is updated with a revision that is not the current one anymore
+	ErrStale = errors.New("repository: stale revision")
+)
+
+// dbError translates the driver errors to the typed errors of the package and keeps the original error in the chain
+func dbError(err error) error {
+	if err == nil || errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) || errors.Is(err, ErrConstraint) || errors.Is(err, ErrStale) {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Class() == "23" {
+		if pqErr.Code.Name() == "unique_violation" {
+			return fmt.Errorf("%w: %w", ErrConflict, err)
+		}
+		return fmt.Errorf("%w: %w", ErrConstraint, err)
+	}
+	return err
+}
+
+// withTimeout bounds every query of a repository call by the query timeout, a timeout of 0 only follows the context
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// checkRevision turns an update that matches no row into ErrStale, the row is read before it is updated
+// so no row means that another update has changed the revision in the meantime
+func checkRevision(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrStale
+	}
+	return nil
+}
@@ -1,24 +1,33 @@
 package repository
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type FavList struct {
-	Id               int       `db:"id"`
-	UserId           string    `db:"user_id"`
-	Name             string    `db:"name"`
-	Menues           string    `db:"menues"`
-	List             string    `db:"list"`
-	Protein          float64   `db:"protein"`
-	Fat              float64   `db:"fat"`
-	Carb             float64   `db:"carb"`
-	Status           int       `db:"status"`
-	IsUpdated        int       `db:"is_updated"`
-	CreatedTimestamp time.Time `db:"created_timestamp"`
+	Id               int                `db:"id"`
+	UserId           string             `db:"user_id"`
+	Name             string             `db:"name"`
+	Menues           string             `db:"menues"`
+	Items            []Item             `db:"-"`
+	Protein          float64            `db:"protein"`
+	Fat              float64            `db:"fat"`
+	Carb             float64            `db:"carb"`
+	Alcohol          float64            `db:"alcohol"`
+	Nutrients        map[string]float64 `db:"-"`
+	Status           int                `db:"status"`
+	IsUpdated        int                `db:"is_updated"`
+	Revision         int                `db:"revision"`  // Adds 1 on every update
+	ClientId         *string            `db:"client_id"` // Id that the client generated for a "Favorite List" that is created offline
+	CreatedTimestamp time.Time          `db:"created_timestamp"`
 }
 
 type FavListRepository interface {
-	GetFavListsByUserId(string) ([]FavList, error)
-	GetFavListById(int) (*FavList, error)
-	CreateFavList(FavList) (*FavList, error)
-	UpdateFavList(FavList) error
+	GetFavListsByUserId(context.Context, string) ([]FavList, error)
+	GetFavListById(context.Context, int) (*FavList, error)
+	CreateFavList(context.Context, FavList) (*FavList, error)
+	UpdateFavList(context.Context, FavList) error
+	GetFavListByClientId(context.Context, string, string) (*FavList, error)
+	GetFavListByIdIncludingDeleted(context.Context, int) (*FavList, error)
 }
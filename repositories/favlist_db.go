@@ -1,82 +1,140 @@
 package repository
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
 
 type favListRepositoryDB struct {
-	db *sqlx.DB
+	db           dbtx
+	queryTimeout time.Duration
 }
 
-func NewFavListRepositoryDB(db *sqlx.DB) favListRepositoryDB {
-	return favListRepositoryDB{db: db}
+func NewFavListRepositoryDB(db *sqlx.DB, queryTimeout time.Duration) favListRepositoryDB {
+	return favListRepositoryDB{db: db, queryTimeout: queryTimeout}
 }
 
-func (r favListRepositoryDB) GetFavListsByUserId(userId string) ([]FavList, error) {
+const selectFavList = `SELECT fl.id, fl.user_id, fl.name, fl.status, fl.revision, fl.client_id, fl.created_timestamp,
+		COALESCE(string_agg(concat(m."name", '-', fi.quantity, NULLIF(fi.unit, 'serving'), ' '), ',' ORDER BY fi.menu_id, fi.unit), '') AS menues,
+		COALESCE(SUM(p.servings * m.protein), 0) AS protein, COALESCE(SUM(p.servings * m.fat), 0) AS fat, COALESCE(SUM(p.servings * m.carb), 0) AS carb,
+		COALESCE(SUM(p.servings * m.alcohol), 0) AS alcohol,
+		COALESCE(MIN(m.status), 1) AS is_updated
+		FROM nutritioncalculator_favorite_list AS fl
+		LEFT JOIN favlist_item AS fi ON fi.favlist_id = fl.id
+		LEFT JOIN nutritioncalculator_menu AS m ON m.id = fi.menu_id
+		LEFT JOIN LATERAL (SELECT CASE WHEN fi.unit = 'serving' THEN fi.quantity ELSE fi.quantity / m.serving_size END AS servings) AS p ON true`
+
+func (r favListRepositoryDB) GetFavListsByUserId(ctx context.Context, userId string) ([]FavList, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
 	favLists := []FavList{}
-	err := r.db.Select(&favLists,
-		`SELECT id, user_id , name, list, status, created_timestamp , string_agg(concat(menu_name, '-',nums,' ') ,',') AS menues, SUM(nums * protein ) AS protein, SUM(nums * fat ) AS fat, SUM(nums * carb ) AS carb, MIN(menu_status) AS is_updated
-		FROM 
-		(
-		SELECT fl.id, fl.user_id, fl.name, fl.list, fl.status, fl.created_timestamp,
-		cardinality(regexp_split_to_array(fl.list,',')) - cardinality(array_remove(regexp_split_to_array(fl.list,','),CAST(m.id AS text))) AS nums
-		, m."name" AS menu_name, m.protein , m.fat, m.carb , m.status AS menu_status
-		FROM nutritioncalculator_favorite_list AS fl LEFT JOIN nutritioncalculator_menu AS m  
-		ON CAST(m.id AS text) = ANY(regexp_split_to_array(fl.list,','))
+	err := r.db.SelectContext(ctx, &favLists,
+		selectFavList+`
 		WHERE fl.user_id = $1 AND fl.status = 1
-		) AS t
-		GROUP BY 1, 2, 3, 4, 5, 6`,
+		GROUP BY fl.id`,
 		userId)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
+	}
+	err = r.loadItems(ctx, favLists)
+	if err != nil {
+		return nil, dbError(err)
 	}
 	return favLists, nil
 }
 
-func (r favListRepositoryDB) GetFavListById(favListId int) (*FavList, error) {
+func (r favListRepositoryDB) GetFavListById(ctx context.Context, favListId int) (*FavList, error) {
+	return r.getFavList(ctx, "fl.id = $1 AND fl.status = 1", favListId)
+}
+
+// GetFavListByIdIncludingDeleted finds the "Favorite List" whatever its status is, so a deleted one can be sent back as a tombstone
+func (r favListRepositoryDB) GetFavListByIdIncludingDeleted(ctx context.Context, favListId int) (*FavList, error) {
+	return r.getFavList(ctx, "fl.id = $1", favListId)
+}
+
+// GetFavListByClientId finds the "Favorite List" that the client created offline, a deleted one is returned too
+func (r favListRepositoryDB) GetFavListByClientId(ctx context.Context, userId string, clientId string) (*FavList, error) {
+	return r.getFavList(ctx, "fl.user_id = $1 AND fl.client_id = $2", userId, clientId)
+}
+
+// getFavList gets the one "Favorite List" that matches the condition with its items
+func (r favListRepositoryDB) getFavList(ctx context.Context, condition string, args ...interface{}) (*FavList, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
 	favList := FavList{}
-	err := r.db.Get(&favList,
-		`SELECT id, user_id , name, list, status, created_timestamp , string_agg(concat(menu_name, '-',nums,' ') ,',') AS menues, SUM(nums * protein ) AS protein, SUM(nums * fat ) AS fat, SUM(nums * carb ) AS carb, MIN(menu_status) AS is_updated
-		FROM 
-		(
-		SELECT fl.id, fl.user_id, fl.name, fl.list, fl.status, fl.created_timestamp,
-		cardinality(regexp_split_to_array(fl.list,',')) - cardinality(array_remove(regexp_split_to_array(fl.list,','),CAST(m.id AS text))) AS nums
-		, m."name" AS menu_name, m.protein , m.fat, m.carb , m.status AS menu_status
-		FROM nutritioncalculator_favorite_list AS fl LEFT JOIN nutritioncalculator_menu AS m  
-		ON CAST(m.id AS text) = ANY(regexp_split_to_array(fl.list,','))
-		WHERE fl.id=$1 AND fl.status = 1
-		) AS t
-		GROUP BY 1, 2, 3, 4, 5, 6`,
-		favListId)
+	err := r.db.GetContext(ctx, &favList,
+		selectFavList+`
+		WHERE `+condition+`
+		GROUP BY fl.id`,
+		args...)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
-	return &favList, nil
+	favLists := []FavList{favList}
+	err = r.loadItems(ctx, favLists)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &favLists[0], nil
 }
 
-func (r favListRepositoryDB) CreateFavList(favList FavList) (*FavList, error) {
-	var favListId int
-	err := r.db.QueryRow("INSERT INTO nutritioncalculator_favorite_list (user_id,name,list,status,created_timestamp) VALUES ($1,$2,$3,$4,$5) RETURNING id",
-		favList.UserId,
-		favList.Name,
-		favList.List,
-		favList.Status,
-		favList.CreatedTimestamp).Scan(&favListId)
+func (r favListRepositoryDB) CreateFavList(ctx context.Context, favList FavList) (*FavList, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowContext(ctx, "INSERT INTO nutritioncalculator_favorite_list (user_id,name,status,client_id,created_timestamp) VALUES ($1,$2,$3,$4,$5) RETURNING id, revision",
+			favList.UserId,
+			favList.Name,
+			favList.Status,
+			favList.ClientId,
+			favList.CreatedTimestamp).Scan(&favList.Id, &favList.Revision)
+		if err != nil {
+			return err
+		}
+		return replaceItems(ctx, tx, "favlist_item", "favlist_id", favList.Id, favList.Items)
+	})
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
-	favList.Id = favListId
 	return &favList, nil
 }
 
-func (r favListRepositoryDB) UpdateFavList(favList FavList) error {
-	tx := r.db.MustBegin()
-	tx.MustExec("UPDATE nutritioncalculator_favorite_list SET name=$1,list=$2,status=$3 WHERE id=$4",
-		favList.Name,
-		favList.List,
-		favList.Status,
-		favList.Id)
-	err := tx.Commit()
+func (r favListRepositoryDB) UpdateFavList(ctx context.Context, favList FavList) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE nutritioncalculator_favorite_list SET name=$1,status=$2,revision=revision+1 WHERE id=$3 AND revision=$4",
+			favList.Name,
+			favList.Status,
+			favList.Id,
+			favList.Revision)
+		err = checkRevision(result, err)
+		if err != nil {
+			return err
+		}
+		return replaceItems(ctx, tx, "favlist_item", "favlist_id", favList.Id, favList.Items)
+	})
+	return dbError(err)
+}
+
+func (r favListRepositoryDB) loadItems(ctx context.Context, favLists []FavList) error {
+	favListIds := []int{}
+	for _, favList := range favLists {
+		favListIds = append(favListIds, favList.Id)
+	}
+	items, err := selectItems(ctx, r.db, "favlist_item", "favlist_id", favListIds)
 	if err != nil {
 		return err
 	}
+	nutrients, err := selectItemNutrients(ctx, r.db, "favlist_item", "favlist_id", favListIds)
+	if err != nil {
+		return err
+	}
+	for i := range favLists {
+		favLists[i].Items = items[favLists[i].Id]
+		favLists[i].Nutrients = nutrients[favLists[i].Id]
+	}
 	return nil
 }
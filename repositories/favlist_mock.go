@@ -1,6 +1,10 @@
 package repository
 
-import "github.com/stretchr/testify/mock"
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
 
 type favListRepositoryMock struct {
 	mock.Mock
@@ -10,22 +14,32 @@ func NewFavListRepositoryMock() *favListRepositoryMock {
 	return &favListRepositoryMock{}
 }
 
-func (r *favListRepositoryMock) GetFavListsByUserId(userId string) ([]FavList, error) {
+func (r *favListRepositoryMock) GetFavListsByUserId(ctx context.Context, userId string) ([]FavList, error) {
 	args := r.Called(userId)
 	return args.Get(0).([]FavList), args.Error(1)
 }
 
-func (r *favListRepositoryMock) GetFavListById(favListId int) (*FavList, error) {
+func (r *favListRepositoryMock) GetFavListById(ctx context.Context, favListId int) (*FavList, error) {
 	args := r.Called(favListId)
 	return args.Get(0).(*FavList), args.Error(1)
 }
 
-func (r *favListRepositoryMock) CreateFavList(favList FavList) (*FavList, error) {
+func (r *favListRepositoryMock) CreateFavList(ctx context.Context, favList FavList) (*FavList, error) {
 	args := r.Called(favList)
 	return args.Get(0).(*FavList), args.Error(1)
 }
 
-func (r *favListRepositoryMock) UpdateFavList(favList FavList) error {
+func (r *favListRepositoryMock) UpdateFavList(ctx context.Context, favList FavList) error {
 	args := r.Called(favList)
 	return args.Error(0)
 }
+
+func (r *favListRepositoryMock) GetFavListByClientId(ctx context.Context, userId string, clientId string) (*FavList, error) {
+	args := r.Called(userId, clientId)
+	return args.Get(0).(*FavList), args.Error(1)
+}
+
+func (r *favListRepositoryMock) GetFavListByIdIncludingDeleted(ctx context.Context, favListId int) (*FavList, error) {
+	args := r.Called(favListId)
+	return args.Get(0).(*FavList), args.Error(1)
+}
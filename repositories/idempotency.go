@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type IdempotencyKey struct {
+	UserId              string    `db:"user_id"`
+	Key                 string    `db:"idempotency_key"`
+	Fingerprint         string    `db:"fingerprint"`           // Hash of the request that first used the key
+	ResponseStatus      *int      `db:"response_status"`       // nil = the first request is still in progress
+	ResponseContentType string    `db:"response_content_type"` // Content type of the first response
+	ResponseBody        []byte    `db:"response_body"`         // Body of the first response
+	ExpiresTimestamp    time.Time `db:"expires_timestamp"`     // The key can be used again by another request after this time, the lease while in progress and the TTL after the response
+	CreatedTimestamp    time.Time `db:"created_timestamp"`     // When the key is claimed, a retry that claims the key again after the lease gets a later one
+}
+
+type IdempotencyRepository interface {
+	CreateIdempotencyKey(context.Context, IdempotencyKey) error
+	GetIdempotencyKey(context.Context, string, string) (*IdempotencyKey, error)
+	ExtendIdempotencyKey(context.Context, IdempotencyKey) error
+	SaveIdempotencyResponse(context.Context, IdempotencyKey) error
+	DeleteIdempotencyKey(context.Context, IdempotencyKey) error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type idempotencyRepositoryDB struct {
+	db           dbtx
+	queryTimeout time.Duration
+}
+
+func NewIdempotencyRepositoryDB(db *sqlx.DB, queryTimeout time.Duration) idempotencyRepositoryDB {
+	return idempotencyRepositoryDB{db: db, queryTimeout: queryTimeout}
+}
+
+// CreateIdempotencyKey deletes the expired keys of the "User" and saves the key as in progress,
+// ErrConflict = the key is already used by a request that has not expired
+func (r idempotencyRepositoryDB) CreateIdempotencyKey(ctx context.Context, idempotencyKey IdempotencyKey) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_key WHERE user_id=$1 AND expires_timestamp<=$2",
+		idempotencyKey.UserId,
+		idempotencyKey.CreatedTimestamp)
+	if err != nil {
+		return dbError(err)
+	}
+	_, err = r.db.ExecContext(ctx, "INSERT INTO idempotency_key (user_id,idempotency_key,fingerprint,expires_timestamp,created_timestamp) VALUES ($1,$2,$3,$4,$5)",
+		idempotencyKey.UserId,
+		idempotencyKey.Key,
+		idempotencyKey.Fingerprint,
+		idempotencyKey.ExpiresTimestamp,
+		idempotencyKey.CreatedTimestamp)
+	return dbError(err)
+}
+
+func (r idempotencyRepositoryDB) GetIdempotencyKey(ctx context.Context, userId string, key string) (*IdempotencyKey, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	idempotencyKey := IdempotencyKey{}
+	err := r.db.GetContext(ctx, &idempotencyKey,
+		`SELECT user_id, idempotency_key, fingerprint, response_status, response_content_type, response_body, expires_timestamp, created_timestamp
+		FROM idempotency_key
+		WHERE user_id = $1 AND idempotency_key = $2`,
+		userId,
+		key)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &idempotencyKey, nil
+}
+
+// ExtendIdempotencyKey moves the expiry of the key that is still in progress, ErrStale = the key is claimed again by another request
+func (r idempotencyRepositoryDB) ExtendIdempotencyKey(ctx context.Context, idempotencyKey IdempotencyKey) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	result, err := r.db.ExecContext(ctx, "UPDATE idempotency_key SET expires_timestamp=$1 WHERE user_id=$2 AND idempotency_key=$3 AND created_timestamp=$4 AND response_status IS NULL",
+		idempotencyKey.ExpiresTimestamp,
+		idempotencyKey.UserId,
+		idempotencyKey.Key,
+		idempotencyKey.CreatedTimestamp)
+	return dbError(checkRevision(result, err))
+}
+
+// SaveIdempotencyResponse saves the response of the request that claimed the key at the created timestamp,
+// ErrStale = the key is claimed again by another request so its response is kept
+func (r idempotencyRepositoryDB) SaveIdempotencyResponse(ctx context.Context, idempotencyKey IdempotencyKey) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	result, err := r.db.ExecContext(ctx, "UPDATE idempotency_key SET response_status=$1,response_content_type=$2,response_body=$3,expires_timestamp=$4 WHERE user_id=$5 AND idempotency_key=$6 AND created_timestamp=$7",
+		idempotencyKey.ResponseStatus,
+		idempotencyKey.ResponseContentType,
+		idempotencyKey.ResponseBody,
+		idempotencyKey.ExpiresTimestamp,
+		idempotencyKey.UserId,
+		idempotencyKey.Key,
+		idempotencyKey.CreatedTimestamp)
+	return dbError(checkRevision(result, err))
+}
+
+// DeleteIdempotencyKey releases the key that is claimed at the created timestamp, a key that is claimed again by another request is kept
+func (r idempotencyRepositoryDB) DeleteIdempotencyKey(ctx context.Context, idempotencyKey IdempotencyKey) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_key WHERE user_id=$1 AND idempotency_key=$2 AND created_timestamp=$3",
+		idempotencyKey.UserId,
+		idempotencyKey.Key,
+		idempotencyKey.CreatedTimestamp)
+	return dbError(err)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type idempotencyRepositoryMock struct {
+	mock.Mock
+}
+
+func NewIdempotencyRepositoryMock() *idempotencyRepositoryMock {
+	return &idempotencyRepositoryMock{}
+}
+
+func (r *idempotencyRepositoryMock) CreateIdempotencyKey(ctx context.Context, idempotencyKey IdempotencyKey) error {
+	args := r.Called(idempotencyKey)
+	return args.Error(0)
+}
+
+func (r *idempotencyRepositoryMock) GetIdempotencyKey(ctx context.Context, userId string, key string) (*IdempotencyKey, error) {
+	args := r.Called(userId, key)
+	return args.Get(0).(*IdempotencyKey), args.Error(1)
+}
+
+func (r *idempotencyRepositoryMock) ExtendIdempotencyKey(ctx context.Context, idempotencyKey IdempotencyKey) error {
+	args := r.Called(idempotencyKey)
+	return args.Error(0)
+}
+
+func (r *idempotencyRepositoryMock) SaveIdempotencyResponse(ctx context.Context, idempotencyKey IdempotencyKey) error {
+	args := r.Called(idempotencyKey)
+	return args.Error(0)
+}
+
+func (r *idempotencyRepositoryMock) DeleteIdempotencyKey(ctx context.Context, idempotencyKey IdempotencyKey) error {
+	args := r.Called(idempotencyKey)
+	return args.Error(0)
+}
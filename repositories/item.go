@@ -0,0 +1,9 @@
+package repository
+
+// Item is one "Menu" with its amount inside a "Record" or a "Favorite List",
+// the amount is either servings of the "Menu" (unit = "serving") or the same unit as the "Menu"'s serving size ("g" or "ml")
+type Item struct {
+	MenuId   int     `db:"menu_id"`
+	Quantity float64 `db:"quantity"`
+	Unit     string  `db:"unit"`
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ownedItem struct {
+	OwnerId int `db:"owner_id"`
+	Item
+}
+
+// selectItems loads the items of every owner id at once and groups them by the owner id
+func selectItems(ctx context.Context, q sqlx.QueryerContext, table string, ownerColumn string, ownerIds []int) (map[int][]Item, error) {
+	items := map[int][]Item{}
+	if len(ownerIds) == 0 {
+		return items, nil
+	}
+	rows := []ownedItem{}
+	err := sqlx.SelectContext(ctx, q, &rows,
+		fmt.Sprintf("SELECT %s AS owner_id, menu_id, quantity, unit FROM %s WHERE %s = ANY($1) ORDER BY menu_id, unit", ownerColumn, table, ownerColumn),
+		pq.Array(ownerIds))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		items[row.OwnerId] = append(items[row.OwnerId], row.Item)
+	}
+	return items, nil
+}
+
+// replaceItems overwrites the items of the owner id with the given items
+func replaceItems(ctx context.Context, tx *sqlx.Tx, table string, ownerColumn string, ownerId int, items []Item) error {
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s=$1", table, ownerColumn), ownerId)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (%s,menu_id,quantity,unit) VALUES ($1,$2,$3,$4)", table, ownerColumn),
+			ownerId,
+			item.MenuId,
+			item.Quantity,
+			item.Unit)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withTx runs fn in a transaction that is committed when fn succeeds and rolled back otherwise,
+// fn joins the transaction instead when the repository already runs in a unit of work, behind a savepoint
+// so that a failed step only rolls back its own changes and the unit of work can handle the error and go on
+func withTx(ctx context.Context, db dbtx, fn func(*sqlx.Tx) error) error {
+	if tx, ok := db.(*sqlx.Tx); ok {
+		_, err := tx.ExecContext(ctx, "SAVEPOINT step")
+		if err != nil {
+			return err
+		}
+		err = fn(tx)
+		if err != nil {
+			tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT step")
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT step")
+		return err
+	}
+	tx, err := db.(*sqlx.DB).BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
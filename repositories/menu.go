@@ -1,23 +1,63 @@
 package repository
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Menu struct {
-	Id               int       `db:"id"`
-	Name             string    `db:"name"`
-	Protein          float64   `db:"protein"`
-	Fat              float64   `db:"fat"`
-	Carb             float64   `db:"carb"`
-	CreatorId        string    `db:"creator_id"`
-	CreatorName      string    `db:"creator_name"`
-	Like             int       `db:"count_like"`
-	Status           int       `db:"status"`
-	CreatedTimestamp time.Time `db:"created_timestamp"`
+	Id               int                `db:"id"`
+	Name             string             `db:"name"`
+	Protein          float64            `db:"protein"`
+	Fat              float64            `db:"fat"`
+	Carb             float64            `db:"carb"`
+	Alcohol          float64            `db:"alcohol"`
+	Nutrients        map[string]float64 `db:"-"`
+	ServingSize      float64            `db:"serving_size"`
+	ServingUnit      string             `db:"serving_unit"`
+	CreatorId        string             `db:"creator_id"`
+	CreatorName      string             `db:"creator_name"`
+	Like             int                `db:"count_like"`
+	Status           int                `db:"status"`
+	ParentMenuId     *int               `db:"parent_menu_id"`
+	Version          int                `db:"version"`
+	Revision         int                `db:"revision"` // Adds 1 on every update of the row, unlike the version that is a new row
+	CreatedTimestamp time.Time          `db:"created_timestamp"`
+}
+
+// Sort orders of MenuFilter, an empty sort is the creation order
+const (
+	MenuSortNewest         = "newest"
+	MenuSortLikes          = "likes"
+	MenuSortProteinDensity = "protein_density"
+)
+
+// MenuRange is an inclusive range of a nutrition value, a nil bound is open
+type MenuRange struct {
+	Min *float64
+	Max *float64
+}
+
+// MenuFilter narrows, orders and pages the menues, a zero value returns every menu
+type MenuFilter struct {
+	Name      string // Case-insensitive part of the name
+	CreatorId string
+	Status    *int
+	Protein   MenuRange
+	Fat       MenuRange
+	Carb      MenuRange
+	Kcal      MenuRange
+	Sort      string
+	Limit     int // 0 = no limit
+	Offset    int
 }
 
 type MenuRepository interface {
-	CreateMenu(Menu) (*Menu, error)
-	GetAllMenues() ([]Menu, error)
-	GetMenuById(int) (*Menu, error)
-	UpdateMenu(Menu) error
+	CreateMenu(context.Context, Menu) (*Menu, error)
+	GetAllMenues(context.Context, MenuFilter) ([]Menu, error)
+	GetMenuById(context.Context, int) (*Menu, error)
+	GetMenusByIds(context.Context, []int) ([]Menu, error)
+	GetMenuHistory(context.Context, int) ([]Menu, error)
+	UpdateMenu(context.Context, Menu) error
+	RepointMenu(context.Context, int, Menu) error
 }
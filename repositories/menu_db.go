@@ -1,67 +1,273 @@
 package repository
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
 
 type menuRepositoryDB struct {
-	db *sqlx.DB
+	db           dbtx
+	queryTimeout time.Duration
 }
 
-func NewMenuRositoryDB(db *sqlx.DB) menuRepositoryDB {
-	return menuRepositoryDB{db: db}
+func NewMenuRositoryDB(db *sqlx.DB, queryTimeout time.Duration) menuRepositoryDB {
+	return menuRepositoryDB{db: db, queryTimeout: queryTimeout}
 }
 
-func (r menuRepositoryDB) CreateMenu(menu Menu) (*Menu, error) {
-	var menuId int
-	err := r.db.QueryRow("INSERT INTO nutritioncalculator_menu (name,protein,fat,carb,creator_id,status,created_timestamp) VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id",
-		menu.Name,
-		menu.Protein,
-		menu.Fat,
-		menu.Carb,
-		menu.CreatorId,
-		menu.Status,
-		menu.CreatedTimestamp).Scan(&menuId)
+func (r menuRepositoryDB) CreateMenu(ctx context.Context, menu Menu) (*Menu, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowContext(ctx, "INSERT INTO nutritioncalculator_menu (name,protein,fat,carb,alcohol,serving_size,serving_unit,creator_id,status,parent_menu_id,version,created_timestamp) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) RETURNING id, revision",
+			menu.Name,
+			menu.Protein,
+			menu.Fat,
+			menu.Carb,
+			menu.Alcohol,
+			menu.ServingSize,
+			menu.ServingUnit,
+			menu.CreatorId,
+			menu.Status,
+			menu.ParentMenuId,
+			menu.Version,
+			menu.CreatedTimestamp).Scan(&menu.Id, &menu.Revision)
+		if err != nil {
+			return err
+		}
+		return insertMenuNutrients(ctx, tx, menu.Id, menu.Nutrients)
+	})
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
-	menu.Id = menuId
 	return &menu, nil
 }
 
-func (r menuRepositoryDB) GetAllMenues() ([]Menu, error) {
-	var menues []Menu
-	err := r.db.Select(&menues,
-		`SELECT menu.id, menu.name, menu.protein , menu.fat, menu.carb , menu.creator_id , u1.username AS creator_name, menu.status, menu.created_timestamp, menu.status, COUNT(u2.user_id) AS count_like
+// menuKcal is the energy of a menu with the same factors as the service layer
+const menuKcal = "(menu.protein * 4 + menu.fat * 9 + menu.carb * 4 + menu.alcohol * 7)"
+
+var likePattern = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r menuRepositoryDB) GetAllMenues(ctx context.Context, filter MenuFilter) ([]Menu, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	conditions := []string{}
+	args := []interface{}{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Name != "" {
+		addCondition("menu.name ILIKE '%%' || $%d || '%%'", likePattern.Replace(filter.Name))
+	}
+	if filter.CreatorId != "" {
+		addCondition("menu.creator_id = $%d", filter.CreatorId)
+	}
+	if filter.Status != nil {
+		addCondition("menu.status = $%d", *filter.Status)
+	}
+	for _, column := range []struct {
+		expression string
+		valueRange MenuRange
+	}{{"menu.protein", filter.Protein}, {"menu.fat", filter.Fat}, {"menu.carb", filter.Carb}, {menuKcal, filter.Kcal}} {
+		if column.valueRange.Min != nil {
+			addCondition(column.expression+" >= $%d", *column.valueRange.Min)
+		}
+		if column.valueRange.Max != nil {
+			addCondition(column.expression+" <= $%d", *column.valueRange.Max)
+		}
+	}
+	query := `SELECT menu.id, menu.name, menu.protein , menu.fat, menu.carb , menu.alcohol, menu.serving_size, menu.serving_unit, menu.creator_id , u1.username AS creator_name, menu.status, menu.created_timestamp, menu.status, COUNT(u2.user_id) AS count_like, menu.parent_menu_id, menu.version, menu.revision
 		FROM (nutritioncalculator_menu AS menu INNER JOIN nutritioncalculator_user AS u1 ON menu.creator_id = u1.user_id ) 
-		LEFT JOIN nutritioncalculator_user AS u2 ON CAST(menu.id AS text) = ANY(regexp_split_to_array(u2.favorite_menues,','))
-		GROUP BY 1, 2,3,4,5,6,7,8,9, 10`)
+		LEFT JOIN user_favorite_menu AS u2 ON u2.menu_id = menu.id`
+	if len(conditions) != 0 {
+		query += `
+		WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += `
+		GROUP BY 1, 2,3,4,5,6,7,8,9, 10, 11, 12, 13, 15, 16, 17`
+	switch filter.Sort {
+	case MenuSortNewest:
+		query += " ORDER BY menu.created_timestamp DESC, menu.id DESC"
+	case MenuSortLikes:
+		query += " ORDER BY count_like DESC, menu.id DESC"
+	case MenuSortProteinDensity:
+		query += " ORDER BY menu.protein * 4 / NULLIF(" + menuKcal + ", 0) DESC NULLS LAST, menu.id DESC"
+	default:
+		query += " ORDER BY menu.id"
+	}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+	menues := []Menu{}
+	err := r.db.SelectContext(ctx, &menues, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
+	}
+	err = r.loadNutrients(ctx, menues)
+	if err != nil {
+		return nil, dbError(err)
 	}
 	return menues, nil
 }
 
-func (r menuRepositoryDB) GetMenuById(id int) (*Menu, error) {
+func (r menuRepositoryDB) GetMenuById(ctx context.Context, id int) (*Menu, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
 	var menu Menu
-	err := r.db.Get(&menu,
-		`SELECT menu.id, menu.name, menu.protein , menu.fat, menu.carb , menu.creator_id , u1.username AS creator_name, menu.status, menu.created_timestamp, menu.status, COUNT(u2.user_id) AS count_like
+	err := r.db.GetContext(ctx, &menu,
+		`SELECT menu.id, menu.name, menu.protein , menu.fat, menu.carb , menu.alcohol, menu.serving_size, menu.serving_unit, menu.creator_id , u1.username AS creator_name, menu.status, menu.created_timestamp, menu.status, COUNT(u2.user_id) AS count_like, menu.parent_menu_id, menu.version, menu.revision
 		FROM (nutritioncalculator_menu AS menu INNER JOIN nutritioncalculator_user AS u1 ON menu.creator_id = u1.user_id ) 
-		LEFT JOIN nutritioncalculator_user AS u2 ON CAST(menu.id AS text) = ANY(regexp_split_to_array(u2.favorite_menues,','))
+		LEFT JOIN user_favorite_menu AS u2 ON u2.menu_id = menu.id
 		WHERE menu.id = $1
-		GROUP BY 1, 2,3,4,5,6,7,8,9, 10`,
+		GROUP BY 1, 2,3,4,5,6,7,8,9, 10, 11, 12, 13, 15, 16, 17`,
 		id)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
-	return &menu, nil
+	menues := []Menu{menu}
+	err = r.loadNutrients(ctx, menues)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &menues[0], nil
+}
+
+func (r menuRepositoryDB) GetMenusByIds(ctx context.Context, ids []int) ([]Menu, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	menues := []Menu{}
+	err := r.db.SelectContext(ctx, &menues,
+		`SELECT id, name, protein, fat, carb, alcohol, serving_size, serving_unit, creator_id, status, parent_menu_id, version, revision, created_timestamp
+		FROM nutritioncalculator_menu
+		WHERE id = ANY($1)`,
+		pq.Array(ids))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	err = r.loadNutrients(ctx, menues)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return menues, nil
+}
+
+// GetMenuHistory walks up to the first version of the menu and returns every version that descends from it ordered by version
+func (r menuRepositoryDB) GetMenuHistory(ctx context.Context, id int) ([]Menu, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	menues := []Menu{}
+	err := r.db.SelectContext(ctx, &menues,
+		`WITH RECURSIVE ancestor AS (
+			SELECT id, parent_menu_id FROM nutritioncalculator_menu WHERE id = $1
+			UNION ALL
+			SELECT m.id, m.parent_menu_id FROM nutritioncalculator_menu AS m INNER JOIN ancestor AS a ON m.id = a.parent_menu_id
+		), lineage AS (
+			SELECT id FROM ancestor WHERE parent_menu_id IS NULL
+			UNION ALL
+			SELECT m.id FROM nutritioncalculator_menu AS m INNER JOIN lineage AS l ON m.parent_menu_id = l.id
+		)
+		SELECT menu.id, menu.name, menu.protein , menu.fat, menu.carb , menu.alcohol, menu.serving_size, menu.serving_unit, menu.creator_id , u1.username AS creator_name, menu.status, menu.created_timestamp, menu.status, COUNT(u2.user_id) AS count_like, menu.parent_menu_id, menu.version, menu.revision
+		FROM (nutritioncalculator_menu AS menu INNER JOIN nutritioncalculator_user AS u1 ON menu.creator_id = u1.user_id ) 
+		LEFT JOIN user_favorite_menu AS u2 ON u2.menu_id = menu.id
+		WHERE menu.id IN (SELECT id FROM lineage)
+		GROUP BY 1, 2,3,4,5,6,7,8,9, 10, 11, 12, 13, 15, 16, 17
+		ORDER BY menu.version, menu.id`,
+		id)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	err = r.loadNutrients(ctx, menues)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return menues, nil
+}
+
+func (r menuRepositoryDB) UpdateMenu(ctx context.Context, menu Menu) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE nutritioncalculator_menu SET status=0,revision=revision+1 WHERE id=$1 AND revision=$2 AND status=1",
+			menu.Id,
+			menu.Revision)
+		return checkRevision(result, err)
+	})
+	return dbError(err)
 }
 
-func (r menuRepositoryDB) UpdateMenu(menu Menu) error {
-	tx := r.db.MustBegin()
-	tx.MustExec("UPDATE nutritioncalculator_menu SET status=0 WHERE id=$1",
-		menu.Id)
-	err := tx.Commit()
+func (r menuRepositoryDB) loadNutrients(ctx context.Context, menues []Menu) error {
+	menuIds := []int{}
+	for _, menu := range menues {
+		menuIds = append(menuIds, menu.Id)
+	}
+	nutrients, err := selectMenuNutrients(ctx, r.db, menuIds)
 	if err != nil {
 		return err
 	}
+	for i := range menues {
+		menues[i].Nutrients = nutrients[menues[i].Id]
+	}
 	return nil
 }
+
+// RepointMenu moves the favorite menues and the favorite list items of the old menu to the new version for every user that opts in,
+// a favorite list item in a unit that the new version can not be measured in stays on the old menu
+func (r menuRepositoryDB) RepointMenu(ctx context.Context, oldMenuId int, newMenu Menu) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, `UPDATE nutritioncalculator_user SET revision = revision + 1
+			WHERE auto_update_menues AND user_id IN (SELECT user_id FROM user_favorite_menu WHERE menu_id = $1)`,
+			oldMenuId)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE nutritioncalculator_favorite_list AS fl SET revision = fl.revision + 1
+			FROM nutritioncalculator_user AS u
+			WHERE u.user_id = fl.user_id AND u.auto_update_menues
+			AND fl.id IN (SELECT favlist_id FROM favlist_item WHERE menu_id = $1 AND unit IN ('serving', $2))`,
+			oldMenuId, newMenu.ServingUnit)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO user_favorite_menu (user_id, menu_id)
+			SELECT f.user_id, $2 FROM user_favorite_menu AS f INNER JOIN nutritioncalculator_user AS u ON u.user_id = f.user_id
+			WHERE f.menu_id = $1 AND u.auto_update_menues
+			ON CONFLICT DO NOTHING`,
+			oldMenuId, newMenu.Id)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM user_favorite_menu AS f USING nutritioncalculator_user AS u
+			WHERE u.user_id = f.user_id AND f.menu_id = $1 AND u.auto_update_menues`,
+			oldMenuId)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO favlist_item (favlist_id, menu_id, quantity, unit)
+			SELECT fi.favlist_id, $2, fi.quantity, fi.unit FROM favlist_item AS fi
+			INNER JOIN nutritioncalculator_favorite_list AS fl ON fl.id = fi.favlist_id
+			INNER JOIN nutritioncalculator_user AS u ON u.user_id = fl.user_id
+			WHERE fi.menu_id = $1 AND u.auto_update_menues AND fi.unit IN ('serving', $3)
+			ON CONFLICT (favlist_id, menu_id, unit) DO UPDATE SET quantity = favlist_item.quantity + EXCLUDED.quantity`,
+			oldMenuId, newMenu.Id, newMenu.ServingUnit)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM favlist_item AS fi USING nutritioncalculator_favorite_list AS fl, nutritioncalculator_user AS u
+			WHERE fl.id = fi.favlist_id AND u.user_id = fl.user_id AND fi.menu_id = $1 AND u.auto_update_menues AND fi.unit IN ('serving', $2)`,
+			oldMenuId, newMenu.ServingUnit)
+		return err
+	})
+	return dbError(err)
+}
@@ -1,6 +1,10 @@
 package repository
 
-import "github.com/stretchr/testify/mock"
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
 
 type menuRepositoryMock struct {
 	mock.Mock
@@ -10,22 +14,37 @@ func NewMenuRepositoryMock() *menuRepositoryMock {
 	return &menuRepositoryMock{}
 }
 
-func (r *menuRepositoryMock) CreateMenu(menu Menu) (*Menu, error) {
+func (r *menuRepositoryMock) CreateMenu(ctx context.Context, menu Menu) (*Menu, error) {
 	args := r.Called(menu)
 	return args.Get(0).(*Menu), args.Error(1)
 }
 
-func (r *menuRepositoryMock) GetAllMenues() ([]Menu, error) {
-	args := r.Called()
+func (r *menuRepositoryMock) GetAllMenues(ctx context.Context, filter MenuFilter) ([]Menu, error) {
+	args := r.Called(filter)
 	return args.Get(0).([]Menu), args.Error(1)
 }
 
-func (r *menuRepositoryMock) GetMenuById(menuId int) (*Menu, error) {
+func (r *menuRepositoryMock) GetMenuById(ctx context.Context, menuId int) (*Menu, error) {
 	args := r.Called(menuId)
 	return args.Get(0).(*Menu), args.Error(1)
 }
 
-func (r *menuRepositoryMock) UpdateMenu(menu Menu) error {
+func (r *menuRepositoryMock) GetMenusByIds(ctx context.Context, menuIds []int) ([]Menu, error) {
+	args := r.Called(menuIds)
+	return args.Get(0).([]Menu), args.Error(1)
+}
+
+func (r *menuRepositoryMock) UpdateMenu(ctx context.Context, menu Menu) error {
 	args := r.Called(menu)
 	return args.Error(0)
 }
+
+func (r *menuRepositoryMock) GetMenuHistory(ctx context.Context, menuId int) ([]Menu, error) {
+	args := r.Called(menuId)
+	return args.Get(0).([]Menu), args.Error(1)
+}
+
+func (r *menuRepositoryMock) RepointMenu(ctx context.Context, oldMenuId int, newMenu Menu) error {
+	args := r.Called(oldMenuId, newMenu)
+	return args.Error(0)
+}
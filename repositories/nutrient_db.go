@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ownedNutrient struct {
+	OwnerId  int     `db:"owner_id"`
+	Nutrient string  `db:"nutrient"`
+	Amount   float64 `db:"amount"`
+}
+
+func groupNutrients(rows []ownedNutrient) map[int]map[string]float64 {
+	nutrients := map[int]map[string]float64{}
+	for _, row := range rows {
+		if nutrients[row.OwnerId] == nil {
+			nutrients[row.OwnerId] = map[string]float64{}
+		}
+		nutrients[row.OwnerId][row.Nutrient] = row.Amount
+	}
+	return nutrients
+}
+
+// selectMenuNutrients loads the known nutrients of every menu id at once and groups them by the menu id
+func selectMenuNutrients(ctx context.Context, q sqlx.QueryerContext, menuIds []int) (map[int]map[string]float64, error) {
+	if len(menuIds) == 0 {
+		return map[int]map[string]float64{}, nil
+	}
+	rows := []ownedNutrient{}
+	err := sqlx.SelectContext(ctx, q, &rows,
+		"SELECT menu_id AS owner_id, nutrient, amount FROM menu_nutrient WHERE menu_id = ANY($1)",
+		pq.Array(menuIds))
+	if err != nil {
+		return nil, err
+	}
+	return groupNutrients(rows), nil
+}
+
+// insertMenuNutrients stores the known nutrients of a new menu, the unknown ones are left without a row
+func insertMenuNutrients(ctx context.Context, tx *sqlx.Tx, menuId int, nutrients map[string]float64) error {
+	for nutrient, amount := range nutrients {
+		_, err := tx.ExecContext(ctx, "INSERT INTO menu_nutrient (menu_id,nutrient,amount) VALUES ($1,$2,$3)",
+			menuId,
+			nutrient,
+			amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectItemNutrients sums the known nutrients of the items of every owner id the same way as the macros,
+// a menu without the nutrient does not add anything to the total
+func selectItemNutrients(ctx context.Context, q sqlx.QueryerContext, table string, ownerColumn string, ownerIds []int) (map[int]map[string]float64, error) {
+	if len(ownerIds) == 0 {
+		return map[int]map[string]float64{}, nil
+	}
+	rows := []ownedNutrient{}
+	err := sqlx.SelectContext(ctx, q, &rows,
+		fmt.Sprintf(`SELECT i.%[2]s AS owner_id, mn.nutrient,
+		SUM(CASE WHEN i.unit = 'serving' THEN i.quantity ELSE i.quantity / m.serving_size END * mn.amount) AS amount
+		FROM %[1]s AS i
+		INNER JOIN nutritioncalculator_menu AS m ON m.id = i.menu_id
+		INNER JOIN menu_nutrient AS mn ON mn.menu_id = i.menu_id
+		WHERE i.%[2]s = ANY($1)
+		GROUP BY 1, 2`, table, ownerColumn),
+		pq.Array(ownerIds))
+	if err != nil {
+		return nil, err
+	}
+	return groupNutrients(rows), nil
+}
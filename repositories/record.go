@@ -1,26 +1,50 @@
 package repository
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Record struct {
-	Id               int       `db:"id"`
-	UserId           string    `db:"user_id"`
-	List             string    `db:"list"`
-	Menues           string    `db:"menues"`
-	Note             string    `db:"note"`
-	Weight           float64   `db:"weight"`
-	Protein          float64   `db:"protein"`
-	Fat              float64   `db:"fat"`
-	Carb             float64   `db:"carb"`
-	EventTimestamp   time.Time `db:"event_timestamp"`
-	Status           int       `db:"status"`
-	IsUpdated        int       `db:"is_updated"`
-	CreatedTimestamp time.Time `db:"created_timestamp"`
+	Id               int                `db:"id"`
+	UserId           string             `db:"user_id"`
+	Items            []Item             `db:"-"`
+	Menues           string             `db:"menues"`
+	Note             string             `db:"note"`
+	Weight           float64            `db:"weight"`
+	Protein          float64            `db:"protein"`
+	Fat              float64            `db:"fat"`
+	Carb             float64            `db:"carb"`
+	Alcohol          float64            `db:"alcohol"`
+	Nutrients        map[string]float64 `db:"-"`
+	EventTimestamp   time.Time          `db:"event_timestamp"`
+	Status           int                `db:"status"`
+	IsUpdated        int                `db:"is_updated"`
+	Revision         int                `db:"revision"`  // Adds 1 on every update
+	ClientId         *string            `db:"client_id"` // Id that the client generated for a "Record" that is created offline
+	CreatedTimestamp time.Time          `db:"created_timestamp"`
+}
+
+// RecordFilter narrows and orders the records of a user, a zero value returns every record from the oldest
+type RecordFilter struct {
+	From       *time.Time    // Inclusive lower bound of the event timestamp
+	To         *time.Time    // Exclusive upper bound of the event timestamp
+	After      *RecordCursor // Position of the last record of the previous page
+	Descending bool          // Newest record first
+	Limit      int           // Maximum amount of records, 0 = no limit
+}
+
+// RecordCursor is the position of a record in the (event_timestamp, id) order
+type RecordCursor struct {
+	EventTimestamp time.Time
+	Id             int
 }
 
 type RecordRepository interface {
-	GetRecordsByUserId(string) ([]Record, error)
-	GetRecordById(int) (*Record, error)
-	CreateRecord(Record) (*Record, error)
-	UpdateRecord(Record) error
+	GetRecordsByUserId(context.Context, string, RecordFilter) ([]Record, error)
+	GetRecordById(context.Context, int) (*Record, error)
+	CreateRecord(context.Context, Record) (*Record, error)
+	UpdateRecord(context.Context, Record) error
+	GetRecordByClientId(context.Context, string, string) (*Record, error)
+	GetRecordByIdIncludingDeleted(context.Context, int) (*Record, error)
 }
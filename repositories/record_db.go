@@ -1,86 +1,168 @@
 package repository
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
 
 type recordRepositoryDB struct {
-	db *sqlx.DB
+	db           dbtx
+	queryTimeout time.Duration
 }
 
-func NewRecordRepositoryDB(db *sqlx.DB) recordRepositoryDB {
-	return recordRepositoryDB{db: db}
+func NewRecordRepositoryDB(db *sqlx.DB, queryTimeout time.Duration) recordRepositoryDB {
+	return recordRepositoryDB{db: db, queryTimeout: queryTimeout}
 }
 
-func (r recordRepositoryDB) GetRecordsByUserId(userId string) ([]Record, error) {
+const selectRecord = `SELECT r.id, r.user_id, r.note, r.weight, r.status, r.revision, r.client_id, r.created_timestamp, r.event_timestamp,
+		COALESCE(string_agg(concat(m."name", '-', ri.quantity, NULLIF(ri.unit, 'serving'), ' '), ',' ORDER BY ri.menu_id, ri.unit), '') AS menues,
+		COALESCE(SUM(p.servings * m.protein), 0) AS protein, COALESCE(SUM(p.servings * m.fat), 0) AS fat, COALESCE(SUM(p.servings * m.carb), 0) AS carb,
+		COALESCE(SUM(p.servings * m.alcohol), 0) AS alcohol,
+		COALESCE(MIN(m.status), 1) AS is_updated
+		FROM nutritioncalculator_record AS r
+		LEFT JOIN record_item AS ri ON ri.record_id = r.id
+		LEFT JOIN nutritioncalculator_menu AS m ON m.id = ri.menu_id
+		LEFT JOIN LATERAL (SELECT CASE WHEN ri.unit = 'serving' THEN ri.quantity ELSE ri.quantity / m.serving_size END AS servings) AS p ON true`
+
+func (r recordRepositoryDB) GetRecordsByUserId(ctx context.Context, userId string, filter RecordFilter) ([]Record, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	conditions := []string{"r.user_id = $1", "r.status = 1"}
+	args := []interface{}{userId}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("r.event_timestamp >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("r.event_timestamp < $%d", len(args)))
+	}
+	order, comparison := "ASC", ">"
+	if filter.Descending {
+		order, comparison = "DESC", "<"
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.EventTimestamp, filter.After.Id)
+		conditions = append(conditions, fmt.Sprintf("(r.event_timestamp, r.id) %s ($%d, $%d)", comparison, len(args)-1, len(args)))
+	}
+	query := selectRecord + `
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY r.id
+		ORDER BY r.event_timestamp ` + order + `, r.id ` + order
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
 	records := []Record{}
-	err := r.db.Select(&records,
-		`SELECT id, user_id , list, note, weight, status, created_timestamp , event_timestamp, string_agg(concat(menu_name, '-',nums,' ') ,',') AS menues, SUM(nums * protein ) AS protein, SUM(nums * fat ) AS fat, SUM(nums * carb ) AS carb, MIN(menu_status) AS is_updated
-		FROM 
-		(
-		SELECT r.id, r.user_id, r.list, r.note, r.weight, r.status, r.created_timestamp, r.event_timestamp,
-		cardinality(regexp_split_to_array(r.list,',')) - cardinality(array_remove(regexp_split_to_array(r.list,','),CAST(m.id AS text))) AS nums
-		, m."name" AS menu_name, m.protein , m.fat, m.carb , m.status AS menu_status
-		FROM nutritioncalculator_record AS r LEFT JOIN nutritioncalculator_menu AS m  
-		ON CAST(m.id AS text) = ANY(regexp_split_to_array(r.list,','))
-		WHERE r.user_id = $1 AND r.status = 1
-		) AS t
-		GROUP BY 1, 2, 3, 4, 5, 6, 7, 8`,
-		userId)
+	err := r.db.SelectContext(ctx, &records, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
+	}
+	err = r.loadItems(ctx, records)
+	if err != nil {
+		return nil, dbError(err)
 	}
 	return records, nil
 }
 
-func (r recordRepositoryDB) GetRecordById(recordId int) (*Record, error) {
+func (r recordRepositoryDB) GetRecordById(ctx context.Context, recordId int) (*Record, error) {
+	return r.getRecord(ctx, "r.id = $1 AND r.status = 1", recordId)
+}
+
+// GetRecordByIdIncludingDeleted finds the "Record" whatever its status is, so a deleted one can be sent back as a tombstone
+func (r recordRepositoryDB) GetRecordByIdIncludingDeleted(ctx context.Context, recordId int) (*Record, error) {
+	return r.getRecord(ctx, "r.id = $1", recordId)
+}
+
+// GetRecordByClientId finds the "Record" that the client created offline, a deleted one is returned too
+func (r recordRepositoryDB) GetRecordByClientId(ctx context.Context, userId string, clientId string) (*Record, error) {
+	return r.getRecord(ctx, "r.user_id = $1 AND r.client_id = $2", userId, clientId)
+}
+
+// getRecord gets the one "Record" that matches the condition with its items
+func (r recordRepositoryDB) getRecord(ctx context.Context, condition string, args ...interface{}) (*Record, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
 	record := Record{}
-	err := r.db.Get(&record,
-		`SELECT id, user_id , list, note, weight, status, created_timestamp , event_timestamp, string_agg(concat(menu_name, '-',nums,' ') ,',') AS menues, SUM(nums * protein ) AS protein, SUM(nums * fat ) AS fat, SUM(nums * carb ) AS carb, MIN(menu_status) AS is_updated
-		FROM 
-		(
-		SELECT r.id, r.user_id, r.list, r.note, r.weight, r.status, r.created_timestamp , r.event_timestamp,
-		cardinality(regexp_split_to_array(r.list,',')) - cardinality(array_remove(regexp_split_to_array(r.list,','),CAST(m.id AS text))) AS nums
-		, m."name" AS menu_name, m.protein , m.fat, m.carb , m.status AS menu_status
-		FROM nutritioncalculator_record AS r LEFT JOIN nutritioncalculator_menu AS m  
-		ON CAST(m.id AS text) = ANY(regexp_split_to_array(r.list,','))
-		WHERE r.id = $1 AND r.status = 1
-		) AS t
-		GROUP BY 1, 2, 3, 4, 5, 6, 7, 8`,
-		recordId)
+	err := r.db.GetContext(ctx, &record,
+		selectRecord+`
+		WHERE `+condition+`
+		GROUP BY r.id`,
+		args...)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
-	return &record, nil
+	records := []Record{record}
+	err = r.loadItems(ctx, records)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &records[0], nil
 }
 
-func (r recordRepositoryDB) CreateRecord(record Record) (*Record, error) {
-	var recordId int
-	err := r.db.QueryRow("INSERT INTO nutritioncalculator_record (user_id,List,weight,note,event_timestamp,status,created_timestamp) VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id",
-		record.UserId,
-		record.List,
-		record.Weight,
-		record.Note,
-		record.EventTimestamp,
-		record.Status,
-		record.CreatedTimestamp).Scan(&recordId)
+func (r recordRepositoryDB) CreateRecord(ctx context.Context, record Record) (*Record, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowContext(ctx, "INSERT INTO nutritioncalculator_record (user_id,weight,note,event_timestamp,status,client_id,created_timestamp) VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id, revision",
+			record.UserId,
+			record.Weight,
+			record.Note,
+			record.EventTimestamp,
+			record.Status,
+			record.ClientId,
+			record.CreatedTimestamp).Scan(&record.Id, &record.Revision)
+		if err != nil {
+			return err
+		}
+		return replaceItems(ctx, tx, "record_item", "record_id", record.Id, record.Items)
+	})
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
-	record.Id = recordId
 	return &record, nil
 }
 
-func (r recordRepositoryDB) UpdateRecord(record Record) error {
-	tx := r.db.MustBegin()
-	tx.MustExec("UPDATE nutritioncalculator_record SET list=$1,note=$2,weight=$3,event_timestamp=$4,status=$5 WHERE id=$6",
-		record.List,
-		record.Note,
-		record.Weight,
-		record.EventTimestamp,
-		record.Status,
-		record.Id)
-	err := tx.Commit()
+func (r recordRepositoryDB) UpdateRecord(ctx context.Context, record Record) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE nutritioncalculator_record SET note=$1,weight=$2,event_timestamp=$3,status=$4,revision=revision+1 WHERE id=$5 AND revision=$6",
+			record.Note,
+			record.Weight,
+			record.EventTimestamp,
+			record.Status,
+			record.Id,
+			record.Revision)
+		err = checkRevision(result, err)
+		if err != nil {
+			return err
+		}
+		return replaceItems(ctx, tx, "record_item", "record_id", record.Id, record.Items)
+	})
+	return dbError(err)
+}
+
+func (r recordRepositoryDB) loadItems(ctx context.Context, records []Record) error {
+	recordIds := []int{}
+	for _, record := range records {
+		recordIds = append(recordIds, record.Id)
+	}
+	items, err := selectItems(ctx, r.db, "record_item", "record_id", recordIds)
+	if err != nil {
+		return err
+	}
+	nutrients, err := selectItemNutrients(ctx, r.db, "record_item", "record_id", recordIds)
 	if err != nil {
 		return err
 	}
+	for i := range records {
+		records[i].Items = items[records[i].Id]
+		records[i].Nutrients = nutrients[records[i].Id]
+	}
 	return nil
 }
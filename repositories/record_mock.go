@@ -1,6 +1,10 @@
 package repository
 
-import "github.com/stretchr/testify/mock"
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
 
 type recordRepositoryMock struct {
 	mock.Mock
@@ -10,22 +14,32 @@ func NewRecordRepositoryMock() *recordRepositoryMock {
 	return &recordRepositoryMock{}
 }
 
-func (r *recordRepositoryMock) GetRecordsByUserId(userId string) ([]Record, error) {
-	args := r.Called(userId)
+func (r *recordRepositoryMock) GetRecordsByUserId(ctx context.Context, userId string, filter RecordFilter) ([]Record, error) {
+	args := r.Called(userId, filter)
 	return args.Get(0).([]Record), args.Error(1)
 }
 
-func (r *recordRepositoryMock) GetRecordById(recordId int) (*Record, error) {
+func (r *recordRepositoryMock) GetRecordById(ctx context.Context, recordId int) (*Record, error) {
 	args := r.Called(recordId)
 	return args.Get(0).(*Record), args.Error(1)
 }
 
-func (r *recordRepositoryMock) CreateRecord(record Record) (*Record, error) {
+func (r *recordRepositoryMock) CreateRecord(ctx context.Context, record Record) (*Record, error) {
 	args := r.Called(record)
 	return args.Get(0).(*Record), args.Error(1)
 }
 
-func (r *recordRepositoryMock) UpdateRecord(record Record) error {
+func (r *recordRepositoryMock) UpdateRecord(ctx context.Context, record Record) error {
 	args := r.Called(record)
 	return args.Error(0)
 }
+
+func (r *recordRepositoryMock) GetRecordByClientId(ctx context.Context, userId string, clientId string) (*Record, error) {
+	args := r.Called(userId, clientId)
+	return args.Get(0).(*Record), args.Error(1)
+}
+
+func (r *recordRepositoryMock) GetRecordByIdIncludingDeleted(ctx context.Context, recordId int) (*Record, error) {
+	args := r.Called(recordId)
+	return args.Get(0).(*Record), args.Error(1)
+}
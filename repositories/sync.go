@@ -0,0 +1,17 @@
+package repository
+
+import "context"
+
+// Changes are the rows of a "User" that are changed by the transactions that finished between the cursor and the next cursor,
+// a deleted "Record", "Favorite List" or "Menu" (status = 0) is a tombstone in them
+type Changes struct {
+	Records        []Record
+	FavLists       []FavList
+	FavoriteMenues []int  // nil = the favorite menues are not changed
+	Menues         []Menu // "Menu" that is changed or that a changed row uses
+	NextCursor     int64  // Cursor of the next changes
+}
+
+type SyncRepository interface {
+	GetChanges(context.Context, string, int64) (*Changes, error)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type syncRepositoryDB struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewSyncRepositoryDB(db *sqlx.DB, queryTimeout time.Duration) syncRepositoryDB {
+	return syncRepositoryDB{db: db, queryTimeout: queryTimeout}
+}
+
+// GetChanges reads every change in one snapshot, the cursor is the oldest transaction that is still running at the snapshot
+// so every transaction before it is already in the snapshot and the ones from it are returned by the next call
+func (r syncRepositoryDB) GetChanges(ctx context.Context, userId string, cursor int64) (*Changes, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer tx.Rollback()
+	changes := Changes{Records: []Record{}, FavLists: []FavList{}, Menues: []Menu{}}
+	err = tx.GetContext(ctx, &changes.NextCursor, "SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint")
+	if err != nil {
+		return nil, dbError(err)
+	}
+	err = tx.SelectContext(ctx, &changes.Records,
+		selectRecord+`
+		WHERE r.user_id = $1 AND r.changed_xid >= $2 AND r.changed_xid < $3
+		GROUP BY r.id
+		ORDER BY r.id`,
+		userId, cursor, changes.NextCursor)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	err = recordRepositoryDB{db: tx}.loadItems(ctx, changes.Records)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	err = tx.SelectContext(ctx, &changes.FavLists,
+		selectFavList+`
+		WHERE fl.user_id = $1 AND fl.changed_xid >= $2 AND fl.changed_xid < $3
+		GROUP BY fl.id
+		ORDER BY fl.id`,
+		userId, cursor, changes.NextCursor)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	err = favListRepositoryDB{db: tx}.loadItems(ctx, changes.FavLists)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	var isUserChanged bool
+	err = tx.GetContext(ctx, &isUserChanged,
+		"SELECT changed_xid >= $2 AND changed_xid < $3 FROM nutritioncalculator_user WHERE user_id = $1",
+		userId, cursor, changes.NextCursor)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	if isUserChanged {
+		user := User{UserId: userId}
+		err = userRepositoryDB{db: tx}.loadFavoriteMenues(ctx, &user)
+		if err != nil {
+			return nil, dbError(err)
+		}
+		changes.FavoriteMenues = user.FavoriteMenues
+	}
+	err = tx.SelectContext(ctx, &changes.Menues,
+		`WITH used AS (
+			SELECT ri.menu_id, r.changed_xid FROM record_item AS ri INNER JOIN nutritioncalculator_record AS r ON r.id = ri.record_id WHERE r.user_id = $1
+			UNION ALL
+			SELECT fi.menu_id, fl.changed_xid FROM favlist_item AS fi INNER JOIN nutritioncalculator_favorite_list AS fl ON fl.id = fi.favlist_id WHERE fl.user_id = $1
+			UNION ALL
+			SELECT f.menu_id, u.changed_xid FROM user_favorite_menu AS f INNER JOIN nutritioncalculator_user AS u ON u.user_id = f.user_id WHERE f.user_id = $1
+			UNION ALL
+			SELECT id, changed_xid FROM nutritioncalculator_menu WHERE creator_id = $1
+		)
+		SELECT menu.id, menu.name, menu.protein , menu.fat, menu.carb , menu.alcohol, menu.serving_size, menu.serving_unit, menu.creator_id , u1.username AS creator_name, menu.status, menu.created_timestamp, menu.status, COUNT(u2.user_id) AS count_like, menu.parent_menu_id, menu.version, menu.revision
+		FROM (nutritioncalculator_menu AS menu INNER JOIN nutritioncalculator_user AS u1 ON menu.creator_id = u1.user_id ) 
+		LEFT JOIN user_favorite_menu AS u2 ON u2.menu_id = menu.id
+		WHERE menu.id IN (SELECT used.menu_id FROM used INNER JOIN nutritioncalculator_menu AS m ON m.id = used.menu_id
+			WHERE (used.changed_xid >= $2 AND used.changed_xid < $3) OR (m.changed_xid >= $2 AND m.changed_xid < $3))
+		GROUP BY 1, 2,3,4,5,6,7,8,9, 10, 11, 12, 13, 15, 16, 17
+		ORDER BY menu.id`,
+		userId, cursor, changes.NextCursor)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	err = menuRepositoryDB{db: tx}.loadNutrients(ctx, changes.Menues)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &changes, nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type syncRepositoryMock struct {
+	mock.Mock
+}
+
+func NewSyncRepositoryMock() *syncRepositoryMock {
+	return &syncRepositoryMock{}
+}
+
+func (r *syncRepositoryMock) GetChanges(ctx context.Context, userId string, cursor int64) (*Changes, error) {
+	args := r.Called(userId, cursor)
+	return args.Get(0).(*Changes), args.Error(1)
+}
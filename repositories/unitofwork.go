@@ -0,0 +1,31 @@
+package repository
+
+import "context"
+
+// Repositories are the repositories that share the transaction of a unit of work
+type Repositories struct {
+	Menu      MenuRepository
+	User      UserRepository
+	FavList   FavListRepository
+	Record    RecordRepository
+	WeightLog WeightLogRepository
+}
+
+// UnitOfWork runs a workflow that touches several repositories so that every change is committed or rolled back as one
+type UnitOfWork interface {
+	Do(context.Context, func(Repositories) error) error
+}
+
+type joinedUnitOfWork struct {
+	repos Repositories
+}
+
+// JoinUnitOfWork runs a workflow with the repositories of the unit of work that it is already in,
+// so a service that uses a unit of work can be a step of a bigger one
+func JoinUnitOfWork(repos Repositories) UnitOfWork {
+	return joinedUnitOfWork{repos: repos}
+}
+
+func (u joinedUnitOfWork) Do(ctx context.Context, fn func(Repositories) error) error {
+	return fn(u.repos)
+}
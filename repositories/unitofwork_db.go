@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// dbtx is satisfied by both *sqlx.DB and *sqlx.Tx so a repository can run on its own or inside a unit of work
+type dbtx interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type unitOfWorkDB struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewUnitOfWorkDB(db *sqlx.DB, queryTimeout time.Duration) unitOfWorkDB {
+	return unitOfWorkDB{db: db, queryTimeout: queryTimeout}
+}
+
+// Do gives fn the repositories bound to one transaction, the transaction is rolled back when fn returns an error
+func (u unitOfWorkDB) Do(ctx context.Context, fn func(Repositories) error) error {
+	err := withTx(ctx, u.db, func(tx *sqlx.Tx) error {
+		return fn(Repositories{
+			Menu:      menuRepositoryDB{db: tx, queryTimeout: u.queryTimeout},
+			User:      userRepositoryDB{db: tx, queryTimeout: u.queryTimeout},
+			FavList:   favListRepositoryDB{db: tx, queryTimeout: u.queryTimeout},
+			Record:    recordRepositoryDB{db: tx, queryTimeout: u.queryTimeout},
+			WeightLog: weightLogRepositoryDB{db: tx, queryTimeout: u.queryTimeout},
+		})
+	})
+	return dbError(err)
+}
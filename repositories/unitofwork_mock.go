@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type unitOfWorkMock struct {
+	mock.Mock
+	repos Repositories
+}
+
+// NewUnitOfWorkMock gives the workflow the mocked repositories, the expected "Do" call returns the result of the commit
+func NewUnitOfWorkMock(repos Repositories) *unitOfWorkMock {
+	return &unitOfWorkMock{repos: repos}
+}
+
+func (u *unitOfWorkMock) Do(ctx context.Context, fn func(Repositories) error) error {
+	err := fn(u.repos)
+	if err != nil {
+		return err
+	}
+	args := u.Called()
+	return args.Error(0)
+}
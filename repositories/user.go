@@ -1,22 +1,33 @@
 package repository
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type User struct {
-	UserId           string    `db:"user_id"`
-	Password         string    `db:"password"`
-	Username         string    `db:"username"`
-	Weight           float64   `db:"weight"`
-	Protein          float64   `db:"protein"`
-	Fat              float64   `db:"fat"`
-	Carb             float64   `db:"carb"`
-	FavoriteMenues   string    `db:"favorite_menues"`
-	CreatedTimestamp time.Time `db:"created_timestamp"`
+	UserId           string     `db:"user_id"`
+	Password         string     `db:"password"`
+	Username         string     `db:"username"`
+	Weight           float64    `db:"weight"`
+	Protein          float64    `db:"protein"`
+	Fat              float64    `db:"fat"`
+	Carb             float64    `db:"carb"`
+	Timezone         string     `db:"timezone"`
+	Sex              string     `db:"sex"`
+	BirthDate        *time.Time `db:"birth_date"`
+	Height           float64    `db:"height"`
+	ActivityLevel    string     `db:"activity_level"`
+	Goal             string     `db:"goal"`
+	AutoUpdateMenues bool       `db:"auto_update_menues"`
+	FavoriteMenues   []int      `db:"-"`
+	Revision         int        `db:"revision"` // Adds 1 on every update
+	CreatedTimestamp time.Time  `db:"created_timestamp"`
 }
 
 type UserRepository interface {
-	GetUserById(string) (*User, error)
-	GetUserByUsername(string) (*User, error)
-	CreateUser(User) error
-	UpdateUser(User) error
+	GetUserById(context.Context, string) (*User, error)
+	GetUserByUsername(context.Context, string) (*User, error)
+	CreateUser(context.Context, User) error
+	UpdateUser(context.Context, User) error
 }
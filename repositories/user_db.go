@@ -1,76 +1,133 @@
 package repository
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
 
 type userRepositoryDB struct {
-	db *sqlx.DB
+	db           dbtx
+	queryTimeout time.Duration
 }
 
-func NewUserRepositoryDB(db *sqlx.DB) userRepositoryDB {
-	return userRepositoryDB{db: db}
+func NewUserRepositoryDB(db *sqlx.DB, queryTimeout time.Duration) userRepositoryDB {
+	return userRepositoryDB{db: db, queryTimeout: queryTimeout}
 }
 
-func (r userRepositoryDB) GetUserById(userId string) (*User, error) {
+func (r userRepositoryDB) GetUserById(ctx context.Context, userId string) (*User, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
 	user := User{}
-	err := r.db.Get(&user,
+	err := r.db.GetContext(ctx, &user,
 		`SELECT 
-		*
+		user_id, password, username, weight, protein, fat, carb, timezone, sex, birth_date, height, activity_level, goal, auto_update_menues, revision, created_timestamp
 	FROM nutritioncalculator_user
 	WHERE user_id=$1`,
 		userId)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
+	}
+	err = r.loadFavoriteMenues(ctx, &user)
+	if err != nil {
+		return nil, dbError(err)
 	}
 	return &user, nil
 }
 
-func (r userRepositoryDB) GetUserByUsername(username string) (*User, error) {
+func (r userRepositoryDB) GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
 	user := User{}
-	err := r.db.Get(&user,
+	err := r.db.GetContext(ctx, &user,
 		`SELECT 
-		*
+		user_id, password, username, weight, protein, fat, carb, timezone, sex, birth_date, height, activity_level, goal, auto_update_menues, revision, created_timestamp
 	FROM nutritioncalculator_user
 	WHERE username=$1`,
 		username)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
+	}
+	err = r.loadFavoriteMenues(ctx, &user)
+	if err != nil {
+		return nil, dbError(err)
 	}
 	return &user, nil
 }
 
-func (r userRepositoryDB) CreateUser(user User) error {
-	tx := r.db.MustBegin()
-	tx.MustExec("INSERT INTO nutritioncalculator_user (user_id,password,username,weight,protein,fat,carb,favorite_menues,created_timestamp) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)",
-		user.UserId,
-		user.Password,
-		user.Username,
-		user.Weight,
-		user.Protein,
-		user.Fat,
-		user.Carb,
-		user.FavoriteMenues,
-		user.CreatedTimestamp)
-	err := tx.Commit()
-	if err != nil {
-		return err
-	}
-	return nil
+func (r userRepositoryDB) CreateUser(ctx context.Context, user User) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO nutritioncalculator_user (user_id,password,username,weight,protein,fat,carb,timezone,sex,birth_date,height,activity_level,goal,auto_update_menues,created_timestamp) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)",
+			user.UserId,
+			user.Password,
+			user.Username,
+			user.Weight,
+			user.Protein,
+			user.Fat,
+			user.Carb,
+			user.Timezone,
+			user.Sex,
+			user.BirthDate,
+			user.Height,
+			user.ActivityLevel,
+			user.Goal,
+			user.AutoUpdateMenues,
+			user.CreatedTimestamp)
+		if err != nil {
+			return err
+		}
+		return replaceFavoriteMenues(ctx, tx, user)
+	})
+	return dbError(err)
+}
+
+func (r userRepositoryDB) UpdateUser(ctx context.Context, user User) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE nutritioncalculator_user SET password=$1,username=$2,weight=$3,protein=$4,fat=$5,carb=$6,timezone=$7,sex=$8,birth_date=$9,height=$10,activity_level=$11,goal=$12,auto_update_menues=$13,revision=revision+1 WHERE user_id=$14 AND revision=$15",
+			user.Password,
+			user.Username,
+			user.Weight,
+			user.Protein,
+			user.Fat,
+			user.Carb,
+			user.Timezone,
+			user.Sex,
+			user.BirthDate,
+			user.Height,
+			user.ActivityLevel,
+			user.Goal,
+			user.AutoUpdateMenues,
+			user.UserId,
+			user.Revision)
+		err = checkRevision(result, err)
+		if err != nil {
+			return err
+		}
+		return replaceFavoriteMenues(ctx, tx, user)
+	})
+	return dbError(err)
 }
 
-func (r userRepositoryDB) UpdateUser(user User) error {
-	tx := r.db.MustBegin()
-	tx.MustExec("UPDATE nutritioncalculator_user SET password=$1,username=$2,weight=$3,protein=$4,fat=$5,carb=$6,favorite_menues=$7 WHERE user_id=$8",
-		user.Password,
-		user.Username,
-		user.Weight,
-		user.Protein,
-		user.Fat,
-		user.Carb,
-		user.FavoriteMenues,
-		user.UserId)
-	err := tx.Commit()
+func (r userRepositoryDB) loadFavoriteMenues(ctx context.Context, user *User) error {
+	user.FavoriteMenues = []int{}
+	return r.db.SelectContext(ctx, &user.FavoriteMenues, "SELECT menu_id FROM user_favorite_menu WHERE user_id=$1 ORDER BY menu_id", user.UserId)
+}
+
+func replaceFavoriteMenues(ctx context.Context, tx *sqlx.Tx, user User) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM user_favorite_menu WHERE user_id=$1", user.UserId)
 	if err != nil {
 		return err
 	}
+	for _, menuId := range user.FavoriteMenues {
+		_, err = tx.ExecContext(ctx, "INSERT INTO user_favorite_menu (user_id,menu_id) VALUES ($1,$2)", user.UserId, menuId)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
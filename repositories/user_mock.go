@@ -1,6 +1,10 @@
 package repository
 
-import "github.com/stretchr/testify/mock"
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
 
 type userRepositoryMock struct {
 	mock.Mock
@@ -10,22 +14,22 @@ func NewUserRepositoryMock() *userRepositoryMock {
 	return &userRepositoryMock{}
 }
 
-func (r *userRepositoryMock) GetUserById(userId string) (*User, error) {
+func (r *userRepositoryMock) GetUserById(ctx context.Context, userId string) (*User, error) {
 	args := r.Called(userId)
 	return args.Get(0).(*User), args.Error(1)
 }
 
-func (r *userRepositoryMock) GetUserByUsername(username string) (*User, error) {
+func (r *userRepositoryMock) GetUserByUsername(ctx context.Context, username string) (*User, error) {
 	args := r.Called(username)
 	return args.Get(0).(*User), args.Error(1)
 }
 
-func (r *userRepositoryMock) CreateUser(user User) error {
+func (r *userRepositoryMock) CreateUser(ctx context.Context, user User) error {
 	args := r.Called(user)
 	return args.Error(0)
 }
 
-func (r *userRepositoryMock) UpdateUser(user User) error {
+func (r *userRepositoryMock) UpdateUser(ctx context.Context, user User) error {
 	args := r.Called(user)
 	return args.Error(0)
 }
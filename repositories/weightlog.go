@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type WeightLog struct {
+	Id               int       `db:"id"`
+	UserId           string    `db:"user_id"`
+	Weight           float64   `db:"weight"`
+	BodyFat          *float64  `db:"body_fat"`
+	LoggedTimestamp  time.Time `db:"logged_timestamp"`
+	Status           int       `db:"status"`
+	CreatedTimestamp time.Time `db:"created_timestamp"`
+}
+
+type WeightLogRepository interface {
+	GetWeightLogsByUserId(context.Context, string, *time.Time, *time.Time) ([]WeightLog, error)
+	GetWeightLogById(context.Context, int) (*WeightLog, error)
+	CreateWeightLog(context.Context, WeightLog) (*WeightLog, error)
+	UpdateWeightLog(context.Context, WeightLog) error
+}
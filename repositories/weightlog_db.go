@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type weightLogRepositoryDB struct {
+	db           dbtx
+	queryTimeout time.Duration
+}
+
+func NewWeightLogRepositoryDB(db *sqlx.DB, queryTimeout time.Duration) weightLogRepositoryDB {
+	return weightLogRepositoryDB{db: db, queryTimeout: queryTimeout}
+}
+
+// GetWeightLogsByUserId returns the active weight logs from "from" (inclusive) to "to" (exclusive) in logged order, a nil bound is open
+func (r weightLogRepositoryDB) GetWeightLogsByUserId(ctx context.Context, userId string, from *time.Time, to *time.Time) ([]WeightLog, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	weightLogs := []WeightLog{}
+	err := r.db.SelectContext(ctx, &weightLogs,
+		`SELECT id, user_id, weight, body_fat, logged_timestamp, status, created_timestamp
+		FROM weight_log
+		WHERE user_id = $1 AND status = 1
+		AND ($2::timestamp IS NULL OR logged_timestamp >= $2)
+		AND ($3::timestamp IS NULL OR logged_timestamp < $3)
+		ORDER BY logged_timestamp, id`,
+		userId,
+		from,
+		to)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return weightLogs, nil
+}
+
+func (r weightLogRepositoryDB) GetWeightLogById(ctx context.Context, weightLogId int) (*WeightLog, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	weightLog := WeightLog{}
+	err := r.db.GetContext(ctx, &weightLog,
+		`SELECT id, user_id, weight, body_fat, logged_timestamp, status, created_timestamp
+		FROM weight_log
+		WHERE id = $1 AND status = 1`,
+		weightLogId)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &weightLog, nil
+}
+
+func (r weightLogRepositoryDB) CreateWeightLog(ctx context.Context, weightLog WeightLog) (*WeightLog, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	err := r.db.QueryRowContext(ctx, "INSERT INTO weight_log (user_id,weight,body_fat,logged_timestamp,status,created_timestamp) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id",
+		weightLog.UserId,
+		weightLog.Weight,
+		weightLog.BodyFat,
+		weightLog.LoggedTimestamp,
+		weightLog.Status,
+		weightLog.CreatedTimestamp).Scan(&weightLog.Id)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &weightLog, nil
+}
+
+func (r weightLogRepositoryDB) UpdateWeightLog(ctx context.Context, weightLog WeightLog) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "UPDATE weight_log SET weight=$1,body_fat=$2,logged_timestamp=$3,status=$4 WHERE id=$5",
+		weightLog.Weight,
+		weightLog.BodyFat,
+		weightLog.LoggedTimestamp,
+		weightLog.Status,
+		weightLog.Id)
+	return dbError(err)
+}
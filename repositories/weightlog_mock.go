@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type weightLogRepositoryMock struct {
+	mock.Mock
+}
+
+func NewWeightLogRepositoryMock() *weightLogRepositoryMock {
+	return &weightLogRepositoryMock{}
+}
+
+func (r *weightLogRepositoryMock) GetWeightLogsByUserId(ctx context.Context, userId string, from *time.Time, to *time.Time) ([]WeightLog, error) {
+	args := r.Called(userId, from, to)
+	return args.Get(0).([]WeightLog), args.Error(1)
+}
+
+func (r *weightLogRepositoryMock) GetWeightLogById(ctx context.Context, weightLogId int) (*WeightLog, error) {
+	args := r.Called(weightLogId)
+	return args.Get(0).(*WeightLog), args.Error(1)
+}
+
+func (r *weightLogRepositoryMock) CreateWeightLog(ctx context.Context, weightLog WeightLog) (*WeightLog, error) {
+	args := r.Called(weightLog)
+	return args.Get(0).(*WeightLog), args.Error(1)
+}
+
+func (r *weightLogRepositoryMock) UpdateWeightLog(ctx context.Context, weightLog WeightLog) error {
+	args := r.Called(weightLog)
+	return args.Error(0)
+}
@@ -0,0 +1,14 @@
+package service
+
+import "time"
+
+type TokenResponse struct {
+	AccessToken string    `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // Signed access token that need to be sent as "Authorization: Bearer <token>"
+	TokenType   string    `json:"token_type" example:"Bearer"`                                    // Type of the access token
+	ExpiresAt   time.Time `json:"expires_at" example:"2023-12-06T10:00:00Z"`                      // Timestamp that the access token is expired
+}
+
+type AuthService interface {
+	GenerateToken(string) (*TokenResponse, error)
+	ParseToken(string) (string, error)
+}
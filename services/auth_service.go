@@ -0,0 +1,50 @@
+package service
+
+import (
+	"go-nutritioncalculator2/errs"
+	"go-nutritioncalculator2/logs"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type authService struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewAuthService(secret string, ttl time.Duration) authService {
+	return authService{secret: []byte(secret), ttl: ttl}
+}
+
+func (s authService) GenerateToken(userId string) (*TokenResponse, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(s.ttl)
+	claims := jwt.RegisteredClaims{
+		Subject:   userId,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		logs.Error(err)
+		return nil, errs.NewUnexpectedError()
+	}
+	tokenRes := TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresAt:   expiresAt,
+	}
+	return &tokenRes, nil
+}
+
+func (s authService) ParseToken(accessToken string) (string, error) {
+	claims := jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || claims.Subject == "" {
+		return "", errs.NewUnauthorizedError("Invalid or expired access token")
+	}
+	return claims.Subject, nil
+}
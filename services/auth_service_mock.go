@@ -0,0 +1,21 @@
+package service
+
+import "github.com/stretchr/testify/mock"
+
+type authServiceMock struct {
+	mock.Mock
+}
+
+func NewAuthServiceMock() *authServiceMock {
+	return &authServiceMock{}
+}
+
+func (s *authServiceMock) GenerateToken(userId string) (*TokenResponse, error) {
+	args := s.Called(userId)
+	return args.Get(0).(*TokenResponse), args.Error(1)
+}
+
+func (s *authServiceMock) ParseToken(accessToken string) (string, error) {
+	args := s.Called(accessToken)
+	return args.String(0), args.Error(1)
+}
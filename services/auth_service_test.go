@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"go-nutritioncalculator2/errs"
+	service "go-nutritioncalculator2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		srv := service.NewAuthService("secret", time.Hour)
+		result, err := srv.GenerateToken("gooddy20")
+		assert.ErrorIs(t, err, nil)
+		assert.NotEmpty(t, result.AccessToken)
+		assert.Equal(t, "Bearer", result.TokenType)
+		assert.WithinDuration(t, time.Now().UTC().Add(time.Hour), result.ExpiresAt, 2*time.Second)
+	})
+}
+
+func TestParseToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		srv := service.NewAuthService("secret", time.Hour)
+		token, _ := srv.GenerateToken("gooddy20")
+		result, err := srv.ParseToken(token.AccessToken)
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, "gooddy20", result)
+	})
+	t.Run("Expired Token", func(t *testing.T) {
+		srv := service.NewAuthService("secret", -time.Hour)
+		token, _ := srv.GenerateToken("gooddy20")
+		_, err := srv.ParseToken(token.AccessToken)
+		assert.ErrorIs(t, err, errs.NewUnauthorizedError("Invalid or expired access token"))
+	})
+	t.Run("Incorrect Secret", func(t *testing.T) {
+		token, _ := service.NewAuthService("other secret", time.Hour).GenerateToken("gooddy20")
+		srv := service.NewAuthService("secret", time.Hour)
+		_, err := srv.ParseToken(token.AccessToken)
+		assert.ErrorIs(t, err, errs.NewUnauthorizedError("Invalid or expired access token"))
+	})
+	t.Run("Malformed Token", func(t *testing.T) {
+		srv := service.NewAuthService("secret", time.Hour)
+		_, err := srv.ParseToken("not a token")
+		assert.ErrorIs(t, err, errs.NewUnauthorizedError("Invalid or expired access token"))
+	})
+}
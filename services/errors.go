@@ -0,0 +1,18 @@
+package service
+
+import (
+	"errors"
+	"go-nutritioncalculator2/errs"
+	"go-nutritioncalculator2/logs"
+	repository "go-nutritioncalculator2/repositories"
+)
+
+// repositoryError is for a repository error that the caller does not handle itself, a row that breaks a constraint
+// e.g. an id of the request that does not exist is an error of the request and any other error is logged and hidden behind a 500
+func repositoryError(err error) error {
+	if errors.Is(err, repository.ErrConstraint) {
+		return errs.NewUnprocessableError(errs.CodeConstraintViolation, "Request refers to data that does not exist or is not allowed")
+	}
+	logs.Error(err)
+	return errs.NewUnexpectedError()
+}
@@ -1,32 +1,51 @@
 package service
 
+import "context"
+
 type FavListResponse struct {
-	Id        int     `json:"id" example:"1"`                              // "Favorite List"'s id that generate by system
-	Name      string  `json:"name" example:"Daily Breakfast"`              // Name of "Favorite List" that named by the user
-	Menues    string  `json:"menues" example:"Moo Yang-2, Sticky Rice-1 "` // Summary each "Menu"'s name and amount of the "Favorite List"
-	List      string  `json:"list" example:"9,9,10"`                       // Summary meal with "Menu"'s id e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Favorite List" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea
-	Protein   float64 `json:"protein" example:"40"`                        // Total protein (g.) in the "Favorite List"
-	Fat       float64 `json:"fat" example:"10"`                            // Total fat (g.) in the "Favorite List"
-	Carb      float64 `json:"carb" example:"20"`                           // Total carb (g.) in the "Favorite List"
-	IsUpdated int     `json:"is_updated" example:"1"`                      // 1 = All "Menu" in the "Favorite List" are up to date, 0 = atleast one "Menu" in the "Favorite List" are not up to date
+	Id         int                `json:"id" example:"1"`                              // "Favorite List"'s id that generate by system
+	Name       string             `json:"name" example:"Daily Breakfast"`              // Name of "Favorite List" that named by the user
+	Menues     string             `json:"menues" example:"Moo Yang-2, Sticky Rice-1 "` // Summary each "Menu"'s name and amount of the "Favorite List"
+	List       string             `json:"list" example:"9,9,10"`                       // Summary meal with "Menu"'s id e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Favorite List" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea
+	Items      []Item             `json:"items"`                                       // Summary meal with "Menu"'s id and quantity
+	Protein    float64            `json:"protein" example:"40"`                        // Total protein (g.) in the "Favorite List"
+	Fat        float64            `json:"fat" example:"10"`                            // Total fat (g.) in the "Favorite List"
+	Carb       float64            `json:"carb" example:"20"`                           // Total carb (g.) in the "Favorite List"
+	Alcohol    float64            `json:"alcohol" example:"0"`                         // Total alcohol (g.) in the "Favorite List"
+	Kcal       float64            `json:"kcal" example:"330"`                          // Total energy (kcal) in the "Favorite List"
+	MacroSplit MacroSplit         `json:"macro_split"`                                 // Percentage of energy from each macro nutrient in the "Favorite List"
+	Nutrients  map[string]float64 `json:"nutrients,omitempty"`                         // Total known extended nutrients in the "Favorite List", a "Menu" without the nutrient does not add to it
+	IsUpdated  int                `json:"is_updated" example:"1"`                      // 1 = All "Menu" in the "Favorite List" are up to date, 0 = atleast one "Menu" in the "Favorite List" are not up to date
+	Revision   int                `json:"revision" example:"3"`                        // Revision of the "Favorite List" that is sent as "If-Match" e.g. "3" to update or delete it
 }
 
 type NewFavListRequest struct {
 	UserId string `json:"user_id" example:"gooddy20" binding:"required"`     // The "User Id" that create this "Favorite List"
 	Name   string `json:"name" example:"Daily Breakfast" binding:"required"` // The name of this "Favorite List"
-	List   string `json:"list" example:"9,9,10" binding:"required"`          // Summary meal with "Menu"'s id  e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Favorite List" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea
+	List   string `json:"list" example:"9,9,10" binding:"menulist"`          // Summary meal with "Menu"'s id  e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Favorite List" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea *required when "items" is empty
+	Items  []Item `json:"items"`                                             // Summary meal with "Menu"'s id and quantity, it is used instead of "list" when it is not empty
 }
 
 type UpdateFavListRequest struct {
-	Id   int    `json:"id" example:"1" binding:"required"` // The "Favorite List"'s id that is updated
-	Name string `json:"name" example:"Daily Breakfast"`    // The name that you want to change to
-	List string `json:"list" example:"9,10"`               // Summary meal with "Menu"'s id that you want to change e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Favorite List" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea
+	Id       int              `json:"id" example:"1" binding:"required"`                           // The "Favorite List"'s id that is updated
+	Name     Nullable[string] `json:"name" swaggertype:"string" example:"Daily Breakfast"`         // The name that you want to change to, null = clear
+	List     Nullable[string] `json:"list" swaggertype:"string" example:"9,10" binding:"menulist"` // Summary meal with "Menu"'s id that you want to change e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Favorite List" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea, it can not be cleared
+	Items    Nullable[[]Item] `json:"items" swaggertype:"array,object"`                            // Summary meal with "Menu"'s id and quantity ("menu_id", "quantity" and "unit" like "Item") that you want to change to, it is used instead of "list" when it is set and it can not be cleared
+	Revision int              `json:"-"`                                                           // Revision from the "If-Match" header, 0 = update whatever the current revision is
+}
+
+type LogFavListRequest struct {
+	Note           string  `json:"note" example:"Breakfast"`                                                   // Note for the "Record", default = name of the "Favorite List"
+	Weight         float64 `json:"weight" example:"63" binding:"omitempty,min=20,max=500"`                     // Weight (kg.) that you are on that day
+	Multiplier     float64 `json:"multiplier" example:"1.5" binding:"min=0"`                                   // Multiply the quantity of every "Menu" in the "Favorite List" e.g. 0.5 = half of the meal, default = 1
+	EventTimestamp string  `json:"event_timestamp" example:"2023-11-01 09:30:00" binding:"required,timestamp"` // Wall-clock timestamp in your time zone that you eat, it is kept as it is and the days of the summary are counted on it *format="2023-01-01 00:00:00"
 }
 
 type FavListService interface {
-	GetFavListsByUserId(string) ([]FavListResponse, error)
-	CreateFavList(NewFavListRequest) error
-	DeleteFavList(int) error
-	UpdateFavList(UpdateFavListRequest) error
-	RecoverFavList(int, int, int) error
+	GetFavListsByUserId(context.Context, string) ([]FavListResponse, error)
+	CreateFavList(context.Context, NewFavListRequest) (*CreatedResponse, error)
+	DeleteFavList(context.Context, string, int, int) error
+	UpdateFavList(context.Context, string, UpdateFavListRequest) error
+	RecoverFavList(context.Context, int, int, int) error
+	LogFavList(context.Context, string, int, LogFavListRequest) (*CreatedResponse, error)
 }
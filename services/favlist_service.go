@@ -1,159 +1,274 @@
 package service
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
 	"go-nutritioncalculator2/errs"
-	"go-nutritioncalculator2/logs"
 	repository "go-nutritioncalculator2/repositories"
-	"net/http"
-	"strconv"
-	"strings"
 	"time"
 )
 
 type favListService struct {
 	favListRepo repository.FavListRepository
+	menuRepo    repository.MenuRepository
+	recordRepo  repository.RecordRepository
 }
 
-func NewFavListService(favListRepo repository.FavListRepository) favListService {
-	return favListService{favListRepo: favListRepo}
+func NewFavListService(favListRepo repository.FavListRepository, menuRepo repository.MenuRepository, recordRepo repository.RecordRepository) favListService {
+	return favListService{favListRepo: favListRepo, menuRepo: menuRepo, recordRepo: recordRepo}
 }
 
-func (s favListService) GetFavListsByUserId(userId string) ([]FavListResponse, error) {
-	favLists, err := s.favListRepo.GetFavListsByUserId(userId)
+func (s favListService) GetFavListsByUserId(ctx context.Context, userId string) ([]FavListResponse, error) {
+	favLists, err := s.favListRepo.GetFavListsByUserId(ctx, userId)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, repository.ErrNotFound) {
 			return []FavListResponse{}, nil
 		}
-		logs.Error(err)
-		return nil, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
 	}
 	favListsRes := []FavListResponse{}
 	for i := 0; i < len(favLists); i++ {
-		favList := FavListResponse{
-			Id:        favLists[i].Id,
-			Name:      favLists[i].Name,
-			Menues:    favLists[i].Menues,
-			List:      favLists[i].List,
-			Protein:   favLists[i].Protein,
-			Fat:       favLists[i].Fat,
-			Carb:      favLists[i].Carb,
-			IsUpdated: favLists[i].IsUpdated,
-		}
-		favListsRes = append(favListsRes, favList)
+		favListsRes = append(favListsRes, toFavListResponse(favLists[i]))
 	}
 	return favListsRes, nil
 }
 
-func (s favListService) CreateFavList(newFavListReq NewFavListRequest) error {
+func (s favListService) CreateFavList(ctx context.Context, newFavListReq NewFavListRequest) (*CreatedResponse, error) {
+	v := validateRequest(newFavListReq)
+	var items []repository.Item
+	var err error
+	if newFavListReq.List == "" && len(newFavListReq.Items) == 0 {
+		v.add("list", "List or Items is required")
+	} else if !v.has("list", "items") {
+		items, err = toRepositoryItems(newFavListReq.List, newFavListReq.Items)
+		if err = v.check(err); err != nil {
+			return nil, err
+		}
+	}
+	if err = v.err(); err != nil {
+		return nil, err
+	}
+	err = checkItemUnits(ctx, s.menuRepo, items, nil)
+	if err != nil {
+		return nil, err
+	}
 	newFavList := repository.FavList{
 		UserId:           newFavListReq.UserId,
 		Name:             newFavListReq.Name,
-		List:             newFavListReq.List,
+		Items:            items,
 		Status:           1,
 		CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 	}
-	_, err := s.favListRepo.CreateFavList(newFavList)
+	created, err := s.favListRepo.CreateFavList(ctx, newFavList)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
 	}
-	return nil
+	return &CreatedResponse{Id: created.Id, Revision: created.Revision}, nil
 }
 
-func (s favListService) DeleteFavList(favListId int) error {
-	favList, err := s.favListRepo.GetFavListById(favListId)
+func (s favListService) DeleteFavList(ctx context.Context, userId string, favListId int, revision int) error {
+	favList, err := s.favListRepo.GetFavListById(ctx, favListId)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeFavListNotFound, fmt.Sprint("Favorite List Id - ", favListId, "is not found"))
+		}
+		return repositoryError(err)
+	}
+	if favList.UserId != userId {
+		return errs.NewPermissionDeniedError()
+	}
+	err = checkRevision("Favorite List", revision, favList.Revision)
+	if err != nil {
+		return err
 	}
 	favList.Status = 0
-	err = s.favListRepo.UpdateFavList(*favList)
+	err = s.favListRepo.UpdateFavList(ctx, *favList)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrStale) {
+			return newStaleError("Favorite List")
+		}
+		return repositoryError(err)
 	}
 	return nil
 }
 
-func (s favListService) UpdateFavList(updateFavListReq UpdateFavListRequest) error {
-	favList, err := s.favListRepo.GetFavListById(updateFavListReq.Id)
+func (s favListService) UpdateFavList(ctx context.Context, userId string, updateFavListReq UpdateFavListRequest) error {
+	v := validateRequest(updateFavListReq)
+	var items []repository.Item
+	var isItemsSet bool
+	var err error
+	if !v.has("list", "items") {
+		items, isItemsSet, err = patchItems(updateFavListReq.List, updateFavListReq.Items)
+		if err = v.check(err); err != nil {
+			return err
+		}
+	}
+	if err = v.err(); err != nil {
+		return err
+	}
+	favList, err := s.favListRepo.GetFavListById(ctx, updateFavListReq.Id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return errs.AppError{Code: http.StatusNotAcceptable, Message: fmt.Sprint("Favorite List Id - ", updateFavListReq.Id, "is not found")}
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeFavListNotFound, fmt.Sprint("Favorite List Id - ", updateFavListReq.Id, "is not found"))
 		}
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return repositoryError(err)
 	}
-	if updateFavListReq.Name != "" {
-		favList.Name = updateFavListReq.Name
+	if favList.UserId != userId {
+		return errs.NewPermissionDeniedError()
 	}
-	if updateFavListReq.List != "" {
-		favList.List = updateFavListReq.List
+	err = checkRevision("Favorite List", updateFavListReq.Revision, favList.Revision)
+	if err != nil {
+		return err
 	}
-	err = s.favListRepo.UpdateFavList(*favList)
+	favList.Name = updateFavListReq.Name.Apply(favList.Name)
+	if isItemsSet {
+		err = checkItemUnits(ctx, s.menuRepo, items, favList.Items)
+		if err != nil {
+			return err
+		}
+		favList.Items = items
+	}
+	err = s.favListRepo.UpdateFavList(ctx, *favList)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrStale) {
+			return newStaleError("Favorite List")
+		}
+		return repositoryError(err)
 	}
 	return nil
 }
 
-func (s favListService) RecoverFavList(favListId int, oldMenuId int, newMenuId int) error {
-	favList, err := s.favListRepo.GetFavListById(favListId)
+func (s favListService) RecoverFavList(ctx context.Context, favListId int, oldMenuId int, newMenuId int) error {
+	favList, err := s.favListRepo.GetFavListById(ctx, favListId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return errs.AppError{Code: http.StatusNotAcceptable, Message: fmt.Sprint("Favorite List Id - ", favListId, "is not found")}
-		}
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
-	}
-	tempFavList := strings.Split(favList.List, ",")
-	minIndex := -1
-	maxIndex := -1
-	for i := 0; i < len(tempFavList); i++ {
-		tempMenuId, err := strconv.Atoi(tempFavList[i])
-		if err != nil {
-			logs.Error(err)
-			return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeFavListNotFound, fmt.Sprint("Favorite List Id - ", favListId, "is not found"))
 		}
-		if tempMenuId < oldMenuId {
-			continue
-		} else if tempMenuId == oldMenuId {
-			if minIndex == -1 {
-				minIndex = i
-			} else {
-				maxIndex = i
-			}
+		return repositoryError(err)
+	}
+	replacedItems := []repository.Item{}
+	items := []repository.Item{}
+	for _, item := range favList.Items {
+		if item.MenuId == oldMenuId {
+			replacedItems = append(replacedItems, item)
 		} else {
-			break
+			items = append(items, item)
 		}
 	}
-	if minIndex == -1 {
+	if len(replacedItems) == 0 {
 		return nil
 	}
-	if maxIndex == -1 {
-		maxIndex = minIndex
-	}
-	if minIndex == 0 {
-		tempFavList = tempFavList[maxIndex+1:]
-	} else if maxIndex == len(tempFavList)-1 {
-		tempFavList = tempFavList[:minIndex]
-	} else {
-		tempFavList = append(tempFavList[:minIndex], tempFavList[maxIndex+1:]...)
-	}
 	if newMenuId != 0 {
-		tempNewMenuId := strconv.Itoa(newMenuId)
-		for i := 0; i <= maxIndex-minIndex; i++ {
-			tempFavList = append(tempFavList, tempNewMenuId)
+		for _, replacedItem := range replacedItems {
+			isMerged := false
+			for i := 0; i < len(items); i++ {
+				if items[i].MenuId == newMenuId && items[i].Unit == replacedItem.Unit {
+					items[i].Quantity += replacedItem.Quantity
+					isMerged = true
+				}
+			}
+			if !isMerged {
+				items = append(items, repository.Item{MenuId: newMenuId, Quantity: replacedItem.Quantity, Unit: replacedItem.Unit})
+			}
 		}
 	}
-	favList.List = strings.Join(tempFavList, ",")
-	err = s.favListRepo.UpdateFavList(*favList)
+	favList.Items = items
+	err = s.favListRepo.UpdateFavList(ctx, *favList)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrStale) {
+			return newStaleError("Favorite List")
+		}
+		return repositoryError(err)
 	}
 	return nil
 }
+
+// LogFavList eats the "Favorite List" as a "Record", every "Menu" in it need to be up to date so the "Record" is not made of outdated nutrition
+func (s favListService) LogFavList(ctx context.Context, userId string, favListId int, logFavListReq LogFavListRequest) (*CreatedResponse, error) {
+	err := validateRequest(logFavListReq).err()
+	if err != nil {
+		return nil, err
+	}
+	eventTimestamp, _ := time.Parse(timestampLayout, logFavListReq.EventTimestamp)
+	multiplier := logFavListReq.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	favList, err := s.favListRepo.GetFavListById(ctx, favListId)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && favList.Status == 0) {
+		return nil, errs.NewNotFoundError(errs.CodeFavListNotFound, fmt.Sprint("Favorite List Id - ", favListId, " is not found"))
+	}
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	if favList.UserId != userId {
+		return nil, errs.NewPermissionDeniedError()
+	}
+	if len(favList.Items) == 0 {
+		return nil, errs.NewUnprocessableError(errs.CodeFavListEmpty, "Favorite List has no Menu to log")
+	}
+	menuIds := []int{}
+	for i, item := range favList.Items {
+		if i == 0 || favList.Items[i-1].MenuId != item.MenuId {
+			menuIds = append(menuIds, item.MenuId)
+		}
+	}
+	menues, err := s.menuRepo.GetMenusByIds(ctx, menuIds)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	activeMenues := map[int]bool{}
+	for _, menu := range menues {
+		activeMenues[menu.Id] = menu.Status == 1
+	}
+	outdatedMenuIds := []int{}
+	for _, menuId := range menuIds {
+		if !activeMenues[menuId] {
+			outdatedMenuIds = append(outdatedMenuIds, menuId)
+		}
+	}
+	if len(outdatedMenuIds) != 0 {
+		return nil, errs.NewConflictError(errs.CodeMenuOutdated, fmt.Sprint("Menu Id - ", toMenuIdList(outdatedMenuIds), " in the Favorite List are outdated, update the Favorite List before logging it"))
+	}
+	items := []repository.Item{}
+	for _, item := range favList.Items {
+		items = append(items, repository.Item{MenuId: item.MenuId, Quantity: item.Quantity * multiplier, Unit: item.Unit})
+	}
+	note := logFavListReq.Note
+	if note == "" {
+		note = favList.Name
+	}
+	created, err := s.recordRepo.CreateRecord(ctx, repository.Record{
+		UserId:           userId,
+		Items:            items,
+		Note:             note,
+		Weight:           logFavListReq.Weight,
+		EventTimestamp:   eventTimestamp,
+		Status:           1,
+		CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return &CreatedResponse{Id: created.Id, Revision: created.Revision}, nil
+}
+
+func toFavListResponse(favList repository.FavList) FavListResponse {
+	return FavListResponse{
+		Id:         favList.Id,
+		Name:       favList.Name,
+		Menues:     favList.Menues,
+		List:       toList(favList.Items),
+		Items:      toItems(favList.Items),
+		Protein:    favList.Protein,
+		Fat:        favList.Fat,
+		Carb:       favList.Carb,
+		Alcohol:    favList.Alcohol,
+		Kcal:       calories(favList.Protein, favList.Fat, favList.Carb, favList.Alcohol),
+		MacroSplit: macroSplit(favList.Protein, favList.Fat, favList.Carb, favList.Alcohol),
+		Nutrients:  roundNutrients(favList.Nutrients),
+		IsUpdated:  favList.IsUpdated,
+		Revision:   favList.Revision,
+	}
+}
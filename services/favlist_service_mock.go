@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -12,27 +13,32 @@ func NewFavListServiceMock() *favListServiceMock {
 	return &favListServiceMock{}
 }
 
-func (s *favListServiceMock) GetFavListsByUserId(userId string) ([]FavListResponse, error) {
+func (s *favListServiceMock) GetFavListsByUserId(ctx context.Context, userId string) ([]FavListResponse, error) {
 	args := s.Called(userId)
 	return args.Get(0).([]FavListResponse), args.Error(1)
 }
 
-func (s *favListServiceMock) CreateFavList(newFavListReq NewFavListRequest) error {
+func (s *favListServiceMock) CreateFavList(ctx context.Context, newFavListReq NewFavListRequest) (*CreatedResponse, error) {
 	args := s.Called(newFavListReq)
-	return args.Error(0)
+	return args.Get(0).(*CreatedResponse), args.Error(1)
 }
 
-func (s *favListServiceMock) DeleteFavList(favListId int) error {
-	args := s.Called(favListId)
+func (s *favListServiceMock) DeleteFavList(ctx context.Context, userId string, favListId int, revision int) error {
+	args := s.Called(userId, favListId, revision)
 	return args.Error(0)
 }
 
-func (s *favListServiceMock) UpdateFavList(updateFavListReq UpdateFavListRequest) error {
-	args := s.Called(updateFavListReq)
+func (s *favListServiceMock) UpdateFavList(ctx context.Context, userId string, updateFavListReq UpdateFavListRequest) error {
+	args := s.Called(userId, updateFavListReq)
 	return args.Error(0)
 }
 
-func (s *favListServiceMock) RecoverFavList(favListId int, oldMenuId int, newMenuId int) error {
+func (s *favListServiceMock) RecoverFavList(ctx context.Context, favListId int, oldMenuId int, newMenuId int) error {
 	args := s.Called(favListId, oldMenuId, newMenuId)
 	return args.Error(0)
 }
+
+func (s *favListServiceMock) LogFavList(ctx context.Context, userId string, favListId int, logFavListReq LogFavListRequest) (*CreatedResponse, error) {
+	args := s.Called(userId, favListId, logFavListReq)
+	return args.Get(0).(*CreatedResponse), args.Error(1)
+}
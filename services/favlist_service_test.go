@@ -1,57 +1,63 @@
 package service_test
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"go-nutritioncalculator2/errs"
 	repository "go-nutritioncalculator2/repositories"
 	service "go-nutritioncalculator2/services"
-	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetFavListsByUserId(t *testing.T) {
 	t.Run("Success Case: Got Favorite Lists", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListsByUserId", "gooddy20").Return([]repository.FavList{
-			{Id: 1, UserId: "gooddy20", Name: "Daily Breakfast", Menues: "Moo Yang-2, Sticky Rice-1 ", List: "9,9,10", Protein: 40, Fat: 10, Carb: 20, Status: 1, IsUpdated: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()},
-			{Id: 2, UserId: "gooddy20", Name: "Daily Breakfast", Menues: "Omelet-2 ", List: "1,1", Protein: 10, Fat: 2, Carb: 0, Status: 1, IsUpdated: 1, CreatedTimestamp: time.Date(2023, 13, 12, 10, 31, 15, 0, time.UTC).UTC()},
+			{Id: 1, UserId: "gooddy20", Name: "Daily Breakfast", Menues: "Moo Yang-2, Sticky Rice-1 ", Items: []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}}, Protein: 40, Fat: 10, Carb: 20, Status: 1, IsUpdated: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()},
+			{Id: 2, UserId: "gooddy20", Name: "Daily Breakfast", Menues: "Omelet-2 ", Items: []repository.Item{{MenuId: 1, Quantity: 2, Unit: "serving"}}, Protein: 10, Fat: 2, Carb: 0, Status: 1, IsUpdated: 1, CreatedTimestamp: time.Date(2023, 13, 12, 10, 31, 15, 0, time.UTC).UTC()},
 		}, nil)
-		srv := service.NewFavListService(repo)
-		result, _ := srv.GetFavListsByUserId("gooddy20")
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		result, _ := srv.GetFavListsByUserId(context.Background(), "gooddy20")
 		expected := []service.FavListResponse{
-			{Id: 1, Name: "Daily Breakfast", Menues: "Moo Yang-2, Sticky Rice-1 ", List: "9,9,10", Protein: 40, Fat: 10, Carb: 20, IsUpdated: 1},
-			{Id: 2, Name: "Daily Breakfast", Menues: "Omelet-2 ", List: "1,1", Protein: 10, Fat: 2, Carb: 0, IsUpdated: 1},
+			{Id: 1, Name: "Daily Breakfast", Menues: "Moo Yang-2, Sticky Rice-1 ", List: "9,9,10", Items: []service.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}}, Protein: 40, Fat: 10, Carb: 20, Kcal: 330, MacroSplit: service.MacroSplit{Protein: 48.5, Fat: 27.3, Carb: 24.2, Alcohol: 0}, IsUpdated: 1},
+			{Id: 2, Name: "Daily Breakfast", Menues: "Omelet-2 ", List: "1,1", Items: []service.Item{{MenuId: 1, Quantity: 2, Unit: "serving"}}, Protein: 10, Fat: 2, Carb: 0, Kcal: 58, MacroSplit: service.MacroSplit{Protein: 69, Fat: 31, Carb: 0, Alcohol: 0}, IsUpdated: 1},
 		}
 		assert.Equal(t, expected, result)
 	})
 	t.Run("Success Case: No Favorite Lists", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
-		repo.On("GetFavListsByUserId", "gooddy20").Return([]repository.FavList{}, sql.ErrNoRows)
-		srv := service.NewFavListService(repo)
-		result, _ := srv.GetFavListsByUserId("gooddy20")
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetFavListsByUserId", "gooddy20").Return([]repository.FavList{}, repository.ErrNotFound)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		result, _ := srv.GetFavListsByUserId(context.Background(), "gooddy20")
 		expected := []service.FavListResponse{}
 		assert.Equal(t, expected, result)
 	})
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListsByUserId", "gooddy20").Return([]repository.FavList{}, sql.ErrConnDone)
-		srv := service.NewFavListService(repo)
-		_, err := srv.GetFavListsByUserId("gooddy20")
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		_, err := srv.GetFavListsByUserId(context.Background(), "gooddy20")
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 	})
 }
 
 func TestCreateFavList(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{1, 3}).Return(servingMenues(1, 3), nil)
 		repo.On("CreateFavList", repository.FavList{
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast V2",
-			List:             "1,1,3",
+			Items:            []repository.Item{{MenuId: 1, Quantity: 2, Unit: "serving"}, {MenuId: 3, Quantity: 1, Unit: "serving"}},
 			Status:           1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}).Return(&repository.FavList{
@@ -59,42 +65,78 @@ func TestCreateFavList(t *testing.T) {
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast V2",
 			Menues:           "Omelet-2, Boiled Egg-1 ",
-			List:             "1,1,3",
+			Items:            []repository.Item{{MenuId: 1, Quantity: 2, Unit: "serving"}, {MenuId: 3, Quantity: 1, Unit: "serving"}},
 			Protein:          14,
 			Fat:              2,
 			Carb:             0,
 			Status:           1,
 			IsUpdated:        1,
+			Revision:         1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}, nil)
-		srv := service.NewFavListService(repo)
-		err := srv.CreateFavList(service.NewFavListRequest{UserId: "gooddy20", Name: "Daily Breakfast V2", List: "1,1,3"})
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		result, err := srv.CreateFavList(context.Background(), service.NewFavListRequest{UserId: "gooddy20", Name: "Daily Breakfast V2", List: "1,1,3"})
 		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, &service.CreatedResponse{Id: 3, Revision: 1}, result)
 	})
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{1, 3}).Return(servingMenues(1, 3), nil)
 		repo.On("CreateFavList", repository.FavList{
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast V2",
-			List:             "1,1,3",
+			Items:            []repository.Item{{MenuId: 1, Quantity: 2, Unit: "serving"}, {MenuId: 3, Quantity: 1, Unit: "serving"}},
 			Status:           1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}).Return(&repository.FavList{}, sql.ErrConnDone)
-		srv := service.NewFavListService(repo)
-		err := srv.CreateFavList(service.NewFavListRequest{UserId: "gooddy20", Name: "Daily Breakfast V2", List: "1,1,3"})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		_, err := srv.CreateFavList(context.Background(), service.NewFavListRequest{UserId: "gooddy20", Name: "Daily Breakfast V2", List: "1,1,3"})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("Success Case: Items", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{1, 3}).Return(servingMenues(1, 3), nil)
+		repo.On("CreateFavList", repository.FavList{
+			UserId:           "gooddy20",
+			Name:             "Daily Breakfast V2",
+			Items:            []repository.Item{{MenuId: 1, Quantity: 2, Unit: "serving"}, {MenuId: 3, Quantity: 1, Unit: "serving"}},
+			Status:           1,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(&repository.FavList{}, nil)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		_, err := srv.CreateFavList(context.Background(), service.NewFavListRequest{UserId: "gooddy20", Name: "Daily Breakfast V2", Items: []service.Item{{MenuId: 3, Quantity: 1, Unit: "serving"}, {MenuId: 1, Quantity: 2, Unit: "serving"}}})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Missing List And Items", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		_, err := srv.CreateFavList(context.Background(), service.NewFavListRequest{UserId: "gooddy20", Name: "Daily Breakfast V2"})
+		assert.ErrorIs(t, err, errs.NewValidationError("list", "List or Items is required"))
+		repo.AssertNotCalled(t, "CreateFavList")
+	})
+	t.Run("Invalid List", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		_, err := srv.CreateFavList(context.Background(), service.NewFavListRequest{UserId: "gooddy20", Name: "Daily Breakfast V2", List: "1,a"})
+		assert.ErrorIs(t, err, errs.NewValidationError("list", `List need to be "Menu"'s id separated by comma e.g. "9,9,10"`))
+		repo.AssertNotCalled(t, "CreateFavList")
 	})
 }
 
 func TestDeleteFavList(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -107,7 +149,7 @@ func TestDeleteFavList(t *testing.T) {
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -115,18 +157,19 @@ func TestDeleteFavList(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewFavListService(repo)
-		err := srv.DeleteFavList(1)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.DeleteFavList(context.Background(), "gooddy20", 1, 0)
 		assert.ErrorIs(t, err, nil)
 	})
 	t.Run("Get Favorite List Database Error", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -134,19 +177,20 @@ func TestDeleteFavList(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, sql.ErrConnDone)
-		srv := service.NewFavListService(repo)
-		err := srv.DeleteFavList(1)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.DeleteFavList(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "UpdateFavList")
 	})
 	t.Run("Update Favorite List Database Error", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -159,7 +203,7 @@ func TestDeleteFavList(t *testing.T) {
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -167,21 +211,75 @@ func TestDeleteFavList(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(sql.ErrConnDone)
-		srv := service.NewFavListService(repo)
-		err := srv.DeleteFavList(1)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.DeleteFavList(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("No The Favorite List Id", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetFavListById", 1).Return(&repository.FavList{}, repository.ErrNotFound)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.DeleteFavList(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeFavListNotFound, fmt.Sprint("Favorite List Id - ", 1, "is not found")))
+		repo.AssertNotCalled(t, "UpdateFavList")
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetFavListById", 1).Return(&repository.FavList{
+			Id:               1,
+			UserId:           "kornkoko",
+			Name:             "Daily Breakfast",
+			Menues:           "Moo Yang-2, Sticky Rice-1 ",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Protein:          40,
+			Fat:              10,
+			Carb:             20,
+			Status:           1,
+			IsUpdated:        1,
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
+		}, nil)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.DeleteFavList(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewPermissionDeniedError())
+		repo.AssertNotCalled(t, "UpdateFavList")
+	})
+	t.Run("Stale Revision", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetFavListById", 1).Return(&repository.FavList{
+			Id:               1,
+			UserId:           "gooddy20",
+			Name:             "Daily Breakfast",
+			Menues:           "Moo Yang-2, Sticky Rice-1 ",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Protein:          40,
+			Fat:              10,
+			Carb:             20,
+			Status:           1,
+			IsUpdated:        1,
+			Revision:         5,
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
+		}, nil)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.DeleteFavList(context.Background(), "gooddy20", 1, 4)
+		assert.ErrorIs(t, err, errs.NewPreconditionFailedError("Favorite List has been changed by another request, get it again and retry"))
+		repo.AssertNotCalled(t, "UpdateFavList")
 	})
 }
 
 func TestUpdateFavList(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -194,7 +292,7 @@ func TestUpdateFavList(t *testing.T) {
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast V2",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 3, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -202,46 +300,66 @@ func TestUpdateFavList(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewFavListService(repo)
-		err := srv.UpdateFavList(service.UpdateFavListRequest{
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.UpdateFavList(context.Background(), "gooddy20", service.UpdateFavListRequest{
 			Id:   1,
-			Name: "Daily Breakfast V2",
-			List: "9,9,9,10",
+			Name: service.NewNullable("Daily Breakfast V2"),
+			List: service.NewNullable("9,9,9,10"),
 		})
 		assert.ErrorIs(t, err, nil)
 	})
+	t.Run("Success Case: Clear Name", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetFavListById", 1).Return(&repository.FavList{Id: 1, UserId: "gooddy20", Name: "Daily Breakfast", Items: []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}}}, nil)
+		repo.On("UpdateFavList", repository.FavList{Id: 1, UserId: "gooddy20", Items: []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}}}).Return(nil)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.UpdateFavList(context.Background(), "gooddy20", service.UpdateFavListRequest{Id: 1, Name: service.NewNull[string]()})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Clear List", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetFavListById", 1).Return(&repository.FavList{Id: 1, UserId: "gooddy20", Items: []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}}}, nil)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.UpdateFavList(context.Background(), "gooddy20", service.UpdateFavListRequest{Id: 1, List: service.NewNullable("")})
+		assert.ErrorIs(t, err, errs.NewValidationError("list", "List or Items can not be cleared"))
+		repo.AssertNotCalled(t, "UpdateFavList")
+	})
 	t.Run("No The Favorite List Id", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
 			Status:           1,
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
-		}, sql.ErrNoRows)
-		srv := service.NewFavListService(repo)
-		err := srv.UpdateFavList(service.UpdateFavListRequest{
+		}, repository.ErrNotFound)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.UpdateFavList(context.Background(), "gooddy20", service.UpdateFavListRequest{
 			Id:   1,
-			Name: "Daily Breakfast V2",
-			List: "9,9,9,10",
+			Name: service.NewNullable("Daily Breakfast V2"),
+			List: service.NewNullable("9,9,9,10"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: fmt.Sprint("Favorite List Id - ", 1, "is not found")})
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeFavListNotFound, fmt.Sprint("Favorite List Id - ", 1, "is not found")))
 		repo.AssertNotCalled(t, "UpdateFavList")
 	})
 	t.Run("Get Favorite List Database Error", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -249,23 +367,25 @@ func TestUpdateFavList(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, sql.ErrConnDone)
-		srv := service.NewFavListService(repo)
-		err := srv.UpdateFavList(service.UpdateFavListRequest{
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.UpdateFavList(context.Background(), "gooddy20", service.UpdateFavListRequest{
 			Id:   1,
-			Name: "Daily Breakfast V2",
-			List: "9,9,9,10",
+			Name: service.NewNullable("Daily Breakfast V2"),
+			List: service.NewNullable("9,9,9,10"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "UpdateFavList")
 	})
 	t.Run("Update Favorite List Database Error", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -278,7 +398,7 @@ func TestUpdateFavList(t *testing.T) {
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast V2",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 3, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -286,25 +406,51 @@ func TestUpdateFavList(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(sql.ErrConnDone)
-		srv := service.NewFavListService(repo)
-		err := srv.UpdateFavList(service.UpdateFavListRequest{
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.UpdateFavList(context.Background(), "gooddy20", service.UpdateFavListRequest{
 			Id:   1,
-			Name: "Daily Breakfast V2",
-			List: "9,9,9,10",
+			Name: service.NewNullable("Daily Breakfast V2"),
+			List: service.NewNullable("9,9,9,10"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetFavListById", 1).Return(&repository.FavList{
+			Id:               1,
+			UserId:           "kornkoko",
+			Name:             "Daily Breakfast",
+			Menues:           "Moo Yang-2, Sticky Rice-1 ",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Protein:          40,
+			Fat:              10,
+			Carb:             20,
+			Status:           1,
+			IsUpdated:        1,
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
+		}, nil)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.UpdateFavList(context.Background(), "gooddy20", service.UpdateFavListRequest{
+			Id:   1,
+			Name: service.NewNullable("Daily Breakfast V2"),
+			List: service.NewNullable("9,9,9,10"),
+		})
+		assert.ErrorIs(t, err, errs.NewPermissionDeniedError())
+		repo.AssertNotCalled(t, "UpdateFavList")
 	})
 }
 
 func TestRecoverFavList(t *testing.T) {
 	t.Run("Success Case 1", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -317,7 +463,7 @@ func TestRecoverFavList(t *testing.T) {
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -325,18 +471,19 @@ func TestRecoverFavList(t *testing.T) {
 			IsUpdated:        0,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewFavListService(repo)
-		err := srv.RecoverFavList(1, 10, 0)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.RecoverFavList(context.Background(), 1, 10, 0)
 		assert.ErrorIs(t, err, nil)
 	})
 	t.Run("Success Case 2", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -349,7 +496,7 @@ func TestRecoverFavList(t *testing.T) {
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "10,11,11",
+			Items:            []repository.Item{{MenuId: 10, Quantity: 1, Unit: "serving"}, {MenuId: 11, Quantity: 2, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -357,18 +504,19 @@ func TestRecoverFavList(t *testing.T) {
 			IsUpdated:        0,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewFavListService(repo)
-		err := srv.RecoverFavList(1, 9, 11)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.RecoverFavList(context.Background(), 1, 9, 11)
 		assert.ErrorIs(t, err, nil)
 	})
 	t.Run("Success Case 3", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 2).Return(&repository.FavList{
 			Id:               2,
 			UserId:           "gooddy20",
 			Name:             "Daily Lunch",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ,Orange Juice-1 ",
-			List:             "9,9,10,11",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}, {MenuId: 11, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             40,
@@ -381,7 +529,7 @@ func TestRecoverFavList(t *testing.T) {
 			UserId:           "gooddy20",
 			Name:             "Daily Lunch",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ,Orange Juice-1 ",
-			List:             "9,9,11",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 11, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             40,
@@ -389,18 +537,19 @@ func TestRecoverFavList(t *testing.T) {
 			IsUpdated:        0,
 			CreatedTimestamp: time.Date(2023, 15, 12, 10, 23, 38, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewFavListService(repo)
-		err := srv.RecoverFavList(2, 10, 0)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.RecoverFavList(context.Background(), 2, 10, 0)
 		assert.ErrorIs(t, err, nil)
 	})
 	t.Run("Success Case: No Deleted Menu in Favorite Lists", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 2).Return(&repository.FavList{
 			Id:               2,
 			UserId:           "gooddy20",
 			Name:             "Daily Lunch",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ,Orange Juice-1 ",
-			List:             "9,9,10,11",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}, {MenuId: 11, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             40,
@@ -408,55 +557,59 @@ func TestRecoverFavList(t *testing.T) {
 			IsUpdated:        0,
 			CreatedTimestamp: time.Date(2023, 15, 12, 10, 23, 38, 0, time.UTC).UTC(),
 		}, nil)
-		srv := service.NewFavListService(repo)
-		err := srv.RecoverFavList(2, 12, 0)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.RecoverFavList(context.Background(), 2, 12, 0)
 		assert.ErrorIs(t, err, nil)
 		repo.AssertNotCalled(t, "UpdateFavList")
 	})
+	t.Run("Success Case: New Menu Already in Favorite List", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetFavListById", 1).Return(&repository.FavList{
+			Id:     1,
+			UserId: "gooddy20",
+			Name:   "Daily Breakfast",
+			Items:  []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Status: 1,
+		}, nil)
+		repo.On("UpdateFavList", repository.FavList{
+			Id:     1,
+			UserId: "gooddy20",
+			Name:   "Daily Breakfast",
+			Items:  []repository.Item{{MenuId: 10, Quantity: 3, Unit: "serving"}},
+			Status: 1,
+		}).Return(nil)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.RecoverFavList(context.Background(), 1, 9, 10)
+		assert.ErrorIs(t, err, nil)
+	})
 	t.Run("No The Favorite List Id", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
-		repo.On("GetFavListById", 2).Return(&repository.FavList{}, sql.ErrNoRows)
-		srv := service.NewFavListService(repo)
-		err := srv.RecoverFavList(2, 12, 0)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: fmt.Sprint("Favorite List Id - ", 2, "is not found")})
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetFavListById", 2).Return(&repository.FavList{}, repository.ErrNotFound)
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.RecoverFavList(context.Background(), 2, 12, 0)
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeFavListNotFound, fmt.Sprint("Favorite List Id - ", 2, "is not found")))
 		repo.AssertNotCalled(t, "UpdateFavList")
 	})
 	t.Run("Get Favorite List Database Error", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 2).Return(&repository.FavList{}, sql.ErrConnDone)
-		srv := service.NewFavListService(repo)
-		err := srv.RecoverFavList(2, 12, 0)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
-		repo.AssertNotCalled(t, "UpdateFavList")
-	})
-	t.Run("Parse List (String to Int) Error", func(t *testing.T) {
-		repo := repository.NewFavListRepositoryMock()
-		repo.On("GetFavListById", 2).Return(&repository.FavList{
-			Id:               2,
-			UserId:           "gooddy20",
-			Name:             "Daily Lunch",
-			Menues:           "Moo Yang-2, Sticky Rice-1 ,Orange Juice-1 ",
-			List:             "9,9,10,11.5",
-			Protein:          40,
-			Fat:              10,
-			Carb:             40,
-			Status:           1,
-			IsUpdated:        0,
-			CreatedTimestamp: time.Date(2023, 15, 12, 10, 23, 38, 0, time.UTC).UTC(),
-		}, nil)
-		srv := service.NewFavListService(repo)
-		err := srv.RecoverFavList(2, 12, 0)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.RecoverFavList(context.Background(), 2, 12, 0)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "UpdateFavList")
 	})
 	t.Run("Update Favorite List Database Error", func(t *testing.T) {
 		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetFavListById", 1).Return(&repository.FavList{
 			Id:               1,
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -469,7 +622,7 @@ func TestRecoverFavList(t *testing.T) {
 			UserId:           "gooddy20",
 			Name:             "Daily Breakfast",
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
-			List:             "10,11,11",
+			Items:            []repository.Item{{MenuId: 10, Quantity: 1, Unit: "serving"}, {MenuId: 11, Quantity: 2, Unit: "serving"}},
 			Protein:          40,
 			Fat:              10,
 			Carb:             20,
@@ -477,8 +630,93 @@ func TestRecoverFavList(t *testing.T) {
 			IsUpdated:        0,
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(sql.ErrConnDone)
-		srv := service.NewFavListService(repo)
-		err := srv.RecoverFavList(1, 9, 11)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv := service.NewFavListService(repo, menuRepo, repository.NewRecordRepositoryMock())
+		err := srv.RecoverFavList(context.Background(), 1, 9, 11)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
+
+func TestLogFavList(t *testing.T) {
+	favList := func() *repository.FavList {
+		return &repository.FavList{Id: 1, UserId: "gooddy20", Name: "Daily Breakfast", Items: []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 150, Unit: "g"}}, Status: 1, IsUpdated: 1}
+	}
+	t.Run("Success", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		repo.On("GetFavListById", 1).Return(favList(), nil)
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
+		record := repository.Record{
+			UserId:           "gooddy20",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 3, Unit: "serving"}, {MenuId: 10, Quantity: 225, Unit: "g"}},
+			Note:             "Daily Breakfast",
+			Weight:           70,
+			EventTimestamp:   time.Date(2023, 12, 5, 8, 0, 0, 0, time.UTC),
+			Status:           1,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}
+		created := record
+		created.Id, created.Revision = 31, 1
+		recordRepo.On("CreateRecord", record).Return(&created, nil)
+		srv := service.NewFavListService(repo, menuRepo, recordRepo)
+		result, err := srv.LogFavList(context.Background(), "gooddy20", 1, service.LogFavListRequest{Weight: 70, Multiplier: 1.5, EventTimestamp: "2023-12-05 08:00:00"})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, &service.CreatedResponse{Id: 31, Revision: 1}, result)
+	})
+	t.Run("Outdated Menu", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		repo.On("GetFavListById", 1).Return(favList(), nil)
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return([]repository.Menu{{Id: 9, ServingUnit: "serving", Status: 1}, {Id: 10, ServingUnit: "g", Status: 0}}, nil)
+		srv := service.NewFavListService(repo, menuRepo, recordRepo)
+		_, err := srv.LogFavList(context.Background(), "gooddy20", 1, service.LogFavListRequest{EventTimestamp: "2023-12-05 08:00:00"})
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeMenuOutdated, "Menu Id - 10 in the Favorite List are outdated, update the Favorite List before logging it"))
+		recordRepo.AssertNotCalled(t, "CreateRecord")
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		repo.On("GetFavListById", 1).Return(favList(), nil)
+		srv := service.NewFavListService(repo, menuRepo, recordRepo)
+		_, err := srv.LogFavList(context.Background(), "kornkoko", 1, service.LogFavListRequest{EventTimestamp: "2023-12-05 08:00:00"})
+		assert.ErrorIs(t, err, errs.NewPermissionDeniedError())
+		recordRepo.AssertNotCalled(t, "CreateRecord")
+	})
+	t.Run("Deleted Favorite List", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		deletedFavList := favList()
+		deletedFavList.Status = 0
+		repo.On("GetFavListById", 1).Return(deletedFavList, nil)
+		srv := service.NewFavListService(repo, menuRepo, recordRepo)
+		_, err := srv.LogFavList(context.Background(), "gooddy20", 1, service.LogFavListRequest{EventTimestamp: "2023-12-05 08:00:00"})
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeFavListNotFound, "Favorite List Id - 1 is not found"))
+	})
+	t.Run("Invalid Multiplier", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		srv := service.NewFavListService(repo, repository.NewMenuRepositoryMock(), repository.NewRecordRepositoryMock())
+		_, err := srv.LogFavList(context.Background(), "gooddy20", 1, service.LogFavListRequest{Multiplier: -1, EventTimestamp: "2023-12-05 08:00:00"})
+		assert.ErrorIs(t, err, errs.NewValidationError("multiplier", "Multiplier can not be negative"))
+		repo.AssertNotCalled(t, "GetFavListById")
+	})
+	t.Run("Invalid Event Timestamp", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		srv := service.NewFavListService(repo, repository.NewMenuRepositoryMock(), repository.NewRecordRepositoryMock())
+		_, err := srv.LogFavList(context.Background(), "gooddy20", 1, service.LogFavListRequest{EventTimestamp: "2023-12-05"})
+		assert.ErrorIs(t, err, errs.NewValidationError("event_timestamp", `Event timestamp need to be in the format "2023-01-01 00:00:00"`))
+	})
+	t.Run("Create Record Database Error", func(t *testing.T) {
+		repo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		repo.On("GetFavListById", 1).Return(favList(), nil)
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
+		recordRepo.On("CreateRecord", mock.Anything).Return(&repository.Record{}, sql.ErrConnDone)
+		srv := service.NewFavListService(repo, menuRepo, recordRepo)
+		_, err := srv.LogFavList(context.Background(), "gooddy20", 1, service.LogFavListRequest{Note: "Brunch", EventTimestamp: "2023-12-05 08:00:00"})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 	})
 }
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotentResponse is the response of the first request with an "Idempotency-Key" that is sent again to its retries
+type IdempotentResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyLease is the claim of a request on its "Idempotency-Key", a retry that claims the key again after the lease is over
+// gets another lease so that the first request can not save its response over the one of the retry or release the key of the retry
+type IdempotencyLease struct {
+	UserId           string
+	Key              string
+	CreatedTimestamp time.Time     // When the request claimed the key, it tells the claims of the same key apart
+	Duration         time.Duration // How long the claim is held unless it is extended, 0 = it is not extended
+}
+
+type IdempotencyService interface {
+	BeginRequest(context.Context, string, string, string) (*IdempotencyLease, *IdempotentResponse, error)
+	ExtendRequest(context.Context, IdempotencyLease) error
+	CompleteRequest(context.Context, IdempotencyLease, IdempotentResponse) error
+	CancelRequest(context.Context, IdempotencyLease) error
+}
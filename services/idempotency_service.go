@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-nutritioncalculator2/errs"
+	"go-nutritioncalculator2/logs"
+	repository "go-nutritioncalculator2/repositories"
+	"regexp"
+	"time"
+)
+
+// idempotencyKeyPattern accepts the visible ASCII characters so that a key is safe to store and to log e.g. a UUID
+var idempotencyKeyPattern = regexp.MustCompile(`^[\x21-\x7E]{1,255}$`)
+
+type idempotencyService struct {
+	idempotencyRepo repository.IdempotencyRepository
+	ttl             time.Duration
+	lease           time.Duration
+}
+
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository, ttl time.Duration, lease time.Duration) idempotencyService {
+	return idempotencyService{idempotencyRepo: idempotencyRepo, ttl: ttl, lease: lease}
+}
+
+// BeginRequest claims the key of the "User" for the request with the fingerprint, a lease = the key is new so the request is run,
+// otherwise it is the response of the first request with the key that the retry gets back.
+// The key is only held for the lease while the request is in progress, so a key of a request that never finishes e.g. the server crashed is released soon
+func (s idempotencyService) BeginRequest(ctx context.Context, userId string, key string, fingerprint string) (*IdempotencyLease, *IdempotentResponse, error) {
+	if !idempotencyKeyPattern.MatchString(key) {
+		return nil, nil, errs.NewValidationError("Idempotency-Key", "Idempotency-Key need to be 1 - 255 visible ASCII characters e.g. a UUID")
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	err := s.idempotencyRepo.CreateIdempotencyKey(ctx, repository.IdempotencyKey{
+		UserId:           userId,
+		Key:              key,
+		Fingerprint:      fingerprint,
+		ExpiresTimestamp: now.Add(s.lease),
+		CreatedTimestamp: now,
+	})
+	if err == nil {
+		return &IdempotencyLease{UserId: userId, Key: key, CreatedTimestamp: now, Duration: s.lease}, nil, nil
+	}
+	if !errors.Is(err, repository.ErrConflict) {
+		return nil, nil, repositoryError(err)
+	}
+	idempotencyKey, err := s.idempotencyRepo.GetIdempotencyKey(ctx, userId, key)
+	if err != nil {
+		return nil, nil, repositoryError(err)
+	}
+	if idempotencyKey.Fingerprint != fingerprint {
+		return nil, nil, errs.NewUnprocessableError(errs.CodeIdempotencyKeyReused, "Idempotency-Key is already used by another request")
+	}
+	if idempotencyKey.ResponseStatus == nil {
+		return nil, nil, errs.NewConflictError(errs.CodeRequestInProgress, "The request with the Idempotency-Key is still in progress, retry it later")
+	}
+	return nil, &IdempotentResponse{
+		Status:      *idempotencyKey.ResponseStatus,
+		ContentType: idempotencyKey.ResponseContentType,
+		Body:        idempotencyKey.ResponseBody,
+	}, nil
+}
+
+// ExtendRequest holds the key of a request that is still in progress for another lease
+func (s idempotencyService) ExtendRequest(ctx context.Context, lease IdempotencyLease) error {
+	err := s.idempotencyRepo.ExtendIdempotencyKey(ctx, repository.IdempotencyKey{
+		UserId:           lease.UserId,
+		Key:              lease.Key,
+		ExpiresTimestamp: time.Now().UTC().Truncate(time.Second).Add(s.lease),
+		CreatedTimestamp: lease.CreatedTimestamp,
+	})
+	if err != nil {
+		return leaseError(err, lease)
+	}
+	return nil
+}
+
+// CompleteRequest saves the response of the request so that the retries with the key get it back until the TTL of the key is over
+func (s idempotencyService) CompleteRequest(ctx context.Context, lease IdempotencyLease, response IdempotentResponse) error {
+	err := s.idempotencyRepo.SaveIdempotencyResponse(ctx, repository.IdempotencyKey{
+		UserId:              lease.UserId,
+		Key:                 lease.Key,
+		ResponseStatus:      &response.Status,
+		ResponseContentType: response.ContentType,
+		ResponseBody:        response.Body,
+		ExpiresTimestamp:    time.Now().UTC().Truncate(time.Second).Add(s.ttl),
+		CreatedTimestamp:    lease.CreatedTimestamp,
+	})
+	if err != nil {
+		return leaseError(err, lease)
+	}
+	return nil
+}
+
+// CancelRequest releases the key of a request that failed on the server so that a retry with the key is run again,
+// a key that is already claimed again by a retry is left to the retry
+func (s idempotencyService) CancelRequest(ctx context.Context, lease IdempotencyLease) error {
+	err := s.idempotencyRepo.DeleteIdempotencyKey(ctx, repository.IdempotencyKey{UserId: lease.UserId, Key: lease.Key, CreatedTimestamp: lease.CreatedTimestamp})
+	if err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+// leaseError is for a request whose key is claimed again by a retry after the lease was over, so the request may have run twice
+func leaseError(err error, lease IdempotencyLease) error {
+	if errors.Is(err, repository.ErrStale) {
+		logs.Error(fmt.Sprint("Idempotency-Key ", lease.Key, " of User ", lease.UserId, " is claimed again by a retry after its lease was over"))
+		return errs.NewConflictError(errs.CodeRequestInProgress, "The Idempotency-Key is claimed again by a retry")
+	}
+	return repositoryError(err)
+}
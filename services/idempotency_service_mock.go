@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type idempotencyServiceMock struct {
+	mock.Mock
+}
+
+func NewIdempotencyServiceMock() *idempotencyServiceMock {
+	return &idempotencyServiceMock{}
+}
+
+func (s *idempotencyServiceMock) BeginRequest(ctx context.Context, userId string, key string, fingerprint string) (*IdempotencyLease, *IdempotentResponse, error) {
+	args := s.Called(userId, key, fingerprint)
+	return args.Get(0).(*IdempotencyLease), args.Get(1).(*IdempotentResponse), args.Error(2)
+}
+
+func (s *idempotencyServiceMock) ExtendRequest(ctx context.Context, lease IdempotencyLease) error {
+	args := s.Called(lease)
+	return args.Error(0)
+}
+
+func (s *idempotencyServiceMock) CompleteRequest(ctx context.Context, lease IdempotencyLease, response IdempotentResponse) error {
+	args := s.Called(lease, response)
+	return args.Error(0)
+}
+
+func (s *idempotencyServiceMock) CancelRequest(ctx context.Context, lease IdempotencyLease) error {
+	args := s.Called(lease)
+	return args.Error(0)
+}
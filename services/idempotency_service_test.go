@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	service "go-nutritioncalculator2/services"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeginRequest(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	newKey := repository.IdempotencyKey{
+		UserId:           "gooddy20",
+		Key:              "retry-1",
+		Fingerprint:      "abc",
+		ExpiresTimestamp: now.Add(time.Minute),
+		CreatedTimestamp: now,
+	}
+	t.Run("Success Case: New Key", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("CreateIdempotencyKey", newKey).Return(nil)
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		lease, result, err := srv.BeginRequest(context.Background(), "gooddy20", "retry-1", "abc")
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, &service.IdempotencyLease{UserId: "gooddy20", Key: "retry-1", CreatedTimestamp: now, Duration: time.Minute}, lease)
+		assert.Nil(t, result)
+		repo.AssertNotCalled(t, "GetIdempotencyKey")
+	})
+	t.Run("Success Case: Retry", func(t *testing.T) {
+		status := http.StatusOK
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("CreateIdempotencyKey", newKey).Return(repository.ErrConflict)
+		repo.On("GetIdempotencyKey", "gooddy20", "retry-1").Return(&repository.IdempotencyKey{
+			UserId:              "gooddy20",
+			Key:                 "retry-1",
+			Fingerprint:         "abc",
+			ResponseStatus:      &status,
+			ResponseContentType: "application/json",
+			ResponseBody:        []byte(`{"id":12}`),
+		}, nil)
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		lease, result, err := srv.BeginRequest(context.Background(), "gooddy20", "retry-1", "abc")
+		assert.ErrorIs(t, err, nil)
+		assert.Nil(t, lease)
+		assert.Equal(t, &service.IdempotentResponse{Status: http.StatusOK, ContentType: "application/json", Body: []byte(`{"id":12}`)}, result)
+	})
+	t.Run("Key Used By Another Request", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("CreateIdempotencyKey", newKey).Return(repository.ErrConflict)
+		repo.On("GetIdempotencyKey", "gooddy20", "retry-1").Return(&repository.IdempotencyKey{UserId: "gooddy20", Key: "retry-1", Fingerprint: "def"}, nil)
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		_, _, err := srv.BeginRequest(context.Background(), "gooddy20", "retry-1", "abc")
+		assert.ErrorIs(t, err, errs.NewUnprocessableError(errs.CodeIdempotencyKeyReused, "Idempotency-Key is already used by another request"))
+	})
+	t.Run("Request In Progress", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("CreateIdempotencyKey", newKey).Return(repository.ErrConflict)
+		repo.On("GetIdempotencyKey", "gooddy20", "retry-1").Return(&repository.IdempotencyKey{UserId: "gooddy20", Key: "retry-1", Fingerprint: "abc"}, nil)
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		_, _, err := srv.BeginRequest(context.Background(), "gooddy20", "retry-1", "abc")
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeRequestInProgress, "The request with the Idempotency-Key is still in progress, retry it later"))
+	})
+	invalidKeys := map[string]string{"Empty": "", "Space": "retry 1", "Not ASCII": "รีไทร", "Too Long": strings.Repeat("k", 256)}
+	for name, key := range invalidKeys {
+		t.Run("Invalid Key: "+name, func(t *testing.T) {
+			repo := repository.NewIdempotencyRepositoryMock()
+			srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+			_, _, err := srv.BeginRequest(context.Background(), "gooddy20", key, "abc")
+			assert.ErrorIs(t, err, errs.NewValidationError("Idempotency-Key", "Idempotency-Key need to be 1 - 255 visible ASCII characters e.g. a UUID"))
+			repo.AssertNotCalled(t, "CreateIdempotencyKey")
+		})
+	}
+	t.Run("Database Error", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("CreateIdempotencyKey", newKey).Return(errors.New("connection refused"))
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		_, _, err := srv.BeginRequest(context.Background(), "gooddy20", "retry-1", "abc")
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+		repo.AssertNotCalled(t, "GetIdempotencyKey")
+	})
+}
+
+func TestExtendRequest(t *testing.T) {
+	created := time.Date(2023, 12, 5, 10, 0, 0, 0, time.UTC)
+	lease := service.IdempotencyLease{UserId: "gooddy20", Key: "retry-1", CreatedTimestamp: created, Duration: time.Minute}
+	idempotencyKey := repository.IdempotencyKey{
+		UserId:           "gooddy20",
+		Key:              "retry-1",
+		ExpiresTimestamp: time.Now().UTC().Truncate(time.Second).Add(time.Minute),
+		CreatedTimestamp: created,
+	}
+	t.Run("Success", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("ExtendIdempotencyKey", idempotencyKey).Return(nil)
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		err := srv.ExtendRequest(context.Background(), lease)
+		assert.ErrorIs(t, err, nil)
+		repo.AssertCalled(t, "ExtendIdempotencyKey", idempotencyKey)
+	})
+	t.Run("Key Claimed By A Retry", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("ExtendIdempotencyKey", idempotencyKey).Return(repository.ErrStale)
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		err := srv.ExtendRequest(context.Background(), lease)
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeRequestInProgress, "The Idempotency-Key is claimed again by a retry"))
+	})
+	t.Run("Database Error", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("ExtendIdempotencyKey", idempotencyKey).Return(errors.New("connection refused"))
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		err := srv.ExtendRequest(context.Background(), lease)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
+
+func TestCompleteRequest(t *testing.T) {
+	status := http.StatusOK
+	created := time.Date(2023, 12, 5, 10, 0, 0, 0, time.UTC)
+	lease := service.IdempotencyLease{UserId: "gooddy20", Key: "retry-1", CreatedTimestamp: created, Duration: time.Minute}
+	idempotencyKey := repository.IdempotencyKey{
+		UserId:              "gooddy20",
+		Key:                 "retry-1",
+		ResponseStatus:      &status,
+		ResponseContentType: "application/json",
+		ResponseBody:        []byte(`{"id":12}`),
+		ExpiresTimestamp:    time.Now().UTC().Truncate(time.Second).Add(24 * time.Hour),
+		CreatedTimestamp:    created,
+	}
+	response := service.IdempotentResponse{Status: http.StatusOK, ContentType: "application/json", Body: []byte(`{"id":12}`)}
+	t.Run("Success", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("SaveIdempotencyResponse", idempotencyKey).Return(nil)
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		err := srv.CompleteRequest(context.Background(), lease, response)
+		assert.ErrorIs(t, err, nil)
+		repo.AssertCalled(t, "SaveIdempotencyResponse", idempotencyKey)
+	})
+	t.Run("Key Claimed By A Retry", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("SaveIdempotencyResponse", idempotencyKey).Return(repository.ErrStale)
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		err := srv.CompleteRequest(context.Background(), lease, response)
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeRequestInProgress, "The Idempotency-Key is claimed again by a retry"))
+	})
+	t.Run("Database Error", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("SaveIdempotencyResponse", idempotencyKey).Return(errors.New("connection refused"))
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		err := srv.CompleteRequest(context.Background(), lease, response)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
+
+func TestCancelRequest(t *testing.T) {
+	created := time.Date(2023, 12, 5, 10, 0, 0, 0, time.UTC)
+	lease := service.IdempotencyLease{UserId: "gooddy20", Key: "retry-1", CreatedTimestamp: created, Duration: time.Minute}
+	idempotencyKey := repository.IdempotencyKey{UserId: "gooddy20", Key: "retry-1", CreatedTimestamp: created}
+	t.Run("Success", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("DeleteIdempotencyKey", idempotencyKey).Return(nil)
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		err := srv.CancelRequest(context.Background(), lease)
+		assert.ErrorIs(t, err, nil)
+		repo.AssertCalled(t, "DeleteIdempotencyKey", idempotencyKey)
+	})
+	t.Run("Database Error", func(t *testing.T) {
+		repo := repository.NewIdempotencyRepositoryMock()
+		repo.On("DeleteIdempotencyKey", idempotencyKey).Return(errors.New("connection refused"))
+		srv := service.NewIdempotencyService(repo, 24*time.Hour, time.Minute)
+		err := srv.CancelRequest(context.Background(), lease)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-nutritioncalculator2/errs"
+	"go-nutritioncalculator2/logs"
+	repository "go-nutritioncalculator2/repositories"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	UnitServing    = "serving"
+	UnitGram       = "g"
+	UnitMilliliter = "ml"
+)
+
+type Item struct {
+	MenuId   int     `json:"menu_id" example:"9" binding:"gt=0"`    // "Menu"'s id
+	Quantity float64 `json:"quantity" example:"1.5" binding:"gt=0"` // Amount of the "Menu" in the unit e.g. 1.5 servings of "Sticky Rice" or 180 g of "Chicken Breast"
+	Unit     string  `json:"unit" example:"serving"`                // "serving" (default), "g" or "ml", "g" and "ml" need to be the same unit as the "Menu"'s serving size
+}
+
+type itemKey struct {
+	menuId int
+	unit   string
+}
+
+// toRepositoryItems converts either the structured "items" or the comma separated "list" (e.g. "9,9,10")
+// into one item per "Menu" and unit ordered by "Menu"'s id, "items" is used when both of them are sent
+func toRepositoryItems(list string, items []Item) ([]repository.Item, error) {
+	quantities := map[itemKey]float64{}
+	if len(items) != 0 {
+		details := []errs.FieldError{}
+		for i, item := range items {
+			if item.MenuId <= 0 || item.Quantity <= 0 {
+				details = append(details, errs.FieldError{Field: fmt.Sprintf("items[%d]", i), Message: "Items need positive menu_id and quantity"})
+				continue
+			}
+			if item.Unit == "" {
+				item.Unit = UnitServing
+			}
+			if item.Unit != UnitServing && item.Unit != UnitGram && item.Unit != UnitMilliliter {
+				details = append(details, errs.FieldError{Field: fmt.Sprintf("items[%d].unit", i), Message: `Items unit need to be "serving", "g" or "ml"`})
+				continue
+			}
+			quantities[itemKey{menuId: item.MenuId, unit: item.Unit}] += item.Quantity
+		}
+		if len(details) != 0 {
+			return nil, errs.NewValidationErrors(details)
+		}
+	} else {
+		for _, tempMenuId := range strings.Split(list, ",") {
+			menuId, err := strconv.Atoi(strings.TrimSpace(tempMenuId))
+			if err != nil || menuId <= 0 {
+				return nil, errs.NewValidationError("list", `List need to be "Menu"'s id separated by comma e.g. "9,9,10"`)
+			}
+			quantities[itemKey{menuId: menuId, unit: UnitServing}]++
+		}
+	}
+	repoItems := []repository.Item{}
+	for key, quantity := range quantities {
+		repoItems = append(repoItems, repository.Item{MenuId: key.menuId, Quantity: quantity, Unit: key.unit})
+	}
+	sort.Slice(repoItems, func(i, j int) bool {
+		if repoItems[i].MenuId == repoItems[j].MenuId {
+			return repoItems[i].Unit < repoItems[j].Unit
+		}
+		return repoItems[i].MenuId < repoItems[j].MenuId
+	})
+	return repoItems, nil
+}
+
+// patchItems converts the "list" or "items" of a partial update like toRepositoryItems, isSet is false when neither of them is sent
+// and the items of a "Record" or "Favorite List" can not be cleared so sending only null or empty ones is an error
+func patchItems(list Nullable[string], items Nullable[[]Item]) (repoItems []repository.Item, isSet bool, err error) {
+	if !list.Set && !items.Set {
+		return nil, false, nil
+	}
+	if list.Value == "" && len(items.Value) == 0 {
+		return nil, true, errs.NewValidationError("list", "List or Items can not be cleared")
+	}
+	repoItems, err = toRepositoryItems(list.Value, items.Value)
+	return repoItems, true, err
+}
+
+// checkItemUnits makes sure that every "Menu" in the items (ordered by "Menu"'s id) exists, is not deleted and can be measured in the item's unit,
+// a deleted "Menu" that is already in the current items of the "Record" or "Favorite List" can be kept
+func checkItemUnits(ctx context.Context, menuRepo repository.MenuRepository, items []repository.Item, currentItems []repository.Item) error {
+	menuIds := []int{}
+	for i, item := range items {
+		if i == 0 || items[i-1].MenuId != item.MenuId {
+			menuIds = append(menuIds, item.MenuId)
+		}
+	}
+	menues, err := menuRepo.GetMenusByIds(ctx, menuIds)
+	if err != nil {
+		logs.Error(err)
+		return errs.NewUnexpectedError()
+	}
+	menuById := map[int]repository.Menu{}
+	for _, menu := range menues {
+		menuById[menu.Id] = menu
+	}
+	isCurrent := map[int]bool{}
+	for _, item := range currentItems {
+		isCurrent[item.MenuId] = true
+	}
+	details := []errs.FieldError{}
+	for i, item := range items {
+		menu, ok := menuById[item.MenuId]
+		isFirst := i == 0 || items[i-1].MenuId != item.MenuId
+		switch {
+		case !ok:
+			if isFirst {
+				details = append(details, errs.FieldError{Field: "items", Message: fmt.Sprint("Menu Id - ", item.MenuId, " is not found")})
+			}
+		case menu.Status != 1 && !isCurrent[item.MenuId]:
+			if isFirst {
+				details = append(details, errs.FieldError{Field: "items", Message: fmt.Sprint("Menu Id - ", item.MenuId, " is deleted")})
+			}
+		case item.Unit != UnitServing && item.Unit != menu.ServingUnit:
+			details = append(details, errs.FieldError{Field: "items", Message: fmt.Sprint("Menu Id - ", item.MenuId, " can not be measured in ", item.Unit)})
+		}
+	}
+	if len(details) != 0 {
+		return errs.NewValidationErrors(details)
+	}
+	return nil
+}
+
+func toItems(repoItems []repository.Item) []Item {
+	items := []Item{}
+	for _, item := range repoItems {
+		items = append(items, Item{MenuId: item.MenuId, Quantity: item.Quantity, Unit: item.Unit})
+	}
+	return items
+}
+
+// toList converts the items back into the comma separated "list" e.g. (9, 2) and (10, 1) -> "9,9,10",
+// only the whole servings can be written in the "list" so "items" need to be used for the exact amount
+func toList(repoItems []repository.Item) string {
+	menuIds := []string{}
+	for _, item := range repoItems {
+		if item.Unit != UnitServing {
+			continue
+		}
+		for i := 1; float64(i) <= item.Quantity; i++ {
+			menuIds = append(menuIds, strconv.Itoa(item.MenuId))
+		}
+	}
+	return strings.Join(menuIds, ",")
+}
+
+// checkFavoriteMenues makes sure that every "Menu" of the "Favorite Menues" (ordered by id) exists and is not deleted,
+// a deleted "Menu" that is already a "Favorite Menu" of the "User" can be kept
+func checkFavoriteMenues(ctx context.Context, menuRepo repository.MenuRepository, field string, menuIds []int, currentMenuIds []int) error {
+	if len(menuIds) == 0 {
+		return nil
+	}
+	menues, err := menuRepo.GetMenusByIds(ctx, menuIds)
+	if err != nil {
+		return repositoryError(err)
+	}
+	menuById := map[int]repository.Menu{}
+	for _, menu := range menues {
+		menuById[menu.Id] = menu
+	}
+	details := []errs.FieldError{}
+	for _, menuId := range menuIds {
+		menu, ok := menuById[menuId]
+		switch {
+		case !ok:
+			details = append(details, errs.FieldError{Field: field, Message: fmt.Sprint("Menu Id - ", menuId, " is not found")})
+		case menu.Status != 1 && !slices.Contains(currentMenuIds, menuId):
+			details = append(details, errs.FieldError{Field: field, Message: fmt.Sprint("Menu Id - ", menuId, " is deleted")})
+		}
+	}
+	if len(details) != 0 {
+		return errs.NewValidationErrors(details)
+	}
+	return nil
+}
+
+// toMenuIds converts either the "Menu"'s ids or the comma separated "Menu"'s ids (e.g. "9,10")
+// into unique "Menu"'s ids ordered by id, the ids are used when both of them are sent
+func toMenuIds(list string, menuIds []int) ([]int, error) {
+	if len(menuIds) == 0 && list != "" {
+		for _, tempMenuId := range strings.Split(list, ",") {
+			menuId, err := strconv.Atoi(strings.TrimSpace(tempMenuId))
+			if err != nil {
+				return nil, errs.NewValidationError("favorite_menues", `Favorite Menues need to be "Menu"'s id separated by comma e.g. "9,10"`)
+			}
+			menuIds = append(menuIds, menuId)
+		}
+	}
+	uniqueMenuIds := []int{}
+	seen := map[int]bool{}
+	for _, menuId := range menuIds {
+		if menuId <= 0 {
+			return nil, errs.NewValidationError("favorite_menues", `Favorite Menues need to be positive "Menu"'s id`)
+		}
+		if !seen[menuId] {
+			seen[menuId] = true
+			uniqueMenuIds = append(uniqueMenuIds, menuId)
+		}
+	}
+	sort.Ints(uniqueMenuIds)
+	return uniqueMenuIds, nil
+}
+
+func toMenuIdList(menuIds []int) string {
+	tempMenuIds := []string{}
+	for _, menuId := range menuIds {
+		tempMenuIds = append(tempMenuIds, strconv.Itoa(menuId))
+	}
+	return strings.Join(tempMenuIds, ",")
+}
@@ -1,37 +1,80 @@
 package service
 
+import "context"
+
 type NewMenuRequest struct {
-	Name      string  `json:"name" example:"7-11 Pepper Chicken Breast" binding:"required"` // Name of this "Menu"
-	Protein   float64 `json:"protein" example:"19" binding:"required"`                      // Protein (g.) of this "Menu"
-	Fat       float64 `json:"fat" example:"0.5" binding:"required"`                         // Fat (g.) of this "Menu"
-	Carb      float64 `json:"carb" example:"0" binding:"required"`                          // Carb (g.) of this "Menu"
-	CreatorId string  `json:"creator_id" example:"gooddy20" binding:"required"`             // "User Id" that create this "Menu"
+	Name        string              `json:"name" example:"7-11 Pepper Chicken Breast" binding:"required"` // Name of this "Menu"
+	Protein     float64             `json:"protein" example:"19" binding:"min=0"`                         // Protein (g.) of this "Menu"
+	Fat         float64             `json:"fat" example:"0.5" binding:"min=0"`                            // Fat (g.) of this "Menu"
+	Carb        float64             `json:"carb" example:"0" binding:"min=0"`                             // Carb (g.) of this "Menu"
+	Alcohol     float64             `json:"alcohol" example:"0" binding:"min=0"`                          // Alcohol (g.) of this "Menu"
+	ServingSize float64             `json:"serving_size" example:"100" binding:"min=0"`                   // Amount of one serving that protein, fat and carb are measured for e.g. 100 (g.), default = 1
+	ServingUnit string              `json:"serving_unit" example:"g"`                                     // Unit of the serving size "serving" (default), "g" or "ml"
+	Nutrients   map[string]*float64 `json:"nutrients"`                                                    // Extended nutrients per serving size e.g. {"fiber": 2.5, "sodium": 450}, a missing or null nutrient is unknown *keys: fiber, sugar, saturated_fat (g.), cholesterol, sodium, potassium, calcium, iron, vitamin_c (mg.), vitamin_a, vitamin_d (mcg.)
+	CreatorId   string              `json:"creator_id" example:"gooddy20" binding:"required"`             // "User Id" that create this "Menu"
 }
 
 type UpdateMenuRequest struct {
-	Id      int     `json:"id" example:"1" binding:"required"`                            // "Menu"'s id that you want to update
-	Name    string  `json:"name" example:"7-11 Chilli Chicken Breast" binding:"required"` // The name that you want to change to
-	Protein float64 `json:"protein" example:"20" binding:"required"`                      // The protein (g.) that you want to change to
-	Fat     float64 `json:"fat" example:"0.5" binding:"required"`                         // The fat (g.) that you want to change to
-	Carb    float64 `json:"carb" example:"1" binding:"required"`                          // The carb (g.) that you want to change to
+	Id          int                           `json:"id" example:"1" binding:"required"`                                                // "Menu"'s id that you want to update
+	Name        Nullable[string]              `json:"name" swaggertype:"string" example:"7-11 Chilli Chicken Breast" binding:"notnull"` // The name that you want to change to, it can not be null
+	Protein     Nullable[float64]             `json:"protein" swaggertype:"number" example:"20" binding:"min=0"`                        // The protein (g.) that you want to change to, null = 0
+	Fat         Nullable[float64]             `json:"fat" swaggertype:"number" example:"0.5" binding:"min=0"`                           // The fat (g.) that you want to change to, null = 0
+	Carb        Nullable[float64]             `json:"carb" swaggertype:"number" example:"1" binding:"min=0"`                            // The carb (g.) that you want to change to, null = 0
+	Alcohol     Nullable[float64]             `json:"alcohol" swaggertype:"number" example:"0" binding:"min=0"`                         // The alcohol (g.) that you want to change to, null = 0
+	ServingSize Nullable[float64]             `json:"serving_size" swaggertype:"number" example:"100" binding:"min=0"`                  // The serving size that you want to change to, null = 1
+	ServingUnit Nullable[string]              `json:"serving_unit" swaggertype:"string" example:"g"`                                    // The unit of the serving size that you want to change to, null = "serving"
+	Nutrients   Nullable[map[string]*float64] `json:"nutrients" swaggertype:"object,number"`                                            // The extended nutrients that you want to change, a nutrient that is omitted is kept and a null one is removed, null = remove every nutrient
+	Revision    int                           `json:"-"`                                                                                // Revision from the "If-Match" header, 0 = update whatever the current revision is
 }
 
 type MenuResponse struct {
-	Id          int     `json:"id" example:"9"`                // "Menu"'s id that generate by system
-	Name        string  `json:"name" example:"Moo Yang"`       // Name of "Menu" that named by the user
-	Protein     float64 `json:"protein" example:"20"`          // Protein of "Menu"
-	Fat         float64 `json:"fat" example:"5"`               // Fat of "Menu"
-	Carb        float64 `json:"carb" example:"0"`              // Carb of "Menu"
-	CreatorId   string  `json:"creator_id" example:"gooddy20"` // "User Id" that create the "Menu"
-	CreatorName string  `json:"creator_name" example:"GoodDy"` // "Username" that create the "Menu"
-	Like        int     `json:"like" example:"1"`              // Amount of using as favorite menu by "User Id"
-	Status      int     `json:"status" example:"1"`            // 1 = Active, 0 = Deleted
+	Id           int                `json:"id" example:"9"`                 // "Menu"'s id that generate by system
+	Name         string             `json:"name" example:"Moo Yang"`        // Name of "Menu" that named by the user
+	Protein      float64            `json:"protein" example:"20"`           // Protein of "Menu"
+	Fat          float64            `json:"fat" example:"5"`                // Fat of "Menu"
+	Carb         float64            `json:"carb" example:"0"`               // Carb of "Menu"
+	Alcohol      float64            `json:"alcohol" example:"0"`            // Alcohol of "Menu"
+	Kcal         float64            `json:"kcal" example:"125"`             // Energy (kcal) of "Menu" = 4 x protein + 9 x fat + 4 x carb + 7 x alcohol
+	MacroSplit   MacroSplit         `json:"macro_split"`                    // Percentage of energy from each macro nutrient
+	ServingSize  float64            `json:"serving_size" example:"1"`       // Amount of one serving that protein, fat and carb are measured for
+	ServingUnit  string             `json:"serving_unit" example:"serving"` // Unit of the serving size "serving", "g" or "ml"
+	Nutrients    map[string]float64 `json:"nutrients,omitempty"`            // Known extended nutrients per serving size, an unknown nutrient is omitted
+	CreatorId    string             `json:"creator_id" example:"gooddy20"`  // "User Id" that create the "Menu"
+	CreatorName  string             `json:"creator_name" example:"GoodDy"`  // "Username" that create the "Menu"
+	Like         int                `json:"like" example:"1"`               // Amount of using as favorite menu by "User Id"
+	Status       int                `json:"status" example:"1"`             // 1 = Active, 0 = Deleted
+	ParentMenuId *int               `json:"parent_menu_id" example:"4"`     // "Menu"'s id of the previous version, null = the first version
+	Version      int                `json:"version" example:"2"`            // Version of the "Menu" in its lineage, 1 = the first version
+	Revision     int                `json:"revision" example:"1"`           // Revision of the "Menu" that is sent as "If-Match" e.g. "1" to update or delete it
+}
+
+type MenuQuery struct {
+	Name       string   // Case-insensitive part of the name
+	CreatorId  string   // "User Id" that create the "Menu"
+	Status     string   // "1" (default) = Active, "0" = Deleted, "all" = both
+	MinProtein *float64 // Minimum protein (g.)
+	MaxProtein *float64 // Maximum protein (g.)
+	MinFat     *float64 // Minimum fat (g.)
+	MaxFat     *float64 // Maximum fat (g.)
+	MinCarb    *float64 // Minimum carb (g.)
+	MaxCarb    *float64 // Maximum carb (g.)
+	MinKcal    *float64 // Minimum energy (kcal)
+	MaxKcal    *float64 // Maximum energy (kcal)
+	Sort       string   // "newest", "likes", "protein_density" or empty = creation order
+	Limit      int      // Maximum amount of "Menu" in the page, 0 = 50
+	Offset     int      // Amount of "Menu" to skip
+}
+
+type MenuPageResponse struct {
+	Menues     []MenuResponse `json:"menues"`                   // "Menu" in the page
+	NextOffset *int           `json:"next_offset" example:"50"` // "offset" of the next page, null when it is the last page
 }
 
 type MenuService interface {
-	CreateMenu(NewMenuRequest) error
-	GetAllMenues() ([]MenuResponse, error)
-	UpdateMenu(UpdateMenuRequest) error
-	RecoverMenu(int, string) (*MenuResponse, error)
-	DeleteMenu(int) error
+	CreateMenu(context.Context, NewMenuRequest) (*CreatedResponse, error)
+	GetAllMenues(context.Context, MenuQuery) (*MenuPageResponse, error)
+	UpdateMenu(context.Context, string, UpdateMenuRequest) (*CreatedResponse, error)
+	RecoverMenu(context.Context, string, int, string) (*MenuResponse, error)
+	DeleteMenu(context.Context, string, int, int) error
+	GetMenuHistory(context.Context, int) ([]MenuResponse, error)
 }
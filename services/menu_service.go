@@ -1,137 +1,373 @@
 package service
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"fmt"
 	"go-nutritioncalculator2/errs"
-	"go-nutritioncalculator2/logs"
 	repository "go-nutritioncalculator2/repositories"
-	"net/http"
+	"strings"
 	"time"
 )
 
 type menuService struct {
-	menuRepo repository.MenuRepository
+	menuRepo   repository.MenuRepository
+	unitOfWork repository.UnitOfWork
 }
 
-func NewMenuService(menuRepo repository.MenuRepository) menuService {
-	return menuService{menuRepo: menuRepo}
+func NewMenuService(menuRepo repository.MenuRepository, unitOfWork repository.UnitOfWork) menuService {
+	return menuService{menuRepo: menuRepo, unitOfWork: unitOfWork}
 }
 
-func (s menuService) CreateMenu(newMenu NewMenuRequest) error {
+func (s menuService) CreateMenu(ctx context.Context, newMenu NewMenuRequest) (*CreatedResponse, error) {
+	v := validateRequest(newMenu)
 	menu := repository.Menu{
 		Name:             newMenu.Name,
 		Protein:          newMenu.Protein,
 		Fat:              newMenu.Fat,
 		Carb:             newMenu.Carb,
+		Alcohol:          newMenu.Alcohol,
+		ServingSize:      1,
+		ServingUnit:      UnitServing,
 		CreatorId:        newMenu.CreatorId,
 		Status:           1,
+		Version:          1,
 		CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 	}
-	_, err := s.menuRepo.CreateMenu(menu)
+	if newMenu.ServingSize != 0 {
+		menu.ServingSize = newMenu.ServingSize
+	}
+	if newMenu.ServingUnit != "" {
+		menu.ServingUnit = newMenu.ServingUnit
+	}
+	var err error
+	if !v.has("serving_size") {
+		err = v.check(checkServing(menu))
+		if err != nil {
+			return nil, err
+		}
+	}
+	menu.Nutrients, err = toNutrients(newMenu.Nutrients)
+	if err = v.check(err); err != nil {
+		return nil, err
+	}
+	if err = v.err(); err != nil {
+		return nil, err
+	}
+	created, err := s.menuRepo.CreateMenu(ctx, menu)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
 	}
-	return nil
+	return &CreatedResponse{Id: created.Id, Revision: created.Revision}, nil
 }
 
-func (s menuService) GetAllMenues() ([]MenuResponse, error) {
-	menues, err := s.menuRepo.GetAllMenues()
+// Default and maximum amount of "Menu" in a page
+const (
+	defaultMenuLimit = 50
+	maxMenuLimit     = 200
+)
+
+func (s menuService) GetAllMenues(ctx context.Context, query MenuQuery) (*MenuPageResponse, error) {
+	filter, err := toMenuFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	filter.Limit++
+	menues, err := s.menuRepo.GetAllMenues(ctx, filter)
 	if err != nil {
-		logs.Error(err)
-		return nil, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
 	}
 	menuesRes := []MenuResponse{}
-	for i := 0; i < len(menues); i++ {
-		menu := MenuResponse{
-			Id:          menues[i].Id,
-			Name:        menues[i].Name,
-			Protein:     menues[i].Protein,
-			Fat:         menues[i].Fat,
-			Carb:        menues[i].Carb,
-			CreatorId:   menues[i].CreatorId,
-			CreatorName: menues[i].CreatorName,
-			Like:        menues[i].Like,
-			Status:      menues[i].Status,
-		}
-		menuesRes = append(menuesRes, menu)
+	for _, menu := range menues {
+		menuesRes = append(menuesRes, toMenuResponse(menu))
 	}
-	return menuesRes, nil
+	page := MenuPageResponse{Menues: menuesRes}
+	if len(menuesRes) > limit {
+		page.Menues = menuesRes[:limit]
+		nextOffset := filter.Offset + limit
+		page.NextOffset = &nextOffset
+	}
+	return &page, nil
 }
 
-func (s menuService) UpdateMenu(updateMenu UpdateMenuRequest) error {
-	err := s.menuRepo.UpdateMenu(repository.Menu{Id: updateMenu.Id})
+func (s menuService) UpdateMenu(ctx context.Context, userId string, updateMenu UpdateMenuRequest) (*CreatedResponse, error) {
+	err := validateRequest(updateMenu).err()
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, err
 	}
-	menu, err := s.menuRepo.GetMenuById(updateMenu.Id)
+	menu, err := s.menuRepo.GetMenuById(ctx, updateMenu.Id)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found")
+		}
+		return nil, repositoryError(err)
 	}
-	menu.Id = 0
-	menu.Status = 1
-	if updateMenu.Name != "" {
-		menu.Name = updateMenu.Name
+	if menu.CreatorId != userId {
+		return nil, errs.NewPermissionDeniedError()
+	}
+	err = checkRevision("Menu", updateMenu.Revision, menu.Revision)
+	if err != nil {
+		return nil, err
+	}
+	err = s.checkMenuActive(ctx, *menu)
+	if err != nil {
+		return nil, err
+	}
+	menu.ServingSize = updateMenu.ServingSize.Apply(menu.ServingSize)
+	if updateMenu.ServingSize.Null {
+		menu.ServingSize = 1
 	}
-	if updateMenu.Protein != menu.Protein {
-		menu.Protein = updateMenu.Protein
+	menu.ServingUnit = updateMenu.ServingUnit.Apply(menu.ServingUnit)
+	if updateMenu.ServingUnit.Null {
+		menu.ServingUnit = UnitServing
 	}
-	if updateMenu.Fat != menu.Fat {
-		menu.Fat = updateMenu.Fat
+	err = checkServing(*menu)
+	if err != nil {
+		return nil, err
 	}
-	if updateMenu.Carb != menu.Carb {
-		menu.Carb = updateMenu.Carb
+	if updateMenu.Nutrients.Null {
+		menu.Nutrients = nil
+	} else if updateMenu.Nutrients.Set {
+		menu.Nutrients, err = toNutrients(mergeNutrients(menu.Nutrients, updateMenu.Nutrients.Value))
+		if err != nil {
+			return nil, err
+		}
 	}
+	oldRevision := menu.Revision
+	menu.ParentMenuId = &updateMenu.Id
+	menu.Version = nextVersion(*menu)
+	menu.Id = 0
+	menu.Status = 1
+	menu.Name = updateMenu.Name.Apply(menu.Name)
+	menu.Protein = updateMenu.Protein.Apply(menu.Protein)
+	menu.Fat = updateMenu.Fat.Apply(menu.Fat)
+	menu.Carb = updateMenu.Carb.Apply(menu.Carb)
+	menu.Alcohol = updateMenu.Alcohol.Apply(menu.Alcohol)
 	menu.CreatedTimestamp = time.Now().UTC().Truncate(time.Second)
-	_, err = s.menuRepo.CreateMenu(*menu)
+	// The old version is deleted, the new version is created and the favorites are repointed to it as one, or nothing is changed
+	var newMenu *repository.Menu
+	err = s.unitOfWork.Do(ctx, func(repos repository.Repositories) error {
+		err := repos.Menu.UpdateMenu(ctx, repository.Menu{Id: updateMenu.Id, Revision: oldRevision})
+		if err != nil {
+			return err
+		}
+		newMenu, err = repos.Menu.CreateMenu(ctx, *menu)
+		if err != nil {
+			return err
+		}
+		return repos.Menu.RepointMenu(ctx, updateMenu.Id, *newMenu)
+	})
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrStale) {
+			return nil, newStaleError("Menu")
+		}
+		return nil, repositoryError(err)
 	}
-	return nil
+	return &CreatedResponse{Id: newMenu.Id, Revision: newMenu.Revision}, nil
 }
 
-func (s menuService) RecoverMenu(menuId int, name string) (*MenuResponse, error) {
-	menu, err := s.menuRepo.GetMenuById(menuId)
+// RecoverMenu creates a copy of the deleted "Menu" for the "User" as its next version, a "Menu" that is still active
+// or that is already replaced by a newer version can not be recovered so that its lineage does not fork
+func (s menuService) RecoverMenu(ctx context.Context, userId string, menuId int, name string) (*MenuResponse, error) {
+	menu, err := s.menuRepo.GetMenuById(ctx, menuId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errs.AppError{Code: http.StatusNotAcceptable, Message: "Menu Id is not found"}
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found")
 		}
-		logs.Error(err)
-		return nil, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
+	}
+	if menu.Status != 0 {
+		return nil, errs.NewConflictError(errs.CodeMenuNotDeleted, fmt.Sprint("Menu Id - ", menuId, " is not deleted"))
+	}
+	successor, err := s.findSuccessor(ctx, menuId)
+	if err != nil {
+		return nil, err
 	}
+	if successor != nil {
+		return nil, newMenuReplacedError(menuId, successor.Id)
+	}
+	menu.ParentMenuId = &menuId
+	menu.Version = nextVersion(*menu)
 	menu.Id = 0
 	menu.Status = 1
+	menu.CreatorId = userId
+	menu.CreatorName = ""
+	menu.Like = 0
 	menu.CreatedTimestamp = time.Now().UTC().Truncate(time.Second)
 	if name != "" {
 		menu.Name = name
 	}
-	newMenu, err := s.menuRepo.CreateMenu(*menu)
+	newMenu, err := s.menuRepo.CreateMenu(ctx, *menu)
 	if err != nil {
-		logs.Error(err)
-		return nil, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
-	}
-	menuRes := MenuResponse{
-		Id:          newMenu.Id,
-		Name:        newMenu.Name,
-		Protein:     newMenu.Protein,
-		Fat:         newMenu.Fat,
-		Carb:        newMenu.Carb,
-		CreatorId:   newMenu.CreatorId,
-		CreatorName: newMenu.CreatorName,
-		Like:        newMenu.Like,
-		Status:      newMenu.Status,
+		return nil, repositoryError(err)
 	}
+	menuRes := toMenuResponse(*newMenu)
 	return &menuRes, nil
 }
 
-func (s menuService) DeleteMenu(menuId int) error {
-	err := s.menuRepo.UpdateMenu(repository.Menu{Id: menuId})
+// GetMenuHistory lists every version in the lineage of the "Menu" from the first version to the newest one
+func (s menuService) GetMenuHistory(ctx context.Context, menuId int) ([]MenuResponse, error) {
+	menues, err := s.menuRepo.GetMenuHistory(ctx, menuId)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
+	}
+	if len(menues) == 0 {
+		return nil, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found")
+	}
+	menuesRes := []MenuResponse{}
+	for _, menu := range menues {
+		menuesRes = append(menuesRes, toMenuResponse(menu))
+	}
+	return menuesRes, nil
+}
+
+func (s menuService) DeleteMenu(ctx context.Context, userId string, menuId int, revision int) error {
+	menu, err := s.menuRepo.GetMenuById(ctx, menuId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found")
+		}
+		return repositoryError(err)
+	}
+	if menu.CreatorId != userId {
+		return errs.NewPermissionDeniedError()
+	}
+	err = checkRevision("Menu", revision, menu.Revision)
+	if err != nil {
+		return err
+	}
+	err = s.checkMenuActive(ctx, *menu)
+	if err != nil {
+		return err
+	}
+	err = s.menuRepo.UpdateMenu(ctx, repository.Menu{Id: menuId, Revision: menu.Revision})
+	if err != nil {
+		if errors.Is(err, repository.ErrStale) {
+			return newStaleError("Menu")
+		}
+		return repositoryError(err)
 	}
 	return nil
 }
+
+// checkMenuActive refuses a change of a deleted "Menu" so that a version that is already replaced does not get a second successor
+// with the same version, the error names the newer version when there is one
+func (s menuService) checkMenuActive(ctx context.Context, menu repository.Menu) error {
+	if menu.Status == 1 {
+		return nil
+	}
+	successor, err := s.findSuccessor(ctx, menu.Id)
+	if err != nil {
+		return err
+	}
+	if successor != nil {
+		return newMenuReplacedError(menu.Id, successor.Id)
+	}
+	return errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found")
+}
+
+// findSuccessor is the version that replaces the "Menu", nil when the menu is not replaced
+func (s menuService) findSuccessor(ctx context.Context, menuId int) (*repository.Menu, error) {
+	history, err := s.menuRepo.GetMenuHistory(ctx, menuId)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	for _, version := range history {
+		if version.ParentMenuId != nil && *version.ParentMenuId == menuId {
+			return &version, nil
+		}
+	}
+	return nil, nil
+}
+
+func newMenuReplacedError(menuId int, successorId int) errs.AppError {
+	return errs.NewConflictError(errs.CodeMenuOutdated, fmt.Sprint("Menu Id - ", menuId, " is replaced by Menu Id - ", successorId, ", use it instead"))
+}
+
+// nextVersion is the version of the "Menu" that replaces the menu, a menu from before the versions is the first version
+func nextVersion(menu repository.Menu) int {
+	if menu.Version < 1 {
+		return 2
+	}
+	return menu.Version + 1
+}
+
+func toMenuResponse(menu repository.Menu) MenuResponse {
+	return MenuResponse{
+		Id:           menu.Id,
+		Name:         menu.Name,
+		Protein:      menu.Protein,
+		Fat:          menu.Fat,
+		Carb:         menu.Carb,
+		Alcohol:      menu.Alcohol,
+		Kcal:         calories(menu.Protein, menu.Fat, menu.Carb, menu.Alcohol),
+		MacroSplit:   macroSplit(menu.Protein, menu.Fat, menu.Carb, menu.Alcohol),
+		ServingSize:  menu.ServingSize,
+		ServingUnit:  menu.ServingUnit,
+		Nutrients:    menu.Nutrients,
+		CreatorId:    menu.CreatorId,
+		CreatorName:  menu.CreatorName,
+		Like:         menu.Like,
+		Status:       menu.Status,
+		ParentMenuId: menu.ParentMenuId,
+		Version:      menu.Version,
+		Revision:     menu.Revision,
+	}
+}
+
+func checkServing(menu repository.Menu) error {
+	if menu.ServingSize <= 0 {
+		return errs.NewValidationError("serving_size", "Serving size need to be positive")
+	}
+	if menu.ServingUnit != UnitServing && menu.ServingUnit != UnitGram && menu.ServingUnit != UnitMilliliter {
+		return errs.NewValidationError("serving_unit", `Serving unit need to be "serving", "g" or "ml"`)
+	}
+	return nil
+}
+
+func toMenuFilter(query MenuQuery) (repository.MenuFilter, error) {
+	active := 1
+	filter := repository.MenuFilter{
+		Name:      strings.TrimSpace(query.Name),
+		CreatorId: query.CreatorId,
+		Status:    &active,
+		Protein:   repository.MenuRange{Min: query.MinProtein, Max: query.MaxProtein},
+		Fat:       repository.MenuRange{Min: query.MinFat, Max: query.MaxFat},
+		Carb:      repository.MenuRange{Min: query.MinCarb, Max: query.MaxCarb},
+		Kcal:      repository.MenuRange{Min: query.MinKcal, Max: query.MaxKcal},
+		Limit:     defaultMenuLimit,
+		Offset:    query.Offset,
+	}
+	switch query.Status {
+	case "", "1":
+	case "0":
+		deleted := 0
+		filter.Status = &deleted
+	case "all":
+		filter.Status = nil
+	default:
+		return filter, errs.NewValidationError("status", `Status need to be "1", "0" or "all"`)
+	}
+	switch query.Sort {
+	case "", repository.MenuSortNewest, repository.MenuSortLikes, repository.MenuSortProteinDensity:
+		filter.Sort = query.Sort
+	default:
+		return filter, errs.NewValidationError("sort", `Sort need to be "newest", "likes" or "protein_density"`)
+	}
+	for _, valueRange := range []repository.MenuRange{filter.Protein, filter.Fat, filter.Carb, filter.Kcal} {
+		if valueRange.Min != nil && valueRange.Max != nil && *valueRange.Min > *valueRange.Max {
+			return filter, errs.NewValidationError("", "Minimum need to be less than or equal to maximum")
+		}
+	}
+	if query.Limit != 0 {
+		if query.Limit < 0 || query.Limit > maxMenuLimit {
+			return filter, errs.NewValidationError("limit", fmt.Sprintf("Limit need to be between 1 and %d", maxMenuLimit))
+		}
+		filter.Limit = query.Limit
+	}
+	if query.Offset < 0 {
+		return filter, errs.NewValidationError("offset", "Offset can not be negative")
+	}
+	return filter, nil
+}
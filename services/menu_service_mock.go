@@ -1,6 +1,9 @@
 package service
 
-import "github.com/stretchr/testify/mock"
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
 
 type menuServiceMock struct {
 	mock.Mock
@@ -10,27 +13,32 @@ func NewMenuServiceMock() *menuServiceMock {
 	return &menuServiceMock{}
 }
 
-func (s *menuServiceMock) CreateMenu(newMenu NewMenuRequest) error {
+func (s *menuServiceMock) CreateMenu(ctx context.Context, newMenu NewMenuRequest) (*CreatedResponse, error) {
 	args := s.Called(newMenu)
-	return args.Error(0)
+	return args.Get(0).(*CreatedResponse), args.Error(1)
 }
 
-func (s *menuServiceMock) GetAllMenues() ([]MenuResponse, error) {
-	args := s.Called()
-	return args.Get(0).([]MenuResponse), args.Error(1)
+func (s *menuServiceMock) GetAllMenues(ctx context.Context, query MenuQuery) (*MenuPageResponse, error) {
+	args := s.Called(query)
+	return args.Get(0).(*MenuPageResponse), args.Error(1)
 }
 
-func (s *menuServiceMock) UpdateMenu(updateMenu UpdateMenuRequest) error {
-	args := s.Called(updateMenu)
-	return args.Error(0)
+func (s *menuServiceMock) UpdateMenu(ctx context.Context, userId string, updateMenu UpdateMenuRequest) (*CreatedResponse, error) {
+	args := s.Called(userId, updateMenu)
+	return args.Get(0).(*CreatedResponse), args.Error(1)
 }
 
-func (s *menuServiceMock) RecoverMenu(menuId int, name string) (*MenuResponse, error) {
-	args := s.Called(menuId, name)
+func (s *menuServiceMock) RecoverMenu(ctx context.Context, userId string, menuId int, name string) (*MenuResponse, error) {
+	args := s.Called(userId, menuId, name)
 	return args.Get(0).(*MenuResponse), args.Error(1)
 }
 
-func (s *menuServiceMock) DeleteMenu(menuId int) error {
-	args := s.Called(menuId)
+func (s *menuServiceMock) DeleteMenu(ctx context.Context, userId string, menuId int, revision int) error {
+	args := s.Called(userId, menuId, revision)
 	return args.Error(0)
 }
+
+func (s *menuServiceMock) GetMenuHistory(ctx context.Context, menuId int) ([]MenuResponse, error) {
+	args := s.Called(menuId)
+	return args.Get(0).([]MenuResponse), args.Error(1)
+}
@@ -1,15 +1,16 @@
 package service_test
 
 import (
+	"context"
 	"database/sql"
 	"go-nutritioncalculator2/errs"
 	repository "go-nutritioncalculator2/repositories"
 	service "go-nutritioncalculator2/services"
-	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCreateMenu(t *testing.T) {
@@ -20,8 +21,11 @@ func TestCreateMenu(t *testing.T) {
 			Protein:          5,
 			Fat:              1,
 			Carb:             0,
+			ServingSize:      1,
+			ServingUnit:      "serving",
 			CreatorId:        "gooddy20",
 			Status:           1,
+			Version:          1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}).Return(&repository.Menu{
 			Id:               1,
@@ -29,12 +33,17 @@ func TestCreateMenu(t *testing.T) {
 			Protein:          5,
 			Fat:              1,
 			Carb:             0,
+			ServingSize:      1,
+			ServingUnit:      "serving",
 			CreatorId:        "gooddy20",
 			Status:           1,
+			Version:          1,
+			Revision:         1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}, nil)
-		srv := service.NewMenuService(repo)
-		err := srv.CreateMenu(service.NewMenuRequest{
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		result, err := srv.CreateMenu(context.Background(), service.NewMenuRequest{
 			Name:      "Omelet",
 			Protein:   5,
 			Fat:       1,
@@ -42,6 +51,7 @@ func TestCreateMenu(t *testing.T) {
 			CreatorId: "gooddy20",
 		})
 		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, &service.CreatedResponse{Id: 1, Revision: 1}, result)
 	})
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
@@ -50,179 +60,670 @@ func TestCreateMenu(t *testing.T) {
 			Protein:          5,
 			Fat:              1,
 			Carb:             0,
+			ServingSize:      1,
+			ServingUnit:      "serving",
 			CreatorId:        "gooddy20",
 			Status:           1,
+			Version:          1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}).Return(&repository.Menu{}, sql.ErrConnDone)
-		srv := service.NewMenuService(repo)
-		err := srv.CreateMenu(service.NewMenuRequest{
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.CreateMenu(context.Background(), service.NewMenuRequest{
 			Name:      "Omelet",
 			Protein:   5,
 			Fat:       1,
 			Carb:      0,
 			CreatorId: "gooddy20",
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("Success Case: Gram Serving Size", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("CreateMenu", repository.Menu{
+			Name:             "Chicken Breast",
+			Protein:          31,
+			Fat:              3.6,
+			Carb:             0,
+			ServingSize:      100,
+			ServingUnit:      "g",
+			CreatorId:        "gooddy20",
+			Status:           1,
+			Version:          1,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(&repository.Menu{}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.CreateMenu(context.Background(), service.NewMenuRequest{
+			Name:        "Chicken Breast",
+			Protein:     31,
+			Fat:         3.6,
+			ServingSize: 100,
+			ServingUnit: "g",
+			CreatorId:   "gooddy20",
+		})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Invalid Serving", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.CreateMenu(context.Background(), service.NewMenuRequest{Name: "Chicken Breast", Protein: 31, ServingSize: -1, CreatorId: "gooddy20"})
+		assert.ErrorIs(t, err, errs.NewValidationError("serving_size", "Serving size can not be negative"))
+		_, err = srv.CreateMenu(context.Background(), service.NewMenuRequest{Name: "Chicken Breast", Protein: 31, ServingUnit: "oz", CreatorId: "gooddy20"})
+		assert.ErrorIs(t, err, errs.NewValidationError("serving_unit", `Serving unit need to be "serving", "g" or "ml"`))
+		repo.AssertNotCalled(t, "CreateMenu")
+	})
+	t.Run("Success Case: Nutrients", func(t *testing.T) {
+		fiber, sodium := 2.5, 450.0
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("CreateMenu", repository.Menu{
+			Name:             "Oatmeal",
+			Protein:          5,
+			Fat:              3,
+			Carb:             27,
+			ServingSize:      1,
+			ServingUnit:      "serving",
+			Nutrients:        map[string]float64{"fiber": 2.5, "sodium": 450},
+			CreatorId:        "gooddy20",
+			Status:           1,
+			Version:          1,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(&repository.Menu{}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.CreateMenu(context.Background(), service.NewMenuRequest{
+			Name:      "Oatmeal",
+			Protein:   5,
+			Fat:       3,
+			Carb:      27,
+			Nutrients: map[string]*float64{"fiber": &fiber, "sodium": &sodium, "sugar": nil},
+			CreatorId: "gooddy20",
+		})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Invalid Nutrients", func(t *testing.T) {
+		amount, negative := 1.0, -1.0
+		repo := repository.NewMenuRepositoryMock()
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.CreateMenu(context.Background(), service.NewMenuRequest{Name: "Oatmeal", Protein: 5, Nutrients: map[string]*float64{"caffeine": &amount}, CreatorId: "gooddy20"})
+		assert.ErrorIs(t, err, errs.NewValidationError("nutrients", `Nutrient "caffeine" is not supported`))
+		_, err = srv.CreateMenu(context.Background(), service.NewMenuRequest{Name: "Oatmeal", Protein: 5, Nutrients: map[string]*float64{"fiber": &negative}, CreatorId: "gooddy20"})
+		assert.ErrorIs(t, err, errs.NewValidationError("nutrients", `Nutrient "fiber" can not be negative`))
+		repo.AssertNotCalled(t, "CreateMenu")
 	})
 }
 
 func TestGetAllMenues(t *testing.T) {
+	active := 1
+	defaultFilter := repository.MenuFilter{Status: &active, Limit: 51}
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
-		repo.On("GetAllMenues").Return([]repository.Menu{
-			{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()},
-			{Id: 2, Name: "Fried Egg", Protein: 5, Fat: 2, Carb: 0, CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 0, Status: 0, CreatedTimestamp: time.Date(2023, 11, 14, 15, 12, 35, 0, time.UTC).UTC()},
-			{Id: 3, Name: "Khai Tom", Protein: 4, Fat: 0, Carb: 0, CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 18, 06, 11, 0, time.UTC).UTC()},
+		repo.On("GetAllMenues", defaultFilter).Return([]repository.Menu{
+			{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()},
+			{Id: 2, Name: "Fried Egg", Protein: 5, Fat: 2, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 0, Status: 0, CreatedTimestamp: time.Date(2023, 11, 14, 15, 12, 35, 0, time.UTC).UTC()},
+			{Id: 3, Name: "Khai Tom", Protein: 4, Fat: 0, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 18, 06, 11, 0, time.UTC).UTC()},
 		}, nil)
-		srv := service.NewMenuService(repo)
-		result, _ := srv.GetAllMenues()
-		expected := []service.MenuResponse{
-			{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1},
-			{Id: 2, Name: "Fried Egg", Protein: 5, Fat: 2, Carb: 0, CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 0, Status: 0},
-			{Id: 3, Name: "Khai Tom", Protein: 4, Fat: 0, Carb: 0, CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1},
-		}
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		result, _ := srv.GetAllMenues(context.Background(), service.MenuQuery{})
+		expected := &service.MenuPageResponse{Menues: []service.MenuResponse{
+			{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, Kcal: 29, MacroSplit: service.MacroSplit{Protein: 69, Fat: 31, Carb: 0, Alcohol: 0}, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1},
+			{Id: 2, Name: "Fried Egg", Protein: 5, Fat: 2, Carb: 0, Kcal: 38, MacroSplit: service.MacroSplit{Protein: 52.6, Fat: 47.4, Carb: 0, Alcohol: 0}, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 0, Status: 0},
+			{Id: 3, Name: "Khai Tom", Protein: 4, Fat: 0, Carb: 0, Kcal: 16, MacroSplit: service.MacroSplit{Protein: 100, Fat: 0, Carb: 0, Alcohol: 0}, ServingSize: 1, ServingUnit: "serving", CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1},
+		}}
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Success Case: Alcohol", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetAllMenues", defaultFilter).Return([]repository.Menu{
+			{Id: 5, Name: "Beer", Protein: 1, Fat: 0, Carb: 13, Alcohol: 14, ServingSize: 330, ServingUnit: "ml", CreatorId: "gooddy20", CreatorName: "GoodDy", Status: 1},
+			{Id: 6, Name: "Water", ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Status: 1},
+		}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		result, _ := srv.GetAllMenues(context.Background(), service.MenuQuery{})
+		expected := &service.MenuPageResponse{Menues: []service.MenuResponse{
+			{Id: 5, Name: "Beer", Protein: 1, Fat: 0, Carb: 13, Alcohol: 14, Kcal: 154, MacroSplit: service.MacroSplit{Protein: 2.6, Fat: 0, Carb: 33.8, Alcohol: 63.6}, ServingSize: 330, ServingUnit: "ml", CreatorId: "gooddy20", CreatorName: "GoodDy", Status: 1},
+			{Id: 6, Name: "Water", Kcal: 0, MacroSplit: service.MacroSplit{}, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Status: 1},
+		}}
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Success Case: Nutrients", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetAllMenues", defaultFilter).Return([]repository.Menu{
+			{Id: 7, Name: "Oatmeal", Protein: 5, Fat: 3, Carb: 27, ServingSize: 1, ServingUnit: "serving", Nutrients: map[string]float64{"fiber": 2.5}, CreatorId: "gooddy20", CreatorName: "GoodDy", Status: 1},
+		}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		result, _ := srv.GetAllMenues(context.Background(), service.MenuQuery{})
+		expected := &service.MenuPageResponse{Menues: []service.MenuResponse{
+			{Id: 7, Name: "Oatmeal", Protein: 5, Fat: 3, Carb: 27, Kcal: 155, MacroSplit: service.MacroSplit{Protein: 12.9, Fat: 17.4, Carb: 69.7, Alcohol: 0}, ServingSize: 1, ServingUnit: "serving", Nutrients: map[string]float64{"fiber": 2.5}, CreatorId: "gooddy20", CreatorName: "GoodDy", Status: 1},
+		}}
 		assert.Equal(t, expected, result)
 	})
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
-		repo.On("GetAllMenues").Return([]repository.Menu{}, sql.ErrConnDone)
-		srv := service.NewMenuService(repo)
-		_, err := srv.GetAllMenues()
-		assert.Equal(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		repo.On("GetAllMenues", defaultFilter).Return([]repository.Menu{}, sql.ErrConnDone)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.GetAllMenues(context.Background(), service.MenuQuery{})
+		assert.Equal(t, err, errs.NewUnexpectedError())
 	})
+	t.Run("Success Case: Filter And Next Page", func(t *testing.T) {
+		minProtein, maxKcal := 20.0, 500.0
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetAllMenues", repository.MenuFilter{
+			Name:      "chicken",
+			CreatorId: "gooddy20",
+			Protein:   repository.MenuRange{Min: &minProtein},
+			Kcal:      repository.MenuRange{Max: &maxKcal},
+			Sort:      "protein_density",
+			Limit:     3,
+			Offset:    4,
+		}).Return([]repository.Menu{
+			{Id: 8, Name: "Chicken Breast", Protein: 31, Fat: 3.6, ServingSize: 100, ServingUnit: "g", Status: 1},
+			{Id: 9, Name: "Chicken Wing", Protein: 27, Fat: 8, ServingSize: 100, ServingUnit: "g", Status: 0},
+			{Id: 10, Name: "Fried Chicken", Protein: 20, Fat: 15, Carb: 10, ServingSize: 1, ServingUnit: "serving", Status: 1},
+		}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		result, err := srv.GetAllMenues(context.Background(), service.MenuQuery{Name: " chicken ", CreatorId: "gooddy20", Status: "all", MinProtein: &minProtein, MaxKcal: &maxKcal, Sort: "protein_density", Limit: 2, Offset: 4})
+		nextOffset := 6
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, []int{8, 9}, []int{result.Menues[0].Id, result.Menues[1].Id})
+		assert.Equal(t, &nextOffset, result.NextOffset)
+	})
+	type invalidQueryCase struct {
+		Name     string
+		Query    service.MenuQuery
+		Expected error
+	}
+	minimum, maximum := 30.0, 20.0
+	invalidQueryCases := []invalidQueryCase{
+		{Name: "Invalid Status", Query: service.MenuQuery{Status: "deleted"}, Expected: errs.NewValidationError("status", `Status need to be "1", "0" or "all"`)},
+		{Name: "Invalid Sort", Query: service.MenuQuery{Sort: "name"}, Expected: errs.NewValidationError("sort", `Sort need to be "newest", "likes" or "protein_density"`)},
+		{Name: "Minimum Over Maximum", Query: service.MenuQuery{MinCarb: &minimum, MaxCarb: &maximum}, Expected: errs.NewValidationError("", "Minimum need to be less than or equal to maximum")},
+		{Name: "Invalid Limit", Query: service.MenuQuery{Limit: -1}, Expected: errs.NewValidationError("limit", "Limit need to be between 1 and 200")},
+		{Name: "Invalid Offset", Query: service.MenuQuery{Offset: -1}, Expected: errs.NewValidationError("offset", "Offset can not be negative")},
+	}
+	for _, c := range invalidQueryCases {
+		t.Run(c.Name, func(t *testing.T) {
+			repo := repository.NewMenuRepositoryMock()
+			unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+			srv := service.NewMenuService(repo, unitOfWork)
+			_, err := srv.GetAllMenues(context.Background(), c.Query)
+			assert.ErrorIs(t, err, c.Expected)
+			repo.AssertNotCalled(t, "GetAllMenues")
+		})
+	}
 }
 
 func TestUpdateMenu(t *testing.T) {
+	firstId, seventhId := 1, 7
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
 		repo.On("UpdateMenu", repository.Menu{Id: 1}).Return(nil)
-		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 0, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
 		repo.On("CreateMenu", repository.Menu{
 			Id:               0,
 			Name:             "Omelet",
 			Protein:          5.5,
 			Fat:              0.5,
 			Carb:             1,
+			ServingSize:      1,
+			ServingUnit:      "serving",
 			CreatorId:        "gooddy20",
 			CreatorName:      "GoodDy",
 			Like:             2,
 			Status:           1,
+			ParentMenuId:     &firstId,
+			Version:          2,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}).Return(&repository.Menu{
 			Id:               4,
+			ParentMenuId:     &firstId,
+			Version:          2,
+			Revision:         1,
 			Name:             "Omelet",
 			Protein:          5.5,
 			Fat:              0.5,
 			Carb:             1,
+			ServingSize:      1,
+			ServingUnit:      "serving",
 			CreatorId:        "gooddy20",
 			CreatorName:      "GoodDy",
 			Like:             2,
 			Status:           1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}, nil)
-		srv := service.NewMenuService(repo)
-		err := srv.UpdateMenu(service.UpdateMenuRequest{Id: 1, Name: "Omelet", Protein: 5.5, Fat: 0.5, Carb: 1})
+		repo.On("RepointMenu", 1, repository.Menu{
+			Id:               4,
+			ParentMenuId:     &firstId,
+			Version:          2,
+			Revision:         1,
+			Name:             "Omelet",
+			Protein:          5.5,
+			Fat:              0.5,
+			Carb:             1,
+			ServingSize:      1,
+			ServingUnit:      "serving",
+			CreatorId:        "gooddy20",
+			CreatorName:      "GoodDy",
+			Like:             2,
+			Status:           1,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewMenuService(repo, unitOfWork)
+		result, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 1, Name: service.NewNullable("Omelet"), Protein: service.NewNullable[float64](5.5), Fat: service.NewNullable[float64](0.5), Carb: service.NewNullable[float64](1)})
 		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, &service.CreatedResponse{Id: 4, Revision: 1}, result)
 	})
-	t.Run("Update Menu Database Error", func(t *testing.T) {
+	t.Run("Success Case: Keep Nutrients", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
-		repo.On("UpdateMenu", repository.Menu{Id: 1}).Return(sql.ErrConnDone)
-		srv := service.NewMenuService(repo)
-		err := srv.UpdateMenu(service.UpdateMenuRequest{Id: 1, Name: "Omelet", Protein: 5.5, Fat: 0.5, Carb: 1})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
-		repo.AssertNotCalled(t, "GetMenuById")
+		repo.On("GetMenuById", 7).Return(&repository.Menu{Id: 7, Name: "Oatmeal", Protein: 5, Fat: 3, Carb: 27, ServingSize: 1, ServingUnit: "serving", Nutrients: map[string]float64{"fiber": 2.5}, CreatorId: "gooddy20", Status: 1}, nil)
+		repo.On("UpdateMenu", repository.Menu{Id: 7}).Return(nil)
+		repo.On("CreateMenu", repository.Menu{
+			Name:             "Oatmeal",
+			Protein:          6,
+			Fat:              3,
+			Carb:             27,
+			ServingSize:      1,
+			ServingUnit:      "serving",
+			Nutrients:        map[string]float64{"fiber": 2.5},
+			CreatorId:        "gooddy20",
+			Status:           1,
+			ParentMenuId:     &seventhId,
+			Version:          2,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(&repository.Menu{}, nil)
+		repo.On("RepointMenu", 7, repository.Menu{}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 7, Name: service.NewNullable("Oatmeal"), Protein: service.NewNullable[float64](6), Fat: service.NewNullable[float64](3), Carb: service.NewNullable[float64](27)})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Success Case: Clear Nutrients", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 7).Return(&repository.Menu{Id: 7, Name: "Oatmeal", Protein: 5, Fat: 3, Carb: 27, ServingSize: 1, ServingUnit: "serving", Nutrients: map[string]float64{"fiber": 2.5}, CreatorId: "gooddy20", Status: 1}, nil)
+		repo.On("UpdateMenu", repository.Menu{Id: 7}).Return(nil)
+		repo.On("CreateMenu", repository.Menu{
+			Name:             "Oatmeal",
+			Protein:          5,
+			Fat:              3,
+			Carb:             27,
+			ServingSize:      1,
+			ServingUnit:      "serving",
+			CreatorId:        "gooddy20",
+			Status:           1,
+			ParentMenuId:     &seventhId,
+			Version:          2,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(&repository.Menu{}, nil)
+		repo.On("RepointMenu", 7, repository.Menu{}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 7, Name: service.NewNullable("Oatmeal"), Protein: service.NewNullable[float64](5), Fat: service.NewNullable[float64](3), Carb: service.NewNullable[float64](27), Nutrients: service.NewNullable(map[string]*float64{"fiber": nil})})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Success Case: Merge Nutrients and Keep Omitted Fields", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 7).Return(&repository.Menu{Id: 7, Name: "Oatmeal", Protein: 5, Fat: 3, Carb: 27, ServingSize: 1, ServingUnit: "serving", Nutrients: map[string]float64{"fiber": 2.5, "sugar": 1}, CreatorId: "gooddy20", Status: 1}, nil)
+		repo.On("UpdateMenu", repository.Menu{Id: 7}).Return(nil)
+		repo.On("CreateMenu", repository.Menu{
+			Name:             "Oatmeal",
+			Protein:          5,
+			Fat:              0,
+			Carb:             27,
+			ServingSize:      1,
+			ServingUnit:      "serving",
+			Nutrients:        map[string]float64{"sodium": 120, "sugar": 1},
+			CreatorId:        "gooddy20",
+			Status:           1,
+			ParentMenuId:     &seventhId,
+			Version:          2,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(&repository.Menu{}, nil)
+		repo.On("RepointMenu", 7, repository.Menu{}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewMenuService(repo, unitOfWork)
+		sodium := 120.0
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 7, Fat: service.NewNullable[float64](0), Nutrients: service.NewNullable(map[string]*float64{"fiber": nil, "sodium": &sodium})})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Null Name", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 7).Return(&repository.Menu{Id: 7, Name: "Oatmeal", ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 1}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 7, Name: service.NewNull[string]()})
+		assert.ErrorIs(t, err, errs.NewValidationError("name", "Name can not be null"))
+		repo.AssertNotCalled(t, "UpdateMenu", mock.Anything)
+	})
+	t.Run("No The Menu Id", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{}, repository.ErrNotFound)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 1, Name: service.NewNullable("Omelet"), Protein: service.NewNullable[float64](5.5), Fat: service.NewNullable[float64](0.5), Carb: service.NewNullable[float64](1)})
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found"))
+		repo.AssertNotCalled(t, "UpdateMenu")
 		repo.AssertNotCalled(t, "CreateMenu")
 	})
 	t.Run("Get Menu Database Error", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
-		repo.On("UpdateMenu", repository.Menu{Id: 1}).Return(nil)
-		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 0, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()},
-			sql.ErrConnDone)
-		srv := service.NewMenuService(repo)
-		err := srv.UpdateMenu(service.UpdateMenuRequest{Id: 1, Name: "Omelet", Protein: 5.5, Fat: 0.5, Carb: 1})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		repo.On("GetMenuById", 1).Return(&repository.Menu{}, sql.ErrConnDone)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 1, Name: service.NewNullable("Omelet"), Protein: service.NewNullable[float64](5.5), Fat: service.NewNullable[float64](0.5), Carb: service.NewNullable[float64](1)})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+		repo.AssertNotCalled(t, "UpdateMenu")
+		repo.AssertNotCalled(t, "CreateMenu")
+	})
+	t.Run("Not The Creator", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 2, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 1, Name: service.NewNullable("Omelet"), Protein: service.NewNullable[float64](5.5), Fat: service.NewNullable[float64](0.5), Carb: service.NewNullable[float64](1)})
+		assert.ErrorIs(t, err, errs.NewPermissionDeniedError())
+		repo.AssertNotCalled(t, "UpdateMenu")
+		repo.AssertNotCalled(t, "CreateMenu")
+	})
+	t.Run("Menu Is Deleted", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 7).Return(&repository.Menu{Id: 7, Name: "Oatmeal", ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 0, Version: 1}, nil)
+		repo.On("GetMenuHistory", 7).Return([]repository.Menu{{Id: 7, Name: "Oatmeal", Status: 0, Version: 1}}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 7, Protein: service.NewNullable[float64](6)})
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found"))
+		repo.AssertNotCalled(t, "UpdateMenu", mock.Anything)
+		repo.AssertNotCalled(t, "CreateMenu", mock.Anything)
+	})
+	t.Run("Menu Is Already Replaced", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 7).Return(&repository.Menu{Id: 7, Name: "Oatmeal", ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 0, Version: 1}, nil)
+		repo.On("GetMenuHistory", 7).Return([]repository.Menu{{Id: 7, Name: "Oatmeal", Status: 0, Version: 1}, {Id: 8, Name: "Oatmeal", ParentMenuId: &seventhId, Status: 1, Version: 2}}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 7, Protein: service.NewNullable[float64](6)})
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeMenuOutdated, "Menu Id - 7 is replaced by Menu Id - 8, use it instead"))
+		repo.AssertNotCalled(t, "UpdateMenu", mock.Anything)
+		repo.AssertNotCalled(t, "CreateMenu", mock.Anything)
+	})
+	t.Run("Update Menu Database Error", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
+		repo.On("UpdateMenu", repository.Menu{Id: 1}).Return(sql.ErrConnDone)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 1, Name: service.NewNullable("Omelet"), Protein: service.NewNullable[float64](5.5), Fat: service.NewNullable[float64](0.5), Carb: service.NewNullable[float64](1)})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "CreateMenu")
 	})
 	t.Run("Create Menu Database Error", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
 		repo.On("UpdateMenu", repository.Menu{Id: 1}).Return(nil)
-		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 0, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
 		repo.On("CreateMenu", repository.Menu{
 			Id:               0,
 			Name:             "Omelet",
 			Protein:          5.5,
 			Fat:              0.5,
 			Carb:             1,
+			ServingSize:      1,
+			ServingUnit:      "serving",
 			CreatorId:        "gooddy20",
 			CreatorName:      "GoodDy",
 			Like:             2,
 			Status:           1,
+			ParentMenuId:     &firstId,
+			Version:          2,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}).Return(&repository.Menu{
 			Id:               4,
+			ParentMenuId:     &firstId,
+			Version:          2,
 			Name:             "Omelet",
 			Protein:          5.5,
 			Fat:              0.5,
 			Carb:             1,
+			ServingSize:      1,
+			ServingUnit:      "serving",
 			CreatorId:        "gooddy20",
 			CreatorName:      "GoodDy",
 			Like:             2,
 			Status:           1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}, sql.ErrConnDone)
-		srv := service.NewMenuService(repo)
-		err := srv.UpdateMenu(service.UpdateMenuRequest{Id: 1, Name: "Omelet", Protein: 5.5, Fat: 0.5, Carb: 1})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 1, Name: service.NewNullable("Omelet"), Protein: service.NewNullable[float64](5.5), Fat: service.NewNullable[float64](0.5), Carb: service.NewNullable[float64](1)})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+		unitOfWork.AssertNotCalled(t, "Do")
+		repo.AssertNotCalled(t, "RepointMenu", mock.Anything, mock.Anything)
+	})
+	t.Run("Repoint Menu Database Error", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 7).Return(&repository.Menu{Id: 7, Name: "Oatmeal", Protein: 5, Fat: 3, Carb: 27, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 1, Version: 3}, nil)
+		repo.On("UpdateMenu", repository.Menu{Id: 7}).Return(nil)
+		repo.On("CreateMenu", repository.Menu{
+			Name:             "Oatmeal",
+			Protein:          6,
+			Fat:              3,
+			Carb:             27,
+			ServingSize:      1,
+			ServingUnit:      "serving",
+			CreatorId:        "gooddy20",
+			Status:           1,
+			ParentMenuId:     &seventhId,
+			Version:          4,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(&repository.Menu{Id: 8}, nil)
+		repo.On("RepointMenu", 7, repository.Menu{Id: 8}).Return(sql.ErrConnDone)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 7, Name: service.NewNullable("Oatmeal"), Protein: service.NewNullable[float64](6), Fat: service.NewNullable[float64](3), Carb: service.NewNullable[float64](27)})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+		unitOfWork.AssertNotCalled(t, "Do")
+	})
+	t.Run("Commit Error", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 7).Return(&repository.Menu{Id: 7, Name: "Oatmeal", Protein: 5, Fat: 3, Carb: 27, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 1}, nil)
+		repo.On("UpdateMenu", repository.Menu{Id: 7}).Return(nil)
+		repo.On("CreateMenu", mock.Anything).Return(&repository.Menu{Id: 8}, nil)
+		repo.On("RepointMenu", 7, repository.Menu{Id: 8}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		unitOfWork.On("Do").Return(sql.ErrTxDone)
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.UpdateMenu(context.Background(), "gooddy20", service.UpdateMenuRequest{Id: 7, Protein: service.NewNullable[float64](6)})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 	})
 }
 
 func TestRecoverMenu(t *testing.T) {
+	deletedId := 3
+	deletedMenu := func() *repository.Menu {
+		return &repository.Menu{Id: 3, Name: "Khai Tom", Protein: 4, Fat: 0, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 0, Version: 1, CreatedTimestamp: time.Date(2023, 11, 14, 18, 06, 11, 0, time.UTC).UTC()}
+	}
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
-		repo.On("GetMenuById", 3).Return(&repository.Menu{Id: 3, Name: "Khai Tom", Protein: 4, Fat: 0, Carb: 0, CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 18, 06, 11, 0, time.UTC).UTC()}, nil)
-		repo.On("CreateMenu", repository.Menu{Id: 0, Name: "Boiled Egg", Protein: 4, Fat: 0, Carb: 0, CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1, CreatedTimestamp: time.Now().UTC().Truncate(time.Second)}).Return(&repository.Menu{Id: 4, Name: "Boiled Egg", Protein: 4, Fat: 0, Carb: 0, CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1, CreatedTimestamp: time.Now().UTC().Truncate(time.Second)}, nil)
-		srv := service.NewMenuService(repo)
-		result, _ := srv.RecoverMenu(3, "Boiled Egg")
-		expected := &service.MenuResponse{Id: 4, Name: "Boiled Egg", Protein: 4, Fat: 0, Carb: 0, CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1}
+		repo.On("GetMenuById", 3).Return(deletedMenu(), nil)
+		repo.On("GetMenuHistory", 3).Return([]repository.Menu{*deletedMenu()}, nil)
+		repo.On("CreateMenu", repository.Menu{Id: 0, Name: "Boiled Egg", Protein: 4, Fat: 0, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 1, ParentMenuId: &deletedId, Version: 2, CreatedTimestamp: time.Now().UTC().Truncate(time.Second)}).Return(&repository.Menu{Id: 4, Name: "Boiled Egg", Protein: 4, Fat: 0, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 1, ParentMenuId: &deletedId, Version: 2, CreatedTimestamp: time.Now().UTC().Truncate(time.Second)}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		result, _ := srv.RecoverMenu(context.Background(), "gooddy20", 3, "Boiled Egg")
+		expected := &service.MenuResponse{Id: 4, Name: "Boiled Egg", Protein: 4, Fat: 0, Carb: 0, Kcal: 16, MacroSplit: service.MacroSplit{Protein: 100, Fat: 0, Carb: 0, Alcohol: 0}, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 1, ParentMenuId: &deletedId, Version: 2}
 		assert.Equal(t, expected, result)
+		repo.AssertNotCalled(t, "RepointMenu")
+	})
+	t.Run("Menu Is Not Deleted", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		activeMenu := deletedMenu()
+		activeMenu.Status = 1
+		repo.On("GetMenuById", 3).Return(activeMenu, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.RecoverMenu(context.Background(), "gooddy20", 3, "Boiled Egg")
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeMenuNotDeleted, "Menu Id - 3 is not deleted"))
+		repo.AssertNotCalled(t, "CreateMenu", mock.Anything)
+	})
+	t.Run("Menu Is Already Replaced", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 3).Return(deletedMenu(), nil)
+		repo.On("GetMenuHistory", 3).Return([]repository.Menu{*deletedMenu(), {Id: 8, Name: "Khai Tom", ParentMenuId: &deletedId, Version: 2, Status: 1}}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.RecoverMenu(context.Background(), "gooddy20", 3, "Boiled Egg")
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeMenuOutdated, "Menu Id - 3 is replaced by Menu Id - 8, use it instead"))
+		repo.AssertNotCalled(t, "CreateMenu", mock.Anything)
 	})
 	t.Run("No The Menu Id", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
-		repo.On("GetMenuById", 3).Return(&repository.Menu{}, sql.ErrNoRows)
-		srv := service.NewMenuService(repo)
-		_, err := srv.RecoverMenu(3, "Boiled Egg")
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: "Menu Id is not found"})
+		repo.On("GetMenuById", 3).Return(&repository.Menu{}, repository.ErrNotFound)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.RecoverMenu(context.Background(), "gooddy20", 3, "Boiled Egg")
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found"))
 	})
 	t.Run("Get Menu Database Error", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
 		repo.On("GetMenuById", 3).Return(&repository.Menu{}, sql.ErrConnDone)
-		srv := service.NewMenuService(repo)
-		_, err := srv.RecoverMenu(3, "Boiled Egg")
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.RecoverMenu(context.Background(), "gooddy20", 3, "Boiled Egg")
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 	})
 	t.Run("Create Menu Database Error", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
-		repo.On("GetMenuById", 3).Return(&repository.Menu{Id: 3, Name: "Khai Tom", Protein: 4, Fat: 0, Carb: 0, CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 18, 06, 11, 0, time.UTC).UTC()}, nil)
-		repo.On("CreateMenu", repository.Menu{Id: 0, Name: "Boiled Egg", Protein: 4, Fat: 0, Carb: 0, CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 1, Status: 1, CreatedTimestamp: time.Now().UTC().Truncate(time.Second)}).Return(&repository.Menu{}, sql.ErrConnDone)
-		srv := service.NewMenuService(repo)
-		_, err := srv.RecoverMenu(3, "Boiled Egg")
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		repo.On("GetMenuById", 3).Return(deletedMenu(), nil)
+		repo.On("GetMenuHistory", 3).Return([]repository.Menu{*deletedMenu()}, nil)
+		repo.On("CreateMenu", repository.Menu{Id: 0, Name: "Boiled Egg", Protein: 4, Fat: 0, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 1, ParentMenuId: &deletedId, Version: 2, CreatedTimestamp: time.Now().UTC().Truncate(time.Second)}).Return(&repository.Menu{}, sql.ErrConnDone)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.RecoverMenu(context.Background(), "gooddy20", 3, "Boiled Egg")
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
+
+func TestGetMenuHistory(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		firstId := 1
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuHistory", 4).Return([]repository.Menu{
+			{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 0, Version: 1},
+			{Id: 4, Name: "Omelet", Protein: 5.5, Fat: 0.5, Carb: 1, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 1, ParentMenuId: &firstId, Version: 2},
+		}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		result, err := srv.GetMenuHistory(context.Background(), 4)
+		expected := []service.MenuResponse{
+			{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Kcal: 29, MacroSplit: service.MacroSplit{Protein: 69, Fat: 31}, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 0, Version: 1},
+			{Id: 4, Name: "Omelet", Protein: 5.5, Fat: 0.5, Carb: 1, Kcal: 30.5, MacroSplit: service.MacroSplit{Protein: 72.1, Fat: 14.8, Carb: 13.1}, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", Status: 1, ParentMenuId: &firstId, Version: 2},
+		}
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("No The Menu Id", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuHistory", 4).Return([]repository.Menu{}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.GetMenuHistory(context.Background(), 4)
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found"))
+	})
+	t.Run("Database Error", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuHistory", 4).Return([]repository.Menu{}, sql.ErrConnDone)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		_, err := srv.GetMenuHistory(context.Background(), 4)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 	})
 }
 
 func TestDeleteMenu(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
 		repo.On("UpdateMenu", repository.Menu{Id: 1}).Return(nil)
-		srv := service.NewMenuService(repo)
-		err := srv.DeleteMenu(1)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		err := srv.DeleteMenu(context.Background(), "gooddy20", 1, 0)
 		assert.ErrorIs(t, err, nil)
 	})
+	t.Run("No The Menu Id", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{}, repository.ErrNotFound)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		err := srv.DeleteMenu(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found"))
+		repo.AssertNotCalled(t, "UpdateMenu")
+	})
+	t.Run("Not The Creator", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "kornkoko", CreatorName: "Kornkoko", Like: 2, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		err := srv.DeleteMenu(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewPermissionDeniedError())
+		repo.AssertNotCalled(t, "UpdateMenu")
+	})
+	t.Run("Menu Is Deleted", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", CreatorId: "gooddy20", Status: 0, Version: 1, Revision: 2}, nil)
+		repo.On("GetMenuHistory", 1).Return([]repository.Menu{{Id: 1, Name: "Omelet", Status: 0, Version: 1}}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		err := srv.DeleteMenu(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found"))
+		repo.AssertNotCalled(t, "UpdateMenu", mock.Anything)
+	})
+	t.Run("Menu Is Already Replaced", func(t *testing.T) {
+		firstId := 1
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", CreatorId: "gooddy20", Status: 0, Version: 1, Revision: 2}, nil)
+		repo.On("GetMenuHistory", 1).Return([]repository.Menu{{Id: 1, Name: "Omelet", Status: 0, Version: 1}, {Id: 4, Name: "Omelet", ParentMenuId: &firstId, Status: 1, Version: 2}}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		err := srv.DeleteMenu(context.Background(), "gooddy20", 1, 2)
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeMenuOutdated, "Menu Id - 1 is replaced by Menu Id - 4, use it instead"))
+		repo.AssertNotCalled(t, "UpdateMenu", mock.Anything)
+	})
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
 		repo.On("UpdateMenu", repository.Menu{Id: 1}).Return(sql.ErrConnDone)
-		srv := service.NewMenuService(repo)
-		err := srv.DeleteMenu(1)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		err := srv.DeleteMenu(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("Stale Revision", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1, Revision: 3, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		err := srv.DeleteMenu(context.Background(), "gooddy20", 1, 2)
+		assert.ErrorIs(t, err, errs.NewPreconditionFailedError("Menu has been changed by another request, get it again and retry"))
+		repo.AssertNotCalled(t, "UpdateMenu")
+	})
+	t.Run("Changed By Another Request", func(t *testing.T) {
+		repo := repository.NewMenuRepositoryMock()
+		repo.On("GetMenuById", 1).Return(&repository.Menu{Id: 1, Name: "Omelet", Protein: 5, Fat: 1, Carb: 0, ServingSize: 1, ServingUnit: "serving", CreatorId: "gooddy20", CreatorName: "GoodDy", Like: 2, Status: 1, Revision: 3, CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC()}, nil)
+		repo.On("UpdateMenu", repository.Menu{Id: 1, Revision: 3}).Return(repository.ErrStale)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Menu: repo})
+		srv := service.NewMenuService(repo, unitOfWork)
+		err := srv.DeleteMenu(context.Background(), "gooddy20", 1, 3)
+		assert.ErrorIs(t, err, errs.NewPreconditionFailedError("Menu has been changed by another request, get it again and retry"))
 	})
 }
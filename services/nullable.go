@@ -0,0 +1,52 @@
+package service
+
+import "encoding/json"
+
+// Nullable is a field of a partial update request that tells apart the 3 states that a plain field can not:
+// omitted = keep the current value, null = clear the value and any other JSON value = change to the value
+type Nullable[T any] struct {
+	Set   bool // The field is in the request
+	Null  bool // The field is explicitly null
+	Value T    // The value of the field when it is set and not null
+}
+
+// NewNullable is a field that is set to the value
+func NewNullable[T any](value T) Nullable[T] {
+	return Nullable[T]{Set: true, Value: value}
+}
+
+// NewNull is a field that is explicitly set to null
+func NewNull[T any]() Nullable[T] {
+	return Nullable[T]{Set: true, Null: true}
+}
+
+// UnmarshalJSON is only called for a field that is in the request, including when it is null
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+// Apply returns the value after the update, the current value when the field is omitted or the zero value when it is null
+func (n Nullable[T]) Apply(current T) T {
+	if !n.Set {
+		return current
+	}
+	if n.Null {
+		var zero T
+		return zero
+	}
+	return n.Value
+}
+
+// nullableField lets validateRequest look into a "Nullable" field of any type
+type nullableField interface {
+	nullable() (isSet bool, isNull bool, value any)
+}
+
+func (n Nullable[T]) nullable() (bool, bool, any) {
+	return n.Set, n.Null, n.Value
+}
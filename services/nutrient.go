@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"go-nutritioncalculator2/errs"
+	"sort"
+)
+
+// nutrientUnits is the catalog of the extended nutrients that a "Menu" can carry besides the macro nutrients
+// with the unit that their amount is measured in, adding a key here is enough to support a new nutrient
+var nutrientUnits = map[string]string{
+	"fiber":         "g",
+	"sugar":         "g",
+	"saturated_fat": "g",
+	"cholesterol":   "mg",
+	"sodium":        "mg",
+	"potassium":     "mg",
+	"calcium":       "mg",
+	"iron":          "mg",
+	"vitamin_a":     "mcg",
+	"vitamin_c":     "mg",
+	"vitamin_d":     "mcg",
+}
+
+// toNutrients keeps the known amounts of the requested nutrients, a null amount means the amount is unknown
+func toNutrients(nutrients map[string]*float64) (map[string]float64, error) {
+	keys := []string{}
+	for key := range nutrients {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := map[string]float64{}
+	for _, key := range keys {
+		if _, ok := nutrientUnits[key]; !ok {
+			return nil, errs.NewValidationError("nutrients", fmt.Sprintf("Nutrient %q is not supported", key))
+		}
+		if nutrients[key] == nil {
+			continue
+		}
+		if *nutrients[key] < 0 {
+			return nil, errs.NewValidationError("nutrients", fmt.Sprintf("Nutrient %q can not be negative", key))
+		}
+		result[key] = *nutrients[key]
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// mergeNutrients applies the requested nutrients on top of the current ones like a JSON merge patch,
+// a requested null amount removes the nutrient and a nutrient that is not requested is kept
+func mergeNutrients(current map[string]float64, nutrients map[string]*float64) map[string]*float64 {
+	merged := map[string]*float64{}
+	for key, amount := range current {
+		amount := amount
+		merged[key] = &amount
+	}
+	for key, amount := range nutrients {
+		merged[key] = amount
+	}
+	return merged
+}
+
+// roundNutrients rounds the total amount of each known nutrient to 1 decimal
+func roundNutrients(nutrients map[string]float64) map[string]float64 {
+	if len(nutrients) == 0 {
+		return nil
+	}
+	result := map[string]float64{}
+	for key, amount := range nutrients {
+		result[key] = round(amount)
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package service
+
+import "math"
+
+// Energy (kcal) of 1 g. of each macro nutrient
+const (
+	KcalPerGramProtein = 4
+	KcalPerGramFat     = 9
+	KcalPerGramCarb    = 4
+	KcalPerGramAlcohol = 7
+)
+
+type MacroSplit struct {
+	Protein float64 `json:"protein" example:"30.5"` // Percentage of energy from protein
+	Fat     float64 `json:"fat" example:"41.2"`     // Percentage of energy from fat
+	Carb    float64 `json:"carb" example:"28.3"`    // Percentage of energy from carb
+	Alcohol float64 `json:"alcohol" example:"0"`    // Percentage of energy from alcohol
+}
+
+// calories is the energy (kcal) of the macro nutrients (g.) rounded to 1 decimal
+func calories(protein float64, fat float64, carb float64, alcohol float64) float64 {
+	return round(protein*KcalPerGramProtein + fat*KcalPerGramFat + carb*KcalPerGramCarb + alcohol*KcalPerGramAlcohol)
+}
+
+// macroSplit is the percentage of energy from each macro nutrient (g.) rounded to 1 decimal, all 0 when there is no energy
+func macroSplit(protein float64, fat float64, carb float64, alcohol float64) MacroSplit {
+	kcal := protein*KcalPerGramProtein + fat*KcalPerGramFat + carb*KcalPerGramCarb + alcohol*KcalPerGramAlcohol
+	if kcal <= 0 {
+		return MacroSplit{}
+	}
+	return MacroSplit{
+		Protein: round(protein * KcalPerGramProtein / kcal * 100),
+		Fat:     round(fat * KcalPerGramFat / kcal * 100),
+		Carb:    round(carb * KcalPerGramCarb / kcal * 100),
+		Alcohol: round(alcohol * KcalPerGramAlcohol / kcal * 100),
+	}
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash is compared against when the "User Id" is not found so that a log in takes as long for an unknown "User Id"
+// as for a known one and the response time does not reveal which "User Id" exist
+const dummyPasswordHash = "$2a$10$/aWw4VBUfgZ1qg5M9/54eOpBCp.XbK8oveT1765UlBdeuNS1F0CfW"
+
+func hashPassword(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
+
+func isHashedPassword(storedPassword string) bool {
+	return strings.HasPrefix(storedPassword, "$2a$") || strings.HasPrefix(storedPassword, "$2b$") || strings.HasPrefix(storedPassword, "$2y$")
+}
+
+// verifyPassword compares the password with the stored one, which can still be plaintext for the accounts
+// that were created before hashing, and reports whether the stored password need to be rehashed
+func verifyPassword(storedPassword string, password string) (isMatch bool, needRehash bool) {
+	if isHashedPassword(storedPassword) {
+		return bcrypt.CompareHashAndPassword([]byte(storedPassword), []byte(password)) == nil, false
+	}
+	isMatch = subtle.ConstantTimeCompare([]byte(storedPassword), []byte(password)) == 1
+	return isMatch, isMatch
+}
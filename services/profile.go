@@ -0,0 +1,96 @@
+package service
+
+import (
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	"math"
+	"time"
+)
+
+// Factor that multiplies BMR into TDEE of each activity level
+var activityFactors = map[string]float64{
+	"sedentary":   1.2,
+	"light":       1.375,
+	"moderate":    1.55,
+	"active":      1.725,
+	"very_active": 1.9,
+}
+
+// Energy of each goal as a share of TDEE and its protein (g.) per body weight (kg.)
+var goals = map[string]struct {
+	energy       float64
+	proteinPerKg float64
+}{
+	"cut":      {energy: 0.8, proteinPerKg: 2.2},
+	"maintain": {energy: 1, proteinPerKg: 1.8},
+	"bulk":     {energy: 1.1, proteinPerKg: 2},
+}
+
+// Share of the suggested energy that comes from fat, carb fills the rest
+const fatEnergyShare = 0.25
+
+// checkProfile validates the body profile of a "User", an empty value is not set and is always valid
+func checkProfile(sex string, birthDate string, height float64, activityLevel string, goal string) (*time.Time, error) {
+	if sex != "" && sex != "male" && sex != "female" {
+		return nil, errs.NewValidationError("sex", `Sex need to be "male" or "female"`)
+	}
+	if height < 0 {
+		return nil, errs.NewValidationError("height", "Height need to be positive")
+	}
+	if _, ok := activityFactors[activityLevel]; activityLevel != "" && !ok {
+		return nil, errs.NewValidationError("activity_level", `Activity level need to be "sedentary", "light", "moderate", "active" or "very_active"`)
+	}
+	if _, ok := goals[goal]; goal != "" && !ok {
+		return nil, errs.NewValidationError("goal", `Goal need to be "cut", "maintain" or "bulk"`)
+	}
+	if birthDate == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", birthDate)
+	if err != nil {
+		return nil, errs.NewValidationError("birth_date", `Birth date need to be in the format "2023-01-01"`)
+	}
+	if !date.Before(time.Now().UTC()) {
+		return nil, errs.NewValidationError("birth_date", "Birth date need to be in the past")
+	}
+	return &date, nil
+}
+
+// suggestTargets calculates BMR by the Mifflin-St Jeor equation, TDEE of the activity level and the daily targets of the goal
+// for the profile of the "User" on "today"
+func suggestTargets(user repository.User, today time.Time) (*TargetResponse, error) {
+	factor, hasActivityLevel := activityFactors[user.ActivityLevel]
+	goal, hasGoal := goals[user.Goal]
+	if user.Sex == "" || user.BirthDate == nil || user.Height <= 0 || user.Weight <= 0 || !hasActivityLevel || !hasGoal {
+		return nil, errs.NewUnprocessableError(errs.CodeProfileIncomplete, "Sex, birth date, height, weight, activity level and goal need to be set to suggest the targets")
+	}
+	age := today.Year() - user.BirthDate.Year()
+	if today.Month() < user.BirthDate.Month() || (today.Month() == user.BirthDate.Month() && today.Day() < user.BirthDate.Day()) {
+		age--
+	}
+	bmr := 10*user.Weight + 6.25*user.Height - 5*float64(age) - 161
+	if user.Sex == "male" {
+		bmr = 10*user.Weight + 6.25*user.Height - 5*float64(age) + 5
+	}
+	tdee := bmr * factor
+	return &TargetResponse{
+		UserId:        user.UserId,
+		Sex:           user.Sex,
+		Age:           age,
+		Weight:        user.Weight,
+		Height:        user.Height,
+		ActivityLevel: user.ActivityLevel,
+		Goal:          user.Goal,
+		Bmr:           round(bmr),
+		Tdee:          round(tdee),
+		Target:        goalTargets(tdee*goal.energy, user.Weight, user.Goal),
+	}, nil
+}
+
+// goalTargets splits the daily energy (kcal) of the goal into protein by the body weight (kg.), fat by its share of the energy and carb for the rest
+func goalTargets(kcal float64, weight float64, goal string) NutritionTotal {
+	protein := goals[goal].proteinPerKg * weight
+	fat := kcal * fatEnergyShare / KcalPerGramFat
+	carb := math.Max(0, (kcal-protein*KcalPerGramProtein-fat*KcalPerGramFat)/KcalPerGramCarb)
+	return NutritionTotal{Protein: round(protein), Fat: round(fat), Carb: round(carb), Kcal: calories(protein, fat, carb, 0)}
+}
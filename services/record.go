@@ -1,39 +1,64 @@
 package service
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type NewRecordRequest struct {
-	UserId         string  `json:"user_id" example:"gooddy20" binding:"required"`                    // "User Id" that create this "Record"
-	List           string  `json:"list" example:"9,9,10" binding:"required"`                         // Summary meal with "Menu"'s id e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Record" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea
-	Note           string  `json:"note" example:"Breakfast"`                                         // Note for this "Record"
-	Weight         float64 `json:"weight" example:"63"`                                              // Weight (kg.) that you are on that day
-	EventTimestamp string  `json:"event_timestamp" example:"2023-11-01 09:30:00" binding:"required"` // Timestamp that you eat *format="2023-01-01 00:00:00"
+	UserId         string  `json:"user_id" example:"gooddy20" binding:"required"`                              // "User Id" that create this "Record"
+	List           string  `json:"list" example:"9,9,10" binding:"menulist"`                                   // Summary meal with "Menu"'s id e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Record" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea *required when "items" is empty
+	Items          []Item  `json:"items"`                                                                      // Summary meal with "Menu"'s id and quantity, it is used instead of "list" when it is not empty
+	Note           string  `json:"note" example:"Breakfast"`                                                   // Note for this "Record"
+	Weight         float64 `json:"weight" example:"63" binding:"omitempty,min=20,max=500"`                     // Weight (kg.) that you are on that day
+	EventTimestamp string  `json:"event_timestamp" example:"2023-11-01 09:30:00" binding:"required,timestamp"` // Wall-clock timestamp in your time zone that you eat, it is kept as it is and the days of the summary are counted on it *format="2023-01-01 00:00:00"
 }
 
 type UpdateRecordRequest struct {
-	Id             int     `json:"id" example:"1" binding:"required"`             // "Record"'s id that you want to update
-	List           string  `json:"list" example:"9,9,10"`                         // Summary meal with "Menu"'s id that you want to change to e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Record" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea
-	Note           string  `json:"note" example:"Lunch"`                          // Note that you want to change to
-	Weight         float64 `json:"weight" example:"63"`                           // Weight (kg.) that you want to change to
-	EventTimestamp string  `json:"event_timestamp" example:"2023-11-01 12:30:00"` // Timestamp that you want to change to *format="2023-01-01 00:00:00"
+	Id             int               `json:"id" example:"1" binding:"required"`                                                              // "Record"'s id that you want to update
+	List           Nullable[string]  `json:"list" swaggertype:"string" example:"9,9,10" binding:"menulist"`                                  // Summary meal with "Menu"'s id that you want to change to e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Record" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea, it can not be cleared
+	Items          Nullable[[]Item]  `json:"items" swaggertype:"array,object"`                                                               // Summary meal with "Menu"'s id and quantity ("menu_id", "quantity" and "unit" like "Item") that you want to change to, it is used instead of "list" when it is set and it can not be cleared
+	Note           Nullable[string]  `json:"note" swaggertype:"string" example:"Lunch"`                                                      // Note that you want to change to, null = clear
+	Weight         Nullable[float64] `json:"weight" swaggertype:"number" example:"63" binding:"omitempty,min=20,max=500"`                    // Weight (kg.) that you want to change to, null = clear
+	EventTimestamp Nullable[string]  `json:"event_timestamp" swaggertype:"string" example:"2023-11-01 12:30:00" binding:"notnull,timestamp"` // Wall-clock timestamp in your time zone that you want to change to *format="2023-01-01 00:00:00", it can not be null
+	Revision       int               `json:"-"`                                                                                              // Revision from the "If-Match" header, 0 = update whatever the current revision is
 }
 
 type RecordResponse struct {
-	Id             int       `db:"id"`   // "Record"'s id
-	List           string    `db:"list"` // Summary meal with "Menu"'s id e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Record" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea
-	Menues         string    `db:"menues"`
-	Note           string    `db:"note"`            // Note for the "Record"
-	Weight         float64   `db:"weight"`          // Weight (kg.) that you are on that day
-	Protein        float64   `db:"protein"`         // Total protein (g.) of the "Record"
-	Fat            float64   `db:"fat"`             // Total fat (g.) of the "Record"
-	Carb           float64   `db:"carb"`            // Total carb (g.) of the "Record"
-	EventTimestamp time.Time `db:"event_timestamp"` // Timestamp that you eat *format="2023-01-01 00:00:00"
-	IsUpdated      int       `db:"is_updated"`      // 1 = All "Menu" in the "Record" are up to date, 0 = atleast one "Menu" in the "Record" are not up to date
+	Id             int                `db:"id"`    // "Record"'s id
+	List           string             `db:"list"`  // Summary meal with "Menu"'s id e.g. "9,9,10" -> 9 = "Moo Yang" and 10 = "Sticky Rice" so the "Record" contain "Moo Yang" 2 ea and "Sticky Rice" 1 ea
+	Items          []Item             `db:"items"` // Summary meal with "Menu"'s id and quantity
+	Menues         string             `db:"menues"`
+	Note           string             `db:"note"`            // Note for the "Record"
+	Weight         float64            `db:"weight"`          // Weight (kg.) that you are on that day
+	Protein        float64            `db:"protein"`         // Total protein (g.) of the "Record"
+	Fat            float64            `db:"fat"`             // Total fat (g.) of the "Record"
+	Carb           float64            `db:"carb"`            // Total carb (g.) of the "Record"
+	Alcohol        float64            `db:"alcohol"`         // Total alcohol (g.) of the "Record"
+	Kcal           float64            `db:"kcal"`            // Total energy (kcal) of the "Record"
+	MacroSplit     MacroSplit         `db:"macro_split"`     // Percentage of energy from each macro nutrient of the "Record"
+	Nutrients      map[string]float64 `db:"nutrients"`       // Total known extended nutrients of the "Record", a "Menu" without the nutrient does not add to it
+	EventTimestamp time.Time          `db:"event_timestamp"` // Timestamp that you eat *format="2023-01-01 00:00:00"
+	IsUpdated      int                `db:"is_updated"`      // 1 = All "Menu" in the "Record" are up to date, 0 = atleast one "Menu" in the "Record" are not up to date
+	Revision       int                `db:"revision"`        // Revision of the "Record" that is sent as "If-Match" e.g. "3" to update or delete it
+}
+
+type RecordQuery struct {
+	From   string // Only "Record" that is eaten from this timestamp (inclusive) *format="2023-01-01" or "2023-01-01 00:00:00"
+	To     string // Only "Record" that is eaten before this timestamp (exclusive) *format="2023-01-01" or "2023-01-01 00:00:00"
+	Limit  int    // Maximum amount of "Record" in the page, 0 = 50
+	Cursor string // "next_cursor" of the previous page
+	Sort   string // "desc" (default) = newest first, "asc" = oldest first
+}
+
+type RecordPageResponse struct {
+	Records    []RecordResponse `json:"records"`                                   // "Record" in the page
+	NextCursor string           `json:"next_cursor" example:"ZGVzYzoxNzAxNzcy..."` // Cursor of the next page, empty when it is the last page
 }
 
 type RecordService interface {
-	GetAllRecordsByUserId(string) ([]RecordResponse, error)
-	CreateRecord(NewRecordRequest) error
-	DeleteRecord(int) error
-	UpdateRecord(UpdateRecordRequest) error
+	GetAllRecordsByUserId(context.Context, string, RecordQuery) (*RecordPageResponse, error)
+	CreateRecord(context.Context, NewRecordRequest) (*CreatedResponse, error)
+	DeleteRecord(context.Context, string, int, int) error
+	UpdateRecord(context.Context, string, UpdateRecordRequest) error
 }
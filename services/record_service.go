@@ -1,122 +1,271 @@
 package service
 
 import (
-	"database/sql"
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"go-nutritioncalculator2/errs"
-	"go-nutritioncalculator2/logs"
 	repository "go-nutritioncalculator2/repositories"
-	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type recordService struct {
 	recordRepo repository.RecordRepository
+	menuRepo   repository.MenuRepository
 }
 
-func NewRecordService(recordRepo repository.RecordRepository) recordService {
-	return recordService{recordRepo: recordRepo}
+func NewRecordService(recordRepo repository.RecordRepository, menuRepo repository.MenuRepository) recordService {
+	return recordService{recordRepo: recordRepo, menuRepo: menuRepo}
 }
 
-func (s recordService) GetAllRecordsByUserId(userId string) ([]RecordResponse, error) {
-	records, err := s.recordRepo.GetRecordsByUserId(userId)
+// Default and maximum amount of "Record" in a page
+const (
+	defaultRecordLimit = 50
+	maxRecordLimit     = 200
+)
+
+func (s recordService) GetAllRecordsByUserId(ctx context.Context, userId string, query RecordQuery) (*RecordPageResponse, error) {
+	filter, err := toRecordFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	filter.Limit++
+	records, err := s.recordRepo.GetRecordsByUserId(ctx, userId, filter)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return []RecordResponse{}, nil
+		if errors.Is(err, repository.ErrNotFound) {
+			return &RecordPageResponse{Records: []RecordResponse{}}, nil
 		}
-		logs.Error(err)
-		return nil, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
+	}
+	page := RecordPageResponse{Records: []RecordResponse{}}
+	if len(records) > limit {
+		records = records[:limit]
+		last := records[limit-1]
+		page.NextCursor = encodeRecordCursor(filter.Descending, repository.RecordCursor{EventTimestamp: last.EventTimestamp, Id: last.Id})
 	}
 	recordsRes := []RecordResponse{}
 	for i := 0; i < len(records); i++ {
-		record := RecordResponse{
-			Id:             records[i].Id,
-			List:           records[i].List,
-			Note:           records[i].Note,
-			Menues:         records[i].Menues,
-			Weight:         records[i].Weight,
-			Protein:        records[i].Protein,
-			Fat:            records[i].Fat,
-			Carb:           records[i].Carb,
-			EventTimestamp: records[i].EventTimestamp,
-			IsUpdated:      records[i].IsUpdated,
-		}
-		recordsRes = append(recordsRes, record)
+		recordsRes = append(recordsRes, toRecordResponse(records[i]))
 	}
-	return recordsRes, nil
+	page.Records = recordsRes
+	return &page, nil
 }
 
-func (s recordService) CreateRecord(newRecordReq NewRecordRequest) error {
-	tempEventTimestamp, err := time.Parse("2006-01-02 15:04:05", newRecordReq.EventTimestamp)
+func (s recordService) CreateRecord(ctx context.Context, newRecordReq NewRecordRequest) (*CreatedResponse, error) {
+	v := validateRequest(newRecordReq)
+	var items []repository.Item
+	var err error
+	if newRecordReq.List == "" && len(newRecordReq.Items) == 0 {
+		v.add("list", "List or Items is required")
+	} else if !v.has("list", "items") {
+		items, err = toRepositoryItems(newRecordReq.List, newRecordReq.Items)
+		if err = v.check(err); err != nil {
+			return nil, err
+		}
+	}
+	if err = v.err(); err != nil {
+		return nil, err
+	}
+	err = checkItemUnits(ctx, s.menuRepo, items, nil)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, err
 	}
+	tempEventTimestamp, _ := time.Parse(timestampLayout, newRecordReq.EventTimestamp)
 	newRecord := repository.Record{
 		UserId:           newRecordReq.UserId,
-		List:             newRecordReq.List,
+		Items:            items,
 		Note:             newRecordReq.Note,
 		Weight:           newRecordReq.Weight,
 		EventTimestamp:   tempEventTimestamp,
 		Status:           1,
 		CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 	}
-	_, err = s.recordRepo.CreateRecord(newRecord)
+	created, err := s.recordRepo.CreateRecord(ctx, newRecord)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
 	}
-	return nil
+	return &CreatedResponse{Id: created.Id, Revision: created.Revision}, nil
 }
 
-func (s recordService) DeleteRecord(recordId int) error {
-	record, err := s.recordRepo.GetRecordById(recordId)
+func (s recordService) DeleteRecord(ctx context.Context, userId string, recordId int, revision int) error {
+	record, err := s.recordRepo.GetRecordById(ctx, recordId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return errs.AppError{Code: http.StatusNotAcceptable, Message: fmt.Sprint("Record Id - ", recordId, " is not found")}
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeRecordNotFound, fmt.Sprint("Record Id - ", recordId, " is not found"))
 		}
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return repositoryError(err)
+	}
+	if record.UserId != userId {
+		return errs.NewPermissionDeniedError()
+	}
+	err = checkRevision("Record", revision, record.Revision)
+	if err != nil {
+		return err
 	}
 	record.Status = 0
-	err = s.recordRepo.UpdateRecord(*record)
+	err = s.recordRepo.UpdateRecord(ctx, *record)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrStale) {
+			return newStaleError("Record")
+		}
+		return repositoryError(err)
 	}
 	return nil
 }
 
-func (s recordService) UpdateRecord(updateRecordReq UpdateRecordRequest) error {
-	record, err := s.recordRepo.GetRecordById(updateRecordReq.Id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return errs.AppError{Code: http.StatusNotAcceptable, Message: fmt.Sprint("Record Id - ", updateRecordReq.Id, " is not found")}
+func (s recordService) UpdateRecord(ctx context.Context, userId string, updateRecordReq UpdateRecordRequest) error {
+	v := validateRequest(updateRecordReq)
+	var items []repository.Item
+	var isItemsSet bool
+	var err error
+	if !v.has("list", "items") {
+		items, isItemsSet, err = patchItems(updateRecordReq.List, updateRecordReq.Items)
+		if err = v.check(err); err != nil {
+			return err
 		}
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
 	}
-	if updateRecordReq.List != "" {
-		record.List = updateRecordReq.List
+	if err = v.err(); err != nil {
+		return err
 	}
-	if updateRecordReq.Note != "" {
-		record.Note = updateRecordReq.Note
+	record, err := s.recordRepo.GetRecordById(ctx, updateRecordReq.Id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeRecordNotFound, fmt.Sprint("Record Id - ", updateRecordReq.Id, " is not found"))
+		}
+		return repositoryError(err)
 	}
-	if updateRecordReq.Weight != 0 {
-		record.Weight = updateRecordReq.Weight
+	if record.UserId != userId {
+		return errs.NewPermissionDeniedError()
 	}
-	if updateRecordReq.EventTimestamp != "" {
-		tempEventTimestamp, err := time.Parse("2006-01-02 15:04:05", updateRecordReq.EventTimestamp)
+	err = checkRevision("Record", updateRecordReq.Revision, record.Revision)
+	if err != nil {
+		return err
+	}
+	if isItemsSet {
+		err = checkItemUnits(ctx, s.menuRepo, items, record.Items)
 		if err != nil {
-			logs.Error(err)
-			return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+			return err
 		}
-		record.EventTimestamp = tempEventTimestamp
+		record.Items = items
+	}
+	record.Note = updateRecordReq.Note.Apply(record.Note)
+	record.Weight = updateRecordReq.Weight.Apply(record.Weight)
+	if updateRecordReq.EventTimestamp.Set {
+		record.EventTimestamp, _ = time.Parse(timestampLayout, updateRecordReq.EventTimestamp.Value)
 	}
-	err = s.recordRepo.UpdateRecord(*record)
+	err = s.recordRepo.UpdateRecord(ctx, *record)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrStale) {
+			return newStaleError("Record")
+		}
+		return repositoryError(err)
 	}
 	return nil
 }
+
+func toRecordResponse(record repository.Record) RecordResponse {
+	return RecordResponse{
+		Id:             record.Id,
+		List:           toList(record.Items),
+		Items:          toItems(record.Items),
+		Note:           record.Note,
+		Menues:         record.Menues,
+		Weight:         record.Weight,
+		Protein:        record.Protein,
+		Fat:            record.Fat,
+		Carb:           record.Carb,
+		Alcohol:        record.Alcohol,
+		Kcal:           calories(record.Protein, record.Fat, record.Carb, record.Alcohol),
+		MacroSplit:     macroSplit(record.Protein, record.Fat, record.Carb, record.Alcohol),
+		Nutrients:      roundNutrients(record.Nutrients),
+		EventTimestamp: record.EventTimestamp,
+		IsUpdated:      record.IsUpdated,
+		Revision:       record.Revision,
+	}
+}
+
+func toRecordFilter(query RecordQuery) (repository.RecordFilter, error) {
+	filter := repository.RecordFilter{Descending: true, Limit: defaultRecordLimit}
+	switch query.Sort {
+	case "", "desc":
+	case "asc":
+		filter.Descending = false
+	default:
+		return filter, errs.NewValidationError("sort", `Sort need to be "asc" or "desc"`)
+	}
+	if query.Limit != 0 {
+		if query.Limit < 0 || query.Limit > maxRecordLimit {
+			return filter, errs.NewValidationError("limit", fmt.Sprintf("Limit need to be between 1 and %d", maxRecordLimit))
+		}
+		filter.Limit = query.Limit
+	}
+	for _, bound := range []struct {
+		field  string
+		value  string
+		target **time.Time
+	}{{"from", query.From, &filter.From}, {"to", query.To, &filter.To}} {
+		if bound.value == "" {
+			continue
+		}
+		timestamp, err := parseRecordTimestamp(bound.field, bound.value)
+		if err != nil {
+			return filter, err
+		}
+		*bound.target = &timestamp
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errs.NewValidationError("from", "From need to be before To")
+	}
+	if query.Cursor != "" {
+		descending, cursor, err := decodeRecordCursor(query.Cursor)
+		if err != nil || descending != filter.Descending {
+			return filter, errs.NewValidationError("cursor", "Cursor is invalid for the sort order")
+		}
+		filter.After = &cursor
+	}
+	return filter, nil
+}
+
+// parseRecordTimestamp reads a bound of a time range, the error is reported on the field of the bound
+func parseRecordTimestamp(field string, value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+		timestamp, err := time.Parse(layout, value)
+		if err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, errs.NewValidationError(field, `From and To need to be in the format "2023-01-01" or "2023-01-01 00:00:00"`)
+}
+
+// encodeRecordCursor makes an opaque cursor of the record position and the sort order that it belongs to
+func encodeRecordCursor(descending bool, cursor repository.RecordCursor) string {
+	order := "asc"
+	if descending {
+		order = "desc"
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d:%d", order, cursor.EventTimestamp.UnixNano(), cursor.Id)))
+}
+
+func decodeRecordCursor(value string) (bool, repository.RecordCursor, error) {
+	content, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return false, repository.RecordCursor{}, err
+	}
+	parts := strings.Split(string(content), ":")
+	if len(parts) != 3 || (parts[0] != "asc" && parts[0] != "desc") {
+		return false, repository.RecordCursor{}, errors.New("unexpected cursor")
+	}
+	nanoseconds, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return false, repository.RecordCursor{}, err
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return false, repository.RecordCursor{}, err
+	}
+	return parts[0] == "desc", repository.RecordCursor{EventTimestamp: time.Unix(0, nanoseconds).UTC(), Id: id}, nil
+}
@@ -1,6 +1,9 @@
 package service
 
-import "github.com/stretchr/testify/mock"
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
 
 type recordServiceMock struct {
 	mock.Mock
@@ -10,22 +13,22 @@ func NewRecordServiceMock() *recordServiceMock {
 	return &recordServiceMock{}
 }
 
-func (s *recordServiceMock) GetAllRecordsByUserId(userId string) ([]RecordResponse, error) {
-	args := s.Called(userId)
-	return args.Get(0).([]RecordResponse), args.Error(1)
+func (s *recordServiceMock) GetAllRecordsByUserId(ctx context.Context, userId string, query RecordQuery) (*RecordPageResponse, error) {
+	args := s.Called(userId, query)
+	return args.Get(0).(*RecordPageResponse), args.Error(1)
 }
 
-func (s *recordServiceMock) CreateRecord(newRecordReq NewRecordRequest) error {
+func (s *recordServiceMock) CreateRecord(ctx context.Context, newRecordReq NewRecordRequest) (*CreatedResponse, error) {
 	args := s.Called(newRecordReq)
-	return args.Error(0)
+	return args.Get(0).(*CreatedResponse), args.Error(1)
 }
 
-func (s *recordServiceMock) DeleteRecord(recordId int) error {
-	args := s.Called(recordId)
+func (s *recordServiceMock) DeleteRecord(ctx context.Context, userId string, recordId int, revision int) error {
+	args := s.Called(userId, recordId, revision)
 	return args.Error(0)
 }
 
-func (s *recordServiceMock) UpdateRecord(updateRecordReq UpdateRecordRequest) error {
-	args := s.Called(updateRecordReq)
+func (s *recordServiceMock) UpdateRecord(ctx context.Context, userId string, updateRecordReq UpdateRecordRequest) error {
+	args := s.Called(userId, updateRecordReq)
 	return args.Error(0)
 }
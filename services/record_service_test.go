@@ -1,31 +1,43 @@
 package service_test
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"go-nutritioncalculator2/errs"
 	repository "go-nutritioncalculator2/repositories"
 	service "go-nutritioncalculator2/services"
-	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+// servingMenues returns the "Menu"s of the ids that are measured in servings
+func servingMenues(menuIds ...int) []repository.Menu {
+	menues := []repository.Menu{}
+	for _, menuId := range menuIds {
+		menues = append(menues, repository.Menu{Id: menuId, ServingSize: 1, ServingUnit: "serving", Status: 1})
+	}
+	return menues
+}
+
 func TestGetAllRecordsByUserId(t *testing.T) {
 	t.Run("Success Case 1", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
-		repo.On("GetRecordsByUserId", "gooddy20").Return([]repository.Record{
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordsByUserId", "gooddy20", repository.RecordFilter{Descending: true, Limit: 51}).Return([]repository.Record{
 			{Id: 1,
 				UserId:           "gooddy20",
-				List:             "9,9,10",
+				Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 				Menues:           "Moo Yang-2, Sticky Rice-1 ",
 				Note:             "Breakfast",
 				Weight:           70,
 				Protein:          40,
 				Fat:              10,
 				Carb:             20,
+				Nutrients:        map[string]float64{"fiber": 3.04, "sodium": 912.25},
 				EventTimestamp:   time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
 				Status:           1,
 				IsUpdated:        1,
@@ -33,7 +45,7 @@ func TestGetAllRecordsByUserId(t *testing.T) {
 			},
 			{Id: 2,
 				UserId:           "gooddy20",
-				List:             "14",
+				Items:            []repository.Item{{MenuId: 14, Quantity: 1, Unit: "serving"}},
 				Menues:           "Momo Buffet-1",
 				Note:             "My BD",
 				Weight:           71,
@@ -46,73 +58,135 @@ func TestGetAllRecordsByUserId(t *testing.T) {
 				CreatedTimestamp: time.Date(2023, 12, 5, 19, 0, 2, 0, time.UTC).UTC(),
 			},
 		}, nil)
-		srv := service.NewRecordService(repo)
-		result, _ := srv.GetAllRecordsByUserId("gooddy20")
-		expected := []service.RecordResponse{
+		srv := service.NewRecordService(repo, menuRepo)
+		result, _ := srv.GetAllRecordsByUserId(context.Background(), "gooddy20", service.RecordQuery{})
+		expected := &service.RecordPageResponse{Records: []service.RecordResponse{
 			{Id: 1,
 				List:           "9,9,10",
+				Items:          []service.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 				Menues:         "Moo Yang-2, Sticky Rice-1 ",
 				Note:           "Breakfast",
 				Weight:         70,
 				Protein:        40,
 				Fat:            10,
 				Carb:           20,
+				Kcal:           330,
+				MacroSplit:     service.MacroSplit{Protein: 48.5, Fat: 27.3, Carb: 24.2, Alcohol: 0},
+				Nutrients:      map[string]float64{"fiber": 3, "sodium": 912.3},
 				EventTimestamp: time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
 				IsUpdated:      1,
 			},
 			{Id: 2,
 				List:           "14",
+				Items:          []service.Item{{MenuId: 14, Quantity: 1, Unit: "serving"}},
 				Menues:         "Momo Buffet-1",
 				Note:           "My BD",
 				Weight:         71,
 				Protein:        50,
 				Fat:            35,
 				Carb:           40,
+				Kcal:           675,
+				MacroSplit:     service.MacroSplit{Protein: 29.6, Fat: 46.7, Carb: 23.7, Alcohol: 0},
 				EventTimestamp: time.Date(2023, 12, 5, 12, 30, 56, 0, time.UTC).UTC(),
 				IsUpdated:      1,
 			},
-		}
+		}}
 		assert.Equal(t, expected, result)
 	})
 	t.Run("Success Case 2", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
-		repo.On("GetRecordsByUserId", "gooddy20").Return([]repository.Record{}, sql.ErrNoRows)
-		srv := service.NewRecordService(repo)
-		result, _ := srv.GetAllRecordsByUserId("gooddy20")
-		expected := []service.RecordResponse{}
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordsByUserId", "gooddy20", repository.RecordFilter{Descending: true, Limit: 51}).Return([]repository.Record{}, repository.ErrNotFound)
+		srv := service.NewRecordService(repo, menuRepo)
+		result, _ := srv.GetAllRecordsByUserId(context.Background(), "gooddy20", service.RecordQuery{})
+		expected := &service.RecordPageResponse{Records: []service.RecordResponse{}}
 		assert.Equal(t, expected, result)
 	})
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
-		repo.On("GetRecordsByUserId", "gooddy20").Return([]repository.Record{}, sql.ErrConnDone)
-		srv := service.NewRecordService(repo)
-		_, err := srv.GetAllRecordsByUserId("gooddy20")
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordsByUserId", "gooddy20", repository.RecordFilter{Descending: true, Limit: 51}).Return([]repository.Record{}, sql.ErrConnDone)
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.GetAllRecordsByUserId(context.Background(), "gooddy20", service.RecordQuery{})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("Success Case: Next Page", func(t *testing.T) {
+		from := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2023, 12, 5, 12, 0, 0, 0, time.UTC)
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordsByUserId", "gooddy20", repository.RecordFilter{From: &from, To: &to, Limit: 3}).Return([]repository.Record{
+			{Id: 4, EventTimestamp: time.Date(2023, 12, 2, 8, 0, 0, 0, time.UTC)},
+			{Id: 7, EventTimestamp: time.Date(2023, 12, 3, 8, 0, 0, 0, time.UTC)},
+			{Id: 8, EventTimestamp: time.Date(2023, 12, 4, 8, 0, 0, 0, time.UTC)},
+		}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		result, err := srv.GetAllRecordsByUserId(context.Background(), "gooddy20", service.RecordQuery{From: "2023-12-01", To: "2023-12-05 12:00:00", Limit: 2, Sort: "asc"})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, []int{4, 7}, []int{result.Records[0].Id, result.Records[1].Id})
+		assert.NotEmpty(t, result.NextCursor)
+
+		after := repository.RecordCursor{EventTimestamp: time.Date(2023, 12, 3, 8, 0, 0, 0, time.UTC), Id: 7}
+		repo.On("GetRecordsByUserId", "gooddy20", repository.RecordFilter{From: &from, To: &to, After: &after, Limit: 3}).Return([]repository.Record{
+			{Id: 8, EventTimestamp: time.Date(2023, 12, 4, 8, 0, 0, 0, time.UTC)},
+		}, nil)
+		result, err = srv.GetAllRecordsByUserId(context.Background(), "gooddy20", service.RecordQuery{From: "2023-12-01", To: "2023-12-05 12:00:00", Limit: 2, Sort: "asc", Cursor: result.NextCursor})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, 1, len(result.Records))
+		assert.Equal(t, "", result.NextCursor)
 	})
+	type invalidQueryCase struct {
+		Name     string
+		Query    service.RecordQuery
+		Expected error
+	}
+	invalidQueryCases := []invalidQueryCase{
+		{Name: "Invalid Sort", Query: service.RecordQuery{Sort: "newest"}, Expected: errs.NewValidationError("sort", `Sort need to be "asc" or "desc"`)},
+		{Name: "Invalid Limit", Query: service.RecordQuery{Limit: 201}, Expected: errs.NewValidationError("limit", "Limit need to be between 1 and 200")},
+		{Name: "Invalid From", Query: service.RecordQuery{From: "01/12/2023"}, Expected: errs.NewValidationError("from", `From and To need to be in the format "2023-01-01" or "2023-01-01 00:00:00"`)},
+		{Name: "Invalid To", Query: service.RecordQuery{To: "05/12/2023"}, Expected: errs.NewValidationError("to", `From and To need to be in the format "2023-01-01" or "2023-01-01 00:00:00"`)},
+		{Name: "From After To", Query: service.RecordQuery{From: "2023-12-05", To: "2023-12-01"}, Expected: errs.NewValidationError("from", "From need to be before To")},
+		{Name: "Invalid Cursor", Query: service.RecordQuery{Cursor: "not-a-cursor"}, Expected: errs.NewValidationError("cursor", "Cursor is invalid for the sort order")},
+		{Name: "Cursor Of Another Sort", Query: service.RecordQuery{Sort: "desc", Cursor: "YXNjOjE3MDE1OTA0MDAwMDAwMDAwMDA6Nw"}, Expected: errs.NewValidationError("cursor", "Cursor is invalid for the sort order")},
+	}
+	for _, c := range invalidQueryCases {
+		t.Run(c.Name, func(t *testing.T) {
+			repo := repository.NewRecordRepositoryMock()
+			menuRepo := repository.NewMenuRepositoryMock()
+			srv := service.NewRecordService(repo, menuRepo)
+			_, err := srv.GetAllRecordsByUserId(context.Background(), "gooddy20", c.Query)
+			assert.ErrorIs(t, err, c.Expected)
+			repo.AssertNotCalled(t, "GetRecordsByUserId")
+		})
+	}
 }
 
 func TestCreateRecord(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10, 11}).Return(servingMenues(9, 10, 11), nil)
 		repo.On("CreateRecord", repository.Record{
 			UserId:           "gooddy20",
-			List:             "9,9,10,11",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}, {MenuId: 11, Quantity: 1, Unit: "serving"}},
 			Note:             "Lunch",
 			Weight:           70,
 			EventTimestamp:   time.Date(2023, 12, 5, 12, 30, 56, 0, time.UTC).UTC(),
 			Status:           1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}).Return(&repository.Record{
+			Id:               12,
 			UserId:           "gooddy20",
-			List:             "9,9,10,11",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}, {MenuId: 11, Quantity: 1, Unit: "serving"}},
 			Note:             "Lunch",
 			Weight:           70,
 			EventTimestamp:   time.Date(2023, 12, 5, 12, 30, 56, 0, time.UTC).UTC(),
 			Status:           1,
+			Revision:         1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}, nil)
-		srv := service.NewRecordService(repo)
-		err := srv.CreateRecord(service.NewRecordRequest{
+		srv := service.NewRecordService(repo, menuRepo)
+		result, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
 			UserId:         "gooddy20",
 			List:           "9,9,10,11",
 			Note:           "Lunch",
@@ -120,50 +194,241 @@ func TestCreateRecord(t *testing.T) {
 			EventTimestamp: "2023-12-05 12:30:56",
 		})
 		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, &service.CreatedResponse{Id: 12, Revision: 1}, result)
+	})
+	t.Run("Success Case: Items", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
+		repo.On("CreateRecord", repository.Record{
+			UserId:           "gooddy20",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 3, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Note:             "Lunch",
+			Weight:           70,
+			EventTimestamp:   time.Date(2023, 12, 5, 12, 30, 56, 0, time.UTC).UTC(),
+			Status:           1,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(&repository.Record{}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			List:           "1,1",
+			Items:          []service.Item{{MenuId: 10, Quantity: 1, Unit: "serving"}, {MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 9, Quantity: 1, Unit: "serving"}},
+			Note:           "Lunch",
+			Weight:         70,
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Success Case: Gram Items", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{10, 12}).Return([]repository.Menu{
+			{Id: 10, ServingSize: 1, ServingUnit: "serving", Status: 1},
+			{Id: 12, ServingSize: 100, ServingUnit: "g", Status: 1},
+		}, nil)
+		repo.On("CreateRecord", repository.Record{
+			UserId:           "gooddy20",
+			Items:            []repository.Item{{MenuId: 10, Quantity: 1.5, Unit: "serving"}, {MenuId: 12, Quantity: 180, Unit: "g"}, {MenuId: 12, Quantity: 1, Unit: "serving"}},
+			Note:             "Lunch",
+			EventTimestamp:   time.Date(2023, 12, 5, 12, 30, 56, 0, time.UTC).UTC(),
+			Status:           1,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}).Return(&repository.Record{}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			Items:          []service.Item{{MenuId: 12, Quantity: 180, Unit: "g"}, {MenuId: 10, Quantity: 1.5}, {MenuId: 12, Quantity: 1, Unit: "serving"}},
+			Note:           "Lunch",
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Item Unit Is Not The Menu's Serving Unit", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{12}).Return([]repository.Menu{{Id: 12, ServingSize: 100, ServingUnit: "g", Status: 1}}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			Items:          []service.Item{{MenuId: 12, Quantity: 200, Unit: "ml"}},
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, errs.NewValidationError("items", "Menu Id - 12 can not be measured in ml"))
+		repo.AssertNotCalled(t, "CreateRecord")
+	})
+	t.Run("Invalid Item Unit", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			Items:          []service.Item{{MenuId: 12, Quantity: 2, Unit: "cup"}},
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, errs.NewValidationError("items[0].unit", `Items unit need to be "serving", "g" or "ml"`))
+		menuRepo.AssertNotCalled(t, "GetMenusByIds")
+	})
+	t.Run("No The Menu Id", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 99}).Return(servingMenues(9), nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			List:           "9,99",
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, errs.NewValidationError("items", "Menu Id - 99 is not found"))
+		repo.AssertNotCalled(t, "CreateRecord")
+	})
+	t.Run("Get Menues Database Error", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9}).Return([]repository.Menu{}, sql.ErrConnDone)
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			List:           "9",
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+		repo.AssertNotCalled(t, "CreateRecord")
+	})
+	t.Run("Deleted Menu", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10, 99}).Return([]repository.Menu{{Id: 9, ServingUnit: "serving", Status: 1}, {Id: 10, ServingUnit: "serving", Status: 0}}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			List:           "9,10,99",
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, errs.NewValidationErrors([]errs.FieldError{
+			{Field: "items", Message: "Menu Id - 10 is deleted"},
+			{Field: "items", Message: "Menu Id - 99 is not found"},
+		}))
+		repo.AssertNotCalled(t, "CreateRecord")
+	})
+	t.Run("Several Invalid Fields", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			Items:          []service.Item{{MenuId: 0, Quantity: 1}, {MenuId: 9, Quantity: -2}},
+			Weight:         5,
+			EventTimestamp: "05/12/2023",
+		})
+		assert.ErrorIs(t, err, errs.NewValidationErrors([]errs.FieldError{
+			{Field: "items[0].menu_id", Message: "Menu Id need to be positive"},
+			{Field: "items[1].quantity", Message: "Quantity need to be positive"},
+			{Field: "weight", Message: "Weight need to be between 20 and 500"},
+			{Field: "event_timestamp", Message: `Event timestamp need to be in the format "2023-01-01 00:00:00"`},
+		}))
+		menuRepo.AssertNotCalled(t, "GetMenusByIds")
+		repo.AssertNotCalled(t, "CreateRecord")
+	})
+	t.Run("Missing List And Items", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			Note:           "Lunch",
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, errs.NewValidationError("list", "List or Items is required"))
+		repo.AssertNotCalled(t, "CreateRecord")
+	})
+	t.Run("Invalid List", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			List:           "9,9,10,11.5",
+			Note:           "Lunch",
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, errs.NewValidationError("list", `List need to be "Menu"'s id separated by comma e.g. "9,9,10"`))
+		repo.AssertNotCalled(t, "CreateRecord")
+	})
+	t.Run("Invalid Items", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			Items:          []service.Item{{MenuId: 9, Quantity: 0, Unit: "serving"}},
+			Note:           "Lunch",
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, errs.NewValidationError("items[0].quantity", "Quantity need to be positive"))
+		repo.AssertNotCalled(t, "CreateRecord")
 	})
 	t.Run("Parse Event Timestamp (String to Datetime) Error", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
-		srv := service.NewRecordService(repo)
-		err := srv.CreateRecord(service.NewRecordRequest{
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
 			UserId:         "gooddy20",
 			List:           "9,9,10,11",
 			Note:           "Lunch",
 			Weight:         70,
 			EventTimestamp: "2023-12-05 12:3x;56",
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewValidationError("event_timestamp", `Event timestamp need to be in the format "2023-01-01 00:00:00"`))
+		menuRepo.AssertNotCalled(t, "GetMenusByIds")
 		repo.AssertNotCalled(t, "CreateRecord")
 	})
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10, 11}).Return(servingMenues(9, 10, 11), nil)
 		repo.On("CreateRecord", repository.Record{
 			UserId:           "gooddy20",
-			List:             "9,9,10,11",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}, {MenuId: 11, Quantity: 1, Unit: "serving"}},
 			Note:             "Lunch",
 			Weight:           70,
 			EventTimestamp:   time.Date(2023, 12, 5, 12, 30, 56, 0, time.UTC).UTC(),
 			Status:           1,
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 		}).Return(&repository.Record{}, sql.ErrConnDone)
-		srv := service.NewRecordService(repo)
-		err := srv.CreateRecord(service.NewRecordRequest{
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
 			UserId:         "gooddy20",
 			List:           "9,9,10,11",
 			Note:           "Lunch",
 			Weight:         70,
 			EventTimestamp: "2023-12-05 12:30:56",
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("Constraint Violation", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9}).Return(servingMenues(9), nil)
+		repo.On("CreateRecord", mock.Anything).Return(&repository.Record{}, fmt.Errorf("%w: %w", repository.ErrConstraint, sql.ErrNoRows))
+		srv := service.NewRecordService(repo, menuRepo)
+		_, err := srv.CreateRecord(context.Background(), service.NewRecordRequest{
+			UserId:         "gooddy20",
+			List:           "9",
+			EventTimestamp: "2023-12-05 12:30:56",
+		})
+		assert.ErrorIs(t, err, errs.NewUnprocessableError(errs.CodeConstraintViolation, "Request refers to data that does not exist or is not allowed"))
 	})
 }
 
 func TestDeleteRecord(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetRecordById", 1).Return(&repository.Record{
 			Id:               1,
 			UserId:           "gooddy20",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
 			Note:             "Breakfast",
 			Weight:           70,
@@ -178,7 +443,7 @@ func TestDeleteRecord(t *testing.T) {
 		repo.On("UpdateRecord", repository.Record{
 			Id:               1,
 			UserId:           "gooddy20",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
 			Note:             "Breakfast",
 			Weight:           70,
@@ -190,32 +455,35 @@ func TestDeleteRecord(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewRecordService(repo)
-		err := srv.DeleteRecord(1)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.DeleteRecord(context.Background(), "gooddy20", 1, 0)
 		assert.ErrorIs(t, err, nil)
 	})
 	t.Run("No The Record Id", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
-		repo.On("GetRecordById", 1).Return(&repository.Record{}, sql.ErrNoRows)
-		srv := service.NewRecordService(repo)
-		err := srv.DeleteRecord(1)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: fmt.Sprint("Record Id - ", 1, " is not found")})
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordById", 1).Return(&repository.Record{}, repository.ErrNotFound)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.DeleteRecord(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeRecordNotFound, fmt.Sprint("Record Id - ", 1, " is not found")))
 		repo.AssertNotCalled(t, "UpdateRecord")
 	})
 	t.Run("Get Record Database Error", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetRecordById", 1).Return(&repository.Record{}, sql.ErrConnDone)
-		srv := service.NewRecordService(repo)
-		err := srv.DeleteRecord(1)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.DeleteRecord(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "UpdateRecord")
 	})
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetRecordById", 1).Return(&repository.Record{
 			Id:               1,
 			UserId:           "gooddy20",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
 			Note:             "Breakfast",
 			Weight:           70,
@@ -230,7 +498,7 @@ func TestDeleteRecord(t *testing.T) {
 		repo.On("UpdateRecord", repository.Record{
 			Id:               1,
 			UserId:           "gooddy20",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
 			Note:             "Breakfast",
 			Weight:           70,
@@ -242,19 +510,44 @@ func TestDeleteRecord(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
 		}).Return(sql.ErrConnDone)
-		srv := service.NewRecordService(repo)
-		err := srv.DeleteRecord(1)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.DeleteRecord(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordById", 1).Return(&repository.Record{
+			Id:               1,
+			UserId:           "kornkoko",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Menues:           "Moo Yang-2, Sticky Rice-1 ",
+			Note:             "Breakfast",
+			Weight:           70,
+			Protein:          40,
+			Fat:              10,
+			Carb:             20,
+			EventTimestamp:   time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
+			Status:           1,
+			IsUpdated:        1,
+			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
+		}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.DeleteRecord(context.Background(), "gooddy20", 1, 0)
+		assert.ErrorIs(t, err, errs.NewPermissionDeniedError())
+		repo.AssertNotCalled(t, "UpdateRecord")
 	})
 }
 
 func TestUpdateRecord(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
 		repo.On("GetRecordById", 1).Return(&repository.Record{
 			Id:               1,
 			UserId:           "gooddy20",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
 			Note:             "Breakfast",
 			Weight:           70,
@@ -269,7 +562,7 @@ func TestUpdateRecord(t *testing.T) {
 		repo.On("UpdateRecord", repository.Record{
 			Id:               1,
 			UserId:           "gooddy20",
-			List:             "9,9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 3, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
 			Note:             "Extra Lunch",
 			Weight:           74,
@@ -281,50 +574,147 @@ func TestUpdateRecord(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewRecordService(repo)
-		err := srv.UpdateRecord(service.UpdateRecordRequest{
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
+			Id:             1,
+			List:           service.NewNullable("9,9,9,10"),
+			Note:           service.NewNullable("Extra Lunch"),
+			Weight:         service.NewNullable[float64](74),
+			EventTimestamp: service.NewNullable("2023-12-05 12:00:00"),
+		})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Success Case: Keep Deleted Menu", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return([]repository.Menu{{Id: 9, ServingUnit: "serving", Status: 0}, {Id: 10, ServingUnit: "serving", Status: 1}}, nil)
+		repo.On("GetRecordById", 1).Return(&repository.Record{
+			Id:             1,
+			UserId:         "gooddy20",
+			Items:          []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}},
+			EventTimestamp: time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
+			Status:         1,
+		}, nil)
+		repo.On("UpdateRecord", repository.Record{
 			Id:             1,
-			List:           "9,9,9,10",
-			Note:           "Extra Lunch",
-			Weight:         74,
-			EventTimestamp: "2023-12-05 12:00:00",
+			UserId:         "gooddy20",
+			Items:          []repository.Item{{MenuId: 9, Quantity: 1, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			EventTimestamp: time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
+			Status:         1,
+		}).Return(nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
+			Id:   1,
+			List: service.NewNullable("9,10"),
 		})
 		assert.ErrorIs(t, err, nil)
 	})
+	t.Run("Add Deleted Menu", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return([]repository.Menu{{Id: 9, ServingUnit: "serving", Status: 1}, {Id: 10, ServingUnit: "serving", Status: 0}}, nil)
+		repo.On("GetRecordById", 1).Return(&repository.Record{
+			Id:     1,
+			UserId: "gooddy20",
+			Items:  []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}},
+			Status: 1,
+		}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
+			Id:   1,
+			List: service.NewNullable("9,10"),
+		})
+		assert.ErrorIs(t, err, errs.NewValidationError("items", "Menu Id - 10 is deleted"))
+		repo.AssertNotCalled(t, "UpdateRecord")
+	})
+	t.Run("Null Event Timestamp", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
+			Id:             1,
+			EventTimestamp: service.NewNull[string](),
+			Weight:         service.NewNullable[float64](1000),
+		})
+		assert.ErrorIs(t, err, errs.NewValidationErrors([]errs.FieldError{
+			{Field: "weight", Message: "Weight need to be between 20 and 500"},
+			{Field: "event_timestamp", Message: "Event timestamp can not be null"},
+		}))
+		repo.AssertNotCalled(t, "GetRecordById")
+	})
+	t.Run("Success Case: Clear Note and Weight", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordById", 1).Return(&repository.Record{
+			Id:             1,
+			UserId:         "gooddy20",
+			Items:          []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}},
+			Note:           "Breakfast",
+			Weight:         70,
+			EventTimestamp: time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
+		}, nil)
+		repo.On("UpdateRecord", repository.Record{
+			Id:             1,
+			UserId:         "gooddy20",
+			Items:          []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}},
+			EventTimestamp: time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
+		}).Return(nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
+			Id:     1,
+			Note:   service.NewNull[string](),
+			Weight: service.NewNullable[float64](0),
+		})
+		assert.ErrorIs(t, err, nil)
+		menuRepo.AssertNotCalled(t, "GetMenusByIds", mock.Anything)
+	})
+	t.Run("Clear Items", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordById", 1).Return(&repository.Record{Id: 1, UserId: "gooddy20", Items: []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}}}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{Id: 1, Items: service.NewNull[[]service.Item]()})
+		assert.ErrorIs(t, err, errs.NewValidationError("list", "List or Items can not be cleared"))
+		repo.AssertNotCalled(t, "UpdateRecord", mock.Anything)
+	})
 	t.Run("No The Record Id", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
-		repo.On("GetRecordById", 1).Return(&repository.Record{}, sql.ErrNoRows)
-		srv := service.NewRecordService(repo)
-		err := srv.UpdateRecord(service.UpdateRecordRequest{
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordById", 1).Return(&repository.Record{}, repository.ErrNotFound)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
 			Id:             1,
-			List:           "9,9,9,10",
-			Note:           "Extra Lunch",
-			Weight:         74,
-			EventTimestamp: "2023-12-05 12:00:00",
+			List:           service.NewNullable("9,9,9,10"),
+			Note:           service.NewNullable("Extra Lunch"),
+			Weight:         service.NewNullable[float64](74),
+			EventTimestamp: service.NewNullable("2023-12-05 12:00:00"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: fmt.Sprint("Record Id - ", 1, " is not found")})
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeRecordNotFound, fmt.Sprint("Record Id - ", 1, " is not found")))
 		repo.AssertNotCalled(t, "UpdateRecord")
 	})
 	t.Run("Get Record Database Error", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
 		repo.On("GetRecordById", 1).Return(&repository.Record{}, sql.ErrConnDone)
-		srv := service.NewRecordService(repo)
-		err := srv.UpdateRecord(service.UpdateRecordRequest{
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
 			Id:             1,
-			List:           "9,9,9,10",
-			Note:           "Extra Lunch",
-			Weight:         74,
-			EventTimestamp: "2023-12-05 12:00:00",
+			List:           service.NewNullable("9,9,9,10"),
+			Note:           service.NewNullable("Extra Lunch"),
+			Weight:         service.NewNullable[float64](74),
+			EventTimestamp: service.NewNullable("2023-12-05 12:00:00"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "UpdateRecord")
 	})
 	t.Run("Parse (String to Datetime) Error", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
 		repo.On("GetRecordById", 1).Return(&repository.Record{
 			Id:               1,
 			UserId:           "gooddy20",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
 			Note:             "Breakfast",
 			Weight:           70,
@@ -336,23 +726,25 @@ func TestUpdateRecord(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
 		}, nil)
-		srv := service.NewRecordService(repo)
-		err := srv.UpdateRecord(service.UpdateRecordRequest{
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
 			Id:             1,
-			List:           "9,9,9,10",
-			Note:           "Extra Lunch",
-			Weight:         74,
-			EventTimestamp: "2023-12-05 12;0x:0x",
+			List:           service.NewNullable("9,9,9,10"),
+			Note:           service.NewNullable("Extra Lunch"),
+			Weight:         service.NewNullable[float64](74),
+			EventTimestamp: service.NewNullable("2023-12-05 12;0x:0x"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewValidationError("event_timestamp", `Event timestamp need to be in the format "2023-01-01 00:00:00"`))
 		repo.AssertNotCalled(t, "UpdateRecord")
 	})
 	t.Run("Update Record Database Error", func(t *testing.T) {
 		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
 		repo.On("GetRecordById", 1).Return(&repository.Record{
 			Id:               1,
 			UserId:           "gooddy20",
-			List:             "9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
 			Note:             "Breakfast",
 			Weight:           70,
@@ -367,7 +759,7 @@ func TestUpdateRecord(t *testing.T) {
 		repo.On("UpdateRecord", repository.Record{
 			Id:               1,
 			UserId:           "gooddy20",
-			List:             "9,9,9,10",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 3, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
 			Menues:           "Moo Yang-2, Sticky Rice-1 ",
 			Note:             "Extra Lunch",
 			Weight:           74,
@@ -379,14 +771,114 @@ func TestUpdateRecord(t *testing.T) {
 			IsUpdated:        1,
 			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
 		}).Return(sql.ErrConnDone)
-		srv := service.NewRecordService(repo)
-		err := srv.UpdateRecord(service.UpdateRecordRequest{
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
 			Id:             1,
-			List:           "9,9,9,10",
-			Note:           "Extra Lunch",
-			Weight:         74,
-			EventTimestamp: "2023-12-05 12:00:00",
+			List:           service.NewNullable("9,9,9,10"),
+			Note:           service.NewNullable("Extra Lunch"),
+			Weight:         service.NewNullable[float64](74),
+			EventTimestamp: service.NewNullable("2023-12-05 12:00:00"),
+		})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordById", 1).Return(&repository.Record{
+			Id:               1,
+			UserId:           "kornkoko",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Menues:           "Moo Yang-2, Sticky Rice-1 ",
+			Note:             "Breakfast",
+			Weight:           70,
+			Protein:          40,
+			Fat:              10,
+			Carb:             20,
+			EventTimestamp:   time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
+			Status:           1,
+			IsUpdated:        1,
+			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
+		}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
+			Id:             1,
+			List:           service.NewNullable("9,9,9,10"),
+			Note:           service.NewNullable("Extra Lunch"),
+			Weight:         service.NewNullable[float64](74),
+			EventTimestamp: service.NewNullable("2023-12-05 12:00:00"),
+		})
+		assert.ErrorIs(t, err, errs.NewPermissionDeniedError())
+		repo.AssertNotCalled(t, "UpdateRecord")
+	})
+	t.Run("Stale Revision", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordById", 1).Return(&repository.Record{
+			Id:               1,
+			UserId:           "gooddy20",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Menues:           "Moo Yang-2, Sticky Rice-1 ",
+			Note:             "Breakfast",
+			Weight:           70,
+			Protein:          40,
+			Fat:              10,
+			Carb:             20,
+			EventTimestamp:   time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
+			Status:           1,
+			IsUpdated:        1,
+			Revision:         2,
+			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
+		}, nil)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
+			Id:       1,
+			Note:     service.NewNullable("Extra Lunch"),
+			Revision: 1,
+		})
+		assert.ErrorIs(t, err, errs.NewPreconditionFailedError("Record has been changed by another request, get it again and retry"))
+		repo.AssertNotCalled(t, "UpdateRecord")
+	})
+	t.Run("Changed By Another Request", func(t *testing.T) {
+		repo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		repo.On("GetRecordById", 1).Return(&repository.Record{
+			Id:               1,
+			UserId:           "gooddy20",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Menues:           "Moo Yang-2, Sticky Rice-1 ",
+			Note:             "Breakfast",
+			Weight:           70,
+			Protein:          40,
+			Fat:              10,
+			Carb:             20,
+			EventTimestamp:   time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
+			Status:           1,
+			IsUpdated:        1,
+			Revision:         2,
+			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
+		}, nil)
+		repo.On("UpdateRecord", repository.Record{
+			Id:               1,
+			UserId:           "gooddy20",
+			Items:            []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Menues:           "Moo Yang-2, Sticky Rice-1 ",
+			Note:             "Extra Lunch",
+			Weight:           70,
+			Protein:          40,
+			Fat:              10,
+			Carb:             20,
+			EventTimestamp:   time.Date(2023, 12, 4, 10, 30, 12, 0, time.UTC).UTC(),
+			Status:           1,
+			IsUpdated:        1,
+			Revision:         2,
+			CreatedTimestamp: time.Date(2023, 12, 4, 19, 30, 19, 0, time.UTC).UTC(),
+		}).Return(repository.ErrStale)
+		srv := service.NewRecordService(repo, menuRepo)
+		err := srv.UpdateRecord(context.Background(), "gooddy20", service.UpdateRecordRequest{
+			Id:       1,
+			Note:     service.NewNullable("Extra Lunch"),
+			Revision: 2,
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewPreconditionFailedError("Record has been changed by another request, get it again and retry"))
 	})
 }
@@ -0,0 +1,7 @@
+package service
+
+import "context"
+
+type RecoverService interface {
+	RecoverDeletedMenu(context.Context, string, int, string, bool) error
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+)
+
+type recoverService struct {
+	unitOfWork repository.UnitOfWork
+}
+
+func NewRecoverService(unitOfWork repository.UnitOfWork) recoverService {
+	return recoverService{unitOfWork: unitOfWork}
+}
+
+// RecoverDeletedMenu gets the deleted "Menu" off from the "Favorite Menu" of the "User" and replaces it in every "Favorite List" with a copy of it (isCreate = true)
+// or gets it off from them, all the changes are rolled back when one of the steps fails
+func (s recoverService) RecoverDeletedMenu(ctx context.Context, userId string, deletedMenuId int, newMenuName string, isCreate bool) error {
+	err := s.unitOfWork.Do(ctx, func(repos repository.Repositories) error {
+		menuSrv := NewMenuService(repos.Menu, repository.JoinUnitOfWork(repos))
+		userSrv := NewUserService(repos.User, repos.Menu)
+		favListSrv := NewFavListService(repos.FavList, repos.Menu, repos.Record)
+		var newMenuId int
+		if isCreate {
+			newMenu, err := menuSrv.RecoverMenu(ctx, userId, deletedMenuId, newMenuName)
+			if err != nil {
+				return err
+			}
+			newMenuId = newMenu.Id
+		}
+		err := userSrv.RecoverFavoriteMenues(ctx, userId, deletedMenuId)
+		if err != nil {
+			return err
+		}
+		favLists, err := favListSrv.GetFavListsByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		for _, favList := range favLists {
+			err = favListSrv.RecoverFavList(ctx, favList.Id, deletedMenuId, newMenuId)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(errs.AppError); ok {
+			return err
+		}
+		return repositoryError(err)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type recoverServiceMock struct {
+	mock.Mock
+}
+
+func NewRecoverServiceMock() *recoverServiceMock {
+	return &recoverServiceMock{}
+}
+
+func (s *recoverServiceMock) RecoverDeletedMenu(ctx context.Context, userId string, deletedMenuId int, newMenuName string, isCreate bool) error {
+	args := s.Called(userId, deletedMenuId, newMenuName, isCreate)
+	return args.Error(0)
+}
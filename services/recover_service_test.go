@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	service "go-nutritioncalculator2/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecoverDeletedMenu(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		menuRepo := repository.NewMenuRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		favListRepo := repository.NewFavListRepositoryMock()
+		repos := repository.Repositories{Menu: menuRepo, User: userRepo, FavList: favListRepo, Record: repository.NewRecordRepositoryMock()}
+		menuRepo.On("GetMenuById", 9).Return(&repository.Menu{Id: 9, Name: "Moo Yang", Protein: 20, ServingSize: 1, ServingUnit: "serving", CreatorId: "kornkoko", Status: 0, Version: 1}, nil)
+		menuRepo.On("GetMenuHistory", 9).Return([]repository.Menu{{Id: 9, Version: 1}}, nil)
+		menuRepo.On("CreateMenu", mock.MatchedBy(func(menu repository.Menu) bool {
+			return menu.Name == "Moo Yang V2" && *menu.ParentMenuId == 9 && menu.CreatorId == "gooddy20"
+		})).Return(&repository.Menu{Id: 15, Name: "Moo Yang V2"}, nil)
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", FavoriteMenues: []int{9, 10}}, nil)
+		userRepo.On("UpdateUser", repository.User{UserId: "gooddy20", FavoriteMenues: []int{10}}).Return(nil)
+		favListRepo.On("GetFavListsByUserId", "gooddy20").Return([]repository.FavList{{Id: 1}, {Id: 2}}, nil)
+		favListRepo.On("GetFavListById", 1).Return(&repository.FavList{Id: 1, Items: []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}}}, nil)
+		favListRepo.On("GetFavListById", 2).Return(&repository.FavList{Id: 2, Items: []repository.Item{{MenuId: 10, Quantity: 1, Unit: "serving"}}}, nil)
+		favListRepo.On("UpdateFavList", repository.FavList{Id: 1, Items: []repository.Item{{MenuId: 10, Quantity: 1, Unit: "serving"}, {MenuId: 15, Quantity: 2, Unit: "serving"}}}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repos)
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewRecoverService(unitOfWork)
+		err := srv.RecoverDeletedMenu(context.Background(), "gooddy20", 9, "Moo Yang V2", true)
+		assert.ErrorIs(t, err, nil)
+		unitOfWork.AssertCalled(t, "Do")
+	})
+	t.Run("Success Case: Not Create New Menu", func(t *testing.T) {
+		menuRepo := repository.NewMenuRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		favListRepo := repository.NewFavListRepositoryMock()
+		repos := repository.Repositories{Menu: menuRepo, User: userRepo, FavList: favListRepo, Record: repository.NewRecordRepositoryMock()}
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", FavoriteMenues: []int{9}}, nil)
+		userRepo.On("UpdateUser", repository.User{UserId: "gooddy20", FavoriteMenues: []int{}}).Return(nil)
+		favListRepo.On("GetFavListsByUserId", "gooddy20").Return([]repository.FavList{{Id: 1}}, nil)
+		favListRepo.On("GetFavListById", 1).Return(&repository.FavList{Id: 1, Items: []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}}}, nil)
+		favListRepo.On("UpdateFavList", repository.FavList{Id: 1, Items: []repository.Item{{MenuId: 10, Quantity: 1, Unit: "serving"}}}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repos)
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewRecoverService(unitOfWork)
+		err := srv.RecoverDeletedMenu(context.Background(), "gooddy20", 9, "", false)
+		assert.ErrorIs(t, err, nil)
+		menuRepo.AssertNotCalled(t, "CreateMenu", mock.Anything)
+	})
+	t.Run("Rolled Back When a Step Fails", func(t *testing.T) {
+		menuRepo := repository.NewMenuRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		favListRepo := repository.NewFavListRepositoryMock()
+		repos := repository.Repositories{Menu: menuRepo, User: userRepo, FavList: favListRepo, Record: repository.NewRecordRepositoryMock()}
+		menuRepo.On("GetMenuById", 9).Return(&repository.Menu{Id: 9, Name: "Moo Yang", ServingSize: 1, ServingUnit: "serving", Version: 1}, nil)
+		menuRepo.On("GetMenuHistory", 9).Return([]repository.Menu{{Id: 9, Version: 1}}, nil)
+		menuRepo.On("CreateMenu", mock.Anything).Return(&repository.Menu{Id: 15}, nil)
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", FavoriteMenues: []int{9}}, nil)
+		userRepo.On("UpdateUser", mock.Anything).Return(nil)
+		favListRepo.On("GetFavListsByUserId", "gooddy20").Return([]repository.FavList{}, sql.ErrConnDone)
+		unitOfWork := repository.NewUnitOfWorkMock(repos)
+		srv := service.NewRecoverService(unitOfWork)
+		err := srv.RecoverDeletedMenu(context.Background(), "gooddy20", 9, "Moo Yang V2", true)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+		unitOfWork.AssertNotCalled(t, "Do")
+	})
+	t.Run("Deleted Menu Not Found", func(t *testing.T) {
+		menuRepo := repository.NewMenuRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		favListRepo := repository.NewFavListRepositoryMock()
+		repos := repository.Repositories{Menu: menuRepo, User: userRepo, FavList: favListRepo, Record: repository.NewRecordRepositoryMock()}
+		menuRepo.On("GetMenuById", 9).Return(&repository.Menu{}, repository.ErrNotFound)
+		unitOfWork := repository.NewUnitOfWorkMock(repos)
+		srv := service.NewRecoverService(unitOfWork)
+		err := srv.RecoverDeletedMenu(context.Background(), "gooddy20", 9, "Moo Yang V2", true)
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeMenuNotFound, "Menu Id is not found"))
+		userRepo.AssertNotCalled(t, "GetUserById", mock.Anything)
+	})
+	t.Run("Commit Error", func(t *testing.T) {
+		menuRepo := repository.NewMenuRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		favListRepo := repository.NewFavListRepositoryMock()
+		repos := repository.Repositories{Menu: menuRepo, User: userRepo, FavList: favListRepo, Record: repository.NewRecordRepositoryMock()}
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", FavoriteMenues: []int{}}, nil)
+		favListRepo.On("GetFavListsByUserId", "gooddy20").Return([]repository.FavList{}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repos)
+		unitOfWork.On("Do").Return(sql.ErrTxDone)
+		srv := service.NewRecoverService(unitOfWork)
+		err := srv.RecoverDeletedMenu(context.Background(), "gooddy20", 9, "", false)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
@@ -0,0 +1,31 @@
+package service
+
+import "context"
+
+type DailyTotal struct {
+	Date       string         `json:"date" example:"2023-12-05"`   // Calendar day *format="2023-01-01"
+	RecordIds  []int          `json:"record_ids" example:"1,2"`    // "Record"'s id in the day
+	Consumed   NutritionTotal `json:"consumed"`                    // Total nutrition of every "Record" in the day
+	IsLogged   bool           `json:"is_logged" example:"true"`    // "true" = atleast one "Record" in the day, a day that is not logged is not in the statistics
+	IsAdherent bool           `json:"is_adherent" example:"false"` // "true" = protein, fat and carb of the logged day are within ±10% of the target
+}
+
+type ReportResponse struct {
+	UserId       string         `json:"user_id" example:"gooddy20"`      // "User Id"
+	From         string         `json:"from" example:"2023-12-04"`       // First day of the report *format="2023-01-01"
+	To           string         `json:"to" example:"2023-12-10"`         // Last day of the report *format="2023-01-01"
+	Timezone     string         `json:"timezone" example:"Asia/Bangkok"` // Time zone that the days are counted in
+	Target       NutritionTotal `json:"target"`                          // Default protein, fat and carb of the "User"
+	Days         []DailyTotal   `json:"days"`                            // Total nutrition of each day from "from" to "to"
+	LoggedDays   int            `json:"logged_days" example:"6"`         // Amount of days with atleast one "Record"
+	Average      NutritionTotal `json:"average"`                         // Average total of the logged days
+	Min          NutritionTotal `json:"min"`                             // Minimum total of the logged days
+	Max          NutritionTotal `json:"max"`                             // Maximum total of the logged days
+	StdDev       NutritionTotal `json:"std_dev"`                         // Population standard deviation of the totals of the logged days
+	AdherentDays int            `json:"adherent_days" example:"4"`       // Amount of logged days that are within ±10% of the target
+	Adherence    float64        `json:"adherence" example:"66.7"`        // Percentage of the logged days that are within ±10% of the target
+}
+
+type ReportService interface {
+	GetReport(context.Context, string, string, string) (*ReportResponse, error)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	"math"
+	"time"
+)
+
+// Longest date range of a report, it keeps a report of a year in one request
+const maxReportDays = 366
+
+// A logged day is adherent when each macro nutrient with a target is within this fraction of the target
+const adherenceTolerance = 0.1
+
+type reportService struct {
+	userRepo   repository.UserRepository
+	recordRepo repository.RecordRepository
+}
+
+func NewReportService(userRepo repository.UserRepository, recordRepo repository.RecordRepository) reportService {
+	return reportService{userRepo: userRepo, recordRepo: recordRepo}
+}
+
+// GetReport sums the "Record" of each calendar day of the wall-clock "event_timestamp" from "from" to "to" (inclusive)
+// and describes the logged days with their average, min, max, standard deviation and adherence to the user's target
+func (s reportService) GetReport(ctx context.Context, userId string, from string, to string) (*ReportResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
+		}
+		return nil, repositoryError(err)
+	}
+	timezone, _, err := userLocation(*user)
+	if err != nil {
+		return nil, err
+	}
+	firstDay, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return nil, errs.NewValidationError("from", `From and To need to be in the format "2023-01-01"`)
+	}
+	lastDay, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil, errs.NewValidationError("to", `From and To need to be in the format "2023-01-01"`)
+	}
+	if lastDay.Before(firstDay) {
+		return nil, errs.NewValidationError("from", "From need to be before or equal to To")
+	}
+	if firstDay.AddDate(0, 0, maxReportDays).Before(lastDay.AddDate(0, 0, 1)) {
+		return nil, errs.NewValidationError("to", "Date range can not be longer than 366 days")
+	}
+	rangeFrom, rangeTo := firstDay, lastDay.AddDate(0, 0, 1)
+	records, err := s.recordRepo.GetRecordsByUserId(ctx, userId, repository.RecordFilter{From: &rangeFrom, To: &rangeTo})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	report := ReportResponse{
+		UserId:   userId,
+		From:     firstDay.Format("2006-01-02"),
+		To:       lastDay.Format("2006-01-02"),
+		Timezone: timezone,
+		Target:   nutritionTotal(user.Protein, user.Fat, user.Carb, 0),
+		Days:     []DailyTotal{},
+	}
+	dayIndex := map[string]int{}
+	for day := firstDay; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		dayIndex[day.Format("2006-01-02")] = len(report.Days)
+		report.Days = append(report.Days, DailyTotal{Date: day.Format("2006-01-02"), RecordIds: []int{}})
+	}
+	consumed := make([]NutritionTotal, len(report.Days))
+	for _, record := range records {
+		i, ok := dayIndex[record.EventTimestamp.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		report.Days[i].RecordIds = append(report.Days[i].RecordIds, record.Id)
+		consumed[i].Protein += record.Protein
+		consumed[i].Fat += record.Fat
+		consumed[i].Carb += record.Carb
+		consumed[i].Alcohol += record.Alcohol
+	}
+	loggedDays := []NutritionTotal{}
+	for i := range report.Days {
+		report.Days[i].Consumed = nutritionTotal(consumed[i].Protein, consumed[i].Fat, consumed[i].Carb, consumed[i].Alcohol)
+		if len(report.Days[i].RecordIds) == 0 {
+			continue
+		}
+		report.Days[i].IsLogged = true
+		report.Days[i].IsAdherent = isAdherent(report.Days[i].Consumed, report.Target)
+		if report.Days[i].IsAdherent {
+			report.AdherentDays++
+		}
+		loggedDays = append(loggedDays, report.Days[i].Consumed)
+	}
+	report.LoggedDays = len(loggedDays)
+	if report.LoggedDays != 0 {
+		report.Average, report.Min, report.Max, report.StdDev = describe(loggedDays)
+		report.Adherence = round(float64(report.AdherentDays) / float64(report.LoggedDays) * 100)
+	}
+	return &report, nil
+}
+
+// isAdherent checks that each macro nutrient with a target is within ±10% of the target
+func isAdherent(consumed NutritionTotal, target NutritionTotal) bool {
+	pairs := [][2]float64{{consumed.Protein, target.Protein}, {consumed.Fat, target.Fat}, {consumed.Carb, target.Carb}}
+	for _, pair := range pairs {
+		if pair[1] > 0 && math.Abs(pair[0]-pair[1]) > pair[1]*adherenceTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// describe computes the average, min, max and population standard deviation of each field of the totals rounded to 1 decimal
+func describe(totals []NutritionTotal) (NutritionTotal, NutritionTotal, NutritionTotal, NutritionTotal) {
+	fields := func(total *NutritionTotal) []*float64 {
+		return []*float64{&total.Protein, &total.Fat, &total.Carb, &total.Alcohol, &total.Kcal}
+	}
+	var average, min, max, stdDev NutritionTotal
+	for f := range fields(&average) {
+		values := []float64{}
+		for i := range totals {
+			values = append(values, *fields(&totals[i])[f])
+		}
+		sum, minValue, maxValue := 0.0, values[0], values[0]
+		for _, value := range values {
+			sum += value
+			minValue = math.Min(minValue, value)
+			maxValue = math.Max(maxValue, value)
+		}
+		mean := sum / float64(len(values))
+		variance := 0.0
+		for _, value := range values {
+			variance += (value - mean) * (value - mean)
+		}
+		*fields(&average)[f] = round(mean)
+		*fields(&min)[f] = round(minValue)
+		*fields(&max)[f] = round(maxValue)
+		*fields(&stdDev)[f] = round(math.Sqrt(variance / float64(len(values))))
+	}
+	return average, min, max, stdDev
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type reportServiceMock struct {
+	mock.Mock
+}
+
+func NewReportServiceMock() *reportServiceMock {
+	return &reportServiceMock{}
+}
+
+func (s *reportServiceMock) GetReport(ctx context.Context, userId string, from string, to string) (*ReportResponse, error) {
+	args := s.Called(userId, from, to)
+	return args.Get(0).(*ReportResponse), args.Error(1)
+}
@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	service "go-nutritioncalculator2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReport(t *testing.T) {
+	user := &repository.User{UserId: "gooddy20", Username: "GoodDy", Weight: 70, Protein: 120, Fat: 60, Carb: 150, Timezone: "Asia/Bangkok"}
+	t.Run("Success", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(time.Date(2023, 12, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 7, 0, 0, 0, 0, time.UTC))).Return([]repository.Record{
+			{Id: 1, Protein: 115, Fat: 62, Carb: 140, EventTimestamp: time.Date(2023, 12, 4, 1, 0, 0, 0, time.UTC)},
+			{Id: 3, Protein: 20, Fat: 10, Carb: 20, EventTimestamp: time.Date(2023, 12, 5, 1, 0, 0, 0, time.UTC)},
+			{Id: 2, Protein: 60, Fat: 30, Carb: 100, EventTimestamp: time.Date(2023, 12, 5, 23, 30, 0, 0, time.UTC)},
+		}, nil)
+		srv := service.NewReportService(userRepo, recordRepo)
+		result, err := srv.GetReport(context.Background(), "gooddy20", "2023-12-04", "2023-12-06")
+		expected := &service.ReportResponse{
+			UserId:   "gooddy20",
+			From:     "2023-12-04",
+			To:       "2023-12-06",
+			Timezone: "Asia/Bangkok",
+			Target:   service.NutritionTotal{Protein: 120, Fat: 60, Carb: 150, Kcal: 1620},
+			Days: []service.DailyTotal{
+				{Date: "2023-12-04", RecordIds: []int{1}, Consumed: service.NutritionTotal{Protein: 115, Fat: 62, Carb: 140, Kcal: 1578}, IsLogged: true, IsAdherent: true},
+				{Date: "2023-12-05", RecordIds: []int{3, 2}, Consumed: service.NutritionTotal{Protein: 80, Fat: 40, Carb: 120, Kcal: 1160}, IsLogged: true, IsAdherent: false},
+				{Date: "2023-12-06", RecordIds: []int{}, Consumed: service.NutritionTotal{}, IsLogged: false, IsAdherent: false},
+			},
+			LoggedDays:   2,
+			Average:      service.NutritionTotal{Protein: 97.5, Fat: 51, Carb: 130, Kcal: 1369},
+			Min:          service.NutritionTotal{Protein: 80, Fat: 40, Carb: 120, Kcal: 1160},
+			Max:          service.NutritionTotal{Protein: 115, Fat: 62, Carb: 140, Kcal: 1578},
+			StdDev:       service.NutritionTotal{Protein: 17.5, Fat: 11, Carb: 10, Kcal: 209},
+			AdherentDays: 1,
+			Adherence:    50,
+		}
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Success Case: No Record", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(time.Date(2023, 12, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC))).Return([]repository.Record{}, nil)
+		srv := service.NewReportService(userRepo, recordRepo)
+		result, err := srv.GetReport(context.Background(), "gooddy20", "2023-12-04", "2023-12-04")
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, 0, result.LoggedDays)
+		assert.Equal(t, service.NutritionTotal{}, result.Average)
+		assert.Equal(t, float64(0), result.Adherence)
+		assert.Equal(t, []service.DailyTotal{{Date: "2023-12-04", RecordIds: []int{}}}, result.Days)
+	})
+	type testCase struct {
+		Name     string
+		From     string
+		To       string
+		Expected error
+	}
+	cases := []testCase{
+		{Name: "Invalid From", From: "04/12/2023", To: "2023-12-10", Expected: errs.NewValidationError("from", `From and To need to be in the format "2023-01-01"`)},
+		{Name: "Missing To", From: "2023-12-04", To: "", Expected: errs.NewValidationError("to", `From and To need to be in the format "2023-01-01"`)},
+		{Name: "To Before From", From: "2023-12-10", To: "2023-12-04", Expected: errs.NewValidationError("from", "From need to be before or equal to To")},
+		{Name: "Too Long Range", From: "2023-01-01", To: "2024-01-02", Expected: errs.NewValidationError("to", "Date range can not be longer than 366 days")},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			userRepo := repository.NewUserRepositoryMock()
+			recordRepo := repository.NewRecordRepositoryMock()
+			userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+			srv := service.NewReportService(userRepo, recordRepo)
+			_, err := srv.GetReport(context.Background(), "gooddy20", c.From, c.To)
+			assert.ErrorIs(t, err, c.Expected)
+			recordRepo.AssertNotCalled(t, "GetRecordsByUserId")
+		})
+	}
+	t.Run("No The User Id", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{}, repository.ErrNotFound)
+		srv := service.NewReportService(userRepo, recordRepo)
+		_, err := srv.GetReport(context.Background(), "gooddy20", "2023-12-04", "2023-12-10")
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found"))
+	})
+	t.Run("Get Records Database Error", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(time.Date(2023, 12, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 11, 0, 0, 0, 0, time.UTC))).Return([]repository.Record{}, sql.ErrConnDone)
+		srv := service.NewReportService(userRepo, recordRepo)
+		_, err := srv.GetReport(context.Background(), "gooddy20", "2023-12-04", "2023-12-10")
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
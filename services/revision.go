@@ -0,0 +1,26 @@
+package service
+
+import (
+	"fmt"
+	"go-nutritioncalculator2/errs"
+)
+
+// CreatedResponse is the id and revision of a "Record", "Favorite List" or "Menu" that is just created
+type CreatedResponse struct {
+	Id       int `json:"id" example:"12"`      // Id of the created row
+	Revision int `json:"revision" example:"1"` // Revision of the created row that is sent as "If-Match" e.g. "1" to update or delete it
+}
+
+// checkRevision rejects a change that is based on a revision of the resource that is not the current one anymore,
+// a revision of 0 is a request without "If-Match" that changes whatever the current revision is
+func checkRevision(name string, revision int, currentRevision int) error {
+	if revision != 0 && revision != currentRevision {
+		return newStaleError(name)
+	}
+	return nil
+}
+
+// newStaleError is for a change that loses the race with another change of the same resource
+func newStaleError(name string) errs.AppError {
+	return errs.NewPreconditionFailedError(fmt.Sprint(name, " has been changed by another request, get it again and retry"))
+}
@@ -0,0 +1,31 @@
+package service
+
+import "context"
+
+type NutritionTotal struct {
+	Protein float64 `json:"protein" example:"90"` // Protein (g.)
+	Fat     float64 `json:"fat" example:"45"`     // Fat (g.)
+	Carb    float64 `json:"carb" example:"110"`   // Carb (g.)
+	Alcohol float64 `json:"alcohol" example:"0"`  // Alcohol (g.)
+	Kcal    float64 `json:"kcal" example:"1205"`  // Energy (kcal) = 4 x protein + 9 x fat + 4 x carb + 7 x alcohol
+}
+
+type MealSummary struct {
+	Note      string         `json:"note" example:"Breakfast"` // "Record"'s note that the meals are grouped by
+	RecordIds []int          `json:"record_ids" example:"1"`   // "Record"'s id with the note in the day
+	Consumed  NutritionTotal `json:"consumed"`                 // Total nutrition of the "Record" with the note
+}
+
+type DailySummaryResponse struct {
+	UserId    string         `json:"user_id" example:"gooddy20"`      // "User Id"
+	Date      string         `json:"date" example:"2023-12-05"`       // Calendar day of the summary *format="2023-01-01"
+	Timezone  string         `json:"timezone" example:"Asia/Bangkok"` // Time zone that the day is counted in
+	Consumed  NutritionTotal `json:"consumed"`                        // Total nutrition of every "Record" in the day
+	Target    NutritionTotal `json:"target"`                          // Default protein, fat and carb of the "User"
+	Remaining NutritionTotal `json:"remaining"`                       // Target - consumed, it is negative when the target is exceeded
+	Meals     []MealSummary  `json:"meals"`                           // Consumed nutrition of each note in the order that it is first eaten
+}
+
+type SummaryService interface {
+	GetDailySummary(context.Context, string, string) (*DailySummaryResponse, error)
+}
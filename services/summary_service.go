@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-nutritioncalculator2/errs"
+	"go-nutritioncalculator2/logs"
+	repository "go-nutritioncalculator2/repositories"
+	"time"
+)
+
+type summaryService struct {
+	userRepo   repository.UserRepository
+	recordRepo repository.RecordRepository
+}
+
+func NewSummaryService(userRepo repository.UserRepository, recordRepo repository.RecordRepository) summaryService {
+	return summaryService{userRepo: userRepo, recordRepo: recordRepo}
+}
+
+// GetDailySummary compares the "Record" of the calendar day (today in the user's time zone when the date is empty) with the user's target,
+// the "event_timestamp" is the user's wall-clock time so the day is counted on it as it is
+func (s summaryService) GetDailySummary(ctx context.Context, userId string, date string) (*DailySummaryResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
+		}
+		return nil, repositoryError(err)
+	}
+	timezone, location, err := userLocation(*user)
+	if err != nil {
+		return nil, err
+	}
+	day := localToday(location)
+	if date != "" {
+		day, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, errs.NewValidationError("date", `Date need to be in the format "2023-01-01"`)
+		}
+	}
+	from, to := day, day.AddDate(0, 0, 1)
+	records, err := s.recordRepo.GetRecordsByUserId(ctx, userId, repository.RecordFilter{From: &from, To: &to})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	summary := DailySummaryResponse{
+		UserId:   userId,
+		Date:     day.Format("2006-01-02"),
+		Timezone: timezone,
+		Target:   nutritionTotal(user.Protein, user.Fat, user.Carb, 0),
+		Meals:    []MealSummary{},
+	}
+	consumed := NutritionTotal{}
+	mealConsumed := []NutritionTotal{}
+	mealIndex := map[string]int{}
+	for _, record := range records {
+		i, ok := mealIndex[record.Note]
+		if !ok {
+			i = len(summary.Meals)
+			mealIndex[record.Note] = i
+			summary.Meals = append(summary.Meals, MealSummary{Note: record.Note, RecordIds: []int{}})
+			mealConsumed = append(mealConsumed, NutritionTotal{})
+		}
+		summary.Meals[i].RecordIds = append(summary.Meals[i].RecordIds, record.Id)
+		for _, total := range []*NutritionTotal{&consumed, &mealConsumed[i]} {
+			total.Protein += record.Protein
+			total.Fat += record.Fat
+			total.Carb += record.Carb
+			total.Alcohol += record.Alcohol
+		}
+	}
+	for i, total := range mealConsumed {
+		summary.Meals[i].Consumed = nutritionTotal(total.Protein, total.Fat, total.Carb, total.Alcohol)
+	}
+	summary.Consumed = nutritionTotal(consumed.Protein, consumed.Fat, consumed.Carb, consumed.Alcohol)
+	summary.Remaining = NutritionTotal{
+		Protein: round(summary.Target.Protein - summary.Consumed.Protein),
+		Fat:     round(summary.Target.Fat - summary.Consumed.Fat),
+		Carb:    round(summary.Target.Carb - summary.Consumed.Carb),
+		Alcohol: round(summary.Target.Alcohol - summary.Consumed.Alcohol),
+		Kcal:    round(summary.Target.Kcal - summary.Consumed.Kcal),
+	}
+	return &summary, nil
+}
+
+func nutritionTotal(protein float64, fat float64, carb float64, alcohol float64) NutritionTotal {
+	return NutritionTotal{
+		Protein: round(protein),
+		Fat:     round(fat),
+		Carb:    round(carb),
+		Alcohol: round(alcohol),
+		Kcal:    calories(protein, fat, carb, alcohol),
+	}
+}
+
+// userLocation resolves the time zone that the calendar days of the user are counted in, "UTC" when it is not set
+func userLocation(user repository.User) (string, *time.Location, error) {
+	timezone := user.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := loadTimezone(timezone)
+	if err != nil {
+		logs.Error(err)
+		return "", nil, errs.NewUnexpectedError()
+	}
+	return timezone, location, nil
+}
+
+// localToday is the calendar day of now in the time zone, as a day of the wall-clock time like "event_timestamp" and "logged_timestamp"
+func localToday(location *time.Location) time.Time {
+	now := time.Now().In(location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type summaryServiceMock struct {
+	mock.Mock
+}
+
+func NewSummaryServiceMock() *summaryServiceMock {
+	return &summaryServiceMock{}
+}
+
+func (s *summaryServiceMock) GetDailySummary(ctx context.Context, userId string, date string) (*DailySummaryResponse, error) {
+	args := s.Called(userId, date)
+	return args.Get(0).(*DailySummaryResponse), args.Error(1)
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	service "go-nutritioncalculator2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// between is the record filter of the event timestamps from "from" (inclusive) to "to" (exclusive)
+func between(from time.Time, to time.Time) repository.RecordFilter {
+	return repository.RecordFilter{From: &from, To: &to}
+}
+
+func TestGetDailySummary(t *testing.T) {
+	user := &repository.User{UserId: "gooddy20", Username: "GoodDy", Weight: 70, Protein: 120, Fat: 60, Carb: 150, Timezone: "Asia/Bangkok"}
+	t.Run("Success", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 6, 0, 0, 0, 0, time.UTC))).Return([]repository.Record{
+			{Id: 1, Note: "Breakfast", Protein: 40, Fat: 10, Carb: 20},
+			{Id: 2, Note: "Lunch", Protein: 25.5, Fat: 25, Carb: 65},
+			{Id: 3, Note: "Breakfast", Protein: 10, Fat: 5, Carb: 30, Alcohol: 14},
+		}, nil)
+		srv := service.NewSummaryService(userRepo, recordRepo)
+		result, err := srv.GetDailySummary(context.Background(), "gooddy20", "2023-12-05")
+		expected := &service.DailySummaryResponse{
+			UserId:    "gooddy20",
+			Date:      "2023-12-05",
+			Timezone:  "Asia/Bangkok",
+			Consumed:  service.NutritionTotal{Protein: 75.5, Fat: 40, Carb: 115, Alcohol: 14, Kcal: 1220},
+			Target:    service.NutritionTotal{Protein: 120, Fat: 60, Carb: 150, Alcohol: 0, Kcal: 1620},
+			Remaining: service.NutritionTotal{Protein: 44.5, Fat: 20, Carb: 35, Alcohol: -14, Kcal: 400},
+			Meals: []service.MealSummary{
+				{Note: "Breakfast", RecordIds: []int{1, 3}, Consumed: service.NutritionTotal{Protein: 50, Fat: 15, Carb: 50, Alcohol: 14, Kcal: 633}},
+				{Note: "Lunch", RecordIds: []int{2}, Consumed: service.NutritionTotal{Protein: 25.5, Fat: 25, Carb: 65, Alcohol: 0, Kcal: 587}},
+			},
+		}
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Success Case: No Record", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Protein: 100}, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 6, 0, 0, 0, 0, time.UTC))).Return([]repository.Record{}, nil)
+		srv := service.NewSummaryService(userRepo, recordRepo)
+		result, err := srv.GetDailySummary(context.Background(), "gooddy20", "2023-12-05")
+		expected := &service.DailySummaryResponse{
+			UserId:    "gooddy20",
+			Date:      "2023-12-05",
+			Timezone:  "UTC",
+			Target:    service.NutritionTotal{Protein: 100, Kcal: 400},
+			Remaining: service.NutritionTotal{Protein: 100, Kcal: 400},
+			Meals:     []service.MealSummary{},
+		}
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Invalid Date", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+		srv := service.NewSummaryService(userRepo, recordRepo)
+		_, err := srv.GetDailySummary(context.Background(), "gooddy20", "05/12/2023")
+		assert.ErrorIs(t, err, errs.NewValidationError("date", `Date need to be in the format "2023-01-01"`))
+		recordRepo.AssertNotCalled(t, "GetRecordsByUserId")
+	})
+	t.Run("No The User Id", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{}, repository.ErrNotFound)
+		srv := service.NewSummaryService(userRepo, recordRepo)
+		_, err := srv.GetDailySummary(context.Background(), "gooddy20", "2023-12-05")
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found"))
+		recordRepo.AssertNotCalled(t, "GetRecordsByUserId")
+	})
+	t.Run("Get Records Database Error", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 6, 0, 0, 0, 0, time.UTC))).Return([]repository.Record{}, sql.ErrConnDone)
+		srv := service.NewSummaryService(userRepo, recordRepo)
+		_, err := srv.GetDailySummary(context.Background(), "gooddy20", "2023-12-05")
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
@@ -0,0 +1,89 @@
+package service
+
+import "context"
+
+// Entities and operations of a "Sync Mutation"
+const (
+	SyncEntityRecord  = "record"
+	SyncEntityFavList = "favlist"
+	SyncOpUpsert      = "upsert"
+	SyncOpDelete      = "delete"
+)
+
+// Statuses of a "Sync Mutation" result
+const (
+	SyncStatusApplied  = "applied"  // The change is saved
+	SyncStatusConflict = "conflict" // The server's row wins, the change is based on an older revision or the row is deleted on the server
+	SyncStatusRejected = "rejected" // The change can not be saved e.g. it uses a "Menu" that is not found
+)
+
+type SyncRecordResponse struct {
+	RecordResponse
+	ClientId string `json:"client_id,omitempty" example:"6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"` // Id that the client generated for a "Record" that is created offline
+	Deleted  bool   `json:"deleted" example:"false"`                                            // true = the "Record" is deleted and the client should delete it too
+}
+
+type SyncFavListResponse struct {
+	FavListResponse
+	ClientId string `json:"client_id,omitempty" example:"6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"` // Id that the client generated for a "Favorite List" that is created offline
+	Deleted  bool   `json:"deleted" example:"false"`                                            // true = the "Favorite List" is deleted and the client should delete it too
+}
+
+type SyncChangesResponse struct {
+	Records        []SyncRecordResponse  `json:"records"`                              // "Record" that is created, updated or deleted since the cursor
+	FavLists       []SyncFavListResponse `json:"favorite_lists"`                       // "Favorite List" that is created, updated or deleted since the cursor
+	FavoriteMenues []int                 `json:"favorite_menues" example:"9,10"`       // Every "Menu"'s id of the "Favorite Menu", null = not changed since the cursor
+	Menues         []MenuResponse        `json:"menues"`                               // "Menu" that is changed since the cursor or that the changes use, "status" = 0 is a deleted "Menu"
+	NextCursor     string                `json:"next_cursor" example:"c3luYzo3NDg1Nw"` // Cursor that is sent as "since" to get the changes after these ones
+}
+
+type SyncRecordData struct {
+	List           string  `json:"list" example:"9,9,10" binding:"menulist"`                                   // Summary meal with "Menu"'s id e.g. "9,9,10" *required when "items" is empty
+	Items          []Item  `json:"items"`                                                                      // Summary meal with "Menu"'s id and quantity, it is used instead of "list" when it is not empty
+	Note           string  `json:"note" example:"Breakfast"`                                                   // Note for the "Record"
+	Weight         float64 `json:"weight" example:"63" binding:"omitempty,min=20,max=500"`                     // Weight (kg.) that you are on that day
+	EventTimestamp string  `json:"event_timestamp" example:"2023-11-01 09:30:00" binding:"required,timestamp"` // Wall-clock timestamp in your time zone that you eat, it is kept as it is and the days of the summary are counted on it *format="2023-01-01 00:00:00"
+}
+
+type SyncFavListData struct {
+	Name  string `json:"name" example:"Daily Breakfast" binding:"required"` // Name of the "Favorite List"
+	List  string `json:"list" example:"9,9,10" binding:"menulist"`          // Summary meal with "Menu"'s id e.g. "9,9,10" *required when "items" is empty
+	Items []Item `json:"items"`                                             // Summary meal with "Menu"'s id and quantity, it is used instead of "list" when it is not empty
+}
+
+// SyncMutation is a change that the client made offline, the row is the server's "id" or the "client_id" of a row that the client created,
+// an "upsert" replaces the whole row with "record" or "favorite_list"
+type SyncMutation struct {
+	Entity       string           `json:"entity" example:"record" binding:"required,oneof=record favlist"`         // "record" or "favlist"
+	Op           string           `json:"op" example:"upsert" binding:"required,oneof=upsert delete"`              // "upsert" = create or replace the row, "delete" = delete the row
+	Id           int              `json:"id" example:"0" binding:"min=0"`                                          // Server's id of the row, 0 = the row that the client created with "client_id"
+	ClientId     string           `json:"client_id" example:"6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f" binding:"uuid"` // UUID that the client generated for the row that it created offline *required when "id" is 0
+	BaseRevision int              `json:"base_revision" example:"0" binding:"min=0"`                               // Revision of the row that the change is based on, 0 = the client created the row *required when "id" is set
+	Record       *SyncRecordData  `json:"record"`                                                                  // The whole "Record" *required for "upsert" of a "record"
+	FavList      *SyncFavListData `json:"favorite_list"`                                                           // The whole "Favorite List" *required for "upsert" of a "favlist"
+}
+
+type SyncRequest struct {
+	Mutations []SyncMutation `json:"mutations" binding:"required"` // Changes that are applied in order, 1 - 100 and a row can be changed once in a batch
+}
+
+type SyncMutationResult struct {
+	Entity    string               `json:"entity" example:"record"`                                            // "Entity" of the mutation
+	Id        int                  `json:"id" example:"12"`                                                    // Server's id of the row, 0 = the row does not exist
+	ClientId  string               `json:"client_id,omitempty" example:"6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"` // "client_id" of the mutation
+	Status    string               `json:"status" example:"applied"`                                           // "applied", "conflict" or "rejected"
+	Revision  int                  `json:"revision" example:"1"`                                               // Revision of the row on the server after the mutation
+	ErrorCode string               `json:"error_code,omitempty" example:"VALIDATION_FAILED"`                   // Stable code of the error of a "conflict" or "rejected" mutation
+	Message   string               `json:"message,omitempty" example:"Menu Id - 9 is deleted"`                 // Error of a "conflict" or "rejected" mutation
+	Record    *SyncRecordResponse  `json:"record,omitempty"`                                                   // The server's "Record" that wins the conflict
+	FavList   *SyncFavListResponse `json:"favorite_list,omitempty"`                                            // The server's "Favorite List" that wins the conflict
+}
+
+type SyncResponse struct {
+	Results []SyncMutationResult `json:"results"` // Result of every mutation in the same order
+}
+
+type SyncService interface {
+	GetChanges(context.Context, string, string) (*SyncChangesResponse, error)
+	ApplyMutations(context.Context, string, SyncRequest) (*SyncResponse, error)
+}
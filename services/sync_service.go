@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type syncService struct {
+	syncRepo   repository.SyncRepository
+	unitOfWork repository.UnitOfWork
+}
+
+func NewSyncService(syncRepo repository.SyncRepository, unitOfWork repository.UnitOfWork) syncService {
+	return syncService{syncRepo: syncRepo, unitOfWork: unitOfWork}
+}
+
+// Maximum amount of mutations in a batch
+const maxSyncMutations = 100
+
+// GetChanges returns every change of the "User" since the cursor, an empty cursor returns every row of the "User" for the first sync
+func (s syncService) GetChanges(ctx context.Context, userId string, since string) (*SyncChangesResponse, error) {
+	cursor, err := decodeSyncCursor(since)
+	if err != nil {
+		return nil, errs.NewValidationError("since", "Since need to be the next_cursor of the previous sync")
+	}
+	changes, err := s.syncRepo.GetChanges(ctx, userId, cursor)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
+		}
+		return nil, repositoryError(err)
+	}
+	response := SyncChangesResponse{
+		Records:    []SyncRecordResponse{},
+		FavLists:   []SyncFavListResponse{},
+		Menues:     []MenuResponse{},
+		NextCursor: encodeSyncCursor(changes.NextCursor),
+	}
+	for _, record := range changes.Records {
+		response.Records = append(response.Records, toSyncRecordResponse(record))
+	}
+	for _, favList := range changes.FavLists {
+		response.FavLists = append(response.FavLists, toSyncFavListResponse(favList))
+	}
+	if changes.FavoriteMenues != nil {
+		response.FavoriteMenues = append([]int{}, changes.FavoriteMenues...)
+	}
+	for _, menu := range changes.Menues {
+		response.Menues = append(response.Menues, toMenuResponse(menu))
+	}
+	return &response, nil
+}
+
+// ApplyMutations applies the changes that the client made offline in order, the conflicts are resolved the same way every time:
+// the server's row wins when the change is based on an older revision than the server's one or the row is deleted on the server,
+// a row that the client created is found by its "client_id" so a retried batch does not create it twice.
+// The batch is one unit of work so an unexpected error saves none of the mutations and the client can retry the whole batch
+func (s syncService) ApplyMutations(ctx context.Context, userId string, request SyncRequest) (*SyncResponse, error) {
+	v := validateRequest(request)
+	if len(request.Mutations) > maxSyncMutations {
+		v.add("mutations", fmt.Sprint("Mutations can be at most ", maxSyncMutations))
+	}
+	rows := map[string]int{}
+	for i, mutation := range request.Mutations {
+		field := fmt.Sprintf("mutations[%d]", i)
+		row := fmt.Sprint(mutation.Entity, ":", mutation.Id)
+		if mutation.Id == 0 {
+			row = fmt.Sprint(mutation.Entity, ":", strings.ToLower(mutation.ClientId))
+			if mutation.ClientId == "" {
+				v.add(field+".client_id", "Client Id is required when Id is 0")
+			}
+		} else if mutation.BaseRevision == 0 {
+			v.add(field+".base_revision", "Base revision is required when Id is set")
+		}
+		if j, ok := rows[row]; ok {
+			v.add(field, fmt.Sprint("The row is already changed by mutations[", j, "]"))
+		}
+		rows[row] = i
+		if mutation.Op != SyncOpUpsert {
+			continue
+		}
+		switch {
+		case mutation.Entity == SyncEntityRecord && mutation.Record == nil:
+			v.add(field+".record", "Record is required")
+		case mutation.Entity == SyncEntityRecord && mutation.Record.List == "" && len(mutation.Record.Items) == 0:
+			v.add(field+".record.list", "List or Items is required")
+		case mutation.Entity == SyncEntityFavList && mutation.FavList == nil:
+			v.add(field+".favorite_list", "Favorite list is required")
+		case mutation.Entity == SyncEntityFavList && mutation.FavList.List == "" && len(mutation.FavList.Items) == 0:
+			v.add(field+".favorite_list.list", "List or Items is required")
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	response := SyncResponse{Results: []SyncMutationResult{}}
+	err := s.unitOfWork.Do(ctx, func(repos repository.Repositories) error {
+		for _, mutation := range request.Mutations {
+			var result SyncMutationResult
+			var err error
+			switch mutation.Entity {
+			case SyncEntityRecord:
+				result, err = applyRecord(ctx, repos, userId, mutation)
+			default:
+				result, err = applyFavList(ctx, repos, userId, mutation)
+			}
+			if err != nil {
+				return err
+			}
+			response.Results = append(response.Results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(errs.AppError); ok {
+			return nil, err
+		}
+		return nil, repositoryError(err)
+	}
+	return &response, nil
+}
+
+func applyRecord(ctx context.Context, repos repository.Repositories, userId string, mutation SyncMutation) (SyncMutationResult, error) {
+	result := SyncMutationResult{Entity: mutation.Entity, Id: mutation.Id, ClientId: mutation.ClientId}
+	record, err := findRecord(ctx, repos, userId, mutation)
+	if err != nil {
+		return syncResult(result, err)
+	}
+	if record == nil {
+		if mutation.Op == SyncOpDelete {
+			return syncResult(result, nil)
+		}
+		record = &repository.Record{UserId: userId, ClientId: &mutation.ClientId, Status: 1, CreatedTimestamp: time.Now().UTC().Truncate(time.Second)}
+	} else {
+		result.Id, result.Revision = record.Id, record.Revision
+		if isRetriedCreate(mutation, record.Status) {
+			return syncResult(result, nil)
+		}
+		if record.Status == 0 || mutation.BaseRevision != record.Revision {
+			serverRecord := toSyncRecordResponse(*record)
+			result.Record = &serverRecord
+			return syncResult(result, newStaleError("Record"))
+		}
+	}
+	if mutation.Op == SyncOpDelete {
+		record.Status = 0
+	} else {
+		items, err := toRepositoryItems(mutation.Record.List, mutation.Record.Items)
+		if err != nil {
+			return syncResult(result, err)
+		}
+		err = checkItemUnits(ctx, repos.Menu, items, record.Items)
+		if err != nil {
+			return syncResult(result, err)
+		}
+		record.Items = items
+		record.Note = mutation.Record.Note
+		record.Weight = mutation.Record.Weight
+		record.EventTimestamp, _ = time.Parse(timestampLayout, mutation.Record.EventTimestamp)
+	}
+	if record.Id == 0 {
+		newRecord, err := repos.Record.CreateRecord(ctx, *record)
+		if err != nil {
+			return syncResult(result, syncRepositoryError("Record", err))
+		}
+		result.Id, result.Revision = newRecord.Id, newRecord.Revision
+		return syncResult(result, nil)
+	}
+	err = repos.Record.UpdateRecord(ctx, *record)
+	if err != nil {
+		return syncResult(result, syncRepositoryError("Record", err))
+	}
+	result.Revision = record.Revision + 1
+	return syncResult(result, nil)
+}
+
+// findRecord gets the "Record" of the mutation, a deleted one is returned too so the client gets it back as the server's row,
+// nil = the client created it and it is not saved yet
+func findRecord(ctx context.Context, repos repository.Repositories, userId string, mutation SyncMutation) (*repository.Record, error) {
+	if mutation.Id == 0 {
+		record, err := repos.Record.GetRecordByClientId(ctx, userId, mutation.ClientId)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, nil
+			}
+			return nil, repositoryError(err)
+		}
+		return record, nil
+	}
+	record, err := repos.Record.GetRecordByIdIncludingDeleted(ctx, mutation.Id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeRecordNotFound, fmt.Sprint("Record Id - ", mutation.Id, " is not found"))
+		}
+		return nil, repositoryError(err)
+	}
+	if record.UserId != userId {
+		return nil, errs.NewPermissionDeniedError()
+	}
+	return record, nil
+}
+
+func applyFavList(ctx context.Context, repos repository.Repositories, userId string, mutation SyncMutation) (SyncMutationResult, error) {
+	result := SyncMutationResult{Entity: mutation.Entity, Id: mutation.Id, ClientId: mutation.ClientId}
+	favList, err := findFavList(ctx, repos, userId, mutation)
+	if err != nil {
+		return syncResult(result, err)
+	}
+	if favList == nil {
+		if mutation.Op == SyncOpDelete {
+			return syncResult(result, nil)
+		}
+		favList = &repository.FavList{UserId: userId, ClientId: &mutation.ClientId, Status: 1, CreatedTimestamp: time.Now().UTC().Truncate(time.Second)}
+	} else {
+		result.Id, result.Revision = favList.Id, favList.Revision
+		if isRetriedCreate(mutation, favList.Status) {
+			return syncResult(result, nil)
+		}
+		if favList.Status == 0 || mutation.BaseRevision != favList.Revision {
+			serverFavList := toSyncFavListResponse(*favList)
+			result.FavList = &serverFavList
+			return syncResult(result, newStaleError("Favorite List"))
+		}
+	}
+	if mutation.Op == SyncOpDelete {
+		favList.Status = 0
+	} else {
+		items, err := toRepositoryItems(mutation.FavList.List, mutation.FavList.Items)
+		if err != nil {
+			return syncResult(result, err)
+		}
+		err = checkItemUnits(ctx, repos.Menu, items, favList.Items)
+		if err != nil {
+			return syncResult(result, err)
+		}
+		favList.Items = items
+		favList.Name = mutation.FavList.Name
+	}
+	if favList.Id == 0 {
+		newFavList, err := repos.FavList.CreateFavList(ctx, *favList)
+		if err != nil {
+			return syncResult(result, syncRepositoryError("Favorite List", err))
+		}
+		result.Id, result.Revision = newFavList.Id, newFavList.Revision
+		return syncResult(result, nil)
+	}
+	err = repos.FavList.UpdateFavList(ctx, *favList)
+	if err != nil {
+		return syncResult(result, syncRepositoryError("Favorite List", err))
+	}
+	result.Revision = favList.Revision + 1
+	return syncResult(result, nil)
+}
+
+// findFavList gets the "Favorite List" of the mutation, a deleted one is returned too so the client gets it back as the server's row,
+// nil = the client created it and it is not saved yet
+func findFavList(ctx context.Context, repos repository.Repositories, userId string, mutation SyncMutation) (*repository.FavList, error) {
+	if mutation.Id == 0 {
+		favList, err := repos.FavList.GetFavListByClientId(ctx, userId, mutation.ClientId)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, nil
+			}
+			return nil, repositoryError(err)
+		}
+		return favList, nil
+	}
+	favList, err := repos.FavList.GetFavListByIdIncludingDeleted(ctx, mutation.Id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeFavListNotFound, fmt.Sprint("Favorite List Id - ", mutation.Id, " is not found"))
+		}
+		return nil, repositoryError(err)
+	}
+	if favList.UserId != userId {
+		return nil, errs.NewPermissionDeniedError()
+	}
+	return favList, nil
+}
+
+// isRetriedCreate is a create of a row that is already saved by a previous try of the batch, the row is not changed again
+func isRetriedCreate(mutation SyncMutation, status int) bool {
+	return mutation.Op == SyncOpUpsert && mutation.Id == 0 && mutation.BaseRevision == 0 && status == 1
+}
+
+// syncResult turns the error of a mutation into its status, an unexpected error rolls the batch back instead
+func syncResult(result SyncMutationResult, err error) (SyncMutationResult, error) {
+	if err == nil {
+		result.Status = SyncStatusApplied
+		return result, nil
+	}
+	var appErr errs.AppError
+	if !errors.As(err, &appErr) || appErr.ErrorCode == errs.CodeInternal {
+		return result, err
+	}
+	result.Status = SyncStatusRejected
+	if appErr.ErrorCode == errs.CodePreconditionFailed {
+		result.Status = SyncStatusConflict
+	}
+	result.ErrorCode, result.Message = appErr.ErrorCode, appErr.Message
+	return result, nil
+}
+
+// syncRepositoryError is a conflict when another request changes the row or creates it with the same "client_id" in the meantime
+func syncRepositoryError(name string, err error) error {
+	if errors.Is(err, repository.ErrStale) || errors.Is(err, repository.ErrConflict) {
+		return newStaleError(name)
+	}
+	return repositoryError(err)
+}
+
+func toSyncRecordResponse(record repository.Record) SyncRecordResponse {
+	response := SyncRecordResponse{RecordResponse: toRecordResponse(record), Deleted: record.Status == 0}
+	if record.ClientId != nil {
+		response.ClientId = *record.ClientId
+	}
+	return response
+}
+
+func toSyncFavListResponse(favList repository.FavList) SyncFavListResponse {
+	response := SyncFavListResponse{FavListResponse: toFavListResponse(favList), Deleted: favList.Status == 0}
+	if favList.ClientId != nil {
+		response.ClientId = *favList.ClientId
+	}
+	return response
+}
+
+// encodeSyncCursor makes an opaque cursor of the position in the change feed
+func encodeSyncCursor(cursor int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprint("sync:", cursor)))
+}
+
+func decodeSyncCursor(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	content, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return 0, err
+	}
+	position, ok := strings.CutPrefix(string(content), "sync:")
+	if !ok {
+		return 0, errors.New("unexpected cursor")
+	}
+	cursor, err := strconv.ParseInt(position, 10, 64)
+	if err != nil || cursor < 0 {
+		return 0, errors.New("unexpected cursor")
+	}
+	return cursor, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type syncServiceMock struct {
+	mock.Mock
+}
+
+func NewSyncServiceMock() *syncServiceMock {
+	return &syncServiceMock{}
+}
+
+func (s *syncServiceMock) GetChanges(ctx context.Context, userId string, since string) (*SyncChangesResponse, error) {
+	args := s.Called(userId, since)
+	return args.Get(0).(*SyncChangesResponse), args.Error(1)
+}
+
+func (s *syncServiceMock) ApplyMutations(ctx context.Context, userId string, request SyncRequest) (*SyncResponse, error) {
+	args := s.Called(userId, request)
+	return args.Get(0).(*SyncResponse), args.Error(1)
+}
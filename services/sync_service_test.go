@@ -0,0 +1,369 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	service "go-nutritioncalculator2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const clientId = "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"
+
+func TestGetChanges(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		syncRepo := repository.NewSyncRepositoryMock()
+		deletedClientId := clientId
+		syncRepo.On("GetChanges", "gooddy20", int64(0)).Return(&repository.Changes{
+			Records: []repository.Record{
+				{Id: 1, UserId: "gooddy20", Items: []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}}, Menues: "Moo Yang-2 ", Note: "Breakfast", Protein: 40, EventTimestamp: time.Date(2023, 12, 5, 10, 0, 0, 0, time.UTC), Status: 1, IsUpdated: 1, Revision: 2},
+				{Id: 2, UserId: "gooddy20", ClientId: &deletedClientId, Status: 0, IsUpdated: 1, Revision: 3},
+			},
+			FavLists:       []repository.FavList{{Id: 4, UserId: "gooddy20", Name: "Daily Breakfast", Items: []repository.Item{{MenuId: 9, Quantity: 1, Unit: "serving"}}, Status: 1, IsUpdated: 1, Revision: 1}},
+			FavoriteMenues: []int{9, 10},
+			Menues:         []repository.Menu{{Id: 9, Name: "Moo Yang", Protein: 20, ServingSize: 1, ServingUnit: "serving", CreatorId: "kornkoko", Status: 1, Version: 1, Revision: 1}},
+			NextCursor:     7,
+		}, nil)
+		srv := service.NewSyncService(syncRepo, repository.NewUnitOfWorkMock(repository.Repositories{}))
+		changes, err := srv.GetChanges(context.Background(), "gooddy20", "")
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, "c3luYzo3", changes.NextCursor)
+		assert.Len(t, changes.Records, 2)
+		assert.Equal(t, 1, changes.Records[0].Id)
+		assert.Equal(t, "9,9", changes.Records[0].List)
+		assert.Equal(t, 2, changes.Records[0].Revision)
+		assert.False(t, changes.Records[0].Deleted)
+		assert.Empty(t, changes.Records[0].ClientId)
+		assert.True(t, changes.Records[1].Deleted)
+		assert.Equal(t, clientId, changes.Records[1].ClientId)
+		assert.Len(t, changes.FavLists, 1)
+		assert.Equal(t, "Daily Breakfast", changes.FavLists[0].Name)
+		assert.False(t, changes.FavLists[0].Deleted)
+		assert.Equal(t, []int{9, 10}, changes.FavoriteMenues)
+		assert.Len(t, changes.Menues, 1)
+		assert.Equal(t, "Moo Yang", changes.Menues[0].Name)
+		assert.Equal(t, float64(80), changes.Menues[0].Kcal)
+	})
+	t.Run("Success Case: Favorite Menues Not Changed", func(t *testing.T) {
+		syncRepo := repository.NewSyncRepositoryMock()
+		syncRepo.On("GetChanges", "gooddy20", int64(7)).Return(&repository.Changes{NextCursor: 9}, nil)
+		srv := service.NewSyncService(syncRepo, repository.NewUnitOfWorkMock(repository.Repositories{}))
+		changes, err := srv.GetChanges(context.Background(), "gooddy20", "c3luYzo3")
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, &service.SyncChangesResponse{
+			Records:    []service.SyncRecordResponse{},
+			FavLists:   []service.SyncFavListResponse{},
+			Menues:     []service.MenuResponse{},
+			NextCursor: "c3luYzo5",
+		}, changes)
+	})
+	t.Run("Success Case: Favorite Menues Cleared", func(t *testing.T) {
+		syncRepo := repository.NewSyncRepositoryMock()
+		syncRepo.On("GetChanges", "gooddy20", int64(7)).Return(&repository.Changes{FavoriteMenues: []int{}, NextCursor: 9}, nil)
+		srv := service.NewSyncService(syncRepo, repository.NewUnitOfWorkMock(repository.Repositories{}))
+		changes, err := srv.GetChanges(context.Background(), "gooddy20", "c3luYzo3")
+		assert.ErrorIs(t, err, nil)
+		assert.NotNil(t, changes.FavoriteMenues)
+		assert.Empty(t, changes.FavoriteMenues)
+	})
+	for _, since := range []string{"not a cursor", "YXN5bmM6Nw", "c3luYzo"} {
+		t.Run("Invalid Cursor "+since, func(t *testing.T) {
+			syncRepo := repository.NewSyncRepositoryMock()
+			srv := service.NewSyncService(syncRepo, repository.NewUnitOfWorkMock(repository.Repositories{}))
+			_, err := srv.GetChanges(context.Background(), "gooddy20", since)
+			assert.ErrorIs(t, err, errs.NewValidationError("since", "Since need to be the next_cursor of the previous sync"))
+			syncRepo.AssertNotCalled(t, "GetChanges", mock.Anything, mock.Anything)
+		})
+	}
+	t.Run("Database Error", func(t *testing.T) {
+		syncRepo := repository.NewSyncRepositoryMock()
+		syncRepo.On("GetChanges", "gooddy20", int64(0)).Return(&repository.Changes{}, sql.ErrConnDone)
+		srv := service.NewSyncService(syncRepo, repository.NewUnitOfWorkMock(repository.Repositories{}))
+		_, err := srv.GetChanges(context.Background(), "gooddy20", "")
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
+
+func TestApplyMutations(t *testing.T) {
+	breakfast := &service.SyncRecordData{List: "9,9,10", Note: "Breakfast", Weight: 70, EventTimestamp: "2023-12-05 07:30:00"}
+	t.Run("Success Case: Create Offline Record", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		recordRepo.On("GetRecordByClientId", "gooddy20", clientId).Return(&repository.Record{}, repository.ErrNotFound)
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
+		recordRepo.On("CreateRecord", mock.MatchedBy(func(record repository.Record) bool {
+			return record.UserId == "gooddy20" && *record.ClientId == clientId && record.Note == "Breakfast" && record.Weight == 70 && record.Status == 1 &&
+				record.EventTimestamp.Equal(time.Date(2023, 12, 5, 7, 30, 0, 0, time.UTC)) &&
+				assert.ObjectsAreEqual([]repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}}, record.Items)
+		})).Return(&repository.Record{Id: 12, Revision: 1}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo, Menu: menuRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "upsert", ClientId: clientId, Record: breakfast},
+		}})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, &service.SyncResponse{Results: []service.SyncMutationResult{
+			{Entity: "record", Id: 12, ClientId: clientId, Status: "applied", Revision: 1},
+		}}, response)
+	})
+	t.Run("Success Case: Update Record", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		recordRepo.On("GetRecordByIdIncludingDeleted", 1).Return(&repository.Record{Id: 1, UserId: "gooddy20", Items: []repository.Item{{MenuId: 9, Quantity: 1, Unit: "serving"}}, Note: "Snack", Status: 1, Revision: 2}, nil)
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return(servingMenues(9, 10), nil)
+		recordRepo.On("UpdateRecord", repository.Record{
+			Id:             1,
+			UserId:         "gooddy20",
+			Items:          []repository.Item{{MenuId: 9, Quantity: 2, Unit: "serving"}, {MenuId: 10, Quantity: 1, Unit: "serving"}},
+			Note:           "Breakfast",
+			Weight:         70,
+			EventTimestamp: time.Date(2023, 12, 5, 7, 30, 0, 0, time.UTC),
+			Status:         1,
+			Revision:       2,
+		}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo, Menu: menuRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "upsert", Id: 1, BaseRevision: 2, Record: breakfast},
+		}})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, []service.SyncMutationResult{{Entity: "record", Id: 1, Status: "applied", Revision: 3}}, response.Results)
+	})
+	t.Run("Success Case: Create and Delete Favorite Lists", func(t *testing.T) {
+		favListRepo := repository.NewFavListRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		favListRepo.On("GetFavListByClientId", "gooddy20", clientId).Return(&repository.FavList{}, repository.ErrNotFound)
+		menuRepo.On("GetMenusByIds", []int{9}).Return(servingMenues(9), nil)
+		favListRepo.On("CreateFavList", mock.MatchedBy(func(favList repository.FavList) bool {
+			return favList.UserId == "gooddy20" && *favList.ClientId == clientId && favList.Name == "Daily Breakfast" && favList.Status == 1
+		})).Return(&repository.FavList{Id: 5, Revision: 1}, nil)
+		favListRepo.On("GetFavListByIdIncludingDeleted", 4).Return(&repository.FavList{Id: 4, UserId: "gooddy20", Name: "Old Lunch", Status: 1, Revision: 3}, nil)
+		favListRepo.On("UpdateFavList", repository.FavList{Id: 4, UserId: "gooddy20", Name: "Old Lunch", Status: 0, Revision: 3}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{FavList: favListRepo, Menu: menuRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "favlist", Op: "upsert", ClientId: clientId, FavList: &service.SyncFavListData{Name: "Daily Breakfast", List: "9"}},
+			{Entity: "favlist", Op: "delete", Id: 4, BaseRevision: 3},
+		}})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, []service.SyncMutationResult{
+			{Entity: "favlist", Id: 5, ClientId: clientId, Status: "applied", Revision: 1},
+			{Entity: "favlist", Id: 4, Status: "applied", Revision: 4},
+		}, response.Results)
+	})
+	t.Run("Success Case: Delete Record That Is Not Saved", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		recordRepo.On("GetRecordByClientId", "gooddy20", clientId).Return(&repository.Record{}, repository.ErrNotFound)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "delete", ClientId: clientId},
+		}})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, []service.SyncMutationResult{{Entity: "record", ClientId: clientId, Status: "applied"}}, response.Results)
+		recordRepo.AssertNotCalled(t, "CreateRecord", mock.Anything)
+		recordRepo.AssertNotCalled(t, "UpdateRecord", mock.Anything)
+	})
+	t.Run("Conflict: Older Base Revision", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		recordRepo.On("GetRecordByIdIncludingDeleted", 1).Return(&repository.Record{Id: 1, UserId: "gooddy20", Items: []repository.Item{{MenuId: 9, Quantity: 1, Unit: "serving"}}, Note: "Snack", Status: 1, IsUpdated: 1, Revision: 4}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "upsert", Id: 1, BaseRevision: 2, Record: breakfast},
+		}})
+		assert.ErrorIs(t, err, nil)
+		result := response.Results[0]
+		assert.Equal(t, "conflict", result.Status)
+		assert.Equal(t, errs.CodePreconditionFailed, result.ErrorCode)
+		assert.Equal(t, "Record has been changed by another request, get it again and retry", result.Message)
+		assert.Equal(t, 4, result.Revision)
+		assert.Equal(t, "Snack", result.Record.Note)
+		assert.Equal(t, "9", result.Record.List)
+		assert.False(t, result.Record.Deleted)
+		recordRepo.AssertNotCalled(t, "UpdateRecord", mock.Anything)
+	})
+	t.Run("Success Case: Retried Create", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		savedClientId := clientId
+		recordRepo.On("GetRecordByClientId", "gooddy20", clientId).Return(&repository.Record{Id: 12, UserId: "gooddy20", ClientId: &savedClientId, Note: "Breakfast", Status: 1, Revision: 1}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "upsert", ClientId: clientId, Record: breakfast},
+		}})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, []service.SyncMutationResult{{Entity: "record", Id: 12, ClientId: clientId, Status: "applied", Revision: 1}}, response.Results)
+		recordRepo.AssertNotCalled(t, "CreateRecord", mock.Anything)
+		recordRepo.AssertNotCalled(t, "UpdateRecord", mock.Anything)
+	})
+	t.Run("Conflict: Deleted On The Server", func(t *testing.T) {
+		favListRepo := repository.NewFavListRepositoryMock()
+		savedClientId := clientId
+		favListRepo.On("GetFavListByClientId", "gooddy20", clientId).Return(&repository.FavList{Id: 5, UserId: "gooddy20", ClientId: &savedClientId, Name: "Daily Breakfast", Status: 0, Revision: 2}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{FavList: favListRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "favlist", Op: "upsert", ClientId: clientId, BaseRevision: 2, FavList: &service.SyncFavListData{Name: "Daily Lunch", List: "9"}},
+		}})
+		assert.ErrorIs(t, err, nil)
+		result := response.Results[0]
+		assert.Equal(t, "conflict", result.Status)
+		assert.Equal(t, "Favorite List has been changed by another request, get it again and retry", result.Message)
+		assert.True(t, result.FavList.Deleted)
+		favListRepo.AssertNotCalled(t, "UpdateFavList", mock.Anything)
+	})
+	t.Run("Conflict: Deleted Row Of The Id", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		recordRepo.On("GetRecordByIdIncludingDeleted", 1).Return(&repository.Record{Id: 1, UserId: "gooddy20", Note: "Snack", Status: 0, Revision: 3}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "upsert", Id: 1, BaseRevision: 2, Record: breakfast},
+		}})
+		assert.ErrorIs(t, err, nil)
+		result := response.Results[0]
+		assert.Equal(t, "conflict", result.Status)
+		assert.Equal(t, 3, result.Revision)
+		assert.True(t, result.Record.Deleted)
+		assert.Equal(t, "Snack", result.Record.Note)
+		recordRepo.AssertNotCalled(t, "UpdateRecord", mock.Anything)
+	})
+	t.Run("Conflict: Changed By Another Request", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		recordRepo.On("GetRecordByIdIncludingDeleted", 1).Return(&repository.Record{Id: 1, UserId: "gooddy20", Status: 1, Revision: 2}, nil)
+		recordRepo.On("UpdateRecord", repository.Record{Id: 1, UserId: "gooddy20", Status: 0, Revision: 2}).Return(repository.ErrStale)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "delete", Id: 1, BaseRevision: 2},
+		}})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, []service.SyncMutationResult{{
+			Entity:    "record",
+			Id:        1,
+			Status:    "conflict",
+			Revision:  2,
+			ErrorCode: errs.CodePreconditionFailed,
+			Message:   "Record has been changed by another request, get it again and retry",
+		}}, response.Results)
+	})
+	t.Run("Rejected Mutations Do Not Stop The Others", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		otherClientId := "0b6e0f3c-5d1a-4f2b-9c3d-7e8f9a0b1c2d"
+		recordRepo.On("GetRecordByClientId", "gooddy20", clientId).Return(&repository.Record{}, repository.ErrNotFound)
+		menuRepo.On("GetMenusByIds", []int{9, 10}).Return([]repository.Menu{{Id: 9, ServingSize: 1, ServingUnit: "serving", Status: 0}, {Id: 10, ServingSize: 1, ServingUnit: "serving", Status: 1}}, nil)
+		recordRepo.On("GetRecordByIdIncludingDeleted", 3).Return(&repository.Record{Id: 3, UserId: "kornkoko", Status: 1, Revision: 1}, nil)
+		recordRepo.On("GetRecordByClientId", "gooddy20", otherClientId).Return(&repository.Record{}, repository.ErrNotFound)
+		menuRepo.On("GetMenusByIds", []int{10}).Return(servingMenues(10), nil)
+		recordRepo.On("CreateRecord", mock.Anything).Return(&repository.Record{Id: 13, Revision: 1}, nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo, Menu: menuRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "upsert", ClientId: clientId, Record: breakfast},
+			{Entity: "record", Op: "delete", Id: 3, BaseRevision: 1},
+			{Entity: "record", Op: "upsert", ClientId: otherClientId, Record: &service.SyncRecordData{List: "10", EventTimestamp: "2023-12-05 12:00:00"}},
+		}})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, []service.SyncMutationResult{
+			{Entity: "record", ClientId: clientId, Status: "rejected", ErrorCode: errs.CodeValidationFailed, Message: "Menu Id - 9 is deleted"},
+			{Entity: "record", Id: 3, Status: "rejected", ErrorCode: errs.CodePermissionDenied, Message: "Permission denied"},
+			{Entity: "record", Id: 13, ClientId: otherClientId, Status: "applied", Revision: 1},
+		}, response.Results)
+	})
+	t.Run("Not Found Record Id", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		recordRepo.On("GetRecordByIdIncludingDeleted", 1).Return(&repository.Record{}, repository.ErrNotFound)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo})
+		unitOfWork.On("Do").Return(nil)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		response, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "delete", Id: 1, BaseRevision: 1},
+		}})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, []service.SyncMutationResult{{Entity: "record", Id: 1, Status: "rejected", ErrorCode: errs.CodeRecordNotFound, Message: "Record Id - 1 is not found"}}, response.Results)
+	})
+	t.Run("Invalid Mutations", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo})
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		_, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "meal", Op: "delete", ClientId: "not-a-uuid"},
+			{Entity: "record", Op: "upsert"},
+			{Entity: "record", Op: "delete", Id: 1},
+			{Entity: "favlist", Op: "upsert", ClientId: clientId, FavList: &service.SyncFavListData{}},
+			{Entity: "record", Op: "upsert", ClientId: clientId, Record: &service.SyncRecordData{List: "9"}},
+			{Entity: "record", Op: "delete", ClientId: clientId},
+		}})
+		assert.ErrorIs(t, err, errs.NewValidationErrors([]errs.FieldError{
+			{Field: "mutations[0].entity", Message: `Entity need to be one of "record", "favlist"`},
+			{Field: "mutations[0].client_id", Message: `Client Id need to be a UUID e.g. "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"`},
+			{Field: "mutations[3].favorite_list.name", Message: "Name is required"},
+			{Field: "mutations[4].record.event_timestamp", Message: "Event timestamp is required"},
+			{Field: "mutations[1].client_id", Message: "Client Id is required when Id is 0"},
+			{Field: "mutations[1].record", Message: "Record is required"},
+			{Field: "mutations[2].base_revision", Message: "Base revision is required when Id is set"},
+			{Field: "mutations[3].favorite_list.list", Message: "List or Items is required"},
+			{Field: "mutations[5]", Message: "The row is already changed by mutations[4]"},
+		}))
+		recordRepo.AssertNotCalled(t, "GetRecordByIdIncludingDeleted", mock.Anything)
+	})
+	t.Run("Missing Mutations", func(t *testing.T) {
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{})
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		_, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{})
+		assert.ErrorIs(t, err, errs.NewValidationError("mutations", "Mutations is required"))
+	})
+	t.Run("Too Many Mutations", func(t *testing.T) {
+		mutations := []service.SyncMutation{}
+		for i := 1; i <= 101; i++ {
+			mutations = append(mutations, service.SyncMutation{Entity: "record", Op: "delete", Id: i, BaseRevision: 1})
+		}
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{})
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		_, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: mutations})
+		assert.ErrorIs(t, err, errs.NewValidationError("mutations", "Mutations can be at most 100"))
+	})
+	t.Run("Database Error", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		recordRepo.On("GetRecordByIdIncludingDeleted", 1).Return(&repository.Record{Id: 1, UserId: "gooddy20", Status: 1, Revision: 2}, nil)
+		recordRepo.On("UpdateRecord", repository.Record{Id: 1, UserId: "gooddy20", Status: 0, Revision: 2}).Return(nil)
+		recordRepo.On("GetRecordByClientId", "gooddy20", clientId).Return(&repository.Record{}, sql.ErrConnDone)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo})
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		_, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "delete", Id: 1, BaseRevision: 2},
+			{Entity: "record", Op: "upsert", ClientId: clientId, Record: breakfast},
+		}})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+		unitOfWork.AssertNotCalled(t, "Do")
+	})
+	t.Run("Commit Error", func(t *testing.T) {
+		recordRepo := repository.NewRecordRepositoryMock()
+		recordRepo.On("GetRecordByIdIncludingDeleted", 1).Return(&repository.Record{Id: 1, UserId: "gooddy20", Status: 1, Revision: 2}, nil)
+		recordRepo.On("UpdateRecord", repository.Record{Id: 1, UserId: "gooddy20", Status: 0, Revision: 2}).Return(nil)
+		unitOfWork := repository.NewUnitOfWorkMock(repository.Repositories{Record: recordRepo})
+		unitOfWork.On("Do").Return(sql.ErrConnDone)
+		srv := service.NewSyncService(repository.NewSyncRepositoryMock(), unitOfWork)
+		_, err := srv.ApplyMutations(context.Background(), "gooddy20", service.SyncRequest{Mutations: []service.SyncMutation{
+			{Entity: "record", Op: "delete", Id: 1, BaseRevision: 2},
+		}})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
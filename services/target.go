@@ -0,0 +1,20 @@
+package service
+
+import "context"
+
+type TargetResponse struct {
+	UserId        string         `json:"user_id" example:"gooddy20"`        // "User Id"
+	Sex           string         `json:"sex" example:"male"`                // Sex of the "User"
+	Age           int            `json:"age" example:"30"`                  // Age (years) of the "User" today
+	Weight        float64        `json:"weight" example:"70"`               // Weight (kg.) of the "User"
+	Height        float64        `json:"height" example:"175"`              // Height (cm.) of the "User"
+	ActivityLevel string         `json:"activity_level" example:"moderate"` // Activity level of the "User"
+	Goal          string         `json:"goal" example:"cut"`                // Goal of the "User"
+	Bmr           float64        `json:"bmr" example:"1648.8"`              // Basal metabolic rate (kcal/day) by the Mifflin-St Jeor equation
+	Tdee          float64        `json:"tdee" example:"2555.6"`             // Total daily energy expenditure (kcal/day) = BMR x the factor of the activity level
+	Target        NutritionTotal `json:"target"`                            // Suggested daily protein, fat and carb (g.) and energy (kcal) of the goal
+}
+
+type TargetService interface {
+	GetSuggestedTargets(context.Context, string) (*TargetResponse, error)
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	"time"
+)
+
+type targetService struct {
+	userRepo repository.UserRepository
+}
+
+func NewTargetService(userRepo repository.UserRepository) targetService {
+	return targetService{userRepo: userRepo}
+}
+
+func (s targetService) GetSuggestedTargets(ctx context.Context, userId string) (*TargetResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
+		}
+		return nil, repositoryError(err)
+	}
+	_, location, err := userLocation(*user)
+	if err != nil {
+		return nil, err
+	}
+	return suggestTargets(*user, time.Now().In(location))
+}
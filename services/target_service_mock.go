@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type targetServiceMock struct {
+	mock.Mock
+}
+
+func NewTargetServiceMock() *targetServiceMock {
+	return &targetServiceMock{}
+}
+
+func (s *targetServiceMock) GetSuggestedTargets(ctx context.Context, userId string) (*TargetResponse, error) {
+	args := s.Called(userId)
+	return args.Get(0).(*TargetResponse), args.Error(1)
+}
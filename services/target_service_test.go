@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	service "go-nutritioncalculator2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// yearsAgo is the birth date of someone who turns "years" old today
+func yearsAgo(years int) *time.Time {
+	now := time.Now().UTC()
+	birthDate := time.Date(now.Year()-years, now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return &birthDate
+}
+
+func TestGetSuggestedTargets(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		User     repository.User
+		Expected *service.TargetResponse
+	}
+	cases := []TestCase{
+		{
+			Name: "Success Case: Male Cut",
+			User: repository.User{UserId: "gooddy20", Weight: 70, Sex: "male", BirthDate: yearsAgo(30), Height: 175, ActivityLevel: "moderate", Goal: "cut"},
+			Expected: &service.TargetResponse{UserId: "gooddy20", Sex: "male", Age: 30, Weight: 70, Height: 175, ActivityLevel: "moderate", Goal: "cut",
+				Bmr: 1648.8, Tdee: 2555.6, Target: service.NutritionTotal{Protein: 154, Fat: 56.8, Carb: 229.3, Kcal: 2044.5}},
+		},
+		{
+			Name: "Success Case: Female Maintain",
+			User: repository.User{UserId: "gooddy20", Weight: 60, Sex: "female", BirthDate: yearsAgo(25), Height: 165, ActivityLevel: "sedentary", Goal: "maintain"},
+			Expected: &service.TargetResponse{UserId: "gooddy20", Sex: "female", Age: 25, Weight: 60, Height: 165, ActivityLevel: "sedentary", Goal: "maintain",
+				Bmr: 1345.3, Tdee: 1614.3, Target: service.NutritionTotal{Protein: 108, Fat: 44.8, Carb: 194.7, Kcal: 1614.3}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			repo := repository.NewUserRepositoryMock()
+			user := c.User
+			repo.On("GetUserById", "gooddy20").Return(&user, nil)
+			srv := service.NewTargetService(repo)
+			result, err := srv.GetSuggestedTargets(context.Background(), "gooddy20")
+			assert.ErrorIs(t, err, nil)
+			assert.Equal(t, c.Expected, result)
+		})
+	}
+	t.Run("Incomplete Profile", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Weight: 70, Sex: "male", Height: 175, ActivityLevel: "moderate", Goal: "cut"}, nil)
+		srv := service.NewTargetService(repo)
+		_, err := srv.GetSuggestedTargets(context.Background(), "gooddy20")
+		assert.ErrorIs(t, err, errs.NewUnprocessableError(errs.CodeProfileIncomplete, "Sex, birth date, height, weight, activity level and goal need to be set to suggest the targets"))
+	})
+	t.Run("No The User Id", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, repository.ErrNotFound)
+		srv := service.NewTargetService(repo)
+		_, err := srv.GetSuggestedTargets(context.Background(), "gooddy20")
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found"))
+	})
+	t.Run("Database Error", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, errors.New(""))
+		srv := service.NewTargetService(repo)
+		_, err := srv.GetSuggestedTargets(context.Background(), "gooddy20")
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
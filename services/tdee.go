@@ -0,0 +1,23 @@
+package service
+
+import "context"
+
+type AdaptiveTdeeResponse struct {
+	UserId        string         `json:"user_id" example:"gooddy20"`      // "User Id"
+	From          string         `json:"from" example:"2023-11-07"`       // First day of the estimation *format="2023-01-01"
+	To            string         `json:"to" example:"2023-12-04"`         // Last day of the estimation *format="2023-01-01"
+	Timezone      string         `json:"timezone" example:"Asia/Bangkok"` // Time zone that the days are counted in
+	Weeks         int            `json:"weeks" example:"4"`               // Amount of weeks that the estimation looks back
+	LoggedDays    int            `json:"logged_days" example:"26"`        // Amount of days that have at least 1 "Record"
+	AverageIntake float64        `json:"average_intake" example:"2150.4"` // Average energy (kcal) of the logged days
+	StartTrend    float64        `json:"start_trend" example:"71.2"`      // Trend weight (kg.) at the first weighed day
+	EndTrend      float64        `json:"end_trend" example:"70.4"`        // Trend weight (kg.) at the last weighed day
+	WeeklyRate    float64        `json:"weekly_rate" example:"-0.2"`      // Change of the trend weight (kg.) per week
+	Tdee          float64        `json:"tdee" example:"2370.4"`           // Estimated maintenance energy (kcal/day) = average intake - energy of the weight change per day
+	Goal          string         `json:"goal" example:"cut"`              // Goal that the targets are recommended for, "maintain" when the "User" has no goal
+	Target        NutritionTotal `json:"target"`                          // Recommended daily protein, fat and carb (g.) and energy (kcal) of the goal from the estimated TDEE
+}
+
+type TdeeService interface {
+	GetAdaptiveTdee(context.Context, string, int) (*AdaptiveTdeeResponse, error)
+}
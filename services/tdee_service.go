@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	"sort"
+	"time"
+)
+
+// Energy (kcal) that is stored or released by 1 kg. of body weight
+const kcalPerKgBodyWeight = 7700
+
+// Weeks that the estimation looks back by default and at most
+const (
+	defaultAdaptiveWeeks = 4
+	minAdaptiveWeeks     = 2
+	maxAdaptiveWeeks     = 12
+)
+
+// Least logged days and least days between the first and the last weighed day that make the estimation meaningful
+const (
+	minAdaptiveLoggedDays  = 7
+	minAdaptiveWeighedDays = 7
+)
+
+type tdeeService struct {
+	userRepo      repository.UserRepository
+	recordRepo    repository.RecordRepository
+	weightLogRepo repository.WeightLogRepository
+}
+
+func NewTdeeService(userRepo repository.UserRepository, recordRepo repository.RecordRepository, weightLogRepo repository.WeightLogRepository) tdeeService {
+	return tdeeService{userRepo: userRepo, recordRepo: recordRepo, weightLogRepo: weightLogRepo}
+}
+
+// GetAdaptiveTdee back-calculates the maintenance energy from the average intake of the logged days in the last "weeks" weeks before today
+// and the change of the trend weight over the same days, then recommends the targets of the user's goal from it
+func (s tdeeService) GetAdaptiveTdee(ctx context.Context, userId string, weeks int) (*AdaptiveTdeeResponse, error) {
+	if weeks == 0 {
+		weeks = defaultAdaptiveWeeks
+	}
+	if weeks < minAdaptiveWeeks || weeks > maxAdaptiveWeeks {
+		return nil, errs.NewValidationError("weeks", "Weeks need to be between 2 and 12")
+	}
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
+		}
+		return nil, repositoryError(err)
+	}
+	timezone, location, err := userLocation(*user)
+	if err != nil {
+		return nil, err
+	}
+	today := localToday(location)
+	firstDay, lastDay := today.AddDate(0, 0, -7*weeks), today.AddDate(0, 0, -1)
+	readFrom, readTo := firstDay.AddDate(0, 0, -trendWarmUpDays), today
+	records, err := s.recordRepo.GetRecordsByUserId(ctx, userId, repository.RecordFilter{From: &readFrom, To: &readTo})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	weightLogs, err := s.weightLogRepo.GetWeightLogsByUserId(ctx, userId, &readFrom, &readTo)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	from := firstDay.Format("2006-01-02")
+	intake := map[string]float64{}
+	weighedDays := map[string]bool{}
+	for _, weightLog := range weightLogs {
+		weighedDays[weightLog.LoggedTimestamp.Format("2006-01-02")] = true
+	}
+	for _, record := range records {
+		date := record.EventTimestamp.Format("2006-01-02")
+		if date >= from {
+			intake[date] += record.Protein*KcalPerGramProtein + record.Fat*KcalPerGramFat + record.Carb*KcalPerGramCarb + record.Alcohol*KcalPerGramAlcohol
+		}
+		if record.Weight > 0 && !weighedDays[date] {
+			weightLogs = append(weightLogs, repository.WeightLog{UserId: userId, Weight: record.Weight, LoggedTimestamp: record.EventTimestamp})
+		}
+	}
+	if len(intake) < minAdaptiveLoggedDays {
+		return nil, errs.NewUnprocessableError(errs.CodeNotEnoughData, "At least 7 logged days are needed to estimate TDEE")
+	}
+	sort.SliceStable(weightLogs, func(i, j int) bool {
+		return weightLogs[i].LoggedTimestamp.Before(weightLogs[j].LoggedTimestamp)
+	})
+	points := []WeightTrendPoint{}
+	for _, point := range weightTrend(weightLogs) {
+		if point.Date >= from {
+			points = append(points, point)
+		}
+	}
+	if len(points) < 2 {
+		return nil, errs.NewUnprocessableError(errs.CodeNotEnoughData, "Weights at least 7 days apart are needed to estimate TDEE")
+	}
+	start, end := points[0], points[len(points)-1]
+	startDay, _ := time.Parse("2006-01-02", start.Date)
+	endDay, _ := time.Parse("2006-01-02", end.Date)
+	weighedSpan := endDay.Sub(startDay).Hours() / 24
+	if weighedSpan < minAdaptiveWeighedDays {
+		return nil, errs.NewUnprocessableError(errs.CodeNotEnoughData, "Weights at least 7 days apart are needed to estimate TDEE")
+	}
+	totalIntake := 0.0
+	for _, kcal := range intake {
+		totalIntake += kcal
+	}
+	averageIntake := totalIntake / float64(len(intake))
+	dailyChange := (end.Trend - start.Trend) / weighedSpan
+	tdee := averageIntake - dailyChange*kcalPerKgBodyWeight
+	goal := user.Goal
+	if goal == "" {
+		goal = "maintain"
+	}
+	return &AdaptiveTdeeResponse{
+		UserId:        userId,
+		From:          from,
+		To:            lastDay.Format("2006-01-02"),
+		Timezone:      timezone,
+		Weeks:         weeks,
+		LoggedDays:    len(intake),
+		AverageIntake: round(averageIntake),
+		StartTrend:    start.Trend,
+		EndTrend:      end.Trend,
+		WeeklyRate:    round2(dailyChange * 7),
+		Tdee:          round(tdee),
+		Goal:          goal,
+		Target:        goalTargets(tdee*goals[goal].energy, end.Trend, goal),
+	}, nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type tdeeServiceMock struct {
+	mock.Mock
+}
+
+func NewTdeeServiceMock() *tdeeServiceMock {
+	return &tdeeServiceMock{}
+}
+
+func (s *tdeeServiceMock) GetAdaptiveTdee(ctx context.Context, userId string, weeks int) (*AdaptiveTdeeResponse, error) {
+	args := s.Called(userId, weeks)
+	return args.Get(0).(*AdaptiveTdeeResponse), args.Error(1)
+}
@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	service "go-nutritioncalculator2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAdaptiveTdee(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	firstDay, lastDay := today.AddDate(0, 0, -14), today.AddDate(0, 0, -1)
+	readFrom, readTo := firstDay.AddDate(0, 0, -30), today
+	dailyRecords := func(weight float64) []repository.Record {
+		records := []repository.Record{}
+		for day := firstDay; day.Before(today); day = day.AddDate(0, 0, 1) {
+			records = append(records, repository.Record{Id: len(records) + 1, Protein: 150, Fat: 70, Carb: 250, Weight: weight, EventTimestamp: day.Add(12 * time.Hour)})
+		}
+		return records
+	}
+	t.Run("Success", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		weightLogRepo := repository.NewWeightLogRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Goal: "cut"}, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(readFrom, readTo)).Return(dailyRecords(0), nil)
+		weightLogRepo.On("GetWeightLogsByUserId", "gooddy20", &readFrom, &readTo).Return([]repository.WeightLog{
+			{Id: 1, Weight: 70, LoggedTimestamp: firstDay.Add(7 * time.Hour)},
+			{Id: 2, Weight: 69, LoggedTimestamp: lastDay.Add(7 * time.Hour)},
+		}, nil)
+		srv := service.NewTdeeService(userRepo, recordRepo, weightLogRepo)
+		result, err := srv.GetAdaptiveTdee(context.Background(), "gooddy20", 2)
+		expected := &service.AdaptiveTdeeResponse{
+			UserId:        "gooddy20",
+			From:          firstDay.Format("2006-01-02"),
+			To:            lastDay.Format("2006-01-02"),
+			Timezone:      "UTC",
+			Weeks:         2,
+			LoggedDays:    14,
+			AverageIntake: 2230,
+			StartTrend:    70,
+			EndTrend:      69.02,
+			WeeklyRate:    -0.53,
+			Tdee:          2810.5,
+			Goal:          "cut",
+			Target:        service.NutritionTotal{Protein: 151.8, Fat: 62.5, Carb: 269.7, Kcal: 2248.4},
+		}
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Success Case: Weight of Record", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		weightLogRepo := repository.NewWeightLogRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20"}, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(readFrom, readTo)).Return(dailyRecords(70), nil)
+		weightLogRepo.On("GetWeightLogsByUserId", "gooddy20", &readFrom, &readTo).Return([]repository.WeightLog{}, nil)
+		srv := service.NewTdeeService(userRepo, recordRepo, weightLogRepo)
+		result, err := srv.GetAdaptiveTdee(context.Background(), "gooddy20", 2)
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, 2230.0, result.Tdee)
+		assert.Equal(t, 0.0, result.WeeklyRate)
+		assert.Equal(t, "maintain", result.Goal)
+		assert.Equal(t, service.NutritionTotal{Protein: 126, Fat: 61.9, Carb: 292.1, Kcal: 2230}, result.Target)
+	})
+	t.Run("Not Enough Weight", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		weightLogRepo := repository.NewWeightLogRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20"}, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(readFrom, readTo)).Return(dailyRecords(0), nil)
+		weightLogRepo.On("GetWeightLogsByUserId", "gooddy20", &readFrom, &readTo).Return([]repository.WeightLog{
+			{Id: 1, Weight: 70, LoggedTimestamp: lastDay.AddDate(0, 0, -3)},
+			{Id: 2, Weight: 69, LoggedTimestamp: lastDay},
+		}, nil)
+		srv := service.NewTdeeService(userRepo, recordRepo, weightLogRepo)
+		_, err := srv.GetAdaptiveTdee(context.Background(), "gooddy20", 2)
+		assert.ErrorIs(t, err, errs.NewUnprocessableError(errs.CodeNotEnoughData, "Weights at least 7 days apart are needed to estimate TDEE"))
+	})
+	t.Run("Not Enough Logged Days", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		recordRepo := repository.NewRecordRepositoryMock()
+		weightLogRepo := repository.NewWeightLogRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20"}, nil)
+		recordRepo.On("GetRecordsByUserId", "gooddy20", between(readFrom, readTo)).Return(dailyRecords(70)[:6], nil)
+		weightLogRepo.On("GetWeightLogsByUserId", "gooddy20", &readFrom, &readTo).Return([]repository.WeightLog{}, nil)
+		srv := service.NewTdeeService(userRepo, recordRepo, weightLogRepo)
+		_, err := srv.GetAdaptiveTdee(context.Background(), "gooddy20", 2)
+		assert.ErrorIs(t, err, errs.NewUnprocessableError(errs.CodeNotEnoughData, "At least 7 logged days are needed to estimate TDEE"))
+	})
+	t.Run("Invalid Weeks", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		srv := service.NewTdeeService(userRepo, repository.NewRecordRepositoryMock(), repository.NewWeightLogRepositoryMock())
+		_, err := srv.GetAdaptiveTdee(context.Background(), "gooddy20", 13)
+		assert.ErrorIs(t, err, errs.NewValidationError("weeks", "Weeks need to be between 2 and 12"))
+		userRepo.AssertNotCalled(t, "GetUserById")
+	})
+	t.Run("No The User Id", func(t *testing.T) {
+		userRepo := repository.NewUserRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{}, repository.ErrNotFound)
+		srv := service.NewTdeeService(userRepo, repository.NewRecordRepositoryMock(), repository.NewWeightLogRepositoryMock())
+		_, err := srv.GetAdaptiveTdee(context.Background(), "gooddy20", 0)
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found"))
+	})
+}
@@ -1,33 +1,64 @@
 package service
 
+import (
+	"context"
+	"time"
+)
+
 type NewUserRequest struct {
-	UserId   string  `json:"user_id" example:"gooddy20" binding:"required"`      // "User Id"
-	Password string  `json:"password" example:"zxc123zxc123" binding:"required"` // "Password"
-	Username string  `json:"username" example:"GoodDy" binding:"required"`       // "Username"
-	Weight   float64 `json:"weight" example:"70"`                                // Default weight (kg.) of the "User"
-	Protein  float64 `json:"protein" example:"120"`                              // Default protein (g.) of the "User"
-	Fat      float64 `json:"fat" example:"60"`                                   // Default fat (g.) of the "User"
-	Carb     float64 `json:"carb" example:"120"`                                 // Default carb (g.) of the "User"
+	UserId           string  `json:"user_id" example:"gooddy20" binding:"required"`          // "User Id"
+	Password         string  `json:"password" example:"zxc123zxc123" binding:"required"`     // "Password"
+	Username         string  `json:"username" example:"GoodDy" binding:"required"`           // "Username"
+	Weight           float64 `json:"weight" example:"70" binding:"omitempty,min=20,max=500"` // Default weight (kg.) of the "User"
+	Protein          float64 `json:"protein" example:"120" binding:"min=0"`                  // Default protein (g.) of the "User"
+	Fat              float64 `json:"fat" example:"60" binding:"min=0"`                       // Default fat (g.) of the "User"
+	Carb             float64 `json:"carb" example:"120" binding:"min=0"`                     // Default carb (g.) of the "User"
+	Timezone         string  `json:"timezone" example:"Asia/Bangkok"`                        // IANA time zone that the days of the "User" are counted in, default = "UTC"
+	Sex              string  `json:"sex" example:"male"`                                     // "male" or "female"
+	BirthDate        string  `json:"birth_date" example:"1993-04-20"`                        // Birth date of the "User" *format="2023-01-01"
+	Height           float64 `json:"height" example:"175"`                                   // Height (cm.) of the "User"
+	ActivityLevel    string  `json:"activity_level" example:"moderate"`                      // "sedentary", "light", "moderate", "active" or "very_active"
+	Goal             string  `json:"goal" example:"cut"`                                     // "cut", "maintain" or "bulk"
+	AutoUpdateMenues bool    `json:"auto_update_menues" example:"true"`                      // "true" = favorite menues and favorite lists follow the newest version of an updated "Menu"
 }
 
 type UpdateUserRequest struct {
-	UserId         string  `json:"user_id" example:"gooddy20" binding:"required"` // "User Id"
-	Password       string  `json:"password" example:"zxc123zxc456"`               // "Password" that you want to change
-	Username       string  `json:"username" example:"GooDDy19"`                   // "Username" that you want to change to
-	Weight         float64 `json:"weight" example:"72"`                           // Weight (kg.) that you want to change to
-	Protein        float64 `json:"protein" example:"150"`                         // Protein (g.) that you want to change to
-	Fat            float64 `json:"fat" example:"70"`                              // Fat (g.) that you want to change to
-	Carb           float64 `json:"carb" example:"160"`                            // Carb that you want to change to
-	FavoriteMenues string  `json:"favorite_menues" example:"4,7,9,10,11"`         // Favorite Menues's id that you want to change to e.g. "9,10" 9 = "Moo Yang" and 10 = "Sticky Rice" so this "User" got "Moo Yang" and "Sticky Rice" as "Favorite Menu"
+	UserId                string            `json:"user_id" example:"gooddy20" binding:"required"`                               // "User Id"
+	Password              Nullable[string]  `json:"password" swaggertype:"string" example:"zxc123zxc456" binding:"notnull"`      // "Password" that you want to change, it can not be null
+	Username              Nullable[string]  `json:"username" swaggertype:"string" example:"GooDDy19" binding:"notnull"`          // "Username" that you want to change to, it can not be null
+	Weight                Nullable[float64] `json:"weight" swaggertype:"number" example:"72" binding:"omitempty,min=20,max=500"` // Weight (kg.) that you want to change to, null = clear
+	Protein               Nullable[float64] `json:"protein" swaggertype:"number" example:"150" binding:"min=0"`                  // Protein (g.) that you want to change to, null = clear
+	Fat                   Nullable[float64] `json:"fat" swaggertype:"number" example:"70" binding:"min=0"`                       // Fat (g.) that you want to change to, null = clear
+	Carb                  Nullable[float64] `json:"carb" swaggertype:"number" example:"160" binding:"min=0"`                     // Carb that you want to change to, null = clear
+	FavoriteMenues        Nullable[string]  `json:"favorite_menues" swaggertype:"string" example:"4,7,9,10,11"`                  // Favorite Menues's id that you want to change to e.g. "9,10" 9 = "Moo Yang" and 10 = "Sticky Rice" so this "User" got "Moo Yang" and "Sticky Rice" as "Favorite Menu", null or "" = clear
+	FavoriteMenuIds       Nullable[[]int]   `json:"favorite_menu_ids" swaggertype:"array,integer" example:"4,7,9,10,11"`         // Favorite Menues's id that you want to change to, it is used instead of "favorite_menues" when it is set, null or [] = clear
+	Timezone              Nullable[string]  `json:"timezone" swaggertype:"string" example:"Asia/Bangkok"`                        // IANA time zone that you want to change to, null = UTC
+	Sex                   Nullable[string]  `json:"sex" swaggertype:"string" example:"male"`                                     // "male" or "female" that you want to change to, null = clear
+	BirthDate             Nullable[string]  `json:"birth_date" swaggertype:"string" example:"1993-04-20"`                        // Birth date that you want to change to *format="2023-01-01", null = clear
+	Height                Nullable[float64] `json:"height" swaggertype:"number" example:"176"`                                   // Height (cm.) that you want to change to, null = clear
+	ActivityLevel         Nullable[string]  `json:"activity_level" swaggertype:"string" example:"active"`                        // "sedentary", "light", "moderate", "active" or "very_active" that you want to change to, null = clear
+	Goal                  Nullable[string]  `json:"goal" swaggertype:"string" example:"maintain"`                                // "cut", "maintain" or "bulk" that you want to change to, null = clear
+	ApplySuggestedTargets bool              `json:"apply_suggested_targets" example:"false"`                                     // "true" = replace protein, fat and carb with the targets suggested from the updated profile
+	AutoUpdateMenues      Nullable[bool]    `json:"auto_update_menues" swaggertype:"boolean" example:"true"`                     // "true" = favorite menues and favorite lists follow the newest version of an updated "Menu", null = "false"
+	Revision              int               `json:"-"`                                                                           // Revision from the "If-Match" header, 0 = update whatever the current revision is
 }
 
 type UserResponse struct {
-	Username       string  `json:"username" example:"GoodDy"`      // "Username"
-	Weight         float64 `json:"weight" example:"62"`            // Default weight (kg.) of the "User"
-	Protein        float64 `json:"protein" example:"140"`          // Default protein (g.) of the "User"
-	Fat            float64 `json:"fat" example:"40"`               // Default fat (g.) of the "User"
-	Carb           float64 `json:"carb" example:"130"`             // Default carb (g.) of the "User"
-	FavoriteMenues string  `json:"favorite_menues" example:"9,10"` // Favorite Menues's id e.g. "9,10" 9 = "Moo Yang" and 10 = "Sticky Rice" so this "User" got "Moo Yang" and "Sticky Rice" as "Favorite Menu"
+	Username         string  `json:"username" example:"GoodDy"`         // "Username"
+	Weight           float64 `json:"weight" example:"62"`               // Default weight (kg.) of the "User"
+	Protein          float64 `json:"protein" example:"140"`             // Default protein (g.) of the "User"
+	Fat              float64 `json:"fat" example:"40"`                  // Default fat (g.) of the "User"
+	Carb             float64 `json:"carb" example:"130"`                // Default carb (g.) of the "User"
+	FavoriteMenues   string  `json:"favorite_menues" example:"9,10"`    // Favorite Menues's id e.g. "9,10" 9 = "Moo Yang" and 10 = "Sticky Rice" so this "User" got "Moo Yang" and "Sticky Rice" as "Favorite Menu"
+	FavoriteMenuIds  []int   `json:"favorite_menu_ids" example:"9,10"`  // Favorite Menues's id
+	Timezone         string  `json:"timezone" example:"Asia/Bangkok"`   // IANA time zone that the days of the "User" are counted in
+	Sex              string  `json:"sex" example:"male"`                // "male" or "female", "" = not set
+	BirthDate        string  `json:"birth_date" example:"1993-04-20"`   // Birth date of the "User", "" = not set
+	Height           float64 `json:"height" example:"175"`              // Height (cm.) of the "User", 0 = not set
+	ActivityLevel    string  `json:"activity_level" example:"moderate"` // Activity level of the "User", "" = not set
+	Goal             string  `json:"goal" example:"cut"`                // Goal of the "User", "" = not set
+	AutoUpdateMenues bool    `json:"auto_update_menues" example:"true"` // "true" = favorite menues and favorite lists follow the newest version of an updated "Menu"
+	Revision         int     `json:"revision" example:"3"`              // Revision of the "User" that is also sent as the "ETag" header
 }
 
 type LogInRequest struct {
@@ -36,13 +67,16 @@ type LogInRequest struct {
 }
 
 type LogInResponse struct {
-	IsLogIn bool `json:"IsLogIn" example:"true"` // "true" = Pass, "false" = Incorrect "User Id" or "Password"
+	IsLogIn     bool       `json:"IsLogIn" example:"true"`                                                   // "true" = Pass, "false" = Incorrect "User Id" or "Password"
+	AccessToken string     `json:"access_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // Signed access token that need to be sent as "Authorization: Bearer <token>" (only when "IsLogIn" = "true")
+	TokenType   string     `json:"token_type,omitempty" example:"Bearer"`                                    // Type of the access token
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" example:"2023-12-06T10:00:00Z"`                      // Timestamp that the access token is expired
 }
 
 type UserService interface {
-	CheckLogIn(LogInRequest) (*LogInResponse, error)
-	GetUserDetail(string) (*UserResponse, error)
-	CreateUser(NewUserRequest) error
-	UpdateUser(UpdateUserRequest) error
-	RecoverFavoriteMenues(string, int) error
+	CheckLogIn(context.Context, LogInRequest) (*LogInResponse, error)
+	GetUserDetail(context.Context, string) (*UserResponse, error)
+	CreateUser(context.Context, NewUserRequest) error
+	UpdateUser(context.Context, UpdateUserRequest) error
+	RecoverFavoriteMenues(context.Context, string, int) error
 }
@@ -1,61 +1,90 @@
 package service
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"go-nutritioncalculator2/errs"
 	"go-nutritioncalculator2/logs"
 	repository "go-nutritioncalculator2/repositories"
-	"net/http"
 	"regexp"
-	"strconv"
-	"strings"
 	"time"
 )
 
 type userService struct {
 	userRepo repository.UserRepository
+	menuRepo repository.MenuRepository
 }
 
-func NewUserService(userRepo repository.UserRepository) userService {
-	return userService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepository, menuRepo repository.MenuRepository) userService {
+	return userService{userRepo: userRepo, menuRepo: menuRepo}
 }
 
-func (s userService) CheckLogIn(logInReq LogInRequest) (*LogInResponse, error) {
-	user, err := s.userRepo.GetUserById(logInReq.UserId)
+// "User Id" and "Username" need atleast 6 letters and "Password" need atleast 6 characters without whitespace
+var (
+	userIdPattern   = regexp.MustCompile(`\w{6,}`)
+	usernamePattern = regexp.MustCompile(`\w{6,}`)
+	passwordPattern = regexp.MustCompile(`\S{6,}`)
+)
+
+func (s userService) CheckLogIn(ctx context.Context, logInReq LogInRequest) (*LogInResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, logInReq.UserId)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, repository.ErrNotFound) {
+			verifyPassword(dummyPasswordHash, logInReq.Password)
 			return &LogInResponse{IsLogIn: false}, nil
 		}
-		logs.Error(err)
-		return nil, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
 	}
-	if user.Password == logInReq.Password {
-		return &LogInResponse{IsLogIn: true}, nil
+	isMatch, needRehash := verifyPassword(user.Password, logInReq.Password)
+	if !isMatch {
+		return &LogInResponse{IsLogIn: false}, nil
 	}
-	return &LogInResponse{IsLogIn: false}, nil
+	if needRehash {
+		hashedPassword, err := hashPassword(logInReq.Password)
+		if err != nil {
+			logs.Error(err)
+			return &LogInResponse{IsLogIn: true}, nil
+		}
+		user.Password = hashedPassword
+		err = s.userRepo.UpdateUser(ctx, *user)
+		if err != nil {
+			logs.Error(err)
+		}
+	}
+	return &LogInResponse{IsLogIn: true}, nil
 }
 
-func (s userService) GetUserDetail(userId string) (*UserResponse, error) {
-	user, err := s.userRepo.GetUserById(userId)
+func (s userService) GetUserDetail(ctx context.Context, userId string) (*UserResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, userId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id is not found"}
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
 		}
-		logs.Error(err)
-		return nil, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return nil, repositoryError(err)
 	}
 	userRes := UserResponse{
-		Username:       user.Username,
-		Weight:         user.Weight,
-		Protein:        user.Protein,
-		Fat:            user.Fat,
-		Carb:           user.Carb,
-		FavoriteMenues: user.FavoriteMenues,
+		Username:         user.Username,
+		Weight:           user.Weight,
+		Protein:          user.Protein,
+		Fat:              user.Fat,
+		Carb:             user.Carb,
+		FavoriteMenues:   toMenuIdList(user.FavoriteMenues),
+		FavoriteMenuIds:  append([]int{}, user.FavoriteMenues...),
+		Timezone:         user.Timezone,
+		Sex:              user.Sex,
+		Height:           user.Height,
+		ActivityLevel:    user.ActivityLevel,
+		Goal:             user.Goal,
+		AutoUpdateMenues: user.AutoUpdateMenues,
+		Revision:         user.Revision,
+	}
+	if user.BirthDate != nil {
+		userRes.BirthDate = user.BirthDate.Format("2006-01-02")
 	}
 	return &userRes, nil
 }
 
-func (s userService) CreateUser(newUser NewUserRequest) error {
+func (s userService) CreateUser(ctx context.Context, newUser NewUserRequest) error {
 	user := repository.User{
 		UserId:           newUser.UserId,
 		Password:         newUser.Password,
@@ -64,131 +93,179 @@ func (s userService) CreateUser(newUser NewUserRequest) error {
 		Protein:          newUser.Protein,
 		Fat:              newUser.Fat,
 		Carb:             newUser.Carb,
-		FavoriteMenues:   "",
+		Timezone:         "UTC",
+		Sex:              newUser.Sex,
+		Height:           newUser.Height,
+		ActivityLevel:    newUser.ActivityLevel,
+		Goal:             newUser.Goal,
+		AutoUpdateMenues: newUser.AutoUpdateMenues,
+		FavoriteMenues:   []int{},
 		CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
 	}
+	v := validateRequest(newUser)
 	var err error
-	var isOk bool
-	isOk, err = regexp.MatchString(`\w{6,}`, user.UserId)
-	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
-	} else if len(user.UserId) < 6 || !isOk {
-		return errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id need to contain more than 5 letter and alphabet only"}
+	user.BirthDate, err = checkProfile(newUser.Sex, newUser.BirthDate, newUser.Height, newUser.ActivityLevel, newUser.Goal)
+	if err = v.check(err); err != nil {
+		return err
 	}
-	isOk, err = regexp.MatchString(`\S{6,}`, user.Password)
-	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
-	} else if len(user.Password) < 6 || !isOk {
-		return errs.AppError{Code: http.StatusNotAcceptable, Message: "Password need to contain more than 5 letter and no whitespace"}
+	if newUser.Timezone != "" {
+		_, err = loadTimezone(newUser.Timezone)
+		if err = v.check(err); err != nil {
+			return err
+		}
+		user.Timezone = newUser.Timezone
 	}
-	isOk, err = regexp.MatchString(`\w{6,}`, user.Username)
-	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
-	} else if len(user.Username) < 6 || !isOk {
-		return errs.AppError{Code: http.StatusNotAcceptable, Message: "Username need to contain more than 5 letter and alphabet only"}
+	if !v.has("user_id") && !userIdPattern.MatchString(user.UserId) {
+		v.add("user_id", "User Id need to contain more than 5 letter and alphabet only")
 	}
-	_, err = s.userRepo.GetUserById(user.UserId)
+	if !v.has("password") && !passwordPattern.MatchString(user.Password) {
+		v.add("password", "Password need to contain more than 5 letter and no whitespace")
+	}
+	if !v.has("username") && !usernamePattern.MatchString(user.Username) {
+		v.add("username", "Username need to contain more than 5 letter and alphabet only")
+	}
+	if err = v.err(); err != nil {
+		return err
+	}
+	_, err = s.userRepo.GetUserById(ctx, user.UserId)
 	if err == nil {
-		return errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id is already used"}
-	} else if err != sql.ErrNoRows {
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return errs.NewConflictError(errs.CodeUserIdTaken, "User Id is already used")
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		logs.Error(err)
+		return errs.NewUnexpectedError()
 	}
-	_, err = s.userRepo.GetUserByUsername(user.Username)
+	_, err = s.userRepo.GetUserByUsername(ctx, user.Username)
 	if err == nil {
-		return errs.AppError{Code: http.StatusNotAcceptable, Message: "Username is already used"}
-	} else if err != sql.ErrNoRows {
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return errs.NewConflictError(errs.CodeUsernameTaken, "Username is already used")
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		logs.Error(err)
+		return errs.NewUnexpectedError()
 	}
-	err = s.userRepo.CreateUser(user)
+	user.Password, err = hashPassword(user.Password)
 	if err != nil {
 		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return errs.NewUnexpectedError()
+	}
+	err = s.userRepo.CreateUser(ctx, user)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return errs.NewConflictError(errs.CodeUserAlreadyExists, "User Id or Username is already used")
+		}
+		return repositoryError(err)
 	}
 	return nil
 }
 
-func (s userService) UpdateUser(newUpdateUser UpdateUserRequest) error {
+func (s userService) UpdateUser(ctx context.Context, newUpdateUser UpdateUserRequest) error {
+	v := validateRequest(newUpdateUser)
+	var favoriteMenues []int
 	var err error
-	var isOk bool
-	updateUser := repository.User{
-		UserId:         newUpdateUser.UserId,
-		Password:       newUpdateUser.Password,
-		Username:       newUpdateUser.Username,
-		Weight:         newUpdateUser.Weight,
-		Protein:        newUpdateUser.Protein,
-		Fat:            newUpdateUser.Fat,
-		Carb:           newUpdateUser.Carb,
-		FavoriteMenues: newUpdateUser.FavoriteMenues,
-	}
-	user, err := s.userRepo.GetUserById(updateUser.UserId)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id is not found"}
+	if newUpdateUser.FavoriteMenuIds.Set {
+		favoriteMenues, err = toMenuIds("", newUpdateUser.FavoriteMenuIds.Value)
+	} else if newUpdateUser.FavoriteMenues.Set {
+		favoriteMenues, err = toMenuIds(newUpdateUser.FavoriteMenues.Value, nil)
+	}
+	if err = v.check(err); err != nil {
+		return err
+	}
+	birthDate, err := checkProfile(newUpdateUser.Sex.Value, newUpdateUser.BirthDate.Value, newUpdateUser.Height.Value, newUpdateUser.ActivityLevel.Value, newUpdateUser.Goal.Value)
+	if err = v.check(err); err != nil {
+		return err
+	}
+	if newUpdateUser.Timezone.Value != "" {
+		_, err = loadTimezone(newUpdateUser.Timezone.Value)
+		if err = v.check(err); err != nil {
+			return err
 		}
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
 	}
-	if updateUser.Password != "" {
-		isOk, err = regexp.MatchString(`\S{6,}`, updateUser.Password)
-		if err != nil {
-			logs.Error(err)
-			return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
-		} else if len(updateUser.Password) < 6 || !isOk {
-			return errs.AppError{Code: http.StatusNotAcceptable, Message: "Password need to contain more than 5 letter and no whitespace"}
+	if newUpdateUser.Password.Set && !v.has("password") && !passwordPattern.MatchString(newUpdateUser.Password.Value) {
+		v.add("password", "Password need to contain more than 5 letter and no whitespace")
+	}
+	if newUpdateUser.Username.Set && !v.has("username") && !usernamePattern.MatchString(newUpdateUser.Username.Value) {
+		v.add("username", "Username need to contain more than 5 letter and alphabet only")
+	}
+	if err = v.err(); err != nil {
+		return err
+	}
+	user, err := s.userRepo.GetUserById(ctx, newUpdateUser.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
 		}
-	} else {
-		updateUser.Password = user.Password
+		return repositoryError(err)
 	}
-	if updateUser.Username != "" {
-		isOk, err = regexp.MatchString(`\w{6,}`, updateUser.Username)
+	err = checkRevision("User", newUpdateUser.Revision, user.Revision)
+	if err != nil {
+		return err
+	}
+	if newUpdateUser.FavoriteMenuIds.Set {
+		err = checkFavoriteMenues(ctx, s.menuRepo, "favorite_menu_ids", favoriteMenues, user.FavoriteMenues)
+	} else if newUpdateUser.FavoriteMenues.Set {
+		err = checkFavoriteMenues(ctx, s.menuRepo, "favorite_menues", favoriteMenues, user.FavoriteMenues)
+	}
+	if err != nil {
+		return err
+	}
+	updateUser := *user
+	if newUpdateUser.Password.Set {
+		updateUser.Password, err = hashPassword(newUpdateUser.Password.Value)
 		if err != nil {
 			logs.Error(err)
-			return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
-		} else if len(updateUser.Username) < 6 || !isOk {
-			return errs.AppError{Code: http.StatusNotAcceptable, Message: "Username need to contain more than 5 letter and alphabet only"}
+			return errs.NewUnexpectedError()
 		}
-	} else {
-		updateUser.Username = user.Username
-	}
-	if updateUser.Weight == 0 {
-		updateUser.Weight = user.Weight
-	}
-	if updateUser.Protein == 0 {
-		updateUser.Protein = user.Protein
 	}
-	if updateUser.Fat == 0 {
-		updateUser.Fat = user.Fat
+	updateUser.Username = newUpdateUser.Username.Apply(user.Username)
+	updateUser.Weight = newUpdateUser.Weight.Apply(user.Weight)
+	updateUser.Protein = newUpdateUser.Protein.Apply(user.Protein)
+	updateUser.Fat = newUpdateUser.Fat.Apply(user.Fat)
+	updateUser.Carb = newUpdateUser.Carb.Apply(user.Carb)
+	updateUser.Timezone = newUpdateUser.Timezone.Apply(user.Timezone)
+	updateUser.Sex = newUpdateUser.Sex.Apply(user.Sex)
+	if newUpdateUser.BirthDate.Set {
+		updateUser.BirthDate = birthDate
 	}
-	if updateUser.Carb == 0 {
-		updateUser.Carb = user.Carb
+	updateUser.Height = newUpdateUser.Height.Apply(user.Height)
+	updateUser.ActivityLevel = newUpdateUser.ActivityLevel.Apply(user.ActivityLevel)
+	updateUser.Goal = newUpdateUser.Goal.Apply(user.Goal)
+	updateUser.AutoUpdateMenues = newUpdateUser.AutoUpdateMenues.Apply(user.AutoUpdateMenues)
+	if newUpdateUser.FavoriteMenuIds.Set || newUpdateUser.FavoriteMenues.Set {
+		updateUser.FavoriteMenues = favoriteMenues
 	}
-	if updateUser.FavoriteMenues == "" && (repository.User{UserId: newUpdateUser.UserId, Password: newUpdateUser.Password, Username: newUpdateUser.Username, Weight: newUpdateUser.Weight, Protein: newUpdateUser.Protein, Fat: newUpdateUser.Fat, Carb: newUpdateUser.Carb, FavoriteMenues: newUpdateUser.FavoriteMenues}) != (repository.User{UserId: newUpdateUser.UserId}) {
-		updateUser.FavoriteMenues = user.FavoriteMenues
+	if newUpdateUser.ApplySuggestedTargets {
+		location, err := loadTimezone(updateUser.Timezone)
+		if err != nil {
+			return err
+		}
+		targets, err := suggestTargets(updateUser, time.Now().In(location))
+		if err != nil {
+			return err
+		}
+		updateUser.Protein, updateUser.Fat, updateUser.Carb = targets.Target.Protein, targets.Target.Fat, targets.Target.Carb
 	}
-	err = s.userRepo.UpdateUser(updateUser)
+	err = s.userRepo.UpdateUser(ctx, updateUser)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrConflict) {
+			return errs.NewConflictError(errs.CodeUsernameTaken, "Username is already used")
+		}
+		if errors.Is(err, repository.ErrStale) {
+			return newStaleError("User")
+		}
+		return repositoryError(err)
 	}
 	return nil
 }
 
-func (s userService) RecoverFavoriteMenues(userId string, deletedMenuId int) error {
-	user, err := s.userRepo.GetUserById(userId)
+func (s userService) RecoverFavoriteMenues(ctx context.Context, userId string, deletedMenuId int) error {
+	user, err := s.userRepo.GetUserById(ctx, userId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id is not found"}
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
 		}
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		return repositoryError(err)
 	}
-	tempFavoriteMenues := strings.Split(user.FavoriteMenues, ",")
 	index := -1
-	for i := 0; i < len(tempFavoriteMenues); i++ {
-		if tempFavoriteMenues[i] == strconv.Itoa(deletedMenuId) {
+	for i := 0; i < len(user.FavoriteMenues); i++ {
+		if user.FavoriteMenues[i] == deletedMenuId {
 			index = i
 			break
 		}
@@ -196,18 +273,23 @@ func (s userService) RecoverFavoriteMenues(userId string, deletedMenuId int) err
 	if index == -1 {
 		return nil
 	}
-	if index == 0 {
-		tempFavoriteMenues = tempFavoriteMenues[1:]
-	} else if index == len(tempFavoriteMenues)-1 {
-		tempFavoriteMenues = tempFavoriteMenues[:len(tempFavoriteMenues)-1]
-	} else {
-		tempFavoriteMenues = append(tempFavoriteMenues[:index], tempFavoriteMenues[index+1:]...)
-	}
-	user.FavoriteMenues = strings.Join(tempFavoriteMenues, ",")
-	err = s.userRepo.UpdateUser(*user)
+	favoriteMenues := append([]int{}, user.FavoriteMenues[:index]...)
+	user.FavoriteMenues = append(favoriteMenues, user.FavoriteMenues[index+1:]...)
+	err = s.userRepo.UpdateUser(ctx, *user)
 	if err != nil {
-		logs.Error(err)
-		return errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"}
+		if errors.Is(err, repository.ErrStale) {
+			return newStaleError("User")
+		}
+		return repositoryError(err)
 	}
 	return nil
 }
+
+// loadTimezone resolves the IANA time zone of a "User"
+func loadTimezone(name string) (*time.Location, error) {
+	location, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, errs.NewValidationError("timezone", `Timezone need to be an IANA time zone e.g. "Asia/Bangkok"`)
+	}
+	return location, nil
+}
@@ -1,18 +1,30 @@
 package service_test
 
 import (
+	"context"
 	"database/sql"
 	"go-nutritioncalculator2/errs"
 	repository "go-nutritioncalculator2/repositories"
 	service "go-nutritioncalculator2/services"
-	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// matchHashedUser matches the user whose password is the bcrypt hash of the plaintext password
+func matchHashedUser(expected repository.User, password string) interface{} {
+	return mock.MatchedBy(func(user repository.User) bool {
+		hashedPassword := user.Password
+		user.Password = expected.Password
+		return assert.ObjectsAreEqual(expected, user) && bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil
+	})
+}
+
 func TestCheckLogIn(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correctPassword"), bcrypt.MinCost)
 	type testCase struct {
 		Name     string
 		Request  service.LogInRequest
@@ -25,26 +37,59 @@ func TestCheckLogIn(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
 			repo := repository.NewUserRepositoryMock()
-			repo.On("GetUserById", c.Request.UserId).Return(&repository.User{UserId: c.Request.UserId, Password: "correctPassword"}, nil)
-			srv := service.NewUserService(repo)
-			result, _ := srv.CheckLogIn(c.Request)
+			repo.On("GetUserById", c.Request.UserId).Return(&repository.User{UserId: c.Request.UserId, Password: string(hashedPassword)}, nil)
+			menuRepo := repository.NewMenuRepositoryMock()
+			srv := service.NewUserService(repo, menuRepo)
+			result, _ := srv.CheckLogIn(context.Background(), c.Request)
 			assert.Equal(t, c.Expected, result)
+			repo.AssertNotCalled(t, "UpdateUser")
 		})
 	}
+	t.Run("Success Case: Correct Plaintext Password", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Password: "correctPassword"}, nil)
+		repo.On("UpdateUser", matchHashedUser(repository.User{UserId: "gooddy20"}, "correctPassword")).Return(nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		result, _ := srv.CheckLogIn(context.Background(), service.LogInRequest{UserId: "gooddy20", Password: "correctPassword"})
+		assert.Equal(t, &service.LogInResponse{IsLogIn: true}, result)
+		repo.AssertNumberOfCalls(t, "UpdateUser", 1)
+	})
+	t.Run("Success Case: Incorrect Plaintext Password", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Password: "correctPassword"}, nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		result, _ := srv.CheckLogIn(context.Background(), service.LogInRequest{UserId: "gooddy20", Password: "whatPassword"})
+		assert.Equal(t, &service.LogInResponse{IsLogIn: false}, result)
+		repo.AssertNotCalled(t, "UpdateUser")
+	})
+	t.Run("Success Case: Rehash Plaintext Password Database Error", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Password: "correctPassword"}, nil)
+		repo.On("UpdateUser", matchHashedUser(repository.User{UserId: "gooddy20"}, "correctPassword")).Return(sql.ErrConnDone)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		result, err := srv.CheckLogIn(context.Background(), service.LogInRequest{UserId: "gooddy20", Password: "correctPassword"})
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, &service.LogInResponse{IsLogIn: true}, result)
+	})
 	t.Run("Success Case: No The User Id", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
-		repo.On("GetUserById", "gooddy19").Return(&repository.User{}, sql.ErrNoRows)
-		srv := service.NewUserService(repo)
-		result, _ := srv.CheckLogIn(service.LogInRequest{UserId: "gooddy19", Password: "correctPassword"})
+		repo.On("GetUserById", "gooddy19").Return(&repository.User{}, repository.ErrNotFound)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		result, _ := srv.CheckLogIn(context.Background(), service.LogInRequest{UserId: "gooddy19", Password: "correctPassword"})
 		assert.Equal(t, &service.LogInResponse{IsLogIn: false}, result)
 	})
 
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
 		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, sql.ErrConnDone)
-		srv := service.NewUserService(repo)
-		_, err := srv.CheckLogIn(service.LogInRequest{UserId: "gooddy20", Password: "correctPassword"})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		_, err := srv.CheckLogIn(context.Background(), service.LogInRequest{UserId: "gooddy20", Password: "correctPassword"})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 	})
 }
 
@@ -60,34 +105,38 @@ func TestGetUserDetail(t *testing.T) {
 				Protein:          120,
 				Fat:              60,
 				Carb:             120,
-				FavoriteMenues:   "11,12",
+				FavoriteMenues:   []int{11, 12},
 				CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 			}, nil)
-		srv := service.NewUserService(repo)
-		result, _ := srv.GetUserDetail("gooddy20")
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		result, _ := srv.GetUserDetail(context.Background(), "gooddy20")
 		expected := &service.UserResponse{
-			Username:       "GoodDy",
-			Weight:         71,
-			Protein:        120,
-			Fat:            60,
-			Carb:           120,
-			FavoriteMenues: "11,12",
+			Username:        "GoodDy",
+			Weight:          71,
+			Protein:         120,
+			Fat:             60,
+			Carb:            120,
+			FavoriteMenues:  "11,12",
+			FavoriteMenuIds: []int{11, 12},
 		}
 		assert.Equal(t, expected, result)
 	})
 	t.Run("No The User Id", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
-		repo.On("GetUserById", "gooddy19").Return(&repository.User{}, sql.ErrNoRows)
-		srv := service.NewUserService(repo)
-		_, err := srv.GetUserDetail("gooddy19")
-		assert.Equal(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id is not found"})
+		repo.On("GetUserById", "gooddy19").Return(&repository.User{}, repository.ErrNotFound)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		_, err := srv.GetUserDetail(context.Background(), "gooddy19")
+		assert.Equal(t, err, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found"))
 	})
 	t.Run("Database Error", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
 		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, sql.ErrConnDone)
-		srv := service.NewUserService(repo)
-		_, err := srv.GetUserDetail("gooddy20")
-		assert.Equal(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		_, err := srv.GetUserDetail(context.Background(), "gooddy20")
+		assert.Equal(t, err, errs.NewUnexpectedError())
 	})
 }
 
@@ -98,36 +147,52 @@ func TestCreateUser(t *testing.T) {
 		Expected error
 	}
 	invalid_parameter_cases := []TestCase{
-		{Name: "Invalid User Id", Request: service.NewUserRequest{UserId: "good", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0}, Expected: errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id need to contain more than 5 letter and alphabet only"}},
-		{Name: "Invalid Password", Request: service.NewUserRequest{UserId: "gooddy21", Password: "p a s s", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0}, Expected: errs.AppError{Code: http.StatusNotAcceptable, Message: "Password need to contain more than 5 letter and no whitespace"}},
-		{Name: "Invalid Username", Request: service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "Good", Weight: 68, Protein: 0, Fat: 0, Carb: 0}, Expected: errs.AppError{Code: http.StatusNotAcceptable, Message: "Username need to contain more than 5 letter and alphabet only"}},
+		{Name: "Invalid User Id", Request: service.NewUserRequest{UserId: "good", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0}, Expected: errs.NewValidationError("user_id", "User Id need to contain more than 5 letter and alphabet only")},
+		{Name: "Invalid Password", Request: service.NewUserRequest{UserId: "gooddy21", Password: "p a s s", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0}, Expected: errs.NewValidationError("password", "Password need to contain more than 5 letter and no whitespace")},
+		{Name: "Invalid Username", Request: service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "Good", Weight: 68, Protein: 0, Fat: 0, Carb: 0}, Expected: errs.NewValidationError("username", "Username need to contain more than 5 letter and alphabet only")},
+		{Name: "Invalid Timezone", Request: service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Timezone: "Mars/Olympus"}, Expected: errs.NewValidationError("timezone", `Timezone need to be an IANA time zone e.g. "Asia/Bangkok"`)},
+		{Name: "Invalid Sex", Request: service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Sex: "m"}, Expected: errs.NewValidationError("sex", `Sex need to be "male" or "female"`)},
+		{Name: "Invalid Birth Date", Request: service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, BirthDate: "20/04/1993"}, Expected: errs.NewValidationError("birth_date", `Birth date need to be in the format "2023-01-01"`)},
+		{Name: "Invalid Activity Level", Request: service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, ActivityLevel: "extreme"}, Expected: errs.NewValidationError("activity_level", `Activity level need to be "sedentary", "light", "moderate", "active" or "very_active"`)},
+		{Name: "Invalid Goal", Request: service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Goal: "recomp"}, Expected: errs.NewValidationError("goal", `Goal need to be "cut", "maintain" or "bulk"`)},
+		{Name: "Missing User Id", Request: service.NewUserRequest{Password: "correctPassword", Username: "GoodDyZa", Weight: 68}, Expected: errs.NewValidationError("user_id", "User Id is required")},
+		{Name: "Implausible Weight", Request: service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 7}, Expected: errs.NewValidationError("weight", "Weight need to be between 20 and 500")},
+		{Name: "Negative Protein", Request: service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: -1}, Expected: errs.NewValidationError("protein", "Protein can not be negative")},
+		{Name: "Several Invalid Fields", Request: service.NewUserRequest{UserId: "good", Password: "p a s s", Username: "GoodDyZa", Weight: 600, Sex: "m"}, Expected: errs.NewValidationErrors([]errs.FieldError{
+			{Field: "weight", Message: "Weight need to be between 20 and 500"},
+			{Field: "sex", Message: `Sex need to be "male" or "female"`},
+			{Field: "user_id", Message: "User Id need to contain more than 5 letter and alphabet only"},
+			{Field: "password", Message: "Password need to contain more than 5 letter and no whitespace"},
+		})},
 	}
 	t.Run("Success", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
 		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, nil)
-		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, sql.ErrNoRows)
+		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, repository.ErrNotFound)
 		repo.On("GetUserByUsername", "GoodDy").Return(&repository.User{}, nil)
-		repo.On("GetUserByUsername", "GoodDyZa").Return(&repository.User{}, sql.ErrNoRows)
-		repo.On("CreateUser", repository.User{
+		repo.On("GetUserByUsername", "GoodDyZa").Return(&repository.User{}, repository.ErrNotFound)
+		repo.On("CreateUser", matchHashedUser(repository.User{
 			UserId:           "gooddy21",
-			Password:         "correctPassword",
 			Username:         "GoodDyZa",
 			Weight:           68,
 			Protein:          0,
 			Fat:              0,
 			Carb:             0,
-			FavoriteMenues:   "",
+			Timezone:         "UTC",
+			FavoriteMenues:   []int{},
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
-		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.CreateUser(service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
+		}, "correctPassword")).Return(nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.CreateUser(context.Background(), service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
 		assert.ErrorIs(t, err, nil)
 	})
 	for _, c := range invalid_parameter_cases {
 		t.Run(c.Name, func(t *testing.T) {
 			repo := repository.NewUserRepositoryMock()
-			srv := service.NewUserService(repo)
-			err := srv.CreateUser(c.Request)
+			menuRepo := repository.NewMenuRepositoryMock()
+			srv := service.NewUserService(repo, menuRepo)
+			err := srv.CreateUser(context.Background(), c.Request)
 			assert.ErrorIs(t, err, c.Expected)
 			repo.AssertNotCalled(t, "GetUserById")
 			repo.AssertNotCalled(t, "GetUserByUsername")
@@ -137,60 +202,75 @@ func TestCreateUser(t *testing.T) {
 	t.Run("User Id is already used", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
 		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, nil)
-		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, sql.ErrNoRows)
-		srv := service.NewUserService(repo)
-		err := srv.CreateUser(service.NewUserRequest{UserId: "gooddy20", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id is already used"})
+		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, repository.ErrNotFound)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.CreateUser(context.Background(), service.NewUserRequest{UserId: "gooddy20", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeUserIdTaken, "User Id is already used"))
 		repo.AssertNotCalled(t, "GetUserByUsername")
 		repo.AssertNotCalled(t, "CreateUser")
 	})
 	t.Run("Get User Database Error", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
 		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, sql.ErrConnDone)
-		srv := service.NewUserService(repo)
-		err := srv.CreateUser(service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.CreateUser(context.Background(), service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "GetUserByUsername")
 		repo.AssertNotCalled(t, "CreateUser")
 	})
 	t.Run("Username is already used", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
 		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, nil)
-		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, sql.ErrNoRows)
+		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, repository.ErrNotFound)
 		repo.On("GetUserByUsername", "GoodDy").Return(&repository.User{}, nil)
-		repo.On("GetUserByUsername", "GoodDyZa").Return(&repository.User{}, sql.ErrNoRows)
-		srv := service.NewUserService(repo)
-		err := srv.CreateUser(service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDy", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: "Username is already used"})
+		repo.On("GetUserByUsername", "GoodDyZa").Return(&repository.User{}, repository.ErrNotFound)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.CreateUser(context.Background(), service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDy", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeUsernameTaken, "Username is already used"))
 		repo.AssertNotCalled(t, "CreateUser")
 	})
 	t.Run("Get Username Database Error", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
-		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, sql.ErrNoRows)
+		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, repository.ErrNotFound)
 		repo.On("GetUserByUsername", "GoodDyZa").Return(&repository.User{}, sql.ErrConnDone)
-		srv := service.NewUserService(repo)
-		err := srv.CreateUser(service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.CreateUser(context.Background(), service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "CreateUser")
 	})
 	t.Run("Create User Database Error", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
-		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, sql.ErrNoRows)
-		repo.On("GetUserByUsername", "GoodDyZa").Return(&repository.User{}, sql.ErrNoRows)
-		repo.On("CreateUser", repository.User{
+		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, repository.ErrNotFound)
+		repo.On("GetUserByUsername", "GoodDyZa").Return(&repository.User{}, repository.ErrNotFound)
+		repo.On("CreateUser", matchHashedUser(repository.User{
 			UserId:           "gooddy21",
-			Password:         "correctPassword",
 			Username:         "GoodDyZa",
 			Weight:           68,
 			Protein:          0,
 			Fat:              0,
 			Carb:             0,
-			FavoriteMenues:   "",
+			Timezone:         "UTC",
+			FavoriteMenues:   []int{},
 			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
-		}).Return(sql.ErrConnDone)
-		srv := service.NewUserService(repo)
-		err := srv.CreateUser(service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		}, "correctPassword")).Return(sql.ErrConnDone)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.CreateUser(context.Background(), service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68, Protein: 0, Fat: 0, Carb: 0})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("User Id is taken while creating", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy21").Return(&repository.User{}, repository.ErrNotFound)
+		repo.On("GetUserByUsername", "GoodDyZa").Return(&repository.User{}, repository.ErrNotFound)
+		repo.On("CreateUser", mock.Anything).Return(repository.ErrConflict)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.CreateUser(context.Background(), service.NewUserRequest{UserId: "gooddy21", Password: "correctPassword", Username: "GoodDyZa", Weight: 68})
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeUserAlreadyExists, "User Id or Username is already used"))
 	})
 }
 
@@ -204,22 +284,23 @@ func TestUpdateUser(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12",
+			FavoriteMenues:   []int{11, 12},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
-		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
-			Password:       "correctPasswordV2",
-			Username:       "GoodDy",
-			Weight:         71,
-			Protein:        120,
-			Fat:            60,
-			Carb:           120,
-			FavoriteMenues: "11,12",
-		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
+		repo.On("UpdateUser", matchHashedUser(repository.User{UserId: "gooddy20",
+			Username:         "GoodDy",
+			Weight:           71,
+			Protein:          120,
+			Fat:              60,
+			Carb:             120,
+			FavoriteMenues:   []int{11, 12},
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
+		}, "correctPasswordV2")).Return(nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
 			UserId:   "gooddy20",
-			Password: "correctPasswordV2",
+			Password: service.NewNullable("correctPasswordV2"),
 		})
 		assert.ErrorIs(t, err, nil)
 	})
@@ -232,22 +313,24 @@ func TestUpdateUser(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12",
+			FavoriteMenues:   []int{11, 12},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
-			Password:       "correctPassword",
-			Username:       "GoodDyInwZa20",
-			Weight:         71,
-			Protein:        120,
-			Fat:            60,
-			Carb:           120,
-			FavoriteMenues: "11,12",
+			Password:         "correctPassword",
+			Username:         "GoodDyInwZa20",
+			Weight:           71,
+			Protein:          120,
+			Fat:              60,
+			Carb:             120,
+			FavoriteMenues:   []int{11, 12},
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
 			UserId:   "gooddy20",
-			Username: "GoodDyInwZa20",
+			Username: service.NewNullable("GoodDyInwZa20"),
 		})
 		assert.ErrorIs(t, err, nil)
 	})
@@ -260,25 +343,62 @@ func TestUpdateUser(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12",
+			FavoriteMenues:   []int{11, 12},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
+		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
+			Password:         "correctPassword",
+			Username:         "GoodDy",
+			Weight:           69,
+			Protein:          115,
+			Fat:              50,
+			Carb:             100,
+			FavoriteMenues:   []int{11, 12},
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
+		}).Return(nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
+			UserId:  "gooddy20",
+			Weight:  service.NewNullable[float64](69),
+			Protein: service.NewNullable[float64](115),
+			Fat:     service.NewNullable[float64](50),
+			Carb:    service.NewNullable[float64](100),
+		})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Success Case: Set Nutrition to Zero and Clear Profile", func(t *testing.T) {
+		birthDate := time.Date(1993, 4, 20, 0, 0, 0, 0, time.UTC)
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20",
+			Password:       "correctPassword",
+			Username:       "GoodDy",
+			Weight:         71,
+			Protein:        120,
+			Fat:            60,
+			Carb:           120,
+			Sex:            "male",
+			BirthDate:      &birthDate,
+			Height:         175,
+			FavoriteMenues: []int{11, 12},
+		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
 			Password:       "correctPassword",
 			Username:       "GoodDy",
-			Weight:         69,
-			Protein:        115,
-			Fat:            50,
-			Carb:           100,
-			FavoriteMenues: "11,12",
+			Weight:         71,
+			Protein:        120,
+			Fat:            0,
+			Carb:           120,
+			FavoriteMenues: []int{11, 12},
 		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
-			UserId:  "gooddy20",
-			Weight:  69,
-			Protein: 115,
-			Fat:     50,
-			Carb:    100,
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
+			UserId:    "gooddy20",
+			Fat:       service.NewNullable[float64](0),
+			Sex:       service.NewNull[string](),
+			BirthDate: service.NewNull[string](),
+			Height:    service.NewNull[float64](),
 		})
 		assert.ErrorIs(t, err, nil)
 	})
@@ -291,26 +411,29 @@ func TestUpdateUser(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12",
+			FavoriteMenues:   []int{11, 12},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
-			Password:       "correctPassword",
-			Username:       "GoodDy",
-			Weight:         71,
-			Protein:        120,
-			Fat:            60,
-			Carb:           120,
-			FavoriteMenues: "11,12,13",
+			Password:         "correctPassword",
+			Username:         "GoodDy",
+			Weight:           71,
+			Protein:          120,
+			Fat:              60,
+			Carb:             120,
+			FavoriteMenues:   []int{11, 12, 13},
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{11, 12, 13}).Return([]repository.Menu{{Id: 11, Status: 1}, {Id: 12, Status: 0}, {Id: 13, Status: 1}}, nil)
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
 			UserId:         "gooddy20",
-			FavoriteMenues: "11,12,13",
+			FavoriteMenues: service.NewNullable("11,12,13"),
 		})
 		assert.ErrorIs(t, err, nil)
 	})
-	t.Run("Success Case: Update Favorite Menues Case 2", func(t *testing.T) {
+	t.Run("Success Case: Clear Favorite Menues", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
 		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20",
 			Password:         "correctPassword",
@@ -319,44 +442,168 @@ func TestUpdateUser(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11",
+			FavoriteMenues:   []int{11},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
+			Password:         "correctPassword",
+			Username:         "GoodDy",
+			Weight:           71,
+			Protein:          120,
+			Fat:              60,
+			Carb:             120,
+			FavoriteMenues:   []int{},
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
+		}).Return(nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
+			UserId:         "gooddy20",
+			FavoriteMenues: service.NewNull[string](),
+		})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Success Case: Update Favorite Menu Ids", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20",
 			Password:       "correctPassword",
 			Username:       "GoodDy",
-			Weight:         71,
-			Protein:        120,
-			Fat:            60,
-			Carb:           120,
-			FavoriteMenues: "",
+			FavoriteMenues: []int{11},
+		}, nil)
+		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
+			Password:       "correctPassword",
+			Username:       "GoodDy",
+			FavoriteMenues: []int{9, 12},
 		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
-			UserId: "gooddy20",
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 12}).Return([]repository.Menu{{Id: 9, Status: 1}, {Id: 12, Status: 1}}, nil)
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
+			UserId:          "gooddy20",
+			FavoriteMenues:  service.NewNullable("1,2"),
+			FavoriteMenuIds: service.NewNullable([]int{12, 9, 12}),
 		})
 		assert.ErrorIs(t, err, nil)
 	})
+	t.Run("Unknown Or Deleted Favorite Menu Ids", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Password: "correctPassword", Username: "GoodDy", FavoriteMenues: []int{11}}, nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9, 12, 99}).Return([]repository.Menu{{Id: 9, Status: 1}, {Id: 12, Status: 0}}, nil)
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
+			UserId:          "gooddy20",
+			FavoriteMenuIds: service.NewNullable([]int{9, 12, 99}),
+		})
+		assert.ErrorIs(t, err, errs.NewValidationErrors([]errs.FieldError{
+			{Field: "favorite_menu_ids", Message: "Menu Id - 12 is deleted"},
+			{Field: "favorite_menu_ids", Message: "Menu Id - 99 is not found"},
+		}))
+		repo.AssertNotCalled(t, "UpdateUser")
+	})
+	t.Run("Get Favorite Menues Database Error", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Password: "correctPassword", Username: "GoodDy"}, nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		menuRepo.On("GetMenusByIds", []int{9}).Return([]repository.Menu{}, sql.ErrConnDone)
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{UserId: "gooddy20", FavoriteMenues: service.NewNullable("9")})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+		repo.AssertNotCalled(t, "UpdateUser")
+	})
+	t.Run("Success Case: Update Profile and Apply Suggested Targets", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20",
+			Password:         "correctPassword",
+			Username:         "GoodDy",
+			Weight:           70,
+			Protein:          120,
+			Fat:              60,
+			Carb:             120,
+			Timezone:         "UTC",
+			Sex:              "male",
+			Height:           175,
+			FavoriteMenues:   []int{11, 12},
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
+		}, nil)
+		birthDate := time.Date(1993, 4, 20, 0, 0, 0, 0, time.UTC)
+		repo.On("UpdateUser", mock.MatchedBy(func(user repository.User) bool {
+			return user.ActivityLevel == "moderate" && user.Goal == "cut" && user.BirthDate.Equal(birthDate) && user.Sex == "male" && user.Height == 175 &&
+				user.Protein == 154 && user.Fat > 50 && user.Carb > 200 && assert.ObjectsAreEqual([]int{11, 12}, user.FavoriteMenues)
+		})).Return(nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
+			UserId:                "gooddy20",
+			BirthDate:             service.NewNullable("1993-04-20"),
+			ActivityLevel:         service.NewNullable("moderate"),
+			Goal:                  service.NewNullable("cut"),
+			ApplySuggestedTargets: true,
+		})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Success Case: Opt In to Auto Update Menues", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20",
+			Password:       "correctPassword",
+			Username:       "GoodDy",
+			Timezone:       "UTC",
+			FavoriteMenues: []int{11, 12},
+		}, nil)
+		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
+			Password:         "correctPassword",
+			Username:         "GoodDy",
+			Timezone:         "UTC",
+			AutoUpdateMenues: true,
+			FavoriteMenues:   []int{11, 12},
+		}).Return(nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{UserId: "gooddy20", AutoUpdateMenues: service.NewNullable(true)})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Apply Suggested Targets With Incomplete Profile", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Password: "correctPassword", Username: "GoodDy", Weight: 70, Timezone: "UTC"}, nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{UserId: "gooddy20", Goal: service.NewNullable("bulk"), ApplySuggestedTargets: true})
+		assert.ErrorIs(t, err, errs.NewUnprocessableError(errs.CodeProfileIncomplete, "Sex, birth date, height, weight, activity level and goal need to be set to suggest the targets"))
+		repo.AssertNotCalled(t, "UpdateUser")
+	})
+	t.Run("Invalid Favorite Menues", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
+			UserId:         "gooddy20",
+			FavoriteMenues: service.NewNullable("11,x"),
+		})
+		assert.ErrorIs(t, err, errs.NewValidationError("favorite_menues", `Favorite Menues need to be "Menu"'s id separated by comma e.g. "9,10"`))
+		repo.AssertNotCalled(t, "GetUserById")
+	})
 	t.Run("No The User Id", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
-		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, sql.ErrNoRows)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, repository.ErrNotFound)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
 			UserId:         "gooddy20",
-			FavoriteMenues: "11,12,13",
+			FavoriteMenues: service.NewNullable("11,12,13"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id is not found"})
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found"))
 		repo.AssertNotCalled(t, "UpdateUser")
 	})
 	t.Run("Get User Database Error", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
 		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, sql.ErrConnDone)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
 			UserId:         "gooddy20",
-			FavoriteMenues: "11,12,13",
+			FavoriteMenues: service.NewNullable("11,12,13"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "UpdateUser")
 	})
 	t.Run("Invalid Password", func(t *testing.T) {
@@ -368,15 +615,24 @@ func TestUpdateUser(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12",
+			FavoriteMenues:   []int{11, 12},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
 			UserId:   "gooddy20",
-			Password: "p a s s",
+			Password: service.NewNullable("p a s s"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: "Password need to contain more than 5 letter and no whitespace"})
+		assert.ErrorIs(t, err, errs.NewValidationError("password", "Password need to contain more than 5 letter and no whitespace"))
+	})
+	t.Run("Null Password", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{UserId: "gooddy20", Password: service.NewNull[string]()})
+		assert.ErrorIs(t, err, errs.NewValidationError("password", "Password can not be null"))
+		repo.AssertNotCalled(t, "GetUserById")
 	})
 	t.Run("Invalid Username", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
@@ -387,15 +643,16 @@ func TestUpdateUser(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12",
+			FavoriteMenues:   []int{11, 12},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
 			UserId:   "gooddy20",
-			Username: "good",
+			Username: service.NewNullable("good"),
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: "Username need to contain more than 5 letter and alphabet only"})
+		assert.ErrorIs(t, err, errs.NewValidationError("username", "Username need to contain more than 5 letter and alphabet only"))
 	})
 	t.Run("Update User Database Error", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
@@ -406,24 +663,73 @@ func TestUpdateUser(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12",
+			FavoriteMenues:   []int{11, 12},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
-			Password:       "correctPassword",
-			Username:       "GoodDyInwZa20",
-			Weight:         71,
-			Protein:        120,
-			Fat:            60,
-			Carb:           120,
-			FavoriteMenues: "11,12",
+			Password:         "correctPassword",
+			Username:         "GoodDyInwZa20",
+			Weight:           71,
+			Protein:          120,
+			Fat:              60,
+			Carb:             120,
+			FavoriteMenues:   []int{11, 12},
+			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(sql.ErrConnDone)
-		srv := service.NewUserService(repo)
-		err := srv.UpdateUser(service.UpdateUserRequest{
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
+			UserId:   "gooddy20",
+			Username: service.NewNullable("GoodDyInwZa20"),
+		})
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+	t.Run("Username is already used", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Password: "correctPassword", Username: "GoodDy", FavoriteMenues: []int{11}}, nil)
+		repo.On("UpdateUser", mock.Anything).Return(repository.ErrConflict)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{UserId: "gooddy20", Username: service.NewNullable("KornKoko20")})
+		assert.ErrorIs(t, err, errs.NewConflictError(errs.CodeUsernameTaken, "Username is already used"))
+	})
+	t.Run("Stale Revision", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20",
+			Username: "GoodDy",
+			Weight:   71,
+			Revision: 7,
+		}, nil)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
+			UserId:   "gooddy20",
+			Weight:   service.NewNullable[float64](72),
+			Revision: 6,
+		})
+		assert.ErrorIs(t, err, errs.NewPreconditionFailedError("User has been changed by another request, get it again and retry"))
+		repo.AssertNotCalled(t, "UpdateUser")
+	})
+	t.Run("Changed By Another Request", func(t *testing.T) {
+		repo := repository.NewUserRepositoryMock()
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20",
+			Username: "GoodDy",
+			Weight:   71,
+			Revision: 7,
+		}, nil)
+		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
+			Username: "GoodDy",
+			Weight:   72,
+			Revision: 7,
+		}).Return(repository.ErrStale)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.UpdateUser(context.Background(), service.UpdateUserRequest{
 			UserId:   "gooddy20",
-			Username: "GoodDyInwZa20",
+			Weight:   service.NewNullable[float64](72),
+			Revision: 7,
 		})
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		assert.ErrorIs(t, err, errs.NewPreconditionFailedError("User has been changed by another request, get it again and retry"))
 	})
 }
 
@@ -437,7 +743,7 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12,14,17",
+			FavoriteMenues:   []int{11, 12, 14, 17},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
@@ -447,11 +753,12 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "12,14,17",
+			FavoriteMenues:   []int{12, 14, 17},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.RecoverFavoriteMenues("gooddy20", 11)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.RecoverFavoriteMenues(context.Background(), "gooddy20", 11)
 		assert.ErrorIs(t, err, nil)
 	})
 	t.Run("Success Case 2", func(t *testing.T) {
@@ -463,7 +770,7 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12,14,17",
+			FavoriteMenues:   []int{11, 12, 14, 17},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
@@ -473,11 +780,12 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12,14",
+			FavoriteMenues:   []int{11, 12, 14},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.RecoverFavoriteMenues("gooddy20", 17)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.RecoverFavoriteMenues(context.Background(), "gooddy20", 17)
 		assert.ErrorIs(t, err, nil)
 	})
 	t.Run("Success Case 3", func(t *testing.T) {
@@ -489,7 +797,7 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12,14,17",
+			FavoriteMenues:   []int{11, 12, 14, 17},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
@@ -499,11 +807,12 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,14,17",
+			FavoriteMenues:   []int{11, 14, 17},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.RecoverFavoriteMenues("gooddy20", 12)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.RecoverFavoriteMenues(context.Background(), "gooddy20", 12)
 		assert.ErrorIs(t, err, nil)
 	})
 	t.Run("Success Case: No The Deleted Menu Id in Favorite Menues", func(t *testing.T) {
@@ -515,7 +824,7 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12,14,17",
+			FavoriteMenues:   []int{11, 12, 14, 17},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
@@ -525,27 +834,30 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12,14,17",
+			FavoriteMenues:   []int{11, 12, 14, 17},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(nil)
-		srv := service.NewUserService(repo)
-		err := srv.RecoverFavoriteMenues("gooddy20", 18)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.RecoverFavoriteMenues(context.Background(), "gooddy20", 18)
 		assert.ErrorIs(t, err, nil)
 	})
 	t.Run("No The User Id", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
-		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, sql.ErrNoRows)
-		srv := service.NewUserService(repo)
-		err := srv.RecoverFavoriteMenues("gooddy20", 18)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusNotAcceptable, Message: "User Id is not found"})
+		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, repository.ErrNotFound)
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.RecoverFavoriteMenues(context.Background(), "gooddy20", 18)
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found"))
 		repo.AssertNotCalled(t, "UpdateUser")
 	})
 	t.Run("Get User Database Error", func(t *testing.T) {
 		repo := repository.NewUserRepositoryMock()
 		repo.On("GetUserById", "gooddy20").Return(&repository.User{}, sql.ErrConnDone)
-		srv := service.NewUserService(repo)
-		err := srv.RecoverFavoriteMenues("gooddy20", 18)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.RecoverFavoriteMenues(context.Background(), "gooddy20", 18)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 		repo.AssertNotCalled(t, "UpdateUser")
 	})
 	t.Run("Update User Database Error", func(t *testing.T) {
@@ -557,7 +869,7 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,12,14,17",
+			FavoriteMenues:   []int{11, 12, 14, 17},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}, nil)
 		repo.On("UpdateUser", repository.User{UserId: "gooddy20",
@@ -567,11 +879,12 @@ func TestRecoverFavoriteMenues(t *testing.T) {
 			Protein:          120,
 			Fat:              60,
 			Carb:             120,
-			FavoriteMenues:   "11,14,17",
+			FavoriteMenues:   []int{11, 14, 17},
 			CreatedTimestamp: time.Date(2023, 11, 14, 11, 30, 32, 0, time.UTC).UTC(),
 		}).Return(sql.ErrConnDone)
-		srv := service.NewUserService(repo)
-		err := srv.RecoverFavoriteMenues("gooddy20", 12)
-		assert.ErrorIs(t, err, errs.AppError{Code: http.StatusInternalServerError, Message: "Unexpected error"})
+		menuRepo := repository.NewMenuRepositoryMock()
+		srv := service.NewUserService(repo, menuRepo)
+		err := srv.RecoverFavoriteMenues(context.Background(), "gooddy20", 12)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
 	})
 }
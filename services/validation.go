@@ -0,0 +1,244 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-nutritioncalculator2/errs"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of the timestamps and dates in the requests
+const (
+	timestampLayout = "2006-01-02 15:04:05"
+	dateLayout      = "2006-01-02"
+)
+
+var (
+	menuListPattern = regexp.MustCompile(`^\s*0*[1-9]\d*\s*(,\s*0*[1-9]\d*\s*)*$`)
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// validation collects every invalid field of a request so that they are returned at once
+type validation struct {
+	details []errs.FieldError
+}
+
+// validateRequest checks the request against the rules in the "binding" tags of its fields
+//
+//	required      the field is sent and not empty, a "Nullable" field is set and not null
+//	notnull       a "Nullable" field can be omitted but can not be null
+//	omitempty     the other rules are skipped for an empty value
+//	gt=N, lt=N    the number is more than or less than N
+//	min=N, max=N  the number is in the range
+//	timestamp     the text is in the format "2023-01-01 00:00:00"
+//	date          the text is in the format "2023-01-01"
+//	menulist      the text is "Menu"'s ids separated by comma e.g. "9,9,10"
+//	uuid          the text is a UUID e.g. "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"
+//	oneof=A B     the text is one of the words
+//
+// and a struct or the elements of a slice of structs are checked by their own tags as e.g. "items[0].menu_id"
+func validateRequest(request any) *validation {
+	v := &validation{}
+	v.validateStruct("", reflect.ValueOf(request))
+	return v
+}
+
+func (v *validation) validateStruct(prefix string, value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		name = prefix + name
+		rules := parseRules(field.Tag.Get("binding"))
+		fieldValue, isSet := value.Field(i), true
+		if n, ok := fieldValue.Interface().(nullableField); ok {
+			var isNull bool
+			var nullableValue any
+			isSet, isNull, nullableValue = n.nullable()
+			if isNull && rules.notNull {
+				v.add(name, fmt.Sprint(fieldLabel(name), " can not be null"))
+				continue
+			}
+			isSet = isSet && !isNull
+			fieldValue = reflect.ValueOf(nullableValue)
+		} else if fieldValue.Kind() == reflect.Pointer {
+			isSet = !fieldValue.IsNil()
+			fieldValue = fieldValue.Elem()
+		}
+		if !isSet || fieldValue.IsZero() || (fieldValue.Kind() == reflect.String && strings.TrimSpace(fieldValue.String()) == "") {
+			if rules.required {
+				v.add(name, fmt.Sprint(fieldLabel(name), " is required"))
+			}
+			if !isSet || rules.required || rules.omitEmpty {
+				continue
+			}
+		}
+		v.validateValue(name, fieldValue, rules)
+		if fieldValue.Kind() == reflect.Struct {
+			v.validateStruct(name+".", fieldValue)
+		}
+		if fieldValue.Kind() == reflect.Slice && fieldValue.Type().Elem().Kind() == reflect.Struct {
+			for j := 0; j < fieldValue.Len(); j++ {
+				v.validateStruct(fmt.Sprintf("%s[%d].", name, j), fieldValue.Index(j))
+			}
+		}
+	}
+}
+
+func (v *validation) validateValue(name string, value reflect.Value, rules rules) {
+	label := fieldLabel(name)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		number := value.Convert(reflect.TypeOf(float64(0))).Float()
+		switch {
+		case rules.min != nil && rules.max != nil && (number < *rules.min || number > *rules.max):
+			v.add(name, fmt.Sprint(label, " need to be between ", formatNumber(*rules.min), " and ", formatNumber(*rules.max)))
+		case rules.min != nil && rules.lt != nil && (number < *rules.min || number >= *rules.lt):
+			v.add(name, fmt.Sprint(label, " need to be between ", formatNumber(*rules.min), " and less than ", formatNumber(*rules.lt)))
+		case rules.gt != nil && number <= *rules.gt && *rules.gt == 0:
+			v.add(name, fmt.Sprint(label, " need to be positive"))
+		case rules.gt != nil && number <= *rules.gt:
+			v.add(name, fmt.Sprint(label, " need to be more than ", formatNumber(*rules.gt)))
+		case rules.min != nil && number < *rules.min && *rules.min == 0:
+			v.add(name, fmt.Sprint(label, " can not be negative"))
+		case rules.min != nil && number < *rules.min:
+			v.add(name, fmt.Sprint(label, " need to be at least ", formatNumber(*rules.min)))
+		case rules.max != nil && number > *rules.max:
+			v.add(name, fmt.Sprint(label, " need to be at most ", formatNumber(*rules.max)))
+		case rules.lt != nil && number >= *rules.lt:
+			v.add(name, fmt.Sprint(label, " need to be less than ", formatNumber(*rules.lt)))
+		}
+	case reflect.String:
+		text := value.String()
+		if text == "" {
+			return
+		}
+		if len(rules.oneOf) != 0 && !slices.Contains(rules.oneOf, text) {
+			v.add(name, fmt.Sprint(label, ` need to be one of "`, strings.Join(rules.oneOf, `", "`), `"`))
+		}
+		switch rules.format {
+		case "timestamp":
+			if _, err := time.Parse(timestampLayout, text); err != nil {
+				v.add(name, fmt.Sprint(label, ` need to be in the format "2023-01-01 00:00:00"`))
+			}
+		case "date":
+			if _, err := time.Parse(dateLayout, text); err != nil {
+				v.add(name, fmt.Sprint(label, ` need to be in the format "2023-01-01"`))
+			}
+		case "menulist":
+			if !menuListPattern.MatchString(text) {
+				v.add(name, fmt.Sprint(label, ` need to be "Menu"'s id separated by comma e.g. "9,9,10"`))
+			}
+		case "uuid":
+			if !uuidPattern.MatchString(text) {
+				v.add(name, fmt.Sprint(label, ` need to be a UUID e.g. "6f1c2a9e-3b4d-4c5e-8f70-1a2b3c4d5e6f"`))
+			}
+		}
+	}
+}
+
+func (v *validation) add(field string, message string) {
+	v.details = append(v.details, errs.FieldError{Field: field, Message: message})
+}
+
+// has tells that atleast one of the fields is already invalid so a check that depends on them can be skipped
+func (v *validation) has(fields ...string) bool {
+	for _, detail := range v.details {
+		for _, field := range fields {
+			if detail.Field == field || strings.HasPrefix(detail.Field, field+"[") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// check keeps the field errors of a validation error to return them with the others, any other error is returned as it is
+func (v *validation) check(err error) error {
+	var appErr errs.AppError
+	if errors.As(err, &appErr) && appErr.ErrorCode == errs.CodeValidationFailed && len(appErr.Details) != 0 {
+		v.details = append(v.details, appErr.Details...)
+		return nil
+	}
+	return err
+}
+
+func (v *validation) err() error {
+	if len(v.details) == 0 {
+		return nil
+	}
+	return errs.NewValidationErrors(v.details)
+}
+
+type rules struct {
+	required  bool
+	notNull   bool
+	omitEmpty bool
+	gt        *float64
+	lt        *float64
+	min       *float64
+	max       *float64
+	format    string
+	oneOf     []string
+}
+
+func parseRules(tag string) rules {
+	r := rules{}
+	for _, rule := range strings.Split(tag, ",") {
+		key, param, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			r.required = true
+		case "notnull":
+			r.notNull = true
+		case "omitempty":
+			r.omitEmpty = true
+		case "gt", "lt", "min", "max":
+			number, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				panic(fmt.Sprintf("binding %q need a number", rule))
+			}
+			switch key {
+			case "gt":
+				r.gt = &number
+			case "lt":
+				r.lt = &number
+			case "min":
+				r.min = &number
+			default:
+				r.max = &number
+			}
+		case "timestamp", "date", "menulist", "uuid":
+			r.format = key
+		case "oneof":
+			r.oneOf = strings.Fields(param)
+		}
+	}
+	return r
+}
+
+// fieldLabel turns the JSON name of the field into the name in a message e.g. "items[0].menu_id" -> "Menu Id"
+func fieldLabel(name string) string {
+	if i := strings.LastIndex(name, "."); i != -1 {
+		name = name[i+1:]
+	}
+	words := strings.Split(name, "_")
+	for i, word := range words {
+		if word == "id" {
+			words[i] = "Id"
+		}
+	}
+	label := strings.Join(words, " ")
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+type NewWeightLogRequest struct {
+	UserId          string   `json:"user_id" example:"gooddy20" binding:"required"`                      // "User Id" that weigh
+	Weight          float64  `json:"weight" example:"70.5" binding:"required,min=20,max=500"`            // Body weight (kg.)
+	BodyFat         *float64 `json:"body_fat" example:"18.5" binding:"min=0,lt=100"`                     // Body fat (%), null when it is not measured
+	LoggedTimestamp string   `json:"logged_timestamp" example:"2023-12-05 07:00:00" binding:"timestamp"` // Wall-clock timestamp in your time zone that you weigh, default = now in your time zone *format="2023-01-01 00:00:00"
+}
+
+type UpdateWeightLogRequest struct {
+	Id              int               `json:"id" example:"1" binding:"required"`                                                               // "Weight Log"'s id that you want to update
+	Weight          Nullable[float64] `json:"weight" swaggertype:"number" example:"70.2" binding:"notnull,min=20,max=500"`                     // Body weight (kg.) that you want to change to, it can not be null
+	BodyFat         Nullable[float64] `json:"body_fat" swaggertype:"number" example:"18.2" binding:"min=0,lt=100"`                             // Body fat (%) that you want to change to, null = clear
+	LoggedTimestamp Nullable[string]  `json:"logged_timestamp" swaggertype:"string" example:"2023-12-05 07:30:00" binding:"notnull,timestamp"` // Wall-clock timestamp in your time zone that you want to change to *format="2023-01-01 00:00:00", it can not be null
+}
+
+type WeightLogResponse struct {
+	Id              int       `json:"id" example:"1"`                                  // "Weight Log"'s id
+	Weight          float64   `json:"weight" example:"70.5"`                           // Body weight (kg.)
+	BodyFat         *float64  `json:"body_fat" example:"18.5"`                         // Body fat (%), null when it is not measured
+	LoggedTimestamp time.Time `json:"logged_timestamp" example:"2023-12-05T07:00:00Z"` // Wall-clock timestamp in your time zone that you weigh, the "Z" does not mean UTC
+}
+
+type WeightTrendPoint struct {
+	Date    string   `json:"date" example:"2023-12-05"` // Calendar day *format="2023-01-01"
+	Weight  float64  `json:"weight" example:"70.5"`     // Average weight (kg.) of the day
+	BodyFat *float64 `json:"body_fat" example:"18.5"`   // Average body fat (%) of the day, null when it is not measured
+	Trend   float64  `json:"trend" example:"70.8"`      // 7-day exponential moving average of the weight (kg.)
+}
+
+type WeightTrendResponse struct {
+	UserId     string             `json:"user_id" example:"gooddy20"`      // "User Id"
+	From       string             `json:"from" example:"2023-09-07"`       // First day of the trend *format="2023-01-01"
+	To         string             `json:"to" example:"2023-12-05"`         // Last day of the trend *format="2023-01-01"
+	Timezone   string             `json:"timezone" example:"Asia/Bangkok"` // Time zone that the days are counted in
+	Points     []WeightTrendPoint `json:"points"`                          // Each logged day from "from" to "to"
+	Trend      *float64           `json:"trend" example:"70.8"`            // Latest trend weight (kg.), null when nothing is logged
+	WeeklyRate *float64           `json:"weekly_rate" example:"-0.4"`      // Change of the trend weight (kg.) per week, null when there is less than 2 logged days
+}
+
+type WeightLogService interface {
+	GetWeightLogsByUserId(context.Context, string, string, string) ([]WeightLogResponse, error)
+	CreateWeightLog(context.Context, NewWeightLogRequest) error
+	UpdateWeightLog(context.Context, string, UpdateWeightLogRequest) error
+	DeleteWeightLog(context.Context, string, int) error
+	GetWeightTrend(context.Context, string, string, string) (*WeightTrendResponse, error)
+}
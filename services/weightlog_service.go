@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	"math"
+	"time"
+)
+
+// The trend is an exponential moving average over this many days, each day moves it by 2 / (days + 1) of the gap
+const trendDays = 7
+
+// Default length of a trend and the days before it that are read to warm the moving average up
+const (
+	defaultTrendDays = 90
+	trendWarmUpDays  = 30
+)
+
+type weightLogService struct {
+	weightLogRepo repository.WeightLogRepository
+	userRepo      repository.UserRepository
+}
+
+func NewWeightLogService(weightLogRepo repository.WeightLogRepository, userRepo repository.UserRepository) weightLogService {
+	return weightLogService{weightLogRepo: weightLogRepo, userRepo: userRepo}
+}
+
+func (s weightLogService) GetWeightLogsByUserId(ctx context.Context, userId string, from string, to string) ([]WeightLogResponse, error) {
+	var fromTimestamp, toTimestamp *time.Time
+	for _, bound := range []struct {
+		field  string
+		value  string
+		target **time.Time
+	}{{"from", from, &fromTimestamp}, {"to", to, &toTimestamp}} {
+		if bound.value == "" {
+			continue
+		}
+		timestamp, err := parseRecordTimestamp(bound.field, bound.value)
+		if err != nil {
+			return nil, err
+		}
+		*bound.target = &timestamp
+	}
+	weightLogs, err := s.weightLogRepo.GetWeightLogsByUserId(ctx, userId, fromTimestamp, toTimestamp)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	weightLogsRes := []WeightLogResponse{}
+	for _, weightLog := range weightLogs {
+		weightLogsRes = append(weightLogsRes, WeightLogResponse{
+			Id:              weightLog.Id,
+			Weight:          weightLog.Weight,
+			BodyFat:         weightLog.BodyFat,
+			LoggedTimestamp: weightLog.LoggedTimestamp,
+		})
+	}
+	return weightLogsRes, nil
+}
+
+func (s weightLogService) CreateWeightLog(ctx context.Context, newWeightLogReq NewWeightLogRequest) error {
+	err := validateRequest(newWeightLogReq).err()
+	if err != nil {
+		return err
+	}
+	weightLog := repository.WeightLog{
+		UserId:           newWeightLogReq.UserId,
+		Weight:           newWeightLogReq.Weight,
+		BodyFat:          newWeightLogReq.BodyFat,
+		Status:           1,
+		CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+	}
+	if newWeightLogReq.LoggedTimestamp != "" {
+		weightLog.LoggedTimestamp, _ = time.Parse(timestampLayout, newWeightLogReq.LoggedTimestamp)
+	} else {
+		// "logged_timestamp" is the user's wall-clock time so the default is now in the user's time zone
+		user, err := s.userRepo.GetUserById(ctx, newWeightLogReq.UserId)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
+			}
+			return repositoryError(err)
+		}
+		_, location, err := userLocation(*user)
+		if err != nil {
+			return err
+		}
+		now := time.Now().In(location)
+		weightLog.LoggedTimestamp = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+	}
+	_, err = s.weightLogRepo.CreateWeightLog(ctx, weightLog)
+	if err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+func (s weightLogService) UpdateWeightLog(ctx context.Context, userId string, updateWeightLogReq UpdateWeightLogRequest) error {
+	err := validateRequest(updateWeightLogReq).err()
+	if err != nil {
+		return err
+	}
+	weightLog, err := s.weightLogRepo.GetWeightLogById(ctx, updateWeightLogReq.Id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeWeightLogNotFound, fmt.Sprint("Weight Log Id - ", updateWeightLogReq.Id, " is not found"))
+		}
+		return repositoryError(err)
+	}
+	if weightLog.UserId != userId {
+		return errs.NewPermissionDeniedError()
+	}
+	weightLog.Weight = updateWeightLogReq.Weight.Apply(weightLog.Weight)
+	if updateWeightLogReq.BodyFat.Set {
+		weightLog.BodyFat = nil
+		if !updateWeightLogReq.BodyFat.Null {
+			weightLog.BodyFat = &updateWeightLogReq.BodyFat.Value
+		}
+	}
+	if updateWeightLogReq.LoggedTimestamp.Set {
+		weightLog.LoggedTimestamp, _ = time.Parse(timestampLayout, updateWeightLogReq.LoggedTimestamp.Value)
+	}
+	err = s.weightLogRepo.UpdateWeightLog(ctx, *weightLog)
+	if err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+func (s weightLogService) DeleteWeightLog(ctx context.Context, userId string, weightLogId int) error {
+	weightLog, err := s.weightLogRepo.GetWeightLogById(ctx, weightLogId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NewNotFoundError(errs.CodeWeightLogNotFound, fmt.Sprint("Weight Log Id - ", weightLogId, " is not found"))
+		}
+		return repositoryError(err)
+	}
+	if weightLog.UserId != userId {
+		return errs.NewPermissionDeniedError()
+	}
+	weightLog.Status = 0
+	err = s.weightLogRepo.UpdateWeightLog(ctx, *weightLog)
+	if err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+// GetWeightTrend smooths the average weight of each logged day of the wall-clock "logged_timestamp" from "from" to "to"
+// (the last 90 days up to today in the user's time zone by default) with a 7-day exponential moving average and reports how fast the trend moves per week
+func (s weightLogService) GetWeightTrend(ctx context.Context, userId string, from string, to string) (*WeightTrendResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found")
+		}
+		return nil, repositoryError(err)
+	}
+	timezone, location, err := userLocation(*user)
+	if err != nil {
+		return nil, err
+	}
+	lastDay := localToday(location)
+	if to != "" {
+		lastDay, err = time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, errs.NewValidationError("to", `From and To need to be in the format "2023-01-01"`)
+		}
+	}
+	firstDay := lastDay.AddDate(0, 0, 1-defaultTrendDays)
+	if from != "" {
+		firstDay, err = time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, errs.NewValidationError("from", `From and To need to be in the format "2023-01-01"`)
+		}
+	}
+	if lastDay.Before(firstDay) {
+		return nil, errs.NewValidationError("from", "From need to be before or equal to To")
+	}
+	if firstDay.AddDate(0, 0, maxReportDays).Before(lastDay.AddDate(0, 0, 1)) {
+		return nil, errs.NewValidationError("to", "Date range can not be longer than 366 days")
+	}
+	readFrom, readTo := firstDay.AddDate(0, 0, -trendWarmUpDays), lastDay.AddDate(0, 0, 1)
+	weightLogs, err := s.weightLogRepo.GetWeightLogsByUserId(ctx, userId, &readFrom, &readTo)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	trend := WeightTrendResponse{
+		UserId:   userId,
+		From:     firstDay.Format("2006-01-02"),
+		To:       lastDay.Format("2006-01-02"),
+		Timezone: timezone,
+		Points:   []WeightTrendPoint{},
+	}
+	points := weightTrend(weightLogs)
+	for _, point := range points {
+		if point.Date >= trend.From {
+			trend.Points = append(trend.Points, point)
+		}
+	}
+	if len(points) != 0 {
+		latest := points[len(points)-1].Trend
+		trend.Trend = &latest
+		trend.WeeklyRate = weeklyRate(points)
+	}
+	return &trend, nil
+}
+
+// weightTrend averages the weight logs of each day and applies the moving average day by day,
+// a gap of n days moves the trend as much as n days of the same weight would
+func weightTrend(weightLogs []repository.WeightLog) []WeightTrendPoint {
+	type dailyWeight struct {
+		date                  string
+		weight, bodyFat       float64
+		weights, bodyFatCount int
+	}
+	days := []*dailyWeight{}
+	for _, weightLog := range weightLogs {
+		date := weightLog.LoggedTimestamp.Format("2006-01-02")
+		if len(days) == 0 || days[len(days)-1].date != date {
+			days = append(days, &dailyWeight{date: date})
+		}
+		day := days[len(days)-1]
+		day.weight += weightLog.Weight
+		day.weights++
+		if weightLog.BodyFat != nil {
+			day.bodyFat += *weightLog.BodyFat
+			day.bodyFatCount++
+		}
+	}
+	alpha := 2.0 / (trendDays + 1)
+	points := []WeightTrendPoint{}
+	var trend float64
+	var previousDay time.Time
+	for i, day := range days {
+		weight := day.weight / float64(day.weights)
+		date, _ := time.Parse("2006-01-02", day.date)
+		if i == 0 {
+			trend = weight
+		} else {
+			gap := math.Round(date.Sub(previousDay).Hours() / 24)
+			trend += (1 - math.Pow(1-alpha, gap)) * (weight - trend)
+		}
+		previousDay = date
+		point := WeightTrendPoint{Date: day.date, Weight: round2(weight), Trend: round2(trend)}
+		if day.bodyFatCount != 0 {
+			bodyFat := round2(day.bodyFat / float64(day.bodyFatCount))
+			point.BodyFat = &bodyFat
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// weeklyRate compares the latest trend with the trend a week before, or with the first trend scaled to a week when the logs are shorter than a week
+func weeklyRate(points []WeightTrendPoint) *float64 {
+	if len(points) < 2 {
+		return nil
+	}
+	latest := points[len(points)-1]
+	latestDay, _ := time.Parse("2006-01-02", latest.Date)
+	weekBefore := latestDay.AddDate(0, 0, -7).Format("2006-01-02")
+	base := points[0]
+	for _, point := range points[:len(points)-1] {
+		if point.Date <= weekBefore {
+			base = point
+		}
+	}
+	baseDay, _ := time.Parse("2006-01-02", base.Date)
+	days := math.Round(latestDay.Sub(baseDay).Hours() / 24)
+	rate := round2((latest.Trend - base.Trend) / days * 7)
+	return &rate
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
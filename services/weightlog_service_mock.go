@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type weightLogServiceMock struct {
+	mock.Mock
+}
+
+func NewWeightLogServiceMock() *weightLogServiceMock {
+	return &weightLogServiceMock{}
+}
+
+func (s *weightLogServiceMock) GetWeightLogsByUserId(ctx context.Context, userId string, from string, to string) ([]WeightLogResponse, error) {
+	args := s.Called(userId, from, to)
+	return args.Get(0).([]WeightLogResponse), args.Error(1)
+}
+
+func (s *weightLogServiceMock) CreateWeightLog(ctx context.Context, newWeightLogReq NewWeightLogRequest) error {
+	args := s.Called(newWeightLogReq)
+	return args.Error(0)
+}
+
+func (s *weightLogServiceMock) UpdateWeightLog(ctx context.Context, userId string, updateWeightLogReq UpdateWeightLogRequest) error {
+	args := s.Called(userId, updateWeightLogReq)
+	return args.Error(0)
+}
+
+func (s *weightLogServiceMock) DeleteWeightLog(ctx context.Context, userId string, weightLogId int) error {
+	args := s.Called(userId, weightLogId)
+	return args.Error(0)
+}
+
+func (s *weightLogServiceMock) GetWeightTrend(ctx context.Context, userId string, from string, to string) (*WeightTrendResponse, error) {
+	args := s.Called(userId, from, to)
+	return args.Get(0).(*WeightTrendResponse), args.Error(1)
+}
@@ -0,0 +1,277 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go-nutritioncalculator2/errs"
+	repository "go-nutritioncalculator2/repositories"
+	service "go-nutritioncalculator2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetWeightLogsByUserId(t *testing.T) {
+	bodyFat := 18.5
+	t.Run("Success", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		from := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+		repo.On("GetWeightLogsByUserId", "gooddy20", &from, (*time.Time)(nil)).Return([]repository.WeightLog{
+			{Id: 1, UserId: "gooddy20", Weight: 70.5, BodyFat: &bodyFat, LoggedTimestamp: time.Date(2023, 12, 1, 7, 0, 0, 0, time.UTC), Status: 1},
+			{Id: 2, UserId: "gooddy20", Weight: 70.2, LoggedTimestamp: time.Date(2023, 12, 2, 7, 0, 0, 0, time.UTC), Status: 1},
+		}, nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		result, err := srv.GetWeightLogsByUserId(context.Background(), "gooddy20", "2023-12-01", "")
+		expected := []service.WeightLogResponse{
+			{Id: 1, Weight: 70.5, BodyFat: &bodyFat, LoggedTimestamp: time.Date(2023, 12, 1, 7, 0, 0, 0, time.UTC)},
+			{Id: 2, Weight: 70.2, LoggedTimestamp: time.Date(2023, 12, 2, 7, 0, 0, 0, time.UTC)},
+		}
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Invalid Timestamp", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		srv := service.NewWeightLogService(repo, userRepo)
+		_, err := srv.GetWeightLogsByUserId(context.Background(), "gooddy20", "", "05/12/2023")
+		assert.ErrorIs(t, err, errs.NewValidationError("to", `From and To need to be in the format "2023-01-01" or "2023-01-01 00:00:00"`))
+		repo.AssertNotCalled(t, "GetWeightLogsByUserId")
+	})
+	t.Run("Error", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		repo.On("GetWeightLogsByUserId", "gooddy20", (*time.Time)(nil), (*time.Time)(nil)).Return([]repository.WeightLog{}, errors.New(""))
+		srv := service.NewWeightLogService(repo, userRepo)
+		_, err := srv.GetWeightLogsByUserId(context.Background(), "gooddy20", "", "")
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
+
+func TestCreateWeightLog(t *testing.T) {
+	bodyFat := 18.5
+	t.Run("Success", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		weightLog := repository.WeightLog{
+			UserId:           "gooddy20",
+			Weight:           70.5,
+			BodyFat:          &bodyFat,
+			LoggedTimestamp:  time.Date(2023, 12, 5, 7, 0, 0, 0, time.UTC),
+			Status:           1,
+			CreatedTimestamp: time.Now().UTC().Truncate(time.Second),
+		}
+		repo.On("CreateWeightLog", weightLog).Return(&weightLog, nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.CreateWeightLog(context.Background(), service.NewWeightLogRequest{UserId: "gooddy20", Weight: 70.5, BodyFat: &bodyFat, LoggedTimestamp: "2023-12-05 07:00:00"})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Success Case: Now In The User's Time Zone", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20", Timezone: "Asia/Bangkok"}, nil)
+		repo.On("CreateWeightLog", mock.MatchedBy(func(weightLog repository.WeightLog) bool {
+			wallClock := time.Now().UTC().Add(7 * time.Hour)
+			return weightLog.LoggedTimestamp.Location() == time.UTC && wallClock.Sub(weightLog.LoggedTimestamp) >= 0 && wallClock.Sub(weightLog.LoggedTimestamp) < time.Minute
+		})).Return(&repository.WeightLog{}, nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.CreateWeightLog(context.Background(), service.NewWeightLogRequest{UserId: "gooddy20", Weight: 70.5})
+		assert.ErrorIs(t, err, nil)
+		repo.AssertExpectations(t)
+	})
+	t.Run("No The User Id", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{}, repository.ErrNotFound)
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.CreateWeightLog(context.Background(), service.NewWeightLogRequest{UserId: "gooddy20", Weight: 70.5})
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found"))
+		repo.AssertNotCalled(t, "CreateWeightLog")
+	})
+	t.Run("Invalid Weight", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.CreateWeightLog(context.Background(), service.NewWeightLogRequest{UserId: "gooddy20", Weight: -70})
+		assert.ErrorIs(t, err, errs.NewValidationError("weight", "Weight need to be between 20 and 500"))
+		repo.AssertNotCalled(t, "CreateWeightLog")
+	})
+	t.Run("Invalid Body Fat", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		srv := service.NewWeightLogService(repo, userRepo)
+		invalidBodyFat := 100.0
+		err := srv.CreateWeightLog(context.Background(), service.NewWeightLogRequest{UserId: "gooddy20", Weight: 70, BodyFat: &invalidBodyFat})
+		assert.ErrorIs(t, err, errs.NewValidationError("body_fat", "Body fat need to be between 0 and less than 100"))
+		repo.AssertNotCalled(t, "CreateWeightLog")
+	})
+	t.Run("Invalid Logged Timestamp", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.CreateWeightLog(context.Background(), service.NewWeightLogRequest{UserId: "gooddy20", Weight: 70, LoggedTimestamp: "2023-12-05"})
+		assert.ErrorIs(t, err, errs.NewValidationError("logged_timestamp", `Logged timestamp need to be in the format "2023-01-01 00:00:00"`))
+		repo.AssertNotCalled(t, "CreateWeightLog")
+	})
+}
+
+func TestUpdateWeightLog(t *testing.T) {
+	bodyFat := 18.5
+	weightLog := func() *repository.WeightLog {
+		return &repository.WeightLog{Id: 1, UserId: "gooddy20", Weight: 70.5, BodyFat: &bodyFat, LoggedTimestamp: time.Date(2023, 12, 5, 7, 0, 0, 0, time.UTC), Status: 1}
+	}
+	t.Run("Success", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		repo.On("GetWeightLogById", 1).Return(weightLog(), nil)
+		repo.On("UpdateWeightLog", repository.WeightLog{Id: 1, UserId: "gooddy20", Weight: 70.2, BodyFat: &bodyFat, LoggedTimestamp: time.Date(2023, 12, 5, 7, 30, 0, 0, time.UTC), Status: 1}).Return(nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.UpdateWeightLog(context.Background(), "gooddy20", service.UpdateWeightLogRequest{Id: 1, Weight: service.NewNullable(70.2), LoggedTimestamp: service.NewNullable("2023-12-05 07:30:00")})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Success Case: Clear Body Fat", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		repo.On("GetWeightLogById", 1).Return(weightLog(), nil)
+		repo.On("UpdateWeightLog", repository.WeightLog{Id: 1, UserId: "gooddy20", Weight: 70.5, LoggedTimestamp: time.Date(2023, 12, 5, 7, 0, 0, 0, time.UTC), Status: 1}).Return(nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.UpdateWeightLog(context.Background(), "gooddy20", service.UpdateWeightLogRequest{Id: 1, BodyFat: service.NewNull[float64]()})
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Invalid Null Weight", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.UpdateWeightLog(context.Background(), "gooddy20", service.UpdateWeightLogRequest{Id: 1, Weight: service.NewNull[float64]()})
+		assert.ErrorIs(t, err, errs.NewValidationError("weight", "Weight can not be null"))
+		repo.AssertNotCalled(t, "GetWeightLogById")
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		repo.On("GetWeightLogById", 1).Return(weightLog(), nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.UpdateWeightLog(context.Background(), "kornkoko", service.UpdateWeightLogRequest{Id: 1, Weight: service.NewNullable(70.2)})
+		assert.ErrorIs(t, err, errs.NewPermissionDeniedError())
+		repo.AssertNotCalled(t, "UpdateWeightLog")
+	})
+	t.Run("No The Weight Log Id", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		repo.On("GetWeightLogById", 1).Return(&repository.WeightLog{}, repository.ErrNotFound)
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.UpdateWeightLog(context.Background(), "gooddy20", service.UpdateWeightLogRequest{Id: 1, Weight: service.NewNullable(70.2)})
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeWeightLogNotFound, "Weight Log Id - 1 is not found"))
+	})
+}
+
+func TestDeleteWeightLog(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		repo.On("GetWeightLogById", 1).Return(&repository.WeightLog{Id: 1, UserId: "gooddy20", Weight: 70.5, Status: 1}, nil)
+		repo.On("UpdateWeightLog", repository.WeightLog{Id: 1, UserId: "gooddy20", Weight: 70.5, Status: 0}).Return(nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.DeleteWeightLog(context.Background(), "gooddy20", 1)
+		assert.ErrorIs(t, err, nil)
+	})
+	t.Run("Not The Owner", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		repo.On("GetWeightLogById", 1).Return(&repository.WeightLog{Id: 1, UserId: "gooddy20", Weight: 70.5, Status: 1}, nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.DeleteWeightLog(context.Background(), "kornkoko", 1)
+		assert.ErrorIs(t, err, errs.NewPermissionDeniedError())
+		repo.AssertNotCalled(t, "UpdateWeightLog")
+	})
+	t.Run("Error", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		repo.On("GetWeightLogById", 1).Return(&repository.WeightLog{}, errors.New(""))
+		srv := service.NewWeightLogService(repo, userRepo)
+		err := srv.DeleteWeightLog(context.Background(), "gooddy20", 1)
+		assert.ErrorIs(t, err, errs.NewUnexpectedError())
+	})
+}
+
+func TestGetWeightTrend(t *testing.T) {
+	user := &repository.User{UserId: "gooddy20", Timezone: "Asia/Bangkok"}
+	t.Run("Success", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		bodyFat := 20.0
+		userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+		readFrom := time.Date(2023, 11, 2, 0, 0, 0, 0, time.UTC)
+		readTo := time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC)
+		repo.On("GetWeightLogsByUserId", "gooddy20", &readFrom, &readTo).Return([]repository.WeightLog{
+			{Id: 1, Weight: 70, BodyFat: &bodyFat, LoggedTimestamp: time.Date(2023, 12, 1, 0, 30, 0, 0, time.UTC)},
+			{Id: 2, Weight: 71, LoggedTimestamp: time.Date(2023, 12, 1, 12, 0, 0, 0, time.UTC)},
+			{Id: 3, Weight: 70, BodyFat: &bodyFat, LoggedTimestamp: time.Date(2023, 12, 2, 1, 0, 0, 0, time.UTC)},
+			{Id: 4, Weight: 69, LoggedTimestamp: time.Date(2023, 12, 4, 1, 0, 0, 0, time.UTC)},
+		}, nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		result, err := srv.GetWeightTrend(context.Background(), "gooddy20", "2023-12-02", "2023-12-04")
+		latest, rate := 69.77, -1.7
+		expected := &service.WeightTrendResponse{
+			UserId:   "gooddy20",
+			From:     "2023-12-02",
+			To:       "2023-12-04",
+			Timezone: "Asia/Bangkok",
+			Points: []service.WeightTrendPoint{
+				{Date: "2023-12-02", Weight: 70, BodyFat: &bodyFat, Trend: 70.38},
+				{Date: "2023-12-04", Weight: 69, Trend: 69.77},
+			},
+			Trend:      &latest,
+			WeeklyRate: &rate,
+		}
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Success Case: No Weight Log", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{UserId: "gooddy20"}, nil)
+		readFrom := time.Date(2023, 8, 8, 0, 0, 0, 0, time.UTC)
+		readTo := time.Date(2023, 12, 6, 0, 0, 0, 0, time.UTC)
+		repo.On("GetWeightLogsByUserId", "gooddy20", &readFrom, &readTo).Return([]repository.WeightLog{}, nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		result, err := srv.GetWeightTrend(context.Background(), "gooddy20", "", "2023-12-05")
+		expected := &service.WeightTrendResponse{
+			UserId:   "gooddy20",
+			From:     "2023-09-07",
+			To:       "2023-12-05",
+			Timezone: "UTC",
+			Points:   []service.WeightTrendPoint{},
+		}
+		assert.ErrorIs(t, err, nil)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Invalid Date", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		_, err := srv.GetWeightTrend(context.Background(), "gooddy20", "2023-12-05", "2023-12-01")
+		assert.ErrorIs(t, err, errs.NewValidationError("from", "From need to be before or equal to To"))
+		repo.AssertNotCalled(t, "GetWeightLogsByUserId")
+	})
+	t.Run("Invalid To", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(user, nil)
+		srv := service.NewWeightLogService(repo, userRepo)
+		_, err := srv.GetWeightTrend(context.Background(), "gooddy20", "2023-12-01", "05/12/2023")
+		assert.ErrorIs(t, err, errs.NewValidationError("to", `From and To need to be in the format "2023-01-01"`))
+		repo.AssertNotCalled(t, "GetWeightLogsByUserId")
+	})
+	t.Run("No The User Id", func(t *testing.T) {
+		repo := repository.NewWeightLogRepositoryMock()
+		userRepo := repository.NewUserRepositoryMock()
+		userRepo.On("GetUserById", "gooddy20").Return(&repository.User{}, repository.ErrNotFound)
+		srv := service.NewWeightLogService(repo, userRepo)
+		_, err := srv.GetWeightTrend(context.Background(), "gooddy20", "", "")
+		assert.ErrorIs(t, err, errs.NewNotFoundError(errs.CodeUserNotFound, "User Id is not found"))
+	})
+}